@@ -1,25 +1,36 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
 
 	_ "bico-admin/docs/admin"
 	_ "bico-admin/docs/api"
 	"bico-admin/internal/admin"
 	"bico-admin/internal/api"
 	"bico-admin/internal/core/app"
+	"bico-admin/internal/core/config"
 	"bico-admin/internal/core/logger"
 	"bico-admin/internal/core/server"
 	"bico-admin/internal/job"
 	"bico-admin/internal/migrate"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/tsgen"
 	"bico-admin/web"
 
 	"github.com/spf13/cobra"
+	"github.com/swaggo/swag"
 	"go.uber.org/zap"
 )
 
 var (
-	configPath string
+	configPath   string
+	tsOutputDir  string
+	jwtAlgorithm string
 )
 
 func main() {
@@ -110,9 +121,156 @@ var migrateCmd = &cobra.Command{
 	},
 }
 
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "代码生成",
+	Long:  "根据后端模块定义生成前端代码",
+}
+
+var genTSCmd = &cobra.Command{
+	Use:   "ts",
+	Short: "生成 TypeScript 客户端",
+	Long:  "根据 CRUD 模块与 Swagger 文档生成前端类型、接口函数和权限常量",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := generateTS(tsOutputDir); err != nil {
+			logger.Error("生成 TypeScript 客户端失败", zap.Error(err))
+			os.Exit(1)
+		}
+		logger.Info("TypeScript 客户端生成完成", zap.String("output", tsOutputDir))
+	},
+}
+
+// generateTS 生成 TypeScript 客户端文件。
+//
+// 说明：admin 文档在 docs/admin 包初始化时已补齐 CRUD 路由，这里直接读取注册后的文档，无需连接数据库。
+func generateTS(outputDir string) error {
+	doc, err := swag.ReadDoc("admin")
+	if err != nil {
+		return err
+	}
+
+	files, err := tsgen.Generate(doc, admin.NewCRUDModules(nil, nil, nil, nil, nil, nil), admin.BasePermissions())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return err
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var jwtCmd = &cobra.Command{
+	Use:   "jwt",
+	Short: "JWT 密钥管理",
+	Long:  "管理 jwt.keyring_file 指定的签名密钥环",
+}
+
+var jwtRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "生成并启用新的签名密钥",
+	Long:  "生成新的 active 密钥，原密钥转为退役并继续校验未过期的令牌；退役超过访问令牌有效期的密钥会被移除。密钥环文件不存在时创建。",
+	Run: func(cmd *cobra.Command, args []string) {
+		entry, err := rotateJWTKey(jwtAlgorithm)
+		if err != nil {
+			logger.Error("轮换 JWT 密钥失败", zap.Error(err))
+			os.Exit(1)
+		}
+		logger.Info("JWT 密钥轮换完成", zap.String("kid", entry.ID), zap.String("alg", entry.Algorithm))
+	},
+}
+
+var jwtKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "列出密钥环中的密钥",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := listJWTKeys(); err != nil {
+			logger.Error("读取 JWT 密钥环失败", zap.Error(err))
+			os.Exit(1)
+		}
+	},
+}
+
+// loadJWTConfig 读取 JWT 配置，未配置密钥环文件时无法管理密钥。
+func loadJWTConfig() (*config.JWTConfig, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.JWT.KeyringFile == "" {
+		return nil, errors.New("未配置 jwt.keyring_file")
+	}
+	return &cfg.JWT, nil
+}
+
+// rotateJWTKey 轮换签名密钥，alg 为空时使用 jwt.algorithm，均未配置时使用 RS256。
+//
+// 运行中的服务会在文件变更后自动加载新密钥，无需重启。
+func rotateJWTKey(alg string) (*jwt.KeyEntry, error) {
+	cfg, err := loadJWTConfig()
+	if err != nil {
+		return nil, err
+	}
+	if alg == "" {
+		alg = cfg.Algorithm
+	}
+	if alg == "" {
+		alg = jwt.AlgRS256
+	}
+
+	file, err := jwt.ReadKeyFile(cfg.KeyringFile)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := file.Rotate(alg, cfg.AccessExpire())
+	if err != nil {
+		return nil, err
+	}
+	if err := jwt.WriteKeyFile(cfg.KeyringFile, file); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// listJWTKeys 输出密钥环中的密钥，不输出密钥内容。
+func listJWTKeys() error {
+	cfg, err := loadJWTConfig()
+	if err != nil {
+		return err
+	}
+	file, err := jwt.ReadKeyFile(cfg.KeyringFile)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tALG\tSTATUS\tCREATED\tRETIRED")
+	for _, key := range file.Keys {
+		retired := "-"
+		if key.RetiredAt != nil {
+			retired = key.RetiredAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", key.ID, key.Algorithm, key.Status, key.CreatedAt.Format(time.DateTime), retired)
+	}
+	return w.Flush()
+}
+
 func init() {
+	genTSCmd.Flags().StringVarP(&tsOutputDir, "output", "o", "web/src/services/generated", "输出目录")
+	genCmd.AddCommand(genTSCmd)
+	jwtRotateCmd.Flags().StringVar(&jwtAlgorithm, "alg", "", "新密钥算法：HS256/RS256/ES256/EdDSA（默认取 jwt.algorithm）")
+	jwtCmd.AddCommand(jwtRotateCmd)
+	jwtCmd.AddCommand(jwtKeysCmd)
+
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "配置文件路径（默认自动查找 config.yaml 或 config/config.yaml）")
 
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(genCmd)
+	rootCmd.AddCommand(jwtCmd)
 }
@@ -26,7 +26,7 @@ func enhanceAdminDocs() error {
 		return err
 	}
 
-	modules := adminmodule.NewCRUDModules(nil, nil)
+	modules := adminmodule.NewCRUDModules(nil, nil, nil, nil, nil, nil)
 	crudModules := make([]swaggercrud.CRUDModule, 0, len(modules))
 	for _, module := range modules {
 		if module == nil {
@@ -42,7 +42,21 @@ func enhanceAdminDocs() error {
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile("docs/admin/admin_swagger.json", []byte(doc), 0o644); err != nil {
+	if err := writeDocFiles("docs/admin/admin_swagger", doc); err != nil {
+		return err
+	}
+
+	// OpenAPI 3.1 基于增强后的 Swagger 2.0 生成，保证两份文档的路由一致。
+	openAPI, err := swaggercrud.ConvertToOpenAPI31(doc)
+	if err != nil {
+		return err
+	}
+	return writeDocFiles("docs/admin/admin_openapi", openAPI)
+}
+
+// writeDocFiles 同时写入 json 与 yaml 两种格式的文档。
+func writeDocFiles(basePath string, doc string) error {
+	if err := os.WriteFile(basePath+".json", []byte(doc), 0o644); err != nil {
 		return err
 	}
 
@@ -54,5 +68,5 @@ func enhanceAdminDocs() error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile("docs/admin/admin_swagger.yaml", yamlContent, 0o644)
+	return os.WriteFile(basePath+".yaml", yamlContent, 0o644)
 }
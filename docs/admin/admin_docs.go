@@ -103,21 +103,21 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/admin-roles/{id}/permissions": {
+        "/admin-roles/{id}/denies": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "获取指定角色已配置的权限 key 列表",
+                "description": "获取指定角色的拒绝权限列表，拒绝项优先于该角色用户从任何角色获得的授权",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "角色管理"
                 ],
-                "summary": "获取角色权限",
+                "summary": "获取角色拒绝权限",
                 "parameters": [
                     {
                         "type": "integer",
@@ -154,7 +154,7 @@ const docTemplateadmin = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "覆盖指定角色的权限 key 列表",
+                "description": "覆盖指定角色的拒绝权限列表，支持以 :* 结尾的通配符",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,7 +164,7 @@ const docTemplateadmin = `{
                 "tags": [
                     "角色管理"
                 ],
-                "summary": "更新角色权限",
+                "summary": "更新角色拒绝权限",
                 "parameters": [
                     {
                         "type": "integer",
@@ -174,7 +174,7 @@ const docTemplateadmin = `{
                         "required": true
                     },
                     {
-                        "description": "权限列表",
+                        "description": "拒绝权限列表",
                         "name": "body",
                         "in": "body",
                         "required": true,
@@ -193,16 +193,30 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/app-config": {
+        "/admin-roles/{id}/parents": {
             "get": {
-                "description": "获取后台名称、Logo 和调试模式状态",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取指定角色直接继承的父角色 ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "公共"
+                    "角色管理"
+                ],
+                "summary": "获取继承角色",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取应用配置",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -215,7 +229,7 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.appConfigDocResponse"
+                                            "$ref": "#/definitions/handler.roleParentsResponse"
                                         }
                                     }
                                 }
@@ -223,72 +237,76 @@ const docTemplateadmin = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/avatar": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "上传当前用户头像文件",
+                "description": "覆盖指定角色继承的父角色，角色获得父角色及其祖先的全部授权与拒绝项；不能继承超级管理员或形成循环",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "角色管理"
                 ],
-                "summary": "上传头像",
+                "summary": "更新继承角色",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "头像文件",
-                        "name": "avatar",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "角色 ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "父角色 ID 列表",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateRoleParentsReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.adminResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handler.uploadResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handler.adminResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/current-user": {
+        "/admin-roles/{id}/permissions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "获取当前登录用户资料和权限",
+                "description": "获取指定角色已配置的权限 key 列表，可包含 system:* 形式的通配符",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "角色管理"
+                ],
+                "summary": "获取角色权限",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "角色 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取当前用户",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -301,7 +319,7 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.currentUserDocResponse"
+                                            "$ref": "#/definitions/handler.rolePermissionsResponse"
                                         }
                                     }
                                 }
@@ -309,11 +327,14 @@ const docTemplateadmin = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/login": {
-            "post": {
-                "description": "使用账号、密码和验证码换取登录 token",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "覆盖指定角色的权限 key 列表，支持以 :* 结尾的通配符，如 system:admin_user:*",
                 "consumes": [
                     "application/json"
                 ],
@@ -321,19 +342,59 @@ const docTemplateadmin = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "角色管理"
                 ],
-                "summary": "登录",
+                "summary": "更新角色权限",
                 "parameters": [
                     {
-                        "description": "登录参数",
+                        "type": "integer",
+                        "description": "角色 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "权限列表",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.loginRequest"
+                            "$ref": "#/definitions/handler.updateRolePermReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin-users/role-grants/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取指定天数内将要到期的限时角色授权，按到期时间升序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取即将到期的角色授权",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "天数，默认 7，最大 90",
+                        "name": "days",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -347,7 +408,10 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.RoleGrantItem"
+                                            }
                                         }
                                     }
                                 }
@@ -357,21 +421,30 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/admin-users/{id}/2fa/reset": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "将当前 token 加入黑名单",
+                "description": "清除指定用户的两步验证绑定、恢复码与通行密钥，用于用户丢失认证设备的场景",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户管理"
+                ],
+                "summary": "重置两步验证",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "退出登录",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -382,53 +455,61 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/auth/password": {
-            "put": {
+        "/admin-users/{id}/api-tokens": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "修改当前登录用户密码",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "获取指定用户的个人访问令牌，不返回令牌明文",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户管理"
                 ],
-                "summary": "修改密码",
+                "summary": "获取用户访问令牌",
                 "parameters": [
                     {
-                        "description": "密码参数",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/service.ChangePasswordRequest"
-                        }
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.adminResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.apiTokenDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/auth/profile": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "更新当前登录用户的名称和头像",
+                "description": "为指定用户（如 CI 专用账号）创建个人访问令牌，明文令牌仅在创建时返回一次；非超级管理员不能为超级管理员或拥有自己所没有权限的用户创建",
                 "consumes": [
                     "application/json"
                 ],
@@ -436,17 +517,24 @@ const docTemplateadmin = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户管理"
                 ],
-                "summary": "更新个人资料",
+                "summary": "为用户创建访问令牌",
                 "parameters": [
                     {
-                        "description": "个人资料",
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "令牌参数",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/service.UpdateProfileRequest"
+                            "$ref": "#/definitions/service.CreateAPITokenRequest"
                         }
                     }
                 ],
@@ -462,7 +550,7 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.currentUserDocResponse"
+                                            "$ref": "#/definitions/handler.apiTokenCreatedDoc"
                                         }
                                     }
                                 }
@@ -472,53 +560,71 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/captcha": {
-            "get": {
-                "description": "生成登录验证码",
+        "/admin-users/{id}/api-tokens/{tid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销指定用户的某个访问令牌，令牌立即失效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户管理"
+                ],
+                "summary": "吊销用户访问令牌",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "令牌 ID",
+                        "name": "tid",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取验证码",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/handler.adminResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/handler.captchaResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/handler.adminResponse"
                         }
                     }
                 }
             }
         },
-        "/dashboard/overview": {
-            "get": {
+        "/admin-users/{id}/impersonate": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "获取服务器、运行时、数据库和监控指标概览",
+                "description": "超级管理员以指定用户身份登录，用于排查该用户看到的页面与权限；令牌同时携带操作者身份，最长有效 1 小时，期间不能修改密码、两步验证等安全设置，也不能再次发起模拟",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "工作台"
+                    "用户管理"
+                ],
+                "summary": "模拟用户登录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取工作台概览",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -531,7 +637,7 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.DashboardOverview"
+                                            "$ref": "#/definitions/handler.loginDocResponse"
                                         }
                                     }
                                 }
@@ -541,55 +647,161 @@ const docTemplateadmin = `{
                 }
             }
         },
-        "/demo/excel/export": {
+        "/admin-users/{id}/role-grants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "导出示例 Excel 文件",
+                "description": "获取指定用户的全部角色授权及其有效期、原因，含尚未生效的授权",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "示例"
+                    "用户管理"
+                ],
+                "summary": "获取用户角色授权",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "导出 Excel",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/service.RoleGrantItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/demo/excel/import": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "上传并解析示例 Excel 文件",
+                "description": "授予指定用户角色，可设置生效与到期时间及原因；用户已有该角色时覆盖其有效期与原因。到期后授权由定时任务删除",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "示例"
+                    "用户管理"
                 ],
-                "summary": "导入 Excel",
+                "summary": "授予用户角色",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Excel 或 CSV 文件",
-                        "name": "file",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "授权参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RoleGrantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin-users/{id}/role-grants/{gid}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "撤销指定用户的某个角色授权，立即生效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "撤销用户角色授权",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "授权 ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin-users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取指定用户未过期的登录会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取用户登录会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -605,7 +817,10 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.demoExcelImportResponse"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.sessionDocItem"
+                                            }
                                         }
                                     }
                                 }
@@ -613,77 +828,91 @@ const docTemplateadmin = `{
                         }
                     }
                 }
-            }
-        },
-        "/demo/excel/template": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "下载示例 Excel 模板文件",
+                "description": "下线指定用户的全部登录会话，比修改密码更细粒度，不影响用户凭据",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "示例"
+                    "用户管理"
+                ],
+                "summary": "下线用户全部会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "下载 Excel 导入模板",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/handler.adminResponse"
                         }
                     }
                 }
             }
         },
-        "/upload": {
-            "post": {
+        "/admin-users/{id}/sessions/{sid}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "上传富文本图片或视频文件",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "下线指定用户的某个登录会话，该会话的令牌立即失效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "上传"
+                    "用户管理"
                 ],
-                "summary": "上传通用文件",
+                "summary": "下线用户会话",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "通用文件",
-                        "name": "file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "图片文件",
-                        "name": "image",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "视频文件",
-                        "name": "video",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "上传类型，image 或 video",
-                        "name": "type",
-                        "in": "formData"
+                        "type": "integer",
+                        "description": "会话 ID",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
                     }
+                }
+            }
+        },
+        "/app-config": {
+            "get": {
+                "description": "获取后台名称、Logo 和调试模式状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "公共"
                 ],
+                "summary": "获取应用配置",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -696,7 +925,7 @@ const docTemplateadmin = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/handler.uploadResponse"
+                                            "$ref": "#/definitions/handler.appConfigDocResponse"
                                         }
                                     }
                                 }
@@ -705,343 +934,4172 @@ const docTemplateadmin = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.DashboardDatabaseInfo": {
-            "type": "object",
-            "properties": {
-                "driver": {
-                    "type": "string"
-                },
-                "idle": {
-                    "type": "integer"
-                },
-                "inUse": {
-                    "type": "integer"
-                },
-                "maxIdleConnections": {
-                    "type": "integer"
-                },
-                "maxOpenConnections": {
-                    "type": "integer"
-                },
-                "openConnections": {
-                    "type": "integer"
-                },
-                "waitCount": {
-                    "type": "integer"
-                },
-                "waitDurationSeconds": {
-                    "type": "number"
+        },
+        "/auth/2fa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户是否已绑定、是否被角色强制要求以及剩余恢复码数量",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取两步验证状态",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.TwoFactorStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验密码与动态码（或恢复码）后关闭两步验证，角色强制要求时不可关闭",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "关闭两步验证",
+                "parameters": [
+                    {
+                        "description": "关闭参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorDisableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验认证器生成的动态码后启用两步验证，返回的恢复码仅展示一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "启用两步验证",
+                "parameters": [
+                    {
+                        "description": "动态码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.recoveryCodesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/passkey/begin": {
+            "post": {
+                "description": "使用登录返回的 twoFactorToken 获取通行密钥验证参数，options 原样传给 navigator.credentials.get；仅在 twoFactorMethods 包含 passkey 时可用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "通行密钥两步验证参数",
+                "parameters": [
+                    {
+                        "description": "待验证令牌",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorPendingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.PasskeyCeremony"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/passkey/verify": {
+            "post": {
+                "description": "提交 navigator.credentials.get 返回的凭据完成两步验证并换取登录 token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "通行密钥两步验证",
+                "parameters": [
+                    {
+                        "description": "两步验证参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PasskeyTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/pending/setup": {
+            "post": {
+                "description": "所属角色强制要求两步验证但尚未绑定时，凭 twoFactorToken 获取密钥与二维码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "登录中绑定两步验证",
+                "parameters": [
+                    {
+                        "description": "待验证令牌",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorPendingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.twoFactorKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "校验动态码后重新生成恢复码，旧恢复码全部作废",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "重新生成恢复码",
+                "parameters": [
+                    {
+                        "description": "动态码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.recoveryCodesResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "生成新的 TOTP 密钥与 otpauth 二维码，需调用启用接口确认后生效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取绑定二维码",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.twoFactorKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "使用登录返回的 twoFactorToken 与动态码或恢复码换取登录 token；强制绑定场景下首次校验即完成绑定并返回恢复码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "登录两步验证",
+                "parameters": [
+                    {
+                        "description": "两步验证参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.TwoFactorVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/api-tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户的个人访问令牌，不返回令牌明文",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取访问令牌",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.apiTokenDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "为当前用户创建个人访问令牌，供脚本以 Bearer 方式调用接口；明文令牌仅在创建时返回一次，访问令牌本身不能再创建令牌",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "创建访问令牌",
+                "parameters": [
+                    {
+                        "description": "令牌参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.CreateAPITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.apiTokenCreatedDoc"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/api-tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销当前用户的指定访问令牌，令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "吊销访问令牌",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "令牌 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传当前用户头像文件",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "上传头像",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "头像文件",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.uploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/current-user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前登录用户资料和权限",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取当前用户",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.currentUserDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/impersonate/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "结束当前模拟登录，吊销模拟令牌并在发起模拟时的原会话中返回操作者自己的令牌；原登录已失效时返回 401，需重新登录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "退出模拟登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "使用账号、密码和验证码换取登录 token；启用按需验证码时，失败次数未达阈值可不传验证码，需要验证码而未传时返回 code 428",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "登录",
+                "parameters": [
+                    {
+                        "description": "登录参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.loginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "将当前 token 加入黑名单，并吊销本次登录的刷新令牌",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "退出登录",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkey/login/begin": {
+            "post": {
+                "description": "获取免密码登录参数，options 原样传给 navigator.credentials.get，由认证器选择账号",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "通行密钥登录参数",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.PasskeyCeremony"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkey/login/finish": {
+            "post": {
+                "description": "提交 navigator.credentials.get 返回的凭据换取登录 token；通行密钥已验证用户身份，不再要求两步验证；被标记为下次登录修改密码时返回 passwordChangeToken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "通行密钥登录",
+                "parameters": [
+                    {
+                        "description": "登录参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PasskeyLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户已绑定的通行密钥",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取通行密钥",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.passkeyDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/register/begin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取注册参数，options 原样传给 navigator.credentials.create",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "通行密钥注册参数",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.PasskeyCeremony"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/register/finish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "提交 navigator.credentials.create 返回的凭据完成注册；绑定后账号密码登录需通过通行密钥或动态码完成两步验证",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "注册通行密钥",
+                "parameters": [
+                    {
+                        "description": "注册参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.PasskeyRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.passkeyDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/passkeys/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改当前用户指定通行密钥的名称",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "重命名通行密钥",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通行密钥 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "名称",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RenamePasskeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除当前用户的指定通行密钥，删除后不能再用于登录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "删除通行密钥",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通行密钥 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "修改当前登录用户密码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "修改密码",
+                "parameters": [
+                    {
+                        "description": "密码参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/expired": {
+            "post": {
+                "description": "密码过期或管理员要求修改时，凭登录返回的 passwordChangeToken 设置新密码并完成登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "登录时修改密码",
+                "parameters": [
+                    {
+                        "description": "新密码参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.ExpiredPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "向用户名或邮箱匹配的账号发送重置密码邮件；为避免枚举账号，账号不存在或未绑定邮箱时同样返回成功",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "找回密码",
+                "parameters": [
+                    {
+                        "description": "找回密码参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.forgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "凭找回密码邮件中的令牌设置新密码，成功后该用户已登录的会话全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "重置密码",
+                "parameters": [
+                    {
+                        "description": "重置密码参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.resetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/profile": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "更新当前登录用户的用户名、名称和头像",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "更新个人资料",
+                "parameters": [
+                    {
+                        "description": "个人资料",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.currentUserDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌与刷新令牌，旧刷新令牌随即失效；重复使用已轮换的刷新令牌会吊销整次登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "刷新令牌",
+                "parameters": [
+                    {
+                        "description": "刷新令牌",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户未过期的登录会话，current 标记当前请求所在会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取登录会话",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.sessionDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "下线当前用户的指定登录会话，该会话的令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "下线会话",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "会话 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sso/callback": {
+            "post": {
+                "description": "提交身份提供方回调中的 state 与 code 换取登录 token；需要两步验证时与密码登录返回相同结构",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "完成单点登录",
+                "parameters": [
+                    {
+                        "description": "回调参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.SSOCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.loginDocResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sso/{provider}/authorize": {
+            "get": {
+                "description": "返回身份提供方授权地址，前端跳转后由身份提供方回调到配置的 redirect_url；同时写入绑定当前浏览器的 HttpOnly Cookie，回调须由同一浏览器提交",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "发起单点登录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "登录方式标识",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/service.SSOAuthorizeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/captcha": {
+            "get": {
+                "description": "按配置生成数字、字母数字、算式或滑块验证码；滑块验证码提交拼图块左边缘横坐标作为 captchaCode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取验证码",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.captchaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard/overview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取服务器、运行时、数据库和监控指标概览",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "工作台"
+                ],
+                "summary": "获取工作台概览",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.DashboardOverview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/demo/excel/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "导出示例 Excel 文件，传 ids 时只导出勾选行",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "示例"
+                ],
+                "summary": "导出 Excel",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/demo/excel/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传并解析示例 Excel 文件",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "示例"
+                ],
+                "summary": "导入 Excel",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Excel 或 CSV 文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.demoExcelImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/demo/excel/template": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "下载示例 Excel 模板文件",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "示例"
+                ],
+                "summary": "下载 Excel 导入模板",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/departments/tree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按上级关系返回部门树，同级按排序值升序；按名称或状态筛选时，上级未命中的部门作为根节点返回",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门管理"
+                ],
+                "summary": "获取部门树",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "部门名称",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否启用",
+                        "name": "enabled",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.departmentTreeDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/departments/{id}/move": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "将部门连同下级部门移动到新的上级部门下，可同时调整排序值；不能移动到自身或下级部门下",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "部门管理"
+                ],
+                "summary": "移动部门",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "部门 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新的上级部门与排序值",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.moveDepartmentReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "分页查询动态表数据，筛选参数按字段定义的 filter 解析，区间筛选使用 {name}_start / {name}_end",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "获取动态表数据列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序字段，仅支持可排序字段和系统字段",
+                        "name": "sortField",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序方式：ascend/descend",
+                        "name": "sortOrder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.dynamicPageDoc"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "请求体为字段名到值的映射，按字段定义校验",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "创建动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "记录数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/batch": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "批量删除动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "记录 ID 列表",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.deleteBatchDocRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按当前筛选条件导出，传 ids 时只导出勾选行",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "导出动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "勾选记录 ID，逗号分隔",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传 Excel 或 CSV，逐行按字段定义校验，任一行失败则整体不导入",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "导入动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Excel 或 CSV 文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.dynamicImportResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取动态表字段定义，前端据此渲染列表、筛选和表单",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "获取动态表定义",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/template": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "表头为字段显示名称",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "下载动态表导入模板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-data/{table}/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "获取动态表数据详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "记录 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "只更新请求体中出现的字段",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "更新动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "记录 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "记录数据",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表数据"
+                ],
+                "summary": "删除动态表数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "动态表标识",
+                        "name": "table",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "记录 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dynamic-tables/menus": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取已启用动态表的菜单项，前端按 access 权限过滤后追加到导航",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "动态表"
+                ],
+                "summary": "获取动态表菜单",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.dynamicMenuItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "基于已注册 CRUD 模块生成的 GraphQL 接口，字段级复用 REST 路由的权限 key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "GraphQL 查询",
+                "parameters": [
+                    {
+                        "description": "GraphQL 请求",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.graphqlRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.graphqlResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login-logs/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按当前筛选条件导出，传 ids 时只导出勾选行",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "登录日志"
+                ],
+                "summary": "导出登录日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户名",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结果：success / failure",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "失败原因",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IP 前缀",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（2006-01-02 15:04:05）",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（2006-01-02 15:04:05）",
+                        "name": "end_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "勾选记录 ID，逗号分隔",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/security/explain": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "说明用户能否使用指定权限：判定结果与原因、参与计算的角色与授权（含禁用角色和未生效授权）、超级管理员、用户状态与缓存情况",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限排查"
+                ],
+                "summary": "权限排查",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "权限 key，如 system:admin_user:list",
+                        "name": "permission",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.permissionExplanationDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/security/explain/route": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按请求方法与路径找到模块路由声明的权限后进行排查，路由权限中的 {param} 会替换为路径中的值；只能匹配通过模块注册的路由",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "权限排查"
+                ],
+                "summary": "按接口路径排查权限",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户 ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求方法，默认 GET",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求路径，如 /admin-api/admin-users/1，可省略 /admin-api 前缀",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.permissionExplanationDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/security/lockouts/{username}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "提前解除账号或来源 IP 的登录锁定并清零失败次数；kind=ip 时路径参数为 IP",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "登录锁定"
+                ],
+                "summary": "解除登录锁定",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户名或 IP",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "锁定类型：username（默认）/ ip",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传富文本图片或视频文件",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "上传"
+                ],
+                "summary": "上传通用文件",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "通用文件",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "图片文件",
+                        "name": "image",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "视频文件",
+                        "name": "video",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上传类型，image 或 video",
+                        "name": "type",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.uploadResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/views": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取当前用户在指定模块下自己创建和共享给自己的视图",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "获取列表视图",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模块名，即 crud.ModuleConfig.Name",
+                        "name": "module",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.listViewDocItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "创建列表视图",
+                "parameters": [
+                    {
+                        "description": "视图参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.createListViewReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.listViewDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/views/default": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "优先返回自己的默认视图，其次返回共享给自己的默认视图，都没有时 data 为 null",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "获取默认列表视图",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模块名，即 crud.ModuleConfig.Name",
+                        "name": "module",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.listViewDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/views/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "获取列表视图详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "视图 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.listViewDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "只有创建者可以修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "更新列表视图",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "视图 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "视图参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateListViewReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.adminResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handler.listViewDocItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "只有创建者可以删除",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "列表视图"
+                ],
+                "summary": "删除列表视图",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "视图 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.adminResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.DashboardDatabaseInfo": {
+            "type": "object",
+            "properties": {
+                "driver": {
+                    "type": "string"
+                },
+                "idle": {
+                    "type": "integer"
+                },
+                "inUse": {
+                    "type": "integer"
+                },
+                "maxIdleConnections": {
+                    "type": "integer"
+                },
+                "maxOpenConnections": {
+                    "type": "integer"
+                },
+                "openConnections": {
+                    "type": "integer"
+                },
+                "waitCount": {
+                    "type": "integer"
+                },
+                "waitDurationSeconds": {
+                    "type": "number"
                 }
             }
         },
         "handler.DashboardMonitorInfo": {
             "type": "object",
             "properties": {
-                "collectedAt": {
+                "collectedAt": {
+                    "type": "string"
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.DashboardMonitorMetric"
+                    }
+                }
+            }
+        },
+        "handler.DashboardMonitorMetric": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.DashboardOverview": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/handler.DashboardDatabaseInfo"
+                },
+                "monitor": {
+                    "$ref": "#/definitions/handler.DashboardMonitorInfo"
+                },
+                "runtime": {
+                    "$ref": "#/definitions/handler.DashboardRuntimeInfo"
+                },
+                "server": {
+                    "$ref": "#/definitions/handler.DashboardServerInfo"
+                }
+            }
+        },
+        "handler.DashboardRuntimeInfo": {
+            "type": "object",
+            "properties": {
+                "allocMb": {
+                    "type": "number"
+                },
+                "cpuCores": {
+                    "type": "integer"
+                },
+                "gcCycles": {
+                    "type": "integer"
+                },
+                "goMaxProcs": {
+                    "type": "integer"
+                },
+                "goroutines": {
+                    "type": "integer"
+                },
+                "heapInuseMb": {
+                    "type": "number"
+                },
+                "nextGcMb": {
+                    "type": "number"
+                },
+                "sysMb": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.DashboardServerInfo": {
+            "type": "object",
+            "properties": {
+                "arch": {
+                    "type": "string"
+                },
+                "goVersion": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "uptimeSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.adminResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "msg": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.adminRoleDocItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "system": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.apiTokenCreatedDoc": {
+            "type": "object",
+            "properties": {
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.apiTokenDocItem": {
+            "type": "object",
+            "properties": {
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.appConfigDocResponse": {
+            "type": "object",
+            "properties": {
+                "captchaAdaptive": {
+                    "type": "boolean"
+                },
+                "debug": {
+                    "type": "boolean"
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passkey": {
+                    "type": "boolean"
+                },
+                "passwordReset": {
+                    "type": "boolean"
+                },
+                "ssoProviders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.SSOProviderInfo"
+                    }
+                }
+            }
+        },
+        "handler.captchaResponse": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "piece": {
+                    "type": "string"
+                },
+                "pieceY": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.createListViewReq": {
+            "type": "object",
+            "required": [
+                "module",
+                "name"
+            ],
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "module": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "role_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sort_field": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.currentUserDocResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impersonated": {
+                    "description": "模拟登录时由处理器填充，Impersonator 为实际操作者。",
+                    "type": "boolean"
+                },
+                "impersonator": {
+                    "$ref": "#/definitions/service.ImpersonatorInfo"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "super_admin": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.deleteBatchDocRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.demoExcelImportResponse": {
+            "type": "object",
+            "properties": {
+                "preview": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.departmentTreeDocItem": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.departmentTreeDocItem"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "leader_id": {
+                    "type": "integer"
+                },
+                "leader_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.dynamicImportResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.dynamicMenuItem": {
+            "type": "object",
+            "properties": {
+                "access": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.dynamicPageDoc": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": true
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.forgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "account",
+                "captchaCode",
+                "captchaId"
+            ],
+            "properties": {
+                "account": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "captchaCode": {
+                    "type": "string"
+                },
+                "captchaId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.graphqlRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "handler.graphqlResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "handler.listViewDocItem": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "module": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owned": {
+                    "type": "boolean"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "role_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sort_field": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.loginDocResponse": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "type": "integer"
+                },
+                "passwordChangeToken": {
+                    "type": "string"
+                },
+                "passwordExpired": {
+                    "type": "boolean"
+                },
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "twoFactorMethods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "twoFactorSetup": {
+                    "type": "boolean"
+                },
+                "twoFactorToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.loginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "captchaCode": {
+                    "type": "string"
+                },
+                "captchaId": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.moveDepartmentReq": {
+            "type": "object",
+            "required": [
+                "parent_id"
+            ],
+            "properties": {
+                "parent_id": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.passkeyDocItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sign_count": {
+                    "type": "integer"
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.permissionDocItem": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.permissionDocItem"
+                    }
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.permissionExplanationDocItem": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "cache": {
+                    "$ref": "#/definitions/service.PermissionCacheState"
+                },
+                "permission": {
                     "type": "string"
                 },
-                "metrics": {
+                "reason": {
+                    "type": "string"
+                },
+                "registered": {
+                    "type": "boolean"
+                },
+                "roles": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.DashboardMonitorMetric"
+                        "$ref": "#/definitions/service.RoleExplanation"
+                    }
+                },
+                "route": {
+                    "$ref": "#/definitions/handler.routeDocItem"
+                },
+                "super_admin": {
+                    "type": "boolean"
+                },
+                "user_enabled": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.recoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recoveryCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "captchaCode",
+                "captchaId",
+                "newPassword",
+                "token"
+            ],
+            "properties": {
+                "captchaCode": {
+                    "type": "string"
+                },
+                "captchaId": {
+                    "type": "string"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.roleParentsResponse": {
+            "type": "object",
+            "properties": {
+                "parent_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.rolePermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.routeDocItem": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "module": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "permission": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.sessionDocItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.twoFactorKeyResponse": {
+            "type": "object",
+            "properties": {
+                "image": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.updateListViewReq": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "role_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "sort_field": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.updateRoleParentsReq": {
+            "type": "object",
+            "properties": {
+                "parent_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.updateRolePermReq": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.uploadResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "oldPassword"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "oldPassword": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.CreateAPITokenRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "handler.DashboardMonitorMetric": {
+        "service.ExpiredPasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "passwordChangeToken"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "passwordChangeToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ImpersonatorInfo": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PasskeyCeremony": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "type": "object"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PasskeyLoginRequest": {
             "type": "object",
+            "required": [
+                "credential",
+                "token"
+            ],
             "properties": {
-                "key": {
-                    "type": "string"
+                "credential": {
+                    "description": "Credential 为 navigator.credentials.get 返回的凭据 JSON。",
+                    "type": "object"
                 },
-                "label": {
+                "token": {
                     "type": "string"
+                }
+            }
+        },
+        "service.PasskeyRegisterRequest": {
+            "type": "object",
+            "required": [
+                "credential",
+                "name",
+                "token"
+            ],
+            "properties": {
+                "credential": {
+                    "description": "Credential 为 navigator.credentials.create 返回的凭据 JSON。",
+                    "type": "object"
                 },
-                "status": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "unit": {
+                "token": {
                     "type": "string"
-                },
-                "value": {
-                    "type": "number"
                 }
             }
         },
-        "handler.DashboardOverview": {
+        "service.PasskeyTwoFactorRequest": {
             "type": "object",
+            "required": [
+                "credential",
+                "twoFactorToken"
+            ],
             "properties": {
-                "database": {
-                    "$ref": "#/definitions/handler.DashboardDatabaseInfo"
-                },
-                "monitor": {
-                    "$ref": "#/definitions/handler.DashboardMonitorInfo"
+                "credential": {
+                    "type": "object"
                 },
-                "runtime": {
-                    "$ref": "#/definitions/handler.DashboardRuntimeInfo"
-                },
-                "server": {
-                    "$ref": "#/definitions/handler.DashboardServerInfo"
+                "twoFactorToken": {
+                    "type": "string"
                 }
             }
         },
-        "handler.DashboardRuntimeInfo": {
+        "service.PermissionCacheState": {
             "type": "object",
             "properties": {
-                "allocMb": {
-                    "type": "number"
-                },
-                "cpuCores": {
-                    "type": "integer"
-                },
-                "gcCycles": {
-                    "type": "integer"
-                },
-                "goMaxProcs": {
-                    "type": "integer"
-                },
-                "goroutines": {
-                    "type": "integer"
-                },
-                "heapInuseMb": {
-                    "type": "number"
+                "permission_cached": {
+                    "type": "boolean"
                 },
-                "nextGcMb": {
-                    "type": "number"
+                "stale": {
+                    "type": "boolean"
                 },
-                "sysMb": {
-                    "type": "number"
+                "status_cached": {
+                    "type": "boolean"
                 }
             }
         },
-        "handler.DashboardServerInfo": {
+        "service.RefreshRequest": {
             "type": "object",
+            "required": [
+                "refreshToken"
+            ],
             "properties": {
-                "arch": {
-                    "type": "string"
-                },
-                "goVersion": {
-                    "type": "string"
-                },
-                "hostname": {
-                    "type": "string"
-                },
-                "mode": {
-                    "type": "string"
-                },
-                "os": {
-                    "type": "string"
-                },
-                "port": {
-                    "type": "integer"
-                },
-                "startedAt": {
+                "refreshToken": {
                     "type": "string"
-                },
-                "uptimeSeconds": {
-                    "type": "integer"
                 }
             }
         },
-        "handler.adminResponse": {
+        "service.RenamePasskeyRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {},
-                "msg": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
-        "handler.adminRoleDocItem": {
+        "service.RoleExplanation": {
             "type": "object",
             "properties": {
-                "description": {
+                "code": {
                     "type": "string"
                 },
+                "effective": {
+                    "description": "是否参与权限计算",
+                    "type": "boolean"
+                },
                 "enabled": {
                     "type": "boolean"
                 },
+                "grant_active": {
+                    "type": "boolean"
+                },
+                "grant_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "inherited": {
+                    "type": "boolean"
                 },
-                "permissions": {
+                "matched_denies": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "system": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "handler.appConfigDocResponse": {
-            "type": "object",
-            "properties": {
-                "debug": {
-                    "type": "boolean"
-                },
-                "logo": {
-                    "type": "string"
+                "matched_grants": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "name": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.captchaResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
+                },
+                "valid_from": {
                     "type": "string"
                 },
-                "image": {
+                "valid_until": {
                     "type": "string"
                 }
             }
         },
-        "handler.currentUserDocResponse": {
+        "service.RoleGrantItem": {
             "type": "object",
             "properties": {
-                "avatar": {
-                    "type": "string"
-                },
-                "enabled": {
+                "active": {
                     "type": "boolean"
                 },
+                "granted_by": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "reason": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "integer"
+                },
+                "role_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 },
                 "username": {
                     "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
                 }
             }
         },
-        "handler.demoExcelImportResponse": {
+        "service.RoleGrantRequest": {
             "type": "object",
+            "required": [
+                "role_id"
+            ],
             "properties": {
-                "preview": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
                 },
-                "total": {
+                "role_id": {
                     "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
                 }
             }
         },
-        "handler.loginDocResponse": {
+        "service.SSOAuthorizeResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "handler.loginRequest": {
+        "service.SSOCallbackRequest": {
             "type": "object",
             "required": [
-                "captchaCode",
-                "captchaId",
-                "password",
-                "username"
+                "code",
+                "state"
             ],
             "properties": {
-                "captchaCode": {
-                    "type": "string"
-                },
-                "captchaId": {
-                    "type": "string"
-                },
-                "password": {
+                "code": {
                     "type": "string"
                 },
-                "username": {
+                "state": {
                     "type": "string"
                 }
             }
         },
-        "handler.permissionDocItem": {
+        "service.SSOProviderInfo": {
             "type": "object",
             "properties": {
-                "children": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.permissionDocItem"
-                    }
-                },
-                "key": {
+                "label": {
                     "type": "string"
                 },
-                "label": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "handler.rolePermissionsResponse": {
+        "service.TwoFactorCodeRequest": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "code": {
+                    "type": "string"
                 }
             }
         },
-        "handler.updateRolePermReq": {
+        "service.TwoFactorDisableRequest": {
             "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
             "properties": {
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "handler.uploadResponse": {
+        "service.TwoFactorPendingRequest": {
             "type": "object",
+            "required": [
+                "twoFactorToken"
+            ],
             "properties": {
-                "url": {
+                "twoFactorToken": {
                     "type": "string"
                 }
             }
         },
-        "service.ChangePasswordRequest": {
+        "service.TwoFactorStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recoveryCodesRemaining": {
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "Required 表示所属角色强制要求两步验证。",
+                    "type": "boolean"
+                }
+            }
+        },
+        "service.TwoFactorVerifyRequest": {
             "type": "object",
             "required": [
-                "newPassword",
-                "oldPassword"
+                "code",
+                "twoFactorToken"
             ],
             "properties": {
-                "newPassword": {
-                    "type": "string",
-                    "minLength": 8
+                "code": {
+                    "description": "Code 为 6 位动态码或恢复码。",
+                    "type": "string"
                 },
-                "oldPassword": {
+                "twoFactorToken": {
                     "type": "string"
                 }
             }
@@ -1054,6 +5112,10 @@ const docTemplateadmin = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "x-nullable": true
                 }
             }
         }
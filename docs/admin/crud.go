@@ -9,7 +9,7 @@ import (
 //
 // 说明：swag 注册的是 SwaggerInfoadmin 指针，更新模板字段后 UI 会读取增强后的内容。
 func init() {
-	modules := adminmodule.NewCRUDModules(nil, nil)
+	modules := adminmodule.NewCRUDModules(nil, nil, nil, nil, nil, nil)
 	crudModules := make([]swaggercrud.CRUDModule, 0, len(modules))
 	for _, module := range modules {
 		if module == nil {
@@ -4,10 +4,12 @@ import (
 	"bico-admin/internal/admin/model"
 	"bico-admin/internal/admin/service"
 	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/permission"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -53,8 +55,14 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if err != nil {
 			return err
 		}
+		deniesMap, parentsMap, err := h.getPolicyMaps(db, roleIDs)
+		if err != nil {
+			return err
+		}
 		for i := range items {
 			items[i].Permissions = permsMap[items[i].ID]
+			items[i].Denies = deniesMap[items[i].ID]
+			items[i].ParentIDs = parentsMap[items[i].ID]
 			items[i].System = items[i].Code == model.SuperAdminRoleCode
 		}
 		return nil
@@ -66,6 +74,12 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 			return err
 		}
 		item.Permissions = perms
+		deniesMap, parentsMap, err := h.getPolicyMaps(db, []uint{item.ID})
+		if err != nil {
+			return err
+		}
+		item.Denies = deniesMap[item.ID]
+		item.ParentIDs = parentsMap[item.ID]
 		item.System = item.Code == model.SuperAdminRoleCode
 		return nil
 	}
@@ -83,12 +97,18 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 			// 内部标识生成失败时禁止创建不完整角色。
 			return nil, err
 		}
+		allowedIPs, err := service.NormalizeAllowedIPs(req.AllowedIPs)
+		if err != nil {
+			return nil, err
+		}
 		return &model.AdminRole{
-			Name:        req.Name,
-			Code:        code,
-			Description: req.Description,
-			Enabled:     req.Enabled == nil || *req.Enabled,
-			Permissions: req.Permissions,
+			Name:              req.Name,
+			Code:              code,
+			Description:       req.Description,
+			Enabled:           req.Enabled == nil || *req.Enabled,
+			TwoFactorRequired: req.TwoFactorRequired,
+			AllowedIPs:        allowedIPs,
+			Permissions:       req.Permissions,
 		}, nil
 	}
 
@@ -99,8 +119,11 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 
 	h.BuildUpdates = func(req *updateRoleReq, existing *model.AdminRole) (map[string]interface{}, error) {
 		if existing.Code == model.SuperAdminRoleCode {
-			// 超级管理员角色必须保持启用且不可改名。
-			return nil, errors.New("超级管理员角色不可修改")
+			// 超级管理员角色必须保持启用且不可改名，仅允许调整两步验证策略。
+			if req.Name != "" || req.Description != "" || req.Enabled != nil || req.TwoFactorRequired == nil {
+				return nil, errors.New("超级管理员角色不可修改")
+			}
+			return map[string]interface{}{"two_factor_required": *req.TwoFactorRequired}, nil
 		}
 		// 这里校验名称唯一性：只有在传入 name 且发生变更时才检查
 		if req.Name != "" && req.Name != existing.Name {
@@ -123,12 +146,23 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if req.Enabled != nil {
 			updates["enabled"] = *req.Enabled
 		}
+		if req.TwoFactorRequired != nil {
+			updates["two_factor_required"] = *req.TwoFactorRequired
+		}
 		return updates, nil
 	}
 
 	h.UpdateInTx = func(tx *gorm.DB, id uint, existing *model.AdminRole, req *updateRoleReq) error {
-		// 角色启用状态变化会直接影响用户权限结果，需要失效该角色下用户缓存。
-		if req.Enabled != nil && *req.Enabled != existing.Enabled && h.cacheInvalidator != nil {
+		if req.AllowedIPs != nil {
+			allowedIPs, err := updateAllowedIPs(tx, &model.AdminRole{}, id, req.AllowedIPs)
+			if err != nil {
+				return err
+			}
+			existing.AllowedIPs = allowedIPs
+		}
+		// 角色启用状态与来源限制变化会直接影响用户鉴权结果，需要失效该角色及其子角色下用户缓存。
+		// existing 此时已写入本次更新的字段，无法再比较启用状态是否变化，提交了 enabled 即失效。
+		if (req.Enabled != nil || req.AllowedIPs != nil) && h.cacheInvalidator != nil {
 			h.cacheInvalidator.InvalidateRoleUsersPermissionCache(existing.ID)
 		}
 		return nil
@@ -160,11 +194,19 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if err := tx.Where("role_id = ?", id).Delete(&model.AdminRolePermission{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("role_id = ?", id).Delete(&model.AdminRoleDeny{}).Error; err != nil {
+			return err
+		}
+		// 子角色不再继承被删除的角色。
+		if err := tx.Where("role_id = ? OR parent_id = ?", id, id).Delete(&model.AdminRoleParent{}).Error; err != nil {
+			return err
+		}
 		// 再清理用户角色关联，失败则回滚。
 		if err := tx.Where("role_id = ?", id).Delete(&model.AdminUserRole{}).Error; err != nil {
 			return err
 		}
-		return nil
+		// 最后取消共享给该角色的列表视图。
+		return tx.Where("role_id = ?", id).Delete(&model.ListViewRole{}).Error
 	}
 	h.DeleteBatchInTx = func(tx *gorm.DB, ids []uint) error {
 		// 删除角色前先失效这些角色下的用户权限缓存，避免关联清理后无法定位用户集合。
@@ -175,11 +217,18 @@ func NewAdminRoleHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if err := tx.Where("role_id IN ?", ids).Delete(&model.AdminRolePermission{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("role_id IN ?", ids).Delete(&model.AdminRoleDeny{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id IN ? OR parent_id IN ?", ids, ids).Delete(&model.AdminRoleParent{}).Error; err != nil {
+			return err
+		}
 		// 再批量清理用户角色关联，失败则回滚。
 		if err := tx.Where("role_id IN ?", ids).Delete(&model.AdminUserRole{}).Error; err != nil {
 			return err
 		}
-		return nil
+		// 最后取消共享给这些角色的列表视图。
+		return tx.Where("role_id IN ?", ids).Delete(&model.ListViewRole{}).Error
 	}
 	h.BeforeDeleteBatch = func(tx *gorm.DB, ids []uint) error {
 		var count int64
@@ -211,6 +260,10 @@ func (h *AdminRoleHandler) ModuleConfig() crud.ModuleConfig {
 			crud.AuthRoute("GET", "/permissions", "GetAllPermissions"),
 			crud.Route{Method: "GET", Path: "/:id/permissions", Handler: "GetPermissions", Permission: rolePerms.List},
 			crud.Route{Method: "PUT", Path: "/:id/permissions", Handler: "UpdatePermissions", Permission: "system:admin_role:permission"},
+			crud.Route{Method: "GET", Path: "/:id/denies", Handler: "GetDenies", Permission: rolePerms.List},
+			crud.Route{Method: "PUT", Path: "/:id/denies", Handler: "UpdateDenies", Permission: "system:admin_role:permission"},
+			crud.Route{Method: "GET", Path: "/:id/parents", Handler: "GetParents", Permission: rolePerms.List},
+			crud.Route{Method: "PUT", Path: "/:id/parents", Handler: "UpdateParents", Permission: "system:admin_role:permission"},
 		),
 		Swagger: crud.SwaggerConfig{
 			Model:         model.AdminRole{},
@@ -229,24 +282,31 @@ type (
 		Enabled     *bool  `form:"enabled"`
 	}
 	createRoleReq struct {
-		Name        string   `json:"name" binding:"required"`
-		Description string   `json:"description"`
-		Enabled     *bool    `json:"enabled"`
-		Permissions []string `json:"permissions"`
+		Name              string   `json:"name" binding:"required"`
+		Description       string   `json:"description"`
+		Enabled           *bool    `json:"enabled"`
+		Permissions       []string `json:"permissions"`
+		TwoFactorRequired bool     `json:"two_factor_required"`
+		AllowedIPs        []string `json:"allowed_ips"`
 	}
 	updateRoleReq struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Enabled     *bool  `json:"enabled"`
+		Name              string   `json:"name"`
+		Description       string   `json:"description"`
+		Enabled           *bool    `json:"enabled"`
+		TwoFactorRequired *bool    `json:"two_factor_required"`
+		AllowedIPs        []string `json:"allowed_ips"`
 	}
 	updateRolePermReq struct {
 		Permissions []string `json:"permissions"`
 	}
+	updateRoleParentsReq struct {
+		ParentIDs []uint `json:"parent_ids"`
+	}
 )
 
 // GetPermissions 获取角色权限。
 // @Summary 获取角色权限
-// @Description 获取指定角色已配置的权限 key 列表
+// @Description 获取指定角色已配置的权限 key 列表，可包含 system:* 形式的通配符
 // @Tags 角色管理
 // @Produce json
 // @Security BearerAuth
@@ -270,7 +330,7 @@ func (h *AdminRoleHandler) GetPermissions(c *gin.Context) {
 
 // UpdatePermissions 更新角色权限。
 // @Summary 更新角色权限
-// @Description 覆盖指定角色的权限 key 列表
+// @Description 覆盖指定角色的权限 key 列表，支持以 :* 结尾的通配符，如 system:admin_user:*
 // @Tags 角色管理
 // @Accept json
 // @Produce json
@@ -317,6 +377,162 @@ func (h *AdminRoleHandler) UpdatePermissions(c *gin.Context) {
 	}, "权限配置成功", nil)
 }
 
+// GetDenies 获取角色拒绝权限。
+// @Summary 获取角色拒绝权限
+// @Description 获取指定角色的拒绝权限列表，拒绝项优先于该角色用户从任何角色获得的授权
+// @Tags 角色管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色 ID"
+// @Success 200 {object} adminResponse{data=rolePermissionsResponse}
+// @Router /admin-roles/{id}/denies [get]
+func (h *AdminRoleHandler) GetDenies(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	var denies []string
+	if err := h.DB.Model(&model.AdminRoleDeny{}).Where("role_id = ?", id).Pluck("permission", &denies).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, gin.H{"permissions": denies})
+}
+
+// UpdateDenies 更新角色拒绝权限。
+// @Summary 更新角色拒绝权限
+// @Description 覆盖指定角色的拒绝权限列表，支持以 :* 结尾的通配符
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色 ID"
+// @Param body body updateRolePermReq true "拒绝权限列表"
+// @Success 200 {object} adminResponse
+// @Router /admin-roles/{id}/denies [put]
+func (h *AdminRoleHandler) UpdateDenies(c *gin.Context) {
+	var req updateRolePermReq
+	id, ok := h.bindPolicyUpdate(c, &req)
+	if !ok {
+		return
+	}
+	if req.Permissions == nil {
+		h.Error(c, "permissions 字段不能为空")
+		return
+	}
+	perms, err := normalizeRolePerms(req.Permissions)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.AdminRoleDeny{}).Error; err != nil {
+			return err
+		}
+		if h.cacheInvalidator != nil {
+			h.cacheInvalidator.InvalidateRoleUsersPermissionCache(id)
+		}
+		if len(perms) == 0 {
+			return nil
+		}
+		items := make([]model.AdminRoleDeny, 0, len(perms))
+		for _, p := range perms {
+			items = append(items, model.AdminRoleDeny{RoleID: id, Permission: p})
+		}
+		return tx.CreateInBatches(items, 100).Error
+	}, "拒绝权限配置成功", nil)
+}
+
+// GetParents 获取角色继承的父角色。
+// @Summary 获取继承角色
+// @Description 获取指定角色直接继承的父角色 ID
+// @Tags 角色管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色 ID"
+// @Success 200 {object} adminResponse{data=roleParentsResponse}
+// @Router /admin-roles/{id}/parents [get]
+func (h *AdminRoleHandler) GetParents(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	var parentIDs []uint
+	if err := h.DB.Model(&model.AdminRoleParent{}).Where("role_id = ?", id).Pluck("parent_id", &parentIDs).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, gin.H{"parent_ids": parentIDs})
+}
+
+// UpdateParents 更新角色继承的父角色。
+// @Summary 更新继承角色
+// @Description 覆盖指定角色继承的父角色，角色获得父角色及其祖先的全部授权与拒绝项；不能继承超级管理员或形成循环
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色 ID"
+// @Param body body updateRoleParentsReq true "父角色 ID 列表"
+// @Success 200 {object} adminResponse
+// @Router /admin-roles/{id}/parents [put]
+func (h *AdminRoleHandler) UpdateParents(c *gin.Context) {
+	var req updateRoleParentsReq
+	id, ok := h.bindPolicyUpdate(c, &req)
+	if !ok {
+		return
+	}
+	if req.ParentIDs == nil {
+		h.Error(c, "parent_ids 字段不能为空")
+		return
+	}
+	parentIDs := crud.UniqueUints(req.ParentIDs)
+
+	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+		if err := service.CheckRoleParents(tx, id, parentIDs); err != nil {
+			return err
+		}
+		// 先失效原继承关系下的子角色用户，再替换关系。
+		if h.cacheInvalidator != nil {
+			h.cacheInvalidator.InvalidateRoleUsersPermissionCache(id)
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&model.AdminRoleParent{}).Error; err != nil {
+			return err
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+		items := make([]model.AdminRoleParent, 0, len(parentIDs))
+		for _, parentID := range parentIDs {
+			items = append(items, model.AdminRoleParent{RoleID: id, ParentID: parentID})
+		}
+		return tx.Create(&items).Error
+	}, "继承角色配置成功", nil)
+}
+
+// bindPolicyUpdate 解析角色 ID 与请求体，并拒绝修改超级管理员角色的权限策略。
+func (h *AdminRoleHandler) bindPolicyUpdate(c *gin.Context, req any) (uint, bool) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return 0, false
+	}
+	if err := h.BindJSON(c, req); err != nil {
+		return 0, false
+	}
+	var role model.AdminRole
+	if !h.QueryOne(c, h.DB.Where("id = ?", id), &role, "角色不存在") {
+		return 0, false
+	}
+	if role.Code == model.SuperAdminRoleCode {
+		h.Error(c, "超级管理员角色权限不可修改")
+		return 0, false
+	}
+	return id, true
+}
+
 // GetAllPermissions 获取完整权限树。
 // @Summary 获取完整权限树
 // @Description 获取后台所有菜单和按钮权限
@@ -391,13 +607,53 @@ func (h *AdminRoleHandler) getPermsMap(db *gorm.DB, roleIDs []uint) (map[uint][]
 	return permsMap, nil
 }
 
+// getPolicyMaps 批量读取角色的拒绝权限与父角色。
+func (h *AdminRoleHandler) getPolicyMaps(db *gorm.DB, roleIDs []uint) (map[uint][]string, map[uint][]uint, error) {
+	deniesMap := make(map[uint][]string)
+	parentsMap := make(map[uint][]uint)
+	if len(roleIDs) == 0 {
+		return deniesMap, parentsMap, nil
+	}
+
+	var denies []model.AdminRoleDeny
+	if err := db.Where("role_id IN ?", roleIDs).Find(&denies).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, d := range denies {
+		deniesMap[d.RoleID] = append(deniesMap[d.RoleID], d.Permission)
+	}
+	var parents []model.AdminRoleParent
+	if err := db.Where("role_id IN ?", roleIDs).Find(&parents).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, p := range parents {
+		parentsMap[p.RoleID] = append(parentsMap[p.RoleID], p.ParentID)
+	}
+	return deniesMap, parentsMap, nil
+}
+
 func (h *AdminRoleHandler) savePerms(tx *gorm.DB, roleID uint, perms []string) error {
-	if len(perms) == 0 {
+	dedupPerms, err := normalizeRolePerms(perms)
+	if err != nil {
+		return err
+	}
+	if len(dedupPerms) == 0 {
 		return nil
 	}
 
-	validPerms := make(map[string]struct{})
-	for _, key := range crud.GetAllPermissionKeys() {
+	items := make([]model.AdminRolePermission, 0, len(dedupPerms))
+	for _, p := range dedupPerms {
+		items = append(items, model.AdminRolePermission{RoleID: roleID, Permission: p})
+	}
+	return tx.CreateInBatches(items, 100).Error
+}
+
+// normalizeRolePerms 去重并校验角色授权或拒绝项。
+// 具体权限必须已注册；通配符的前缀下必须存在已注册权限，全局通配符只能通过超级管理员角色授予。
+func normalizeRolePerms(perms []string) ([]string, error) {
+	allKeys := crud.GetAllPermissionKeys()
+	validPerms := make(map[string]struct{}, len(allKeys))
+	for _, key := range allKeys {
 		validPerms[key] = struct{}{}
 	}
 
@@ -407,11 +663,18 @@ func (h *AdminRoleHandler) savePerms(tx *gorm.DB, roleID uint, perms []string) e
 		p := strings.TrimSpace(raw)
 		// 空权限直接拒绝，避免脏数据入库。
 		if p == "" {
-			return errors.New("权限标识不能为空")
+			return nil, errors.New("权限标识不能为空")
+		}
+		if p == permission.Wildcard {
+			return nil, errors.New("不支持单独的通配符 *，全部权限请使用超级管理员角色")
 		}
 		// 非法权限直接拒绝，避免越权配置。
-		if _, ok := validPerms[p]; !ok {
-			return fmt.Errorf("存在无效权限标识: %s", p)
+		if permission.IsWildcard(p) {
+			if !slices.ContainsFunc(allKeys, func(key string) bool { return permission.Match(p, key) }) {
+				return nil, fmt.Errorf("通配符未匹配任何权限: %s", p)
+			}
+		} else if _, ok := validPerms[p]; !ok {
+			return nil, fmt.Errorf("存在无效权限标识: %s", p)
 		}
 		// 重复权限自动去重，降低唯一约束冲突概率。
 		if _, ok := seen[p]; ok {
@@ -420,12 +683,7 @@ func (h *AdminRoleHandler) savePerms(tx *gorm.DB, roleID uint, perms []string) e
 		seen[p] = struct{}{}
 		dedupPerms = append(dedupPerms, p)
 	}
-
-	items := make([]model.AdminRolePermission, 0, len(dedupPerms))
-	for _, p := range dedupPerms {
-		items = append(items, model.AdminRolePermission{RoleID: roleID, Permission: p})
-	}
-	return tx.CreateInBatches(items, 100).Error
+	return dedupPerms, nil
 }
 
 var _ crud.Module = (*AdminRoleHandler)(nil)
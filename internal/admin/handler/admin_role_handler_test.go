@@ -1,8 +1,22 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
 )
 
 // TestGenerateRoleCode 验证内部角色标识格式稳定且不会复用。
@@ -26,3 +40,92 @@ func TestGenerateRoleCode(t *testing.T) {
 		t.Fatalf("角色标识发生重复: %s", first)
 	}
 }
+
+// TestRolePolicyEndpoints 验证角色继承、拒绝项与通配符授权的配置接口及其对用户权限的影响。
+func TestRolePolicyEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// 角色变更在事务内失效缓存时会使用事务外的连接查询继承关系，各连接需共享同一个内存库。
+	db, err := gorm.Open(sqlite.Open("file:role_policy?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.ListViewRole{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	authSvc := service.NewAuthService(db, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewAdminRoleHandler(db, authSvc))
+
+	base := model.AdminRole{Name: "基础", Code: "role_base", Enabled: true}
+	child := model.AdminRole{Name: "审计", Code: "role_child", Enabled: true}
+	super := model.AdminRole{Name: "超级管理员", Code: model.SuperAdminRoleCode, Enabled: true}
+	db.Create(&base)
+	db.Create(&child)
+	db.Create(&super)
+	user := model.AdminUser{Username: "auditor", Password: "x", Enabled: true}
+	db.Create(&user)
+	db.Create(&model.AdminUserRole{UserID: user.ID, RoleID: child.ID})
+	allows := func(perm string) bool {
+		t.Helper()
+		set, err := authSvc.GetPermissionSet(user.ID)
+		if err != nil {
+			t.Fatalf("获取权限集合失败: %v", err)
+		}
+		return set.Allows(perm)
+	}
+
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-roles/%d/permissions", base.ID), `{"permissions": ["system:admin_role:*"]}`)
+	if allows("system:admin_role:list") {
+		t.Fatal("未继承前不应拥有基础角色的权限")
+	}
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-roles/%d/parents", child.ID), fmt.Sprintf(`{"parent_ids": [%d]}`, base.ID))
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-roles/%d/denies", child.ID), `{"permissions": ["system:admin_role:delete"]}`)
+	if !allows("system:admin_role:list") || allows("system:admin_role:delete") {
+		t.Fatal("应继承父角色的通配符授权，且拒绝项优先")
+	}
+	perms, _ := authSvc.GetUserPermissions(user.ID)
+	if !slices.Contains(perms, "system:admin_role:permission") || slices.Contains(perms, "system:admin_role:delete") {
+		t.Fatalf("展开后的权限错误: %v", perms)
+	}
+
+	detail := doJSON(t, engine, http.MethodGet, fmt.Sprintf("/admin-roles/%d", child.ID), "")["data"].(map[string]interface{})
+	if fmt.Sprint(detail["parent_ids"]) != fmt.Sprint([]interface{}{float64(base.ID)}) || fmt.Sprint(detail["denies"]) != "[system:admin_role:delete]" {
+		t.Fatalf("角色详情应包含继承与拒绝项: %v", detail)
+	}
+
+	rejected := []struct{ path, body string }{
+		{fmt.Sprintf("/admin-roles/%d/parents", base.ID), fmt.Sprintf(`{"parent_ids": [%d]}`, child.ID)},
+		{fmt.Sprintf("/admin-roles/%d/parents", base.ID), fmt.Sprintf(`{"parent_ids": [%d]}`, base.ID)},
+		{fmt.Sprintf("/admin-roles/%d/parents", base.ID), fmt.Sprintf(`{"parent_ids": [%d]}`, super.ID)},
+		{fmt.Sprintf("/admin-roles/%d/parents", super.ID), `{"parent_ids": []}`},
+		{fmt.Sprintf("/admin-roles/%d/permissions", base.ID), `{"permissions": ["*"]}`},
+		{fmt.Sprintf("/admin-roles/%d/denies", base.ID), `{"permissions": ["unknown:*"]}`},
+	}
+	for _, tc := range rejected {
+		if resp := requestJSON(engine, http.MethodPut, tc.path, tc.body); resp["code"] == float64(0) {
+			t.Fatalf("PUT %s %s 应被拒绝", tc.path, tc.body)
+		}
+	}
+
+	// 修改父角色后继承它的子角色用户缓存同样失效。
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-roles/%d/permissions", base.ID), `{"permissions": ["system:admin_role:list"]}`)
+	if allows("system:admin_role:update") || !allows("system:admin_role:list") {
+		t.Fatal("父角色权限变更后子角色用户应立即生效")
+	}
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-roles/%d", base.ID), `{"enabled": false}`)
+	if allows("system:admin_role:list") {
+		t.Fatal("停用的父角色不应再授予权限")
+	}
+
+	doJSON(t, engine, http.MethodDelete, fmt.Sprintf("/admin-roles/%d", base.ID), "")
+	var remaining int64
+	db.Model(&model.AdminRoleParent{}).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("删除角色应清理继承关系，剩余 %d", remaining)
+	}
+}
@@ -5,24 +5,34 @@ import (
 	"bico-admin/internal/admin/service"
 	"bico-admin/internal/pkg/crud"
 	"bico-admin/internal/pkg/password"
+	"bico-admin/internal/pkg/response"
+	"encoding/json"
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // 权限定义
-var userPerms = crud.NewCRUDPerms("system", "admin_user", "用户管理")
+var userPerms = crud.NewCRUDPerms("system", "admin_user", "用户管理").WithExtra(
+	crud.Permission{Key: "system:admin_user:reset_2fa", Label: "重置两步验证"},
+	crud.Permission{Key: "system:admin_user:session", Label: "会话管理"},
+	crud.Permission{Key: "system:admin_user:api_token", Label: "访问令牌管理"},
+)
 
 // AdminUserHandler 用户管理处理器
 type AdminUserHandler struct {
 	crud.CRUDHandler[model.AdminUser, userListReq, createUserReq, updateUserReq]
 	cacheInvalidator service.AuthCacheInvalidator
+	impersonator     service.Impersonator
 }
 
-func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalidator) *AdminUserHandler {
-	h := &AdminUserHandler{cacheInvalidator: cacheInvalidator}
+func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalidator, impersonator service.Impersonator) *AdminUserHandler {
+	h := &AdminUserHandler{cacheInvalidator: cacheInvalidator, impersonator: impersonator}
 	h.DB = db
 	h.NotFoundMsg = "用户不存在"
 
@@ -45,12 +55,37 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 				roleIDs,
 			)
 		}
+		if req.DepartmentID != 0 {
+			departmentIDs := []uint{req.DepartmentID}
+			if req.IncludeChildren == nil || *req.IncludeChildren {
+				descendants, err := service.DepartmentDescendants(db, departmentIDs)
+				if err != nil {
+					query.AddError(err)
+					return query
+				}
+				departmentIDs = descendants
+			}
+			query = query.Where(
+				"EXISTS (SELECT 1 FROM admin_user_departments WHERE admin_user_departments.user_id = admin_users.id AND admin_user_departments.department_id IN ?)",
+				departmentIDs,
+			)
+		}
 		return query
 	}
+	h.AfterList = func(items []model.AdminUser) error {
+		users := make([]*model.AdminUser, 0, len(items))
+		for i := range items {
+			users = append(users, &items[i])
+		}
+		return fillUserDetails(h.DB, users...)
+	}
 
 	h.BuildGetQuery = func(db *gorm.DB) *gorm.DB {
 		return db.Model(&model.AdminUser{}).Preload("Roles")
 	}
+	h.AfterGet = func(item *model.AdminUser) error {
+		return fillUserDetails(h.DB, item)
+	}
 
 	h.NewModelFromCreate = func(req *createUserReq) (*model.AdminUser, error) {
 		username := strings.TrimSpace(req.Username)
@@ -67,26 +102,44 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 			return nil, service.ErrUsernameExists
 		}
 
+		if err := service.CheckNewPassword(db, &model.AdminUser{Username: username}, req.Password); err != nil {
+			return nil, err
+		}
 		hashed, err := password.Hash(req.Password)
 		if err != nil {
 			return nil, err
 		}
+		allowedIPs, err := service.NormalizeAllowedIPs(req.AllowedIPs)
+		if err != nil {
+			return nil, err
+		}
 
+		now := time.Now()
 		return &model.AdminUser{
-			Username: username,
-			Password: hashed,
-			Name:     req.Name,
-			Avatar:   req.Avatar,
-			Enabled:  req.Enabled == nil || *req.Enabled,
+			Username:           username,
+			Password:           hashed,
+			Name:               req.Name,
+			Email:              service.NormalizeEmail(req.Email),
+			Avatar:             req.Avatar,
+			Enabled:            req.Enabled == nil || *req.Enabled,
+			MustChangePassword: req.MustChangePassword,
+			PasswordChangedAt:  &now,
+			AllowedIPs:         allowedIPs,
 		}, nil
 	}
 
 	h.CreateInTx = func(tx *gorm.DB, item *model.AdminUser, req *createUserReq) error {
+		if err := service.SyncUserDepartments(tx, item.ID, req.DepartmentIDs, req.PrimaryDepartmentID); err != nil {
+			return err
+		}
 		return h.syncRoles(tx, item, req.RoleIDs)
 	}
-	// 需要返回带 Roles 的用户数据
+	// 需要返回带 Roles 与所属部门的用户数据
 	h.ReloadAfterCreate = func(tx *gorm.DB, id uint, item *model.AdminUser) error {
-		return tx.Preload("Roles").First(item, item.ID).Error
+		if err := tx.Preload("Roles").First(item, item.ID).Error; err != nil {
+			return err
+		}
+		return fillUserDetails(tx, item)
 	}
 
 	h.BuildUpdateQuery = func(tx *gorm.DB) *gorm.DB {
@@ -123,23 +176,57 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if req.Name != "" {
 			updates["name"] = req.Name
 		}
+		if req.Email != nil {
+			email := service.NormalizeEmail(*req.Email)
+			if email != "" && !service.ValidEmail(email) {
+				return nil, service.ErrEmailInvalid
+			}
+			updates["email"] = email
+		}
 		if req.Avatar != "" {
 			updates["avatar"] = req.Avatar
 		}
 		if req.Password != "" {
+			if err := service.CheckNewPassword(db, existing, req.Password); err != nil {
+				return nil, err
+			}
 			hashed, err := password.Hash(req.Password)
 			if err != nil {
 				return nil, err
 			}
 			updates["password"] = hashed
+			updates["password_changed_at"] = time.Now()
 			updates["token_version"] = gorm.Expr("token_version + 1")
 		}
+		if req.MustChangePassword != nil {
+			updates["must_change_password"] = *req.MustChangePassword
+		}
 		if req.Enabled != nil {
 			updates["enabled"] = *req.Enabled
 		}
 		return updates, nil
 	}
+	// 旧密码需在更新前写入历史。
+	h.BeforeUpdate = func(tx *gorm.DB, id uint, existing *model.AdminUser, req *updateUserReq, updates map[string]interface{}) error {
+		if _, ok := updates["password"]; !ok {
+			return nil
+		}
+		return service.RecordPasswordHistory(tx, id)
+	}
 	h.UpdateInTx = func(tx *gorm.DB, id uint, existing *model.AdminUser, req *updateUserReq) error {
+		if req.AllowedIPs != nil {
+			allowedIPs, err := updateAllowedIPs(tx, &model.AdminUser{}, id, req.AllowedIPs)
+			if err != nil {
+				return err
+			}
+			existing.AllowedIPs = allowedIPs
+			if h.cacheInvalidator != nil {
+				h.cacheInvalidator.InvalidateUserPermissionCache(existing.ID)
+			}
+		}
+		if err := h.updateDepartments(tx, id, req); err != nil {
+			return err
+		}
 		// 角色发生变更时同步角色关联并失效权限缓存。
 		if req.RoleIDs == nil {
 			// 仅更新启用状态时失效状态缓存。
@@ -165,7 +252,10 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		}
 	}
 	h.ReloadAfterUpdate = func(tx *gorm.DB, id uint, existing *model.AdminUser) error {
-		return tx.Preload("Roles").First(existing, id).Error
+		if err := tx.Preload("Roles").First(existing, id).Error; err != nil {
+			return err
+		}
+		return fillUserDetails(tx, existing)
 	}
 
 	h.BeforeDeleteBatch = func(tx *gorm.DB, ids []uint) error {
@@ -183,6 +273,12 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if err := tx.Model(&user).Association("Roles").Clear(); err != nil {
 			return err
 		}
+		if err := deleteUserAuthRecords(tx, []uint{user.ID}); err != nil {
+			return err
+		}
+		if err := service.RemoveUsersFromDepartments(tx, []uint{user.ID}); err != nil {
+			return err
+		}
 		if h.cacheInvalidator != nil {
 			h.cacheInvalidator.InvalidateUserPermissionCache(user.ID)
 			h.cacheInvalidator.InvalidateUserStatusCache(user.ID)
@@ -194,6 +290,12 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 		if err := tx.Where("user_id IN ?", ids).Delete(&model.AdminUserRole{}).Error; err != nil {
 			return err
 		}
+		if err := deleteUserAuthRecords(tx, ids); err != nil {
+			return err
+		}
+		if err := service.RemoveUsersFromDepartments(tx, ids); err != nil {
+			return err
+		}
 		// 删除用户后批量失效权限与状态缓存。
 		if h.cacheInvalidator != nil {
 			h.cacheInvalidator.InvalidateUsersAuthCache(ids)
@@ -204,6 +306,48 @@ func NewAdminUserHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalida
 	return h
 }
 
+// fillUserDetails 填充用户的所属部门与限时角色授权。
+func fillUserDetails(db *gorm.DB, users ...*model.AdminUser) error {
+	if err := service.FillUserDepartments(db, users...); err != nil {
+		return err
+	}
+	return service.FillUserRoleGrants(db, users...)
+}
+
+// deleteUserAuthRecords 删除用户的会话、刷新令牌、访问令牌、通行密钥与外部身份绑定，用户删除后其令牌已无法通过校验。
+func deleteUserAuthRecords(tx *gorm.DB, userIDs []uint) error {
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&model.AdminSession{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&model.AdminAPIToken{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&model.AdminUserIdentity{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&model.AdminPasskey{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id IN ?", userIDs).Delete(&model.AdminRefreshToken{}).Error
+}
+
+// updateAllowedIPs 校验并保存用户或角色的来源 IP 限制。
+// 按 map 更新字段时不经过 JSON 序列化器，因此这里自行序列化，格式与序列化器写入的一致。
+func updateAllowedIPs(tx *gorm.DB, record interface{}, id uint, items []string) ([]string, error) {
+	allowedIPs, err := service.NormalizeAllowedIPs(items)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(allowedIPs)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(record).Where("id = ?", id).UpdateColumn("allowed_ips", string(value)).Error; err != nil {
+		return nil, err
+	}
+	return allowedIPs, nil
+}
+
 // parseUserRoleIDs 解析用户列表角色筛选参数，忽略非法片段以保持筛选接口容错。
 func parseUserRoleIDs(value string) []uint {
 	if value == "" {
@@ -235,7 +379,21 @@ func (h *AdminUserHandler) ModuleConfig() crud.ModuleConfig {
 		Description:      "用户管理",
 		ParentPermission: PermSystemManage,
 		Permissions:      userPerms.Tree,
-		Routes:           userPerms.Routes(),
+		Routes: userPerms.RoutesWithExtra(
+			crud.Route{Method: "PUT", Path: "/:id/2fa/reset", Handler: "ResetTwoFactor", Permission: "system:admin_user:reset_2fa"},
+			crud.Route{Method: "GET", Path: "/:id/sessions", Handler: "Sessions", Permission: "system:admin_user:session"},
+			crud.Route{Method: "DELETE", Path: "/:id/sessions", Handler: "RevokeSessions", Permission: "system:admin_user:session"},
+			crud.Route{Method: "DELETE", Path: "/:id/sessions/:sid", Handler: "RevokeSession", Permission: "system:admin_user:session"},
+			crud.Route{Method: "GET", Path: "/:id/api-tokens", Handler: "APITokens", Permission: "system:admin_user:api_token"},
+			crud.Route{Method: "POST", Path: "/:id/api-tokens", Handler: "CreateAPIToken", Permission: "system:admin_user:api_token"},
+			crud.Route{Method: "DELETE", Path: "/:id/api-tokens/:tid", Handler: "RevokeAPIToken", Permission: "system:admin_user:api_token"},
+			crud.Route{Method: "GET", Path: "/role-grants/expiring", Handler: "ExpiringRoleGrants", Permission: userPerms.List},
+			crud.Route{Method: "GET", Path: "/:id/role-grants", Handler: "RoleGrants", Permission: userPerms.List},
+			crud.Route{Method: "POST", Path: "/:id/role-grants", Handler: "SaveRoleGrant", Permission: userPerms.Edit},
+			crud.Route{Method: "DELETE", Path: "/:id/role-grants/:gid", Handler: "RevokeRoleGrant", Permission: userPerms.Edit},
+			// 仅超级管理员可用，由认证服务校验，不单独分配权限。
+			crud.Route{Method: "POST", Path: "/:id/impersonate", Handler: "Impersonate"},
+		),
 		Swagger: crud.SwaggerConfig{
 			Model:         model.AdminUser{},
 			ListRequest:   userListReq{},
@@ -246,56 +404,487 @@ func (h *AdminUserHandler) ModuleConfig() crud.ModuleConfig {
 }
 
 // 请求结构
+//
+// must_change_password 要求用户下次登录时修改密码，管理员重置密码时通常一并设置。
+// 更新时 email 传空字符串表示清除邮箱，allowed_ips 传空数组表示取消来源 IP 限制，department_ids 传空数组表示移出全部部门。
+// primary_department_id 须为 department_ids 之一，未指定时以第一个部门为主部门。
+// 列表按 department_id 筛选时默认包含下级部门成员，include_children=false 时只筛选该部门。
 type (
 	userListReq struct {
-		Username string `form:"username"`
-		Name     string `form:"name"`
-		Enabled  *bool  `form:"enabled"`
-		RoleIDs  string `form:"role_ids"`
+		Username        string `form:"username"`
+		Name            string `form:"name"`
+		Enabled         *bool  `form:"enabled"`
+		RoleIDs         string `form:"role_ids"`
+		DepartmentID    uint   `form:"department_id"`
+		IncludeChildren *bool  `form:"include_children"`
 	}
 	createUserReq struct {
-		Username string `json:"username" binding:"required,max=64"`
-		Password string `json:"password" binding:"required,min=8"`
-		Name     string `json:"name"`
-		Avatar   string `json:"avatar"`
-		Enabled  *bool  `json:"enabled"`
-		RoleIDs  []uint `json:"role_ids"`
+		Username            string   `json:"username" binding:"required,max=64"`
+		Password            string   `json:"password" binding:"required,min=8"`
+		Name                string   `json:"name"`
+		Email               string   `json:"email" binding:"omitempty,email,max=128"`
+		Avatar              string   `json:"avatar"`
+		Enabled             *bool    `json:"enabled"`
+		RoleIDs             []uint   `json:"role_ids"`
+		MustChangePassword  bool     `json:"must_change_password"`
+		AllowedIPs          []string `json:"allowed_ips"`
+		DepartmentIDs       []uint   `json:"department_ids"`
+		PrimaryDepartmentID uint     `json:"primary_department_id"`
 	}
 	updateUserReq struct {
-		Username *string `json:"username" binding:"omitempty,max=64"`
-		Name     string  `json:"name"`
-		Avatar   string  `json:"avatar"`
-		Enabled  *bool   `json:"enabled"`
-		RoleIDs  []uint  `json:"role_ids"`
-		Password string  `json:"password" binding:"omitempty,min=8"`
+		Username            *string  `json:"username" binding:"omitempty,max=64"`
+		Name                string   `json:"name"`
+		Email               *string  `json:"email" binding:"omitempty,max=128"`
+		Avatar              string   `json:"avatar"`
+		Enabled             *bool    `json:"enabled"`
+		RoleIDs             []uint   `json:"role_ids"`
+		Password            string   `json:"password" binding:"omitempty,min=8"`
+		MustChangePassword  *bool    `json:"must_change_password"`
+		AllowedIPs          []string `json:"allowed_ips"`
+		DepartmentIDs       []uint   `json:"department_ids"`
+		PrimaryDepartmentID *uint    `json:"primary_department_id"`
+	}
+	expiringRoleGrantsReq struct {
+		Days int `form:"days" binding:"omitempty,min=1,max=90"`
 	}
 )
 
-func (h *AdminUserHandler) syncRoles(tx *gorm.DB, user *model.AdminUser, roleIDs []uint) error {
-	if err := h.ensureRoleChangeKeepsSuperAdmin(tx, user.ID, roleIDs); err != nil {
-		return err
+// ResetTwoFactor 重置用户两步验证。
+// @Summary 重置两步验证
+// @Description 清除指定用户的两步验证绑定、恢复码与通行密钥，用于用户丢失认证设备的场景
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/2fa/reset [put]
+func (h *AdminUserHandler) ResetTwoFactor(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
 	}
-	// 先清空旧关联，确保写入结果与请求保持一致。
-	if err := tx.Model(user).Association("Roles").Clear(); err != nil {
-		return err
+
+	var user model.AdminUser
+	if !h.QueryOne(c, h.DB.Select("id").Where("id = ?", id), &user, "用户不存在") {
+		return
+	}
+
+	if err := service.ResetSecondFactors(h.DB, user.ID); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "两步验证已重置", nil)
+}
+
+// Sessions 获取用户登录会话。
+// @Summary 获取用户登录会话
+// @Description 获取指定用户未过期的登录会话
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse{data=[]sessionDocItem}
+// @Router /admin-users/{id}/sessions [get]
+func (h *AdminUserHandler) Sessions(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	sessions, err := service.ListUserSessions(h.DB, id)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, sessions)
+}
+
+// RevokeSession 下线用户指定会话。
+// @Summary 下线用户会话
+// @Description 下线指定用户的某个登录会话，该会话的令牌立即失效
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Param sid path int true "会话 ID"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/sessions/{sid} [delete]
+func (h *AdminUserHandler) RevokeSession(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	var session model.AdminSession
+	if !h.QueryOne(c, h.DB.Where("id = ? AND user_id = ?", c.Param("sid"), id), &session, service.ErrSessionNotFound.Error()) {
+		return
+	}
+	h.revokeFamilies(session.FamilyID)
+	h.SuccessWithMessage(c, "会话已下线", nil)
+}
+
+// RevokeSessions 下线用户全部会话。
+// @Summary 下线用户全部会话
+// @Description 下线指定用户的全部登录会话，比修改密码更细粒度，不影响用户凭据
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/sessions [delete]
+func (h *AdminUserHandler) RevokeSessions(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	var families []string
+	if err := h.DB.Model(&model.AdminSession{}).Where("user_id = ?", id).Pluck("family_id", &families).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.revokeFamilies(families...)
+	h.SuccessWithMessage(c, "会话已全部下线", nil)
+}
+
+// APITokens 获取用户访问令牌。
+// @Summary 获取用户访问令牌
+// @Description 获取指定用户的个人访问令牌，不返回令牌明文
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse{data=[]apiTokenDocItem}
+// @Router /admin-users/{id}/api-tokens [get]
+func (h *AdminUserHandler) APITokens(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	tokens, err := service.ListUserAPITokens(h.DB, id)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
 	}
-	if len(roleIDs) == 0 {
+	h.Success(c, tokens)
+}
+
+// CreateAPIToken 为用户创建访问令牌。
+// @Summary 为用户创建访问令牌
+// @Description 为指定用户（如 CI 专用账号）创建个人访问令牌，明文令牌仅在创建时返回一次；非超级管理员不能为超级管理员或拥有自己所没有权限的用户创建
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Param body body service.CreateAPITokenRequest true "令牌参数"
+// @Success 200 {object} adminResponse{data=apiTokenCreatedDoc}
+// @Router /admin-users/{id}/api-tokens [post]
+func (h *AdminUserHandler) CreateAPIToken(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	if c.GetBool("api_token") {
+		response.ErrorWithCode(c, 403, "访问令牌不能创建新的访问令牌")
+		return
+	}
+	if c.GetUint("impersonator_id") != 0 {
+		response.ErrorWithCode(c, 403, service.ErrImpersonationActive.Error())
+		return
+	}
+
+	var req service.CreateAPITokenRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	var user model.AdminUser
+	if !h.QueryOne(c, h.DB.Select("id").Where("id = ?", id), &user, "用户不存在") {
+		return
+	}
+
+	operatorID := c.GetUint("user_id")
+	if err := service.CheckAPITokenTarget(h.DB, operatorID, user.ID); err != nil {
+		if errors.Is(err, service.ErrAPITokenTarget) {
+			response.ErrorWithCode(c, 403, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	created, err := service.CreateUserAPIToken(h.DB, user.ID, operatorID, &req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "访问令牌已创建，请立即复制保存", created)
+}
+
+// RevokeAPIToken 吊销用户访问令牌。
+// @Summary 吊销用户访问令牌
+// @Description 吊销指定用户的某个访问令牌，令牌立即失效
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Param tid path int true "令牌 ID"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/api-tokens/{tid} [delete]
+func (h *AdminUserHandler) RevokeAPIToken(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	tokenID, err := strconv.ParseUint(c.Param("tid"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "无效的令牌 ID")
+		return
+	}
+
+	if err := service.RevokeUserAPIToken(h.DB, id, uint(tokenID)); err != nil {
+		if errors.Is(err, service.ErrAPITokenNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "访问令牌已吊销", nil)
+}
+
+// RoleGrants 获取用户角色授权。
+// @Summary 获取用户角色授权
+// @Description 获取指定用户的全部角色授权及其有效期、原因，含尚未生效的授权
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse{data=[]service.RoleGrantItem}
+// @Router /admin-users/{id}/role-grants [get]
+func (h *AdminUserHandler) RoleGrants(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	grants, err := service.ListUserRoleGrants(h.DB, id)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, grants)
+}
+
+// SaveRoleGrant 授予用户角色。
+// @Summary 授予用户角色
+// @Description 授予指定用户角色，可设置生效与到期时间及原因；用户已有该角色时覆盖其有效期与原因。到期后授权由定时任务删除
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Param body body service.RoleGrantRequest true "授权参数"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/role-grants [post]
+func (h *AdminUserHandler) SaveRoleGrant(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	var req service.RoleGrantRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	var user model.AdminUser
+	if !h.QueryOne(c, h.DB.Select("id").Where("id = ?", id), &user, "用户不存在") {
+		return
+	}
+
+	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+		if req.ValidFrom != nil || req.ValidUntil != nil {
+			// 超级管理员授权改为限时后可能到期，需保证仍有其他永久的超级管理员。
+			if err := h.ensureGrantKeepsSuperAdmin(tx, user.ID, req.RoleID); err != nil {
+				return err
+			}
+		}
+		if _, err := service.SaveRoleGrant(tx, user.ID, &req, c.GetUint("user_id")); err != nil {
+			return err
+		}
+		if h.cacheInvalidator != nil {
+			h.cacheInvalidator.InvalidateUserPermissionCache(user.ID)
+		}
 		return nil
+	}, "角色授权已保存", nil)
+}
+
+// RevokeRoleGrant 撤销用户角色授权。
+// @Summary 撤销用户角色授权
+// @Description 撤销指定用户的某个角色授权，立即生效
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Param gid path int true "授权 ID"
+// @Success 200 {object} adminResponse
+// @Router /admin-users/{id}/role-grants/{gid} [delete]
+func (h *AdminUserHandler) RevokeRoleGrant(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	var grant model.AdminUserRole
+	if !h.QueryOne(c, h.DB.Where("id = ? AND user_id = ?", c.Param("gid"), id), &grant, service.ErrRoleGrantNotFound.Error()) {
+		return
 	}
 
-	uniqueRoleIDs := crud.UniqueUints(roleIDs)
-	var roles []*model.AdminRole
-	if err := tx.Where("id IN ?", uniqueRoleIDs).Find(&roles).Error; err != nil {
+	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+		if err := h.ensureGrantKeepsSuperAdmin(tx, grant.UserID, grant.RoleID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&grant).Error; err != nil {
+			return err
+		}
+		if h.cacheInvalidator != nil {
+			h.cacheInvalidator.InvalidateUserPermissionCache(grant.UserID)
+		}
+		return nil
+	}, "角色授权已撤销", nil)
+}
+
+// ExpiringRoleGrants 获取即将到期的角色授权。
+// @Summary 获取即将到期的角色授权
+// @Description 获取指定天数内将要到期的限时角色授权，按到期时间升序
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "天数，默认 7，最大 90"
+// @Success 200 {object} adminResponse{data=[]service.RoleGrantItem}
+// @Router /admin-users/role-grants/expiring [get]
+func (h *AdminUserHandler) ExpiringRoleGrants(c *gin.Context) {
+	var req expiringRoleGrantsReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+	if req.Days == 0 {
+		req.Days = 7
+	}
+
+	grants, err := service.ListExpiringRoleGrants(h.DB, time.Duration(req.Days)*24*time.Hour)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, grants)
+}
+
+// Impersonate 模拟用户登录。
+// @Summary 模拟用户登录
+// @Description 超级管理员以指定用户身份登录，用于排查该用户看到的页面与权限；令牌同时携带操作者身份，最长有效 1 小时，期间不能修改密码、两步验证等安全设置，也不能再次发起模拟
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户 ID"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /admin-users/{id}/impersonate [post]
+func (h *AdminUserHandler) Impersonate(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	if c.GetBool("api_token") || c.GetUint("impersonator_id") != 0 {
+		// 模拟只能由交互登录的会话发起，不能嵌套。
+		response.ErrorWithCode(c, 403, "当前登录方式不能发起模拟登录")
+		return
+	}
+	if h.impersonator == nil {
+		h.Error(c, "模拟登录未启用")
+		return
+	}
+
+	resp, err := h.impersonator.Impersonate(c.GetUint("user_id"), id, c.GetString("token_family"), clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrImpersonationForbidden) {
+			response.ErrorWithCode(c, 403, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "已切换为该用户身份", resp)
+}
+
+// revokeFamilies 吊销会话对应的令牌族。
+func (h *AdminUserHandler) revokeFamilies(families ...string) {
+	if h.cacheInvalidator == nil {
+		return
+	}
+	for _, family := range families {
+		h.cacheInvalidator.RevokeTokenFamily(family)
+	}
+}
+
+// updateDepartments 按更新请求调整所属部门；只提交 primary_department_id 时在现有部门中切换主部门。
+func (h *AdminUserHandler) updateDepartments(tx *gorm.DB, userID uint, req *updateUserReq) error {
+	var primaryID uint
+	if req.PrimaryDepartmentID != nil {
+		primaryID = *req.PrimaryDepartmentID
+	}
+	departmentIDs := req.DepartmentIDs
+	if departmentIDs == nil {
+		if req.PrimaryDepartmentID == nil {
+			return nil
+		}
+		if err := tx.Model(&model.AdminUserDepartment{}).Where("user_id = ?", userID).Order("id").Pluck("department_id", &departmentIDs).Error; err != nil {
+			return err
+		}
+	}
+	return service.SyncUserDepartments(tx, userID, departmentIDs, primaryID)
+}
+
+// syncRoles 按 roleIDs 调整用户角色：保留仍在列表中的授权及其有效期，删除移除的角色，新增的角色为永久授权。
+func (h *AdminUserHandler) syncRoles(tx *gorm.DB, user *model.AdminUser, roleIDs []uint) error {
+	if err := h.ensureRoleChangeKeepsSuperAdmin(tx, user.ID, roleIDs); err != nil {
 		return err
 	}
-	// 如果查询数量不一致，说明请求中存在无效角色 ID。
-	if len(roles) != len(uniqueRoleIDs) {
-		return errors.New("存在无效角色 ID")
+	uniqueRoleIDs := crud.UniqueUints(roleIDs)
+	if len(uniqueRoleIDs) > 0 {
+		var count int64
+		if err := tx.Model(&model.AdminRole{}).Where("id IN ?", uniqueRoleIDs).Count(&count).Error; err != nil {
+			return err
+		}
+		// 如果查询数量不一致，说明请求中存在无效角色 ID。
+		if int(count) != len(uniqueRoleIDs) {
+			return errors.New("存在无效角色 ID")
+		}
 	}
-	if err := tx.Model(user).Association("Roles").Append(roles); err != nil {
+
+	var currentIDs []uint
+	if err := tx.Model(&model.AdminUserRole{}).Where("user_id = ?", user.ID).Pluck("role_id", &currentIDs).Error; err != nil {
 		return err
 	}
-	return nil
+	var removeIDs []uint
+	for _, id := range currentIDs {
+		if !slices.Contains(uniqueRoleIDs, id) {
+			removeIDs = append(removeIDs, id)
+		}
+	}
+	if len(removeIDs) > 0 {
+		if err := tx.Where("user_id = ? AND role_id IN ?", user.ID, removeIDs).Delete(&model.AdminUserRole{}).Error; err != nil {
+			return err
+		}
+	}
+	records := make([]model.AdminUserRole, 0, len(uniqueRoleIDs))
+	for _, id := range uniqueRoleIDs {
+		if !slices.Contains(currentIDs, id) {
+			records = append(records, model.AdminUserRole{UserID: user.ID, RoleID: id})
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.Create(&records).Error
 }
 
 // ensureRoleChangeKeepsSuperAdmin 校验角色调整不会移除最后一个超级管理员。
@@ -327,6 +916,15 @@ func (h *AdminUserHandler) ensureRoleChangeKeepsSuperAdmin(db *gorm.DB, userID u
 	return h.ensureSuperAdminRemains(db, userID)
 }
 
+// ensureGrantKeepsSuperAdmin 调整超级管理员角色的授权前，确保目标用户之外仍有启用的超级管理员。
+func (h *AdminUserHandler) ensureGrantKeepsSuperAdmin(db *gorm.DB, userID, roleID uint) error {
+	isSuper, err := crud.Exists(db, &model.AdminRole{}, "id = ? AND code = ?", roleID, model.SuperAdminRoleCode)
+	if err != nil || !isSuper {
+		return err
+	}
+	return h.ensureSuperAdminRemains(db, userID)
+}
+
 // ensureSuperAdminRemains 确保目标用户之外仍有启用的超级管理员。
 func (h *AdminUserHandler) ensureSuperAdminRemains(db *gorm.DB, userID uint) error {
 	return h.ensureSuperAdminsRemain(db, []uint{userID})
@@ -346,11 +944,14 @@ func (h *AdminUserHandler) ensureSuperAdminsRemain(db *gorm.DB, excludedUserIDs
 		return nil
 	}
 
+	// 限时授权终将到期，只统计当前生效且不限结束时间的授权。
 	var remaining int64
 	if err := db.Table("admin_user_roles").
 		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
 		Joins("JOIN admin_users ON admin_user_roles.user_id = admin_users.id").
 		Where("admin_roles.code = ? AND admin_roles.enabled = ? AND admin_users.enabled = ? AND admin_users.id NOT IN ?", model.SuperAdminRoleCode, true, true, excludedUserIDs).
+		Where("admin_user_roles.valid_until IS NULL").
+		Scopes(service.ActiveUserRoles(time.Now())).
 		Count(&remaining).Error; err != nil {
 		return err
 	}
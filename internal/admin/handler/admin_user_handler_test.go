@@ -2,11 +2,16 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"bico-admin/internal/admin/model"
 	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
 
+	"github.com/gin-gonic/gin"
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
@@ -32,7 +37,7 @@ func TestAdminUserUsernameUpdate(t *testing.T) {
 		t.Fatalf("创建测试用户失败: %v", err)
 	}
 
-	handler := NewAdminUserHandler(database, nil)
+	handler := NewAdminUserHandler(database, nil, nil)
 	newUsername := "  renamed-user  "
 	updates, err := handler.BuildUpdates(&updateUserReq{Username: &newUsername}, &users[0])
 	if err != nil {
@@ -51,3 +56,77 @@ func TestAdminUserUsernameUpdate(t *testing.T) {
 		t.Fatalf("期望用户名已存在错误，实际为: %v", err)
 	}
 }
+
+// TestAdminUserRoleGrants 验证限时角色授权的授予、编辑用户时保留有效期、即将到期列表与超级管理员保护。
+func TestAdminUserRoleGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminPasswordHistory{},
+		&model.AdminDepartment{}, &model.AdminUserDepartment{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	roles := []model.AdminRole{
+		{Name: "超级管理员", Code: model.SuperAdminRoleCode, Enabled: true},
+		{Name: "运营", Code: "ops", Enabled: true},
+	}
+	db.Create(&roles)
+	super, ops := roles[0].ID, roles[1].ID
+	users := []model.AdminUser{{Username: "root", Password: "x", Enabled: true}, {Username: "temp", Password: "x", Enabled: true}}
+	db.Create(&users)
+	root, temp := users[0].ID, users[1].ID
+	db.Create(&model.AdminUserRole{UserID: root, RoleID: super})
+
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewAdminUserHandler(db, nil, nil))
+
+	until := time.Now().Add(72 * time.Hour).Format(time.RFC3339)
+	doJSON(t, engine, http.MethodPost, fmt.Sprintf("/admin-users/%d/role-grants", temp),
+		fmt.Sprintf(`{"role_id": %d, "valid_until": %q, "reason": "临时排障"}`, super, until))
+	if resp := requestJSON(engine, http.MethodPost, fmt.Sprintf("/admin-users/%d/role-grants", temp),
+		fmt.Sprintf(`{"role_id": %d, "valid_until": "2000-01-01T00:00:00Z"}`, ops)); resp["code"] == float64(0) {
+		t.Fatal("已过去的结束时间应被拒绝")
+	}
+
+	// 编辑用户角色时，已有授权应保留有效期。
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-users/%d", temp), fmt.Sprintf(`{"role_ids": [%d, %d]}`, super, ops))
+	user := doJSON(t, engine, http.MethodGet, fmt.Sprintf("/admin-users/%d", temp), "")["data"].(map[string]interface{})
+	grants := user["role_grants"].([]interface{})
+	if len(user["roles"].([]interface{})) != 2 || len(grants) != 1 || grants[0].(map[string]interface{})["reason"] != "临时排障" {
+		t.Fatalf("编辑用户后应保留限时授权: %v", user)
+	}
+
+	expiring := doJSON(t, engine, http.MethodGet, "/admin-users/role-grants/expiring?days=7", "")["data"].([]interface{})
+	if len(expiring) != 1 || expiring[0].(map[string]interface{})["username"] != "temp" {
+		t.Fatalf("即将到期授权错误: %v", expiring)
+	}
+	if got := doJSON(t, engine, http.MethodGet, "/admin-users/role-grants/expiring?days=1", "")["data"].([]interface{}); len(got) != 0 {
+		t.Fatalf("范围外的授权不应列出: %v", got)
+	}
+
+	// 仅剩限时超级管理员时，不能撤销或限时最后一个永久超级管理员。
+	var rootGrant model.AdminUserRole
+	db.Where("user_id = ? AND role_id = ?", root, super).First(&rootGrant)
+	if resp := requestJSON(engine, http.MethodDelete, fmt.Sprintf("/admin-users/%d/role-grants/%d", root, rootGrant.ID), ""); resp["code"] == float64(0) {
+		t.Fatal("不应撤销最后一个永久超级管理员")
+	}
+	if resp := requestJSON(engine, http.MethodPost, fmt.Sprintf("/admin-users/%d/role-grants", root),
+		fmt.Sprintf(`{"role_id": %d, "valid_until": %q}`, super, until)); resp["code"] == float64(0) {
+		t.Fatal("不应将最后一个永久超级管理员改为限时")
+	}
+
+	tempGrants := doJSON(t, engine, http.MethodGet, fmt.Sprintf("/admin-users/%d/role-grants", temp), "")["data"].([]interface{})
+	if len(tempGrants) != 2 {
+		t.Fatalf("应返回用户全部授权: %v", tempGrants)
+	}
+	for _, item := range tempGrants {
+		grant := item.(map[string]interface{})
+		doJSON(t, engine, http.MethodDelete, fmt.Sprintf("/admin-users/%d/role-grants/%v", temp, grant["id"]), "")
+	}
+	if exists, _ := crud.Exists(db, &model.AdminUserRole{}, "user_id = ?", temp); exists {
+		t.Fatal("撤销后不应再有授权")
+	}
+}
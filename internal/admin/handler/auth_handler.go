@@ -6,6 +6,9 @@ import (
 	"bico-admin/internal/pkg/captcha"
 	"bico-admin/internal/pkg/crud"
 	"bico-admin/internal/pkg/response"
+	"errors"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,7 +32,7 @@ func NewAuthHandler(authService service.IAuthService, uploader upload.Uploader,
 
 // Login 登录接口
 // @Summary 登录
-// @Description 使用账号、密码和验证码换取登录 token
+// @Description 使用账号、密码和验证码换取登录 token；启用按需验证码时，失败次数未达阈值可不传验证码，需要验证码而未传时返回 code 428
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -42,16 +45,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 验证验证码
-	if !h.captcha.Verify(req.CaptchaID, req.CaptchaCode) {
-		h.Error(c, "验证码错误")
-		return
+	// 按需验证码模式下，账号或来源 IP 失败次数达到阈值后才校验验证码。
+	client := clientInfo(c)
+	if h.authService.CaptchaRequired(req.Username, client.IP) {
+		if req.CaptchaID == "" {
+			response.ErrorWithCode(c, 428, "请完成验证码")
+			return
+		}
+		if !h.captcha.Verify(req.CaptchaID, req.CaptchaCode) {
+			h.authService.LogCaptchaFailure(req.Username, client)
+			h.Error(c, "验证码错误")
+			return
+		}
 	}
 
 	// 验证码校验通过后，仅将账号密码传入服务层。
 	loginReq := &service.LoginRequest{
 		Username: req.Username,
 		Password: req.Password,
+		Client:   client,
 	}
 	resp, err := h.authService.Login(loginReq)
 	if err != nil {
@@ -62,9 +74,35 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	h.SuccessWithMessage(c, "登录成功", resp)
 }
 
+// Refresh 刷新令牌接口
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌与刷新令牌，旧刷新令牌随即失效；重复使用已轮换的刷新令牌会吊销整次登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.RefreshRequest true "刷新令牌"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req service.RefreshRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+
+	resp, err := h.authService.Refresh(&req)
+	if err != nil {
+		// 刷新失败意味着登录已失效，与访问令牌失效保持相同的 401 语义。
+		response.ErrorWithCode(c, 401, err.Error())
+		return
+	}
+
+	h.Success(c, resp)
+}
+
 // Logout 退出登录接口
 // @Summary 退出登录
-// @Description 将当前 token 加入黑名单
+// @Description 将当前 token 加入黑名单，并吊销本次登录的刷新令牌
 // @Tags 认证
 // @Produce json
 // @Security BearerAuth
@@ -104,10 +142,41 @@ func (h *AuthHandler) CurrentUser(c *gin.Context) {
 		h.NotFound(c, "用户不存在")
 		return
 	}
+	if impersonatorID := c.GetUint("impersonator_id"); impersonatorID != 0 {
+		user.Impersonated = true
+		user.Impersonator = &service.ImpersonatorInfo{ID: impersonatorID, Username: c.GetString("impersonator")}
+	}
 
 	h.Success(c, user)
 }
 
+// StopImpersonation 退出模拟登录
+// @Summary 退出模拟登录
+// @Description 结束当前模拟登录，吊销模拟令牌并在发起模拟时的原会话中返回操作者自己的令牌；原登录已失效时返回 401，需重新登录
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/impersonate/stop [post]
+func (h *AuthHandler) StopImpersonation(c *gin.Context) {
+	if c.GetUint("impersonator_id") == 0 {
+		h.Error(c, service.ErrNotImpersonating.Error())
+		return
+	}
+
+	resp, err := h.authService.StopImpersonation(c.GetString("token_family"), clientInfo(c))
+	if err != nil {
+		if errors.Is(err, service.ErrImpersonationOriginEnd) {
+			// 模拟令牌已吊销，原登录也已失效，需要重新登录。
+			response.ErrorWithCode(c, 401, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "已退出模拟登录", resp)
+}
+
 // UpdateProfile 更新用户资料
 // @Summary 更新个人资料
 // @Description 更新当前登录用户的用户名、名称和头像
@@ -168,6 +237,701 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	h.SuccessWithMessage(c, "密码修改成功", nil)
 }
 
+// ChangeExpiredPassword 登录时修改密码
+// @Summary 登录时修改密码
+// @Description 密码过期或管理员要求修改时，凭登录返回的 passwordChangeToken 设置新密码并完成登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.ExpiredPasswordRequest true "新密码参数"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/password/expired [post]
+func (h *AuthHandler) ChangeExpiredPassword(c *gin.Context) {
+	var req service.ExpiredPasswordRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+
+	resp, err := h.authService.ChangeExpiredPassword(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "密码修改成功", resp)
+}
+
+// VerifyTwoFactor 登录两步验证
+// @Summary 登录两步验证
+// @Description 使用登录返回的 twoFactorToken 与动态码或恢复码换取登录 token；强制绑定场景下首次校验即完成绑定并返回恢复码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.TwoFactorVerifyRequest true "两步验证参数"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req service.TwoFactorVerifyRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+
+	resp, err := h.authService.VerifyTwoFactor(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "登录成功", resp)
+}
+
+// BeginPasskeyTwoFactor 获取两步验证的通行密钥参数
+// @Summary 通行密钥两步验证参数
+// @Description 使用登录返回的 twoFactorToken 获取通行密钥验证参数，options 原样传给 navigator.credentials.get；仅在 twoFactorMethods 包含 passkey 时可用
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.TwoFactorPendingRequest true "待验证令牌"
+// @Success 200 {object} adminResponse{data=service.PasskeyCeremony}
+// @Router /auth/2fa/passkey/begin [post]
+func (h *AuthHandler) BeginPasskeyTwoFactor(c *gin.Context) {
+	var req service.TwoFactorPendingRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	ceremony, err := h.authService.BeginPasskeyTwoFactor(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, ceremony)
+}
+
+// VerifyTwoFactorPasskey 使用通行密钥完成两步验证
+// @Summary 通行密钥两步验证
+// @Description 提交 navigator.credentials.get 返回的凭据完成两步验证并换取登录 token
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.PasskeyTwoFactorRequest true "两步验证参数"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/2fa/passkey/verify [post]
+func (h *AuthHandler) VerifyTwoFactorPasskey(c *gin.Context) {
+	var req service.PasskeyTwoFactorRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+
+	resp, err := h.authService.VerifyTwoFactorPasskey(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "登录成功", resp)
+}
+
+// BeginPasskeyLogin 获取通行密钥登录参数
+// @Summary 通行密钥登录参数
+// @Description 获取免密码登录参数，options 原样传给 navigator.credentials.get，由认证器选择账号
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} adminResponse{data=service.PasskeyCeremony}
+// @Router /auth/passkey/login/begin [post]
+func (h *AuthHandler) BeginPasskeyLogin(c *gin.Context) {
+	ceremony, err := h.authService.BeginPasskeyLogin()
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, ceremony)
+}
+
+// FinishPasskeyLogin 通行密钥登录
+// @Summary 通行密钥登录
+// @Description 提交 navigator.credentials.get 返回的凭据换取登录 token；通行密钥已验证用户身份，不再要求两步验证；被标记为下次登录修改密码时返回 passwordChangeToken
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.PasskeyLoginRequest true "登录参数"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/passkey/login/finish [post]
+func (h *AuthHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req service.PasskeyLoginRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+
+	resp, err := h.authService.FinishPasskeyLogin(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "登录成功", resp)
+}
+
+// SSOAuthorize 发起单点登录
+// @Summary 发起单点登录
+// @Description 返回身份提供方授权地址，前端跳转后由身份提供方回调到配置的 redirect_url；同时写入绑定当前浏览器的 HttpOnly Cookie，回调须由同一浏览器提交
+// @Tags 认证
+// @Produce json
+// @Param provider path string true "登录方式标识"
+// @Success 200 {object} adminResponse{data=service.SSOAuthorizeResponse}
+// @Router /auth/sso/{provider}/authorize [get]
+func (h *AuthHandler) SSOAuthorize(c *gin.Context) {
+	resp, err := h.authService.SSOAuthorize(c.Param("provider"))
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	setSSOBindingCookie(c, resp.Binding, int(service.SSOStateTTL/time.Second))
+	h.Success(c, resp)
+}
+
+// SSOCallback 完成单点登录
+// @Summary 完成单点登录
+// @Description 提交身份提供方回调中的 state 与 code 换取登录 token；需要两步验证时与密码登录返回相同结构
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.SSOCallbackRequest true "回调参数"
+// @Success 200 {object} adminResponse{data=loginDocResponse}
+// @Router /auth/sso/callback [post]
+func (h *AuthHandler) SSOCallback(c *gin.Context) {
+	var req service.SSOCallbackRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	req.Client = clientInfo(c)
+	req.Binding, _ = c.Cookie(ssoBindingCookie)
+
+	resp, err := h.authService.SSOLogin(&req)
+	if !errors.Is(err, service.ErrSSOStateInvalid) {
+		// state 已被消耗，绑定随之作废。
+		setSSOBindingCookie(c, "", -1)
+	}
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "登录成功", resp)
+}
+
+// ForgotPassword 找回密码
+// @Summary 找回密码
+// @Description 向用户名或邮箱匹配的账号发送重置密码邮件；为避免枚举账号，账号不存在或未绑定邮箱时同样返回成功
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body forgotPasswordRequest true "找回密码参数"
+// @Success 200 {object} adminResponse
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	if !h.captcha.Verify(req.CaptchaID, req.CaptchaCode) {
+		h.Error(c, "验证码错误")
+		return
+	}
+
+	if err := h.authService.ForgotPassword(&service.ForgotPasswordRequest{Account: req.Account}); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "如果账号存在且已绑定邮箱，重置密码邮件已发送", nil)
+}
+
+// ResetPassword 重置密码
+// @Summary 重置密码
+// @Description 凭找回密码邮件中的令牌设置新密码，成功后该用户已登录的会话全部失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body resetPasswordRequest true "重置密码参数"
+// @Success 200 {object} adminResponse
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	if !h.captcha.Verify(req.CaptchaID, req.CaptchaCode) {
+		h.Error(c, "验证码错误")
+		return
+	}
+
+	if err := h.authService.ResetPassword(&service.ResetPasswordRequest{Token: req.Token, NewPassword: req.NewPassword}); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "密码已重置，请使用新密码登录", nil)
+}
+
+// JWKS 公钥集合
+//
+// 按 RFC 7517 直接返回 JWK Set，不包裹统一响应结构，便于标准 JWT 库直接拉取；
+// 使用 HS256 共享密钥时集合为空。
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// SetupPendingTwoFactor 登录中绑定两步验证
+// @Summary 登录中绑定两步验证
+// @Description 所属角色强制要求两步验证但尚未绑定时，凭 twoFactorToken 获取密钥与二维码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body service.TwoFactorPendingRequest true "待验证令牌"
+// @Success 200 {object} adminResponse{data=twoFactorKeyResponse}
+// @Router /auth/2fa/pending/setup [post]
+func (h *AuthHandler) SetupPendingTwoFactor(c *gin.Context) {
+	var req service.TwoFactorPendingRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	key, err := h.authService.SetupPendingTwoFactor(&req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, key)
+}
+
+// TwoFactorStatus 获取两步验证状态
+// @Summary 获取两步验证状态
+// @Description 获取当前用户是否已绑定、是否被角色强制要求以及剩余恢复码数量
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=service.TwoFactorStatus}
+// @Router /auth/2fa [get]
+func (h *AuthHandler) TwoFactorStatus(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.authService.GetTwoFactorStatus(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, status)
+}
+
+// SetupTwoFactor 获取两步验证绑定二维码
+// @Summary 获取绑定二维码
+// @Description 生成新的 TOTP 密钥与 otpauth 二维码，需调用启用接口确认后生效
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=twoFactorKeyResponse}
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.authService.SetupTwoFactor(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, key)
+}
+
+// EnableTwoFactor 启用两步验证
+// @Summary 启用两步验证
+// @Description 校验认证器生成的动态码后启用两步验证，返回的恢复码仅展示一次
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.TwoFactorCodeRequest true "动态码"
+// @Success 200 {object} adminResponse{data=recoveryCodesResponse}
+// @Router /auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.TwoFactorCodeRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	codes, err := h.authService.EnableTwoFactor(userID, req.Code)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "两步验证已启用", gin.H{"recoveryCodes": codes})
+}
+
+// DisableTwoFactor 关闭两步验证
+// @Summary 关闭两步验证
+// @Description 校验密码与动态码（或恢复码）后关闭两步验证，角色强制要求时不可关闭
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.TwoFactorDisableRequest true "关闭参数"
+// @Success 200 {object} adminResponse
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.TwoFactorDisableRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(userID, &req); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 校验动态码后重新生成恢复码，旧恢复码全部作废
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.TwoFactorCodeRequest true "动态码"
+// @Success 200 {object} adminResponse{data=recoveryCodesResponse}
+// @Router /auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.TwoFactorCodeRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "恢复码已重新生成", gin.H{"recoveryCodes": codes})
+}
+
+// ListSessions 获取当前用户登录会话
+// @Summary 获取登录会话
+// @Description 获取当前用户未过期的登录会话，current 标记当前请求所在会话
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=[]sessionDocItem}
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(userID, c.GetString("token_family"))
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, sessions)
+}
+
+// RevokeSession 下线当前用户的指定会话
+// @Summary 下线会话
+// @Description 下线当前用户的指定登录会话，该会话的令牌立即失效
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "会话 ID"
+// @Success 200 {object} adminResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.authService.RevokeSession(userID, id); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "会话已下线", nil)
+}
+
+// ListAPITokens 获取当前用户的访问令牌
+// @Summary 获取访问令牌
+// @Description 获取当前用户的个人访问令牌，不返回令牌明文
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=[]apiTokenDocItem}
+// @Router /auth/api-tokens [get]
+func (h *AuthHandler) ListAPITokens(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.authService.ListAPITokens(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, tokens)
+}
+
+// CreateAPIToken 创建访问令牌
+// @Summary 创建访问令牌
+// @Description 为当前用户创建个人访问令牌，供脚本以 Bearer 方式调用接口；明文令牌仅在创建时返回一次，访问令牌本身不能再创建令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.CreateAPITokenRequest true "令牌参数"
+// @Success 200 {object} adminResponse{data=apiTokenCreatedDoc}
+// @Router /auth/api-tokens [post]
+func (h *AuthHandler) CreateAPIToken(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateAPITokenRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	created, err := h.authService.CreateAPIToken(userID, &req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "访问令牌已创建，请立即复制保存", created)
+}
+
+// RevokeAPIToken 吊销当前用户的访问令牌
+// @Summary 吊销访问令牌
+// @Description 吊销当前用户的指定访问令牌，令牌立即失效
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "令牌 ID"
+// @Success 200 {object} adminResponse
+// @Router /auth/api-tokens/{id} [delete]
+func (h *AuthHandler) RevokeAPIToken(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.authService.RevokeAPIToken(userID, id); err != nil {
+		if errors.Is(err, service.ErrAPITokenNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "访问令牌已吊销", nil)
+}
+
+// ListPasskeys 获取当前用户的通行密钥
+// @Summary 获取通行密钥
+// @Description 获取当前用户已绑定的通行密钥
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=[]passkeyDocItem}
+// @Router /auth/passkeys [get]
+func (h *AuthHandler) ListPasskeys(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	passkeys, err := h.authService.ListPasskeys(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, passkeys)
+}
+
+// BeginPasskeyRegistration 获取通行密钥注册参数
+// @Summary 通行密钥注册参数
+// @Description 获取注册参数，options 原样传给 navigator.credentials.create
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=service.PasskeyCeremony}
+// @Router /auth/passkeys/register/begin [post]
+func (h *AuthHandler) BeginPasskeyRegistration(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	ceremony, err := h.authService.BeginPasskeyRegistration(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.Success(c, ceremony)
+}
+
+// FinishPasskeyRegistration 完成通行密钥注册
+// @Summary 注册通行密钥
+// @Description 提交 navigator.credentials.create 返回的凭据完成注册；绑定后账号密码登录需通过通行密钥或动态码完成两步验证
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body service.PasskeyRegisterRequest true "注册参数"
+// @Success 200 {object} adminResponse{data=passkeyDocItem}
+// @Router /auth/passkeys/register/finish [post]
+func (h *AuthHandler) FinishPasskeyRegistration(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.PasskeyRegisterRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	passkey, err := h.authService.FinishPasskeyRegistration(userID, &req)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "通行密钥已添加", passkey)
+}
+
+// RenamePasskey 重命名通行密钥
+// @Summary 重命名通行密钥
+// @Description 修改当前用户指定通行密钥的名称
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "通行密钥 ID"
+// @Param body body service.RenamePasskeyRequest true "名称"
+// @Success 200 {object} adminResponse
+// @Router /auth/passkeys/{id} [put]
+func (h *AuthHandler) RenamePasskey(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	var req service.RenamePasskeyRequest
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	if err := h.authService.RenamePasskey(userID, id, &req); err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "已重命名", nil)
+}
+
+// DeletePasskey 删除通行密钥
+// @Summary 删除通行密钥
+// @Description 删除当前用户的指定通行密钥，删除后不能再用于登录
+// @Tags 认证
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "通行密钥 ID"
+// @Success 200 {object} adminResponse
+// @Router /auth/passkeys/{id} [delete]
+func (h *AuthHandler) DeletePasskey(c *gin.Context) {
+	userID, ok := h.mustUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.authService.DeletePasskey(userID, id); err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithMessage(c, "通行密钥已删除", nil)
+}
+
 // UploadAvatar 上传头像
 // @Summary 上传头像
 // @Description 上传当前用户头像文件
@@ -201,41 +965,45 @@ func (h *AuthHandler) UploadAvatar(c *gin.Context) {
 
 // GetCaptcha 获取验证码
 // @Summary 获取验证码
-// @Description 生成登录验证码
+// @Description 按配置生成数字、字母数字、算式或滑块验证码；滑块验证码提交拼图块左边缘横坐标作为 captchaCode
 // @Tags 认证
 // @Produce json
 // @Success 200 {object} adminResponse{data=captchaResponse}
 // @Router /captcha [get]
 func (h *AuthHandler) GetCaptcha(c *gin.Context) {
-	id, b64s, err := h.captcha.Generate()
+	challenge, err := h.captcha.Generate()
 	if err != nil {
 		response.ErrorWithCode(c, 500, "生成验证码失败")
 		return
 	}
 
-	h.Success(c, gin.H{
-		"id":    id,
-		"image": b64s,
-	})
+	h.Success(c, challenge)
+}
+
+// ssoBindingCookie 单点登录浏览器绑定 Cookie，只在单点登录接口间传递。
+const (
+	ssoBindingCookie     = "bico_sso_binding"
+	ssoBindingCookiePath = "/admin-api/auth/sso"
+)
+
+// setSSOBindingCookie 写入或清除（maxAge 为负）单点登录浏览器绑定 Cookie。
+func setSSOBindingCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoBindingCookie, value, maxAge, ssoBindingCookiePath, "", secure, true)
+}
+
+// clientInfo 读取登录客户端的 IP 与 User-Agent。
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
 }
 
 // mustUserID 获取当前登录用户 ID。
 //
 // 说明：该方法统一处理 user_id 读取与类型校验，避免每个接口重复写 401 分支。
 func (h *AuthHandler) mustUserID(c *gin.Context) (uint, bool) {
-	userID, exists := c.Get("user_id")
-	// 未登录或上下文缺失时统一返回 401。
-	if !exists || userID == nil {
-		response.ErrorWithCode(c, 401, "未授权")
-		return 0, false
-	}
-
-	uid, ok := userID.(uint)
-	// user_id 类型异常时按未授权处理，避免 panic。
-	if !ok {
-		response.ErrorWithCode(c, 401, "未授权")
-		return 0, false
-	}
-
-	return uid, true
+	return requireUserID(c)
 }
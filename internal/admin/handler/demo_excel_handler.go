@@ -126,7 +126,7 @@ func (h *DemoExcelHandler) Export(c *gin.Context) {
 		return
 	}
 
-	rows := filterDemoExcelRows(parseDemoExcelIDs(c.Query("ids")))
+	rows := filterDemoExcelRows(parseExportIDs(c.Query("ids")))
 	_ = excelpkg.AppendRows(f, buildDemoExcelExportRows(rows))
 
 	filename := "导出_示例_" + time.Now().Format("20060102_150405") + ".xlsx"
@@ -136,8 +136,8 @@ func (h *DemoExcelHandler) Export(c *gin.Context) {
 	}
 }
 
-// parseDemoExcelIDs 解析导出选中行 ID，非法片段直接忽略。
-func parseDemoExcelIDs(value string) []uint {
+// parseExportIDs 解析导出选中行 ID，非法片段直接忽略。
+func parseExportIDs(value string) []uint {
 	if value == "" {
 		return nil
 	}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"strings"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 权限定义
+var departmentPerms = crud.NewCRUDPerms("system", "department", "部门管理")
+
+// DepartmentHandler 部门管理处理器。
+//
+// 部门的上级只能通过移动接口调整，以便统一校验循环；仍有成员或子部门的部门不能删除。
+type DepartmentHandler struct {
+	crud.CRUDHandler[model.AdminDepartment, departmentListReq, createDepartmentReq, updateDepartmentReq]
+}
+
+// NewDepartmentHandler 创建部门管理处理器。
+func NewDepartmentHandler(db *gorm.DB) *DepartmentHandler {
+	h := &DepartmentHandler{}
+	h.DB = db
+	h.NotFoundMsg = "部门不存在"
+
+	h.BuildListQuery = func(db *gorm.DB, req *departmentListReq) *gorm.DB {
+		return filterDepartments(db, req).Order("sort ASC, id ASC")
+	}
+	h.AfterList = func(items []model.AdminDepartment) error {
+		ptrs := make([]*model.AdminDepartment, 0, len(items))
+		for i := range items {
+			ptrs = append(ptrs, &items[i])
+		}
+		return fillDepartmentLeaders(h.DB, ptrs)
+	}
+	h.AfterGet = func(item *model.AdminDepartment) error {
+		return fillDepartmentLeaders(h.DB, []*model.AdminDepartment{item})
+	}
+
+	h.NewModelFromCreate = func(req *createDepartmentReq) (*model.AdminDepartment, error) {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			return nil, service.ErrDepartmentNameRequired
+		}
+		if err := service.CheckDepartmentParent(db, 0, req.ParentID); err != nil {
+			return nil, err
+		}
+		leaderID := normalizeLeaderID(req.LeaderID)
+		if err := service.CheckDepartmentLeader(db, leaderID); err != nil {
+			return nil, err
+		}
+		return &model.AdminDepartment{
+			ParentID: req.ParentID,
+			Name:     name,
+			Sort:     req.Sort,
+			LeaderID: leaderID,
+			Enabled:  req.Enabled == nil || *req.Enabled,
+		}, nil
+	}
+
+	h.BuildUpdates = func(req *updateDepartmentReq, existing *model.AdminDepartment) (map[string]interface{}, error) {
+		updates := map[string]interface{}{}
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if name == "" {
+				return nil, service.ErrDepartmentNameRequired
+			}
+			updates["name"] = name
+		}
+		if req.Sort != nil {
+			updates["sort"] = *req.Sort
+		}
+		if req.LeaderID != nil {
+			leaderID := normalizeLeaderID(req.LeaderID)
+			if err := service.CheckDepartmentLeader(db, leaderID); err != nil {
+				return nil, err
+			}
+			updates["leader_id"] = leaderID
+		}
+		if req.Enabled != nil {
+			updates["enabled"] = *req.Enabled
+		}
+		return updates, nil
+	}
+
+	h.BeforeDelete = func(tx *gorm.DB, id uint) error {
+		return service.CheckDepartmentsDeletable(tx, []uint{id})
+	}
+	h.BeforeDeleteBatch = func(tx *gorm.DB, ids []uint) error {
+		return service.CheckDepartmentsDeletable(tx, ids)
+	}
+
+	return h
+}
+
+func (h *DepartmentHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:             "department",
+		Group:            "/departments",
+		Description:      "部门管理",
+		ParentPermission: PermSystemManage,
+		Permissions:      departmentPerms.Tree,
+		Routes: departmentPerms.RoutesWithExtra(
+			crud.Route{Method: "GET", Path: "/tree", Handler: "Tree", Permission: departmentPerms.List},
+			crud.Route{Method: "PUT", Path: "/:id/move", Handler: "Move", Permission: departmentPerms.Edit},
+		),
+		Swagger: crud.SwaggerConfig{
+			Model:         model.AdminDepartment{},
+			ListRequest:   departmentListReq{},
+			CreateRequest: createDepartmentReq{},
+			UpdateRequest: updateDepartmentReq{},
+		},
+	}
+}
+
+// 请求结构
+//
+// leader_id 传 0 表示清除负责人；parent_id 为 0 表示顶级部门。
+type (
+	departmentListReq struct {
+		Name    string `form:"name"`
+		Enabled *bool  `form:"enabled"`
+	}
+	createDepartmentReq struct {
+		ParentID uint   `json:"parent_id"`
+		Name     string `json:"name" binding:"required,max=64"`
+		Sort     int    `json:"sort"`
+		LeaderID *uint  `json:"leader_id"`
+		Enabled  *bool  `json:"enabled"`
+	}
+	updateDepartmentReq struct {
+		Name     *string `json:"name" binding:"omitempty,max=64"`
+		Sort     *int    `json:"sort"`
+		LeaderID *uint   `json:"leader_id"`
+		Enabled  *bool   `json:"enabled"`
+	}
+	moveDepartmentReq struct {
+		ParentID *uint `json:"parent_id" binding:"required"`
+		Sort     *int  `json:"sort"`
+	}
+)
+
+// Tree 获取部门树。
+// @Summary 获取部门树
+// @Description 按上级关系返回部门树，同级按排序值升序；按名称或状态筛选时，上级未命中的部门作为根节点返回
+// @Tags 部门管理
+// @Produce json
+// @Security BearerAuth
+// @Param name query string false "部门名称"
+// @Param enabled query bool false "是否启用"
+// @Success 200 {object} adminResponse{data=[]departmentTreeDocItem}
+// @Router /departments/tree [get]
+func (h *DepartmentHandler) Tree(c *gin.Context) {
+	var req departmentListReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	var items []*model.AdminDepartment
+	if err := filterDepartments(h.DB, &req).Find(&items).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if err := fillDepartmentLeaders(h.DB, items); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, service.BuildDepartmentTree(items))
+}
+
+// Move 移动部门。
+// @Summary 移动部门
+// @Description 将部门连同下级部门移动到新的上级部门下，可同时调整排序值；不能移动到自身或下级部门下
+// @Tags 部门管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "部门 ID"
+// @Param body body moveDepartmentReq true "新的上级部门与排序值"
+// @Success 200 {object} adminResponse
+// @Router /departments/{id}/move [put]
+func (h *DepartmentHandler) Move(c *gin.Context) {
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	var req moveDepartmentReq
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	var department model.AdminDepartment
+	if !h.QueryOne(c, h.DB.Where("id = ?", id), &department, h.NotFoundMsg) {
+		return
+	}
+
+	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+		if err := service.CheckDepartmentParent(tx, id, *req.ParentID); err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"parent_id": *req.ParentID}
+		if req.Sort != nil {
+			updates["sort"] = *req.Sort
+		}
+		return tx.Model(&model.AdminDepartment{}).Where("id = ?", id).Updates(updates).Error
+	}, "部门已移动", nil)
+}
+
+// filterDepartments 按列表参数构建部门查询，列表与树共用。
+func filterDepartments(db *gorm.DB, req *departmentListReq) *gorm.DB {
+	query := db.Model(&model.AdminDepartment{})
+	if req.Name != "" {
+		query = query.Where("name LIKE ?", "%"+req.Name+"%")
+	}
+	if req.Enabled != nil {
+		query = query.Where("enabled = ?", *req.Enabled)
+	}
+	return query
+}
+
+// fillDepartmentLeaders 填充部门负责人名称，用户未设置姓名时使用用户名。
+func fillDepartmentLeaders(db *gorm.DB, items []*model.AdminDepartment) error {
+	leaderIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if item.LeaderID != nil {
+			leaderIDs = append(leaderIDs, *item.LeaderID)
+		}
+	}
+	if len(leaderIDs) == 0 {
+		return nil
+	}
+	var users []model.AdminUser
+	if err := db.Select("id", "username", "name").Where("id IN ?", crud.UniqueUints(leaderIDs)).Find(&users).Error; err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Name
+		if user.Name == "" {
+			names[user.ID] = user.Username
+		}
+	}
+	for _, item := range items {
+		if item.LeaderID != nil {
+			item.LeaderName = names[*item.LeaderID]
+		}
+	}
+	return nil
+}
+
+// normalizeLeaderID 将 0 视为未设置负责人。
+func normalizeLeaderID(id *uint) *uint {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+var _ crud.Module = (*DepartmentHandler)(nil)
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestDepartmentTreeAndMembership 验证部门树、移动与删除保护，以及用户列表按部门（含下级）筛选。
+func TestDepartmentTreeAndMembership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminPasswordHistory{},
+		&model.AdminDepartment{}, &model.AdminUserDepartment{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewDepartmentHandler(db))
+	router.RegisterModule(&engine.RouterGroup, NewAdminUserHandler(db, nil, nil))
+	createDepartment := func(body string) uint {
+		t.Helper()
+		data := doJSON(t, engine, http.MethodPost, "/departments", body)["data"].(map[string]interface{})
+		return uint(data["id"].(float64))
+	}
+
+	leader := model.AdminUser{Username: "leader", Name: "张经理", Password: "x", Enabled: true}
+	db.Create(&leader)
+	head := createDepartment(fmt.Sprintf(`{"name": "总部", "leader_id": %d}`, leader.ID))
+	rd := createDepartment(fmt.Sprintf(`{"name": "研发部", "parent_id": %d, "sort": 2}`, head))
+	backend := createDepartment(fmt.Sprintf(`{"name": "后端组", "parent_id": %d}`, rd))
+	sales := createDepartment(fmt.Sprintf(`{"name": "销售部", "parent_id": %d, "sort": 1}`, head))
+	if resp := requestJSON(engine, http.MethodPost, "/departments", `{"name": "无效", "parent_id": 999}`); resp["code"] == float64(0) {
+		t.Fatal("上级部门不存在时不应创建")
+	}
+
+	tree := doJSON(t, engine, http.MethodGet, "/departments/tree", "")["data"].([]interface{})
+	root := tree[0].(map[string]interface{})
+	children := root["children"].([]interface{})
+	if len(tree) != 1 || root["leader_name"] != "张经理" || len(children) != 2 || children[0].(map[string]interface{})["name"] != "销售部" {
+		t.Fatalf("部门树结构错误: %v", tree)
+	}
+
+	user := doJSON(t, engine, http.MethodPost, "/admin-users", fmt.Sprintf(
+		`{"username": "dev", "password": "Str0ng-Passw0rd", "department_ids": [%d, %d]}`, backend, sales))["data"].(map[string]interface{})
+	if user["primary_department_id"] != float64(backend) {
+		t.Fatalf("未指定主部门时应以第一个部门为主部门: %v", user)
+	}
+	userID := uint(user["id"].(float64))
+	if resp := requestJSON(engine, http.MethodPut, fmt.Sprintf("/admin-users/%d", userID), fmt.Sprintf(`{"primary_department_id": %d}`, rd)); resp["code"] == float64(0) {
+		t.Fatal("主部门必须是所属部门之一")
+	}
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-users/%d", userID), fmt.Sprintf(`{"primary_department_id": %d}`, sales))
+
+	countUsers := func(query string) float64 {
+		t.Helper()
+		return doJSON(t, engine, http.MethodGet, "/admin-users?"+query, "")["data"].(map[string]interface{})["total"].(float64)
+	}
+	if got := countUsers(fmt.Sprintf("department_id=%d", head)); got != 1 {
+		t.Fatalf("按上级部门筛选应包含下级部门成员，实际 %v", got)
+	}
+	if got := countUsers(fmt.Sprintf("department_id=%d&include_children=false", head)); got != 0 {
+		t.Fatalf("不含下级部门时不应返回成员，实际 %v", got)
+	}
+
+	if resp := requestJSON(engine, http.MethodPut, fmt.Sprintf("/departments/%d/move", head), fmt.Sprintf(`{"parent_id": %d}`, backend)); resp["code"] == float64(0) {
+		t.Fatal("不能移动到下级部门下")
+	}
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/departments/%d/move", backend), fmt.Sprintf(`{"parent_id": %d}`, sales))
+	if got := countUsers(fmt.Sprintf("department_id=%d", rd)); got != 0 {
+		t.Fatalf("移动后研发部下不应再有成员，实际 %v", got)
+	}
+
+	if resp := requestJSON(engine, http.MethodDelete, fmt.Sprintf("/departments/%d", sales), ""); resp["msg"] != service.ErrDepartmentHasChildren.Error() {
+		t.Fatalf("有子部门时不应删除: %v", resp)
+	}
+	if resp := requestJSON(engine, http.MethodDelete, "/departments/batch", fmt.Sprintf(`{"ids": [%d, %d]}`, sales, backend)); resp["msg"] != service.ErrDepartmentHasMembers.Error() {
+		t.Fatalf("有成员时不应删除: %v", resp)
+	}
+	doJSON(t, engine, http.MethodPut, fmt.Sprintf("/admin-users/%d", userID), `{"department_ids": []}`)
+	doJSON(t, engine, http.MethodDelete, "/departments/batch", fmt.Sprintf(`{"ids": [%d, %d]}`, sales, backend))
+}
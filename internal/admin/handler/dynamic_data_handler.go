@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+	excelpkg "bico-admin/internal/pkg/excel"
+	"bico-admin/internal/pkg/response"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DynamicDataHandler 动态表数据处理器。
+//
+// 说明：
+// - 所有动态表共用一组路由，通过 :table 参数定位表定义
+// - 权限 key 使用 dynamic:{table}:xxx 模板，请求时按路由参数展开
+// - 表定义只在请求时读取，新增或修改表结构后无需重启
+type DynamicDataHandler struct {
+	crud.BaseHandler
+	db *gorm.DB
+}
+
+// NewDynamicDataHandler 创建动态表数据处理器。
+func NewDynamicDataHandler(db *gorm.DB) *DynamicDataHandler {
+	return &DynamicDataHandler{db: db}
+}
+
+func (h *DynamicDataHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:        "dynamic_data",
+		Group:       "/dynamic-data/:table",
+		Description: "动态表数据",
+		Routes: []crud.Route{
+			{Method: "GET", Path: "", Handler: "List", Permission: "dynamic:{table}:list"},
+			{Method: "GET", Path: "/schema", Handler: "Schema", Permission: "dynamic:{table}:menu"},
+			{Method: "GET", Path: "/template", Handler: "Template", Permission: "dynamic:{table}:import"},
+			{Method: "GET", Path: "/export", Handler: "Export", Permission: "dynamic:{table}:export"},
+			{Method: "POST", Path: "/import", Handler: "Import", Permission: "dynamic:{table}:import"},
+			{Method: "GET", Path: "/:id", Handler: "Get", Permission: "dynamic:{table}:list"},
+			{Method: "POST", Path: "", Handler: "Create", Permission: "dynamic:{table}:create"},
+			{Method: "PUT", Path: "/:id", Handler: "Update", Permission: "dynamic:{table}:edit"},
+			{Method: "DELETE", Path: "/batch", Handler: "DeleteBatch", Permission: "dynamic:{table}:delete"},
+			{Method: "DELETE", Path: "/:id", Handler: "Delete", Permission: "dynamic:{table}:delete"},
+		},
+	}
+}
+
+// Schema 获取动态表定义。
+// @Summary 获取动态表定义
+// @Description 获取动态表字段定义，前端据此渲染列表、筛选和表单
+// @Tags 动态表数据
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Success 200 {object} adminResponse{data=object}
+// @Router /dynamic-data/{table}/schema [get]
+func (h *DynamicDataHandler) Schema(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	h.Success(c, table)
+}
+
+// List 获取动态表数据列表。
+// @Summary 获取动态表数据列表
+// @Description 分页查询动态表数据，筛选参数按字段定义的 filter 解析，区间筛选使用 {name}_start / {name}_end
+// @Tags 动态表数据
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Param sortField query string false "排序字段，仅支持可排序字段和系统字段"
+// @Param sortOrder query string false "排序方式：ascend/descend"
+// @Success 200 {object} adminResponse{data=dynamicPageDoc}
+// @Router /dynamic-data/{table} [get]
+func (h *DynamicDataHandler) List(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	schema := table.Schema()
+
+	query, err := schema.ApplyFilters(h.db.Table(schema.TableName()), c.Request.URL.Query())
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	pg := h.GetPagination(c)
+	records := schema.NewRecords()
+	if err := query.Order(schema.Order(pg.SortField, pg.SortOrder)).
+		Offset(pg.GetOffset()).Limit(pg.GetPageSize()).
+		Find(records).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.SuccessWithPagination(c, records, total)
+}
+
+// Get 获取动态表单条数据。
+// @Summary 获取动态表数据详情
+// @Tags 动态表数据
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param id path int true "记录 ID"
+// @Success 200 {object} adminResponse{data=object}
+// @Router /dynamic-data/{table}/{id} [get]
+func (h *DynamicDataHandler) Get(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	schema := table.Schema()
+	record := schema.NewRecord()
+	if !h.QueryOne(c, h.db.Table(schema.TableName()).Where("id = ?", id), record, "记录不存在") {
+		return
+	}
+	h.Success(c, record)
+}
+
+// Create 创建动态表数据。
+// @Summary 创建动态表数据
+// @Description 请求体为字段名到值的映射，按字段定义校验
+// @Tags 动态表数据
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param body body object true "记录数据"
+// @Success 200 {object} adminResponse{data=object}
+// @Router /dynamic-data/{table} [post]
+func (h *DynamicDataHandler) Create(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	var input map[string]interface{}
+	if err := h.BindJSON(c, &input); err != nil {
+		return
+	}
+
+	schema := table.Schema()
+	values, err := schema.Normalize(input, false)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	record := schema.NewRecord()
+	schema.Fill(record, values)
+
+	h.ExecTx(c, h.db, func(tx *gorm.DB) error {
+		return tx.Table(schema.TableName()).Create(record).Error
+	}, "创建成功", record)
+}
+
+// Update 更新动态表数据。
+// @Summary 更新动态表数据
+// @Description 只更新请求体中出现的字段
+// @Tags 动态表数据
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param id path int true "记录 ID"
+// @Param body body object true "记录数据"
+// @Success 200 {object} adminResponse{data=object}
+// @Router /dynamic-data/{table}/{id} [put]
+func (h *DynamicDataHandler) Update(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	var input map[string]interface{}
+	if err := h.BindJSON(c, &input); err != nil {
+		return
+	}
+
+	schema := table.Schema()
+	values, err := schema.Normalize(input, true)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	record := schema.NewRecord()
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Table(schema.TableName()).Where("id = ?", id)
+		if err := query.First(record).Error; err != nil {
+			return err
+		}
+		values["updated_at"] = time.Now()
+		if err := tx.Table(schema.TableName()).Where("id = ?", id).Updates(values).Error; err != nil {
+			return err
+		}
+		return tx.Table(schema.TableName()).Where("id = ?", id).First(record).Error
+	}); err != nil {
+		h.recordError(c, err)
+		return
+	}
+
+	h.SuccessWithMessage(c, "更新成功", record)
+}
+
+// Delete 删除动态表数据。
+// @Summary 删除动态表数据
+// @Tags 动态表数据
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param id path int true "记录 ID"
+// @Success 200 {object} adminResponse
+// @Router /dynamic-data/{table}/{id} [delete]
+func (h *DynamicDataHandler) Delete(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	schema := table.Schema()
+	result := h.db.Table(schema.TableName()).Where("id = ?", id).Delete(schema.NewRecord())
+	if result.Error != nil {
+		h.Error(c, result.Error.Error())
+		return
+	}
+	if result.RowsAffected == 0 {
+		h.NotFound(c, "记录不存在")
+		return
+	}
+	h.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// DeleteBatch 批量删除动态表数据。
+// @Summary 批量删除动态表数据
+// @Tags 动态表数据
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param body body deleteBatchDocRequest true "记录 ID 列表"
+// @Success 200 {object} adminResponse
+// @Router /dynamic-data/{table}/batch [delete]
+func (h *DynamicDataHandler) DeleteBatch(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+	if len(req.IDs) == 0 {
+		h.Error(c, "ids 不能为空")
+		return
+	}
+
+	ids := crud.UniqueUints(req.IDs)
+	schema := table.Schema()
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(schema.TableName()).Where("id IN ?", ids).Delete(schema.NewRecord())
+		if result.Error != nil {
+			return result.Error
+		}
+		// 与标准 CRUD 一致：必须全部命中，否则整体回滚。
+		if result.RowsAffected != int64(len(ids)) {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}); err != nil {
+		h.recordError(c, err)
+		return
+	}
+	h.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// Template 下载动态表导入模板。
+// @Summary 下载动态表导入模板
+// @Description 表头为字段显示名称
+// @Tags 动态表数据
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Success 200 {file} file
+// @Router /dynamic-data/{table}/template [get]
+func (h *DynamicDataHandler) Template(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	f, err := excelpkg.BuildHeaderTemplate(table.Schema().Headers())
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if err := excelpkg.WriteAsAttachment(c, f, "导入模板_"+table.Label+".xlsx"); err != nil {
+		response.ErrorWithStatus(c, http.StatusInternalServerError, 500, err.Error())
+		return
+	}
+}
+
+// Import 导入动态表数据。
+// @Summary 导入动态表数据
+// @Description 上传 Excel 或 CSV，逐行按字段定义校验，任一行失败则整体不导入
+// @Tags 动态表数据
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param file formData file true "Excel 或 CSV 文件"
+// @Success 200 {object} adminResponse{data=dynamicImportResponse}
+// @Router /dynamic-data/{table}/import [post]
+func (h *DynamicDataHandler) Import(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	result, err := excelpkg.ParseUploadedAuto(c, "file")
+	if err != nil {
+		if err == excelpkg.ErrMissingUploadFile {
+			h.Error(c, "请上传文件")
+			return
+		}
+		if err == excelpkg.ErrUnsupportedImportType {
+			h.Error(c, "不支持的文件类型，请上传 .xlsx/.xlsm/.xltx/.xltm/.csv")
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
+	schema := table.Schema()
+	if err := excelpkg.ValidateHeaders(result.Headers, schema.Headers()); err != nil {
+		h.Error(c, "导入模板不正确，请先下载模板")
+		return
+	}
+	if len(result.Rows) == 0 {
+		h.Error(c, "导入文件没有数据")
+		return
+	}
+
+	records := make([]interface{}, 0, len(result.Rows))
+	for i, row := range result.Rows {
+		input := make(map[string]interface{}, len(schema.Fields))
+		for j, field := range schema.Fields {
+			input[field.Name] = row[j]
+		}
+		values, err := schema.Normalize(input, false)
+		if err != nil {
+			// 行号从表头下一行开始计算，便于用户在 Excel 中定位。
+			h.Error(c, fmt.Sprintf("第 %d 行: %s", i+2, err.Error()))
+			return
+		}
+		record := schema.NewRecord()
+		schema.Fill(record, values)
+		records = append(records, record)
+	}
+
+	h.ExecTx(c, h.db, func(tx *gorm.DB) error {
+		for _, record := range records {
+			if err := tx.Table(schema.TableName()).Create(record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}, "导入成功", gin.H{"total": len(records)})
+}
+
+// Export 导出动态表数据。
+// @Summary 导出动态表数据
+// @Description 按当前筛选条件导出，传 ids 时只导出勾选行
+// @Tags 动态表数据
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param table path string true "动态表标识"
+// @Param ids query string false "勾选记录 ID，逗号分隔"
+// @Success 200 {file} file
+// @Router /dynamic-data/{table}/export [get]
+func (h *DynamicDataHandler) Export(c *gin.Context) {
+	table, ok := h.loadTable(c)
+	if !ok {
+		return
+	}
+	schema := table.Schema()
+
+	query, err := schema.ApplyFilters(h.db.Table(schema.TableName()), c.Request.URL.Query())
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if ids := parseExportIDs(c.Query("ids")); len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	pg := h.GetPagination(c)
+	records := schema.NewRecords()
+	if err := query.Order(schema.Order(pg.SortField, pg.SortOrder)).Find(records).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	f, err := excelpkg.BuildHeaderTemplate(schema.Headers())
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	list := reflect.ValueOf(records).Elem()
+	rows := make([][]interface{}, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		rows = append(rows, schema.Row(list.Index(i)))
+	}
+	_ = excelpkg.AppendRows(f, rows)
+
+	filename := "导出_" + table.Label + "_" + time.Now().Format("20060102_150405") + ".xlsx"
+	if err := excelpkg.WriteAsAttachment(c, f, filename); err != nil {
+		response.ErrorWithStatus(c, http.StatusInternalServerError, 500, err.Error())
+		return
+	}
+}
+
+// loadTable 按路由参数读取已启用的动态表定义。
+func (h *DynamicDataHandler) loadTable(c *gin.Context) (*model.DynamicTable, bool) {
+	var table model.DynamicTable
+	query := h.db.Where("name = ? AND enabled = ?", c.Param("table"), true)
+	if !h.QueryOne(c, query, &table, "动态表不存在") {
+		return nil, false
+	}
+	return &table, true
+}
+
+// recordError 将记录不存在统一转换为 404。
+func (h *DynamicDataHandler) recordError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		h.NotFound(c, "记录不存在")
+		return
+	}
+	h.Error(c, err.Error())
+}
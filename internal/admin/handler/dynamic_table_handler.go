@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/dynamic"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 权限定义
+var dynamicTablePerms = crud.NewCRUDPerms("system", "dynamic_table", "动态表")
+
+// dynamicMenuPathPrefix 动态表前端页面路径前缀。
+const dynamicMenuPathPrefix = "/dynamic/"
+
+// DynamicTableHandler 动态表定义管理处理器
+type DynamicTableHandler struct {
+	crud.CRUDHandler[model.DynamicTable, dynamicTableListReq, createDynamicTableReq, updateDynamicTableReq]
+	cacheInvalidator service.AuthCacheInvalidator
+}
+
+func NewDynamicTableHandler(db *gorm.DB, cacheInvalidator service.AuthCacheInvalidator) *DynamicTableHandler {
+	h := &DynamicTableHandler{cacheInvalidator: cacheInvalidator}
+	h.DB = db
+	h.NotFoundMsg = "动态表不存在"
+
+	h.BuildListQuery = func(db *gorm.DB, req *dynamicTableListReq) *gorm.DB {
+		query := db.Model(&model.DynamicTable{})
+		if req.Name != "" {
+			query = query.Where("name LIKE ?", "%"+req.Name+"%")
+		}
+		if req.Label != "" {
+			query = query.Where("label LIKE ?", "%"+req.Label+"%")
+		}
+		if req.Enabled != nil {
+			query = query.Where("enabled = ?", *req.Enabled)
+		}
+		return query
+	}
+
+	h.NewModelFromCreate = func(req *createDynamicTableReq) (*model.DynamicTable, error) {
+		table := &model.DynamicTable{
+			Name:        req.Name,
+			Label:       req.Label,
+			Description: req.Description,
+			Fields:      req.Fields,
+			Enabled:     req.Enabled == nil || *req.Enabled,
+		}
+		if err := table.Schema().Validate(); err != nil {
+			return nil, err
+		}
+		exists, err := crud.Exists(db, &model.DynamicTable{}, "name = ?", req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.New("动态表标识已存在")
+		}
+		return table, nil
+	}
+
+	h.CreateInTx = func(tx *gorm.DB, item *model.DynamicTable, req *createDynamicTableReq) error {
+		// 物理表已存在（例如删除定义后重建）时只补齐缺失列，保留原有数据。
+		if err := item.Schema().Migrate(tx); err != nil {
+			return fmt.Errorf("创建数据表失败: %w", err)
+		}
+		menu := model.Menu{
+			Name:   item.Label,
+			Path:   dynamicMenuPathPrefix + item.Name,
+			Icon:   "table",
+			Type:   1,
+			Status: menuStatus(item.Enabled),
+		}
+		if err := tx.Create(&menu).Error; err != nil {
+			return err
+		}
+		item.MenuID = menu.ID
+		return tx.Model(item).Update("menu_id", menu.ID).Error
+	}
+
+	h.AfterCreateCommit = func(item *model.DynamicTable, req *createDynamicTableReq) {
+		h.syncPermissions(h.DB, item)
+	}
+
+	h.BuildUpdates = func(req *updateDynamicTableReq, existing *model.DynamicTable) (map[string]interface{}, error) {
+		if req.Fields != nil {
+			next := dynamic.Table{Name: existing.Name, Fields: req.Fields}
+			if err := next.ValidateChange(existing.Schema()); err != nil {
+				return nil, err
+			}
+		}
+
+		updates := map[string]interface{}{}
+		if req.Label != "" {
+			updates["label"] = req.Label
+		}
+		if req.Description != "" {
+			updates["description"] = req.Description
+		}
+		if req.Enabled != nil {
+			updates["enabled"] = *req.Enabled
+		}
+		return updates, nil
+	}
+
+	h.UpdateInTx = func(tx *gorm.DB, id uint, existing *model.DynamicTable, req *updateDynamicTableReq) error {
+		// 字段定义走结构体更新，确保 json 序列化器生效。
+		if req.Fields != nil {
+			if err := tx.Model(&model.DynamicTable{}).Where("id = ?", id).
+				Select("fields").Updates(&model.DynamicTable{Fields: req.Fields}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.First(existing, id).Error; err != nil {
+			return err
+		}
+		if req.Fields != nil {
+			if err := existing.Schema().Migrate(tx); err != nil {
+				return fmt.Errorf("更新数据表失败: %w", err)
+			}
+		}
+		return tx.Model(&model.Menu{}).Where("id = ?", existing.MenuID).Updates(map[string]interface{}{
+			"name":   existing.Label,
+			"status": menuStatus(existing.Enabled),
+		}).Error
+	}
+
+	h.AfterUpdateCommit = func(id uint, existing *model.DynamicTable, req *updateDynamicTableReq) {
+		h.syncPermissions(h.DB, existing)
+	}
+
+	h.DeleteInTx = func(tx *gorm.DB, id uint) error {
+		return h.cleanup(tx, []uint{id})
+	}
+	h.DeleteBatchInTx = func(tx *gorm.DB, ids []uint) error {
+		return h.cleanup(tx, ids)
+	}
+	h.AfterDeleteCommit = func(items []model.DynamicTable) {
+		h.removePermissions(items)
+	}
+
+	return h
+}
+
+func (h *DynamicTableHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:             "dynamic_table",
+		Group:            "/dynamic-tables",
+		Description:      "动态表",
+		ParentPermission: PermSystemManage,
+		Permissions:      dynamicTablePerms.Tree,
+		Routes: dynamicTablePerms.RoutesWithExtra(
+			crud.AuthRoute("GET", "/menus", "Menus"),
+		),
+		Swagger: crud.SwaggerConfig{
+			Model:         model.DynamicTable{},
+			ListRequest:   dynamicTableListReq{},
+			CreateRequest: createDynamicTableReq{},
+			UpdateRequest: updateDynamicTableReq{},
+		},
+	}
+}
+
+// 请求结构
+type (
+	dynamicTableListReq struct {
+		Name    string `form:"name"`
+		Label   string `form:"label"`
+		Enabled *bool  `form:"enabled"`
+	}
+	createDynamicTableReq struct {
+		Name        string          `json:"name" binding:"required"`
+		Label       string          `json:"label" binding:"required"`
+		Description string          `json:"description"`
+		Fields      []dynamic.Field `json:"fields" binding:"required"`
+		Enabled     *bool           `json:"enabled"`
+	}
+	// updateDynamicTableReq 表标识决定物理表名和权限 key，创建后不允许修改。
+	updateDynamicTableReq struct {
+		Label       string          `json:"label"`
+		Description string          `json:"description"`
+		Fields      []dynamic.Field `json:"fields"`
+		Enabled     *bool           `json:"enabled"`
+	}
+	dynamicMenuItem struct {
+		ID     uint   `json:"id"`
+		Name   string `json:"name"`
+		Label  string `json:"label"`
+		Path   string `json:"path"`
+		Access string `json:"access"`
+	}
+)
+
+// Menus 获取动态表菜单。
+// @Summary 获取动态表菜单
+// @Description 获取已启用动态表的菜单项，前端按 access 权限过滤后追加到导航
+// @Tags 动态表
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} adminResponse{data=[]dynamicMenuItem}
+// @Router /dynamic-tables/menus [get]
+func (h *DynamicTableHandler) Menus(c *gin.Context) {
+	var tables []model.DynamicTable
+	if err := h.DB.Where("enabled = ?", true).Order("id ASC").Find(&tables).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	items := make([]dynamicMenuItem, 0, len(tables))
+	for _, table := range tables {
+		items = append(items, dynamicMenuItem{
+			ID:     table.ID,
+			Name:   table.Name,
+			Label:  table.Label,
+			Path:   dynamicMenuPathPrefix + table.Name,
+			Access: dynamicDataPerms(&table).Menu,
+		})
+	}
+	h.Success(c, items)
+}
+
+// LoadDynamicTablePermissions 启动时把已启用动态表的数据权限挂载到权限树。
+//
+// 说明：必须在基础权限树初始化之后调用，否则会被 SetBasePermissions 覆盖。
+func LoadDynamicTablePermissions(db *gorm.DB) error {
+	var tables []model.DynamicTable
+	if err := db.Where("enabled = ?", true).Find(&tables).Error; err != nil {
+		return err
+	}
+	for i := range tables {
+		crud.AddPermissions(PermDynamicData, dynamicDataPerms(&tables[i]).Tree)
+	}
+	return nil
+}
+
+// dynamicDataPerms 生成动态表的数据权限，key 与 DynamicDataHandler 路由中的权限模板一一对应。
+func dynamicDataPerms(table *model.DynamicTable) crud.CRUDPerms {
+	return crud.NewCRUDPerms("dynamic", table.Name, table.Label).WithExtra(
+		crud.Permission{Key: "dynamic:" + table.Name + ":import", Label: "导入"},
+		crud.Permission{Key: "dynamic:" + table.Name + ":export", Label: "导出"},
+	)
+}
+
+// syncPermissions 按当前定义刷新权限树，停用的表只移除权限节点，保留角色授权以便重新启用。
+func (h *DynamicTableHandler) syncPermissions(db *gorm.DB, table *model.DynamicTable) {
+	perms := dynamicDataPerms(table)
+	crud.RemovePermissions(perms.Menu)
+	if table.Enabled {
+		crud.AddPermissions(PermDynamicData, perms.Tree)
+	}
+	// 超级管理员的权限列表来自权限树，需要失效缓存才能看到新表。
+	h.invalidateSuperAdminCache(db)
+}
+
+// cleanup 删除定义前清理菜单，以及角色授权、拒绝项和访问令牌范围中该表的权限，物理表与数据保留，需要时由 DBA 手动清理。
+// 同名表重建后沿用原物理表，旧授权不会随之恢复。
+func (h *DynamicTableHandler) cleanup(tx *gorm.DB, ids []uint) error {
+	var tables []model.DynamicTable
+	if err := tx.Where("id IN ?", ids).Find(&tables).Error; err != nil {
+		return err
+	}
+
+	menuIDs := make([]uint, 0, len(tables))
+	keys := make([]string, 0, len(tables)*7)
+	for i := range tables {
+		menuIDs = append(menuIDs, tables[i].MenuID)
+		keys = append(keys, permissionKeys(dynamicDataPerms(&tables[i]).Tree)...)
+	}
+	if len(menuIDs) > 0 {
+		if err := tx.Where("id IN ?", menuIDs).Delete(&model.Menu{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := tx.Where("permission IN ?", keys).Delete(&model.AdminRolePermission{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("permission IN ?", keys).Delete(&model.AdminRoleDeny{}).Error; err != nil {
+		return err
+	}
+	return service.RemoveAPITokenPermissions(tx, keys)
+}
+
+// removePermissions 删除事务提交后移除权限树节点并失效权限缓存。
+// 授权已随事务删除，无法再定位受影响的角色，删除动态表属低频操作，直接失效全部角色用户的缓存。
+func (h *DynamicTableHandler) removePermissions(tables []model.DynamicTable) {
+	menuKeys := make([]string, 0, len(tables))
+	for i := range tables {
+		menuKeys = append(menuKeys, dynamicDataPerms(&tables[i]).Menu)
+	}
+	crud.RemovePermissions(menuKeys...)
+	if h.cacheInvalidator == nil {
+		return
+	}
+	var roleIDs []uint
+	if err := h.DB.Model(&model.AdminRole{}).Pluck("id", &roleIDs).Error; err != nil {
+		return
+	}
+	h.cacheInvalidator.InvalidateRolesUsersPermissionCache(roleIDs)
+}
+
+// invalidateSuperAdminCache 失效超级管理员用户的权限缓存。
+func (h *DynamicTableHandler) invalidateSuperAdminCache(db *gorm.DB) {
+	if h.cacheInvalidator == nil {
+		return
+	}
+	var role model.AdminRole
+	if err := db.Select("id").Where("code = ?", model.SuperAdminRoleCode).First(&role).Error; err != nil {
+		return
+	}
+	h.cacheInvalidator.InvalidateRoleUsersPermissionCache(role.ID)
+}
+
+// permissionKeys 展开权限树中的全部 key。
+func permissionKeys(perms []crud.Permission) []string {
+	keys := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		keys = append(keys, perm.Key)
+		keys = append(keys, permissionKeys(perm.Children)...)
+	}
+	return keys
+}
+
+// menuStatus 将启用状态转换为菜单状态。
+func menuStatus(enabled bool) int8 {
+	if enabled {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// recordingChecker 记录请求时校验的权限 key，并全部放行。
+type recordingChecker struct {
+	checked []string
+}
+
+func (r *recordingChecker) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r.checked = append(r.checked, permission)
+		c.Next()
+	}
+}
+
+// requestJSON 发送 JSON 请求并解析统一响应。
+func requestJSON(engine *gin.Engine, method, path, body string) map[string]interface{} {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	var resp map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp
+}
+
+// doJSON 发送 JSON 请求并断言业务成功。
+func doJSON(t *testing.T, engine *gin.Engine, method, path, body string) map[string]interface{} {
+	t.Helper()
+	resp := requestJSON(engine, method, path, body)
+	if resp["code"] != float64(0) {
+		t.Fatalf("%s %s 返回错误: %v", method, path, resp)
+	}
+	return resp
+}
+
+// TestDynamicTableLifecycle 验证动态表从定义、数据读写到删除的完整流程。
+func TestDynamicTableLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Menu{}, &model.AdminRole{}, &model.AdminRolePermission{}, &model.AdminRoleDeny{}, &model.AdminAPIToken{}, &model.DynamicTable{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	crud.SetBasePermissions([]crud.Permission{{Key: PermDynamicData, Label: "动态数据"}})
+
+	checker := &recordingChecker{}
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, checker, nil)
+	router.RegisterModule(&engine.RouterGroup, NewDynamicTableHandler(db, nil))
+	router.RegisterModule(&engine.RouterGroup, NewDynamicDataHandler(db))
+
+	doJSON(t, engine, http.MethodPost, "/dynamic-tables", `{
+		"name": "feedback", "label": "意见反馈",
+		"fields": [{"name": "title", "label": "标题", "type": "string", "required": true, "filter": "like"}]
+	}`)
+	if !db.Migrator().HasTable("dyn_feedback") {
+		t.Fatalf("期望创建物理表 dyn_feedback")
+	}
+	var table model.DynamicTable
+	if err := db.Where("name = ?", "feedback").First(&table).Error; err != nil || table.MenuID == 0 {
+		t.Fatalf("动态表定义或菜单未保存: %+v err=%v", table, err)
+	}
+	if !containsKey(crud.GetAllPermissionKeys(), "dynamic:feedback:import") {
+		t.Fatalf("期望注册动态表权限: %v", crud.GetAllPermissionKeys())
+	}
+
+	// 新增字段后直接可用，无需重启。
+	doJSON(t, engine, http.MethodPut, "/dynamic-tables/1", `{
+		"fields": [
+			{"name": "title", "label": "标题", "type": "string", "required": true, "filter": "like"},
+			{"name": "score", "label": "评分", "type": "int"}
+		]
+	}`)
+	doJSON(t, engine, http.MethodPost, "/dynamic-data/feedback", `{"title": "加载慢", "score": 3}`)
+	resp := doJSON(t, engine, http.MethodGet, "/dynamic-data/feedback?title=慢", "")
+	data := resp["data"].(map[string]interface{})
+	if data["total"] != float64(1) {
+		t.Fatalf("期望查询到 1 条记录: %v", data)
+	}
+	item := data["list"].([]interface{})[0].(map[string]interface{})
+	if item["score"] != float64(3) {
+		t.Fatalf("新增字段未生效: %v", item)
+	}
+	if !containsKey(checker.checked, "dynamic:feedback:list") {
+		t.Fatalf("权限模板未按表名展开: %v", checker.checked)
+	}
+
+	// 删除定义时一并清理角色授权、拒绝项与访问令牌范围，重建同名表不会恢复旧授权。
+	db.Create(&model.AdminRolePermission{RoleID: 1, Permission: "dynamic:feedback:list"})
+	db.Create(&model.AdminRoleDeny{RoleID: 1, Permission: "dynamic:feedback:delete"})
+	scoped := model.AdminAPIToken{UserID: 1, Name: "feedback", TokenHash: "h1", Permissions: []string{"dynamic:feedback:list"}}
+	mixed := model.AdminAPIToken{UserID: 1, Name: "mixed", TokenHash: "h2", Permissions: []string{"dynamic:feedback:list", "system:menu:list"}}
+	db.Create(&scoped)
+	db.Create(&mixed)
+
+	doJSON(t, engine, http.MethodDelete, "/dynamic-tables/1", "")
+	var grants, denies, scopedCount int64
+	db.Model(&model.AdminRolePermission{}).Count(&grants)
+	db.Model(&model.AdminRoleDeny{}).Count(&denies)
+	db.Model(&model.AdminAPIToken{}).Where("id = ?", scoped.ID).Count(&scopedCount)
+	if grants != 0 || denies != 0 {
+		t.Fatalf("删除动态表后应清理授权与拒绝项: grants=%d denies=%d", grants, denies)
+	}
+	if scopedCount != 0 {
+		t.Fatal("范围只含该表权限的访问令牌应被删除，避免退化为继承全部权限")
+	}
+	db.First(&mixed, mixed.ID)
+	if len(mixed.Permissions) != 1 || mixed.Permissions[0] != "system:menu:list" {
+		t.Fatalf("访问令牌应只移除该表的权限: %v", mixed.Permissions)
+	}
+	if containsKey(crud.GetAllPermissionKeys(), "dynamic:feedback:list") {
+		t.Fatalf("删除动态表后权限未移除")
+	}
+	var menuCount int64
+	db.Model(&model.Menu{}).Count(&menuCount)
+	if menuCount != 0 {
+		t.Fatalf("删除动态表后菜单未清理")
+	}
+	// 物理表保留，避免误删数据。
+	if !db.Migrator().HasTable("dyn_feedback") {
+		t.Fatalf("删除定义不应删除物理表")
+	}
+}
+
+func containsKey(keys []string, target string) bool {
+	for _, key := range keys {
+		if key == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"bico-admin/internal/pkg/graphql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler GraphQL 处理器
+type GraphQLHandler struct {
+	executor *graphql.Executor
+}
+
+// NewGraphQLHandler 创建 GraphQL 处理器
+func NewGraphQLHandler(executor *graphql.Executor) *GraphQLHandler {
+	return &GraphQLHandler{executor: executor}
+}
+
+// Query 执行 GraphQL 查询
+// @Summary GraphQL 查询
+// @Description 基于已注册 CRUD 模块生成的 GraphQL 接口，字段级复用 REST 路由的权限 key
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body graphqlRequest true "GraphQL 请求"
+// @Success 200 {object} graphqlResponse
+// @Router /graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphql.Request
+	// GET 请求从 query string 读取，便于调试工具直接访问。
+	if c.Request.Method == http.MethodGet {
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, graphqlError("参数错误: "+err.Error()))
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, graphqlError("参数错误: "+err.Error()))
+		return
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, graphqlError("query 不能为空"))
+		return
+	}
+	// 变更只允许 POST，避免被链接或预加载触发。
+	if c.Request.Method == http.MethodGet && req.IsMutation() {
+		c.Header("Allow", http.MethodPost)
+		c.JSON(http.StatusMethodNotAllowed, graphqlError("mutation 仅支持 POST 请求"))
+		return
+	}
+
+	// GraphQL 客户端依赖标准的 {data, errors} 结构，这里不套用统一响应包装。
+	c.JSON(http.StatusOK, h.executor.Execute(c, &req))
+}
+
+// graphqlError 生成符合 GraphQL 规范的错误响应。
+func graphqlError(msg string) gin.H {
+	return gin.H{"errors": []gin.H{{"message": msg}}}
+}
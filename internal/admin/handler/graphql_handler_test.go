@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/graphql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestGraphQLRejectsMutationOverGet 验证 GET 请求只能执行查询，变更必须使用 POST。
+func TestGraphQLRejectsMutationOverGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminDepartment{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	executor, err := graphql.NewExecutor([]crud.Module{NewDepartmentHandler(db)}, &recordingChecker{})
+	if err != nil {
+		t.Fatalf("生成 schema 失败: %v", err)
+	}
+	engine := gin.New()
+	engine.GET("/graphql", NewGraphQLHandler(executor).Query)
+
+	get := func(query, operationName string) int {
+		params := url.Values{"query": {query}, "operationName": {operationName}}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil))
+		return w.Code
+	}
+	if code := get(`{ __typename }`, ""); code != http.StatusOK {
+		t.Fatalf("GET 查询应成功，实际状态码 %d", code)
+	}
+	if code := get(`mutation { deleteDepartment(id: 1) }`, ""); code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET 变更应返回 405，实际状态码 %d", code)
+	}
+	doc := `query q { __typename } mutation m { deleteDepartment(id: 1) }`
+	if code := get(doc, "m"); code != http.StatusMethodNotAllowed {
+		t.Fatalf("按 operationName 选中的变更同样应拒绝，实际状态码 %d", code)
+	}
+	if code := get(doc, "q"); code != http.StatusOK {
+		t.Fatalf("按 operationName 选中的查询应成功，实际状态码 %d", code)
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 权限定义
+var ipRulePerms = crud.NewCRUDPerms("system", "ip_rule", "IP 访问控制")
+
+// clientIPContextKey 保存规则的操作者 IP，事务内据此检查保存后是否仍能访问后台。
+type clientIPContextKey struct{}
+
+// IPRuleHandler IP 访问规则处理器。
+//
+// 规则变更后通知访问控制服务重新加载；变更后操作者当前 IP 无法访问时回滚，避免把自己拒之门外。
+type IPRuleHandler struct {
+	crud.CRUDHandler[model.AdminIPRule, ipRuleListReq, createIPRuleReq, updateIPRuleReq]
+	access *service.IPAccessService
+}
+
+// NewIPRuleHandler 创建 IP 访问规则处理器，access 为 nil 时只维护规则不触发重新加载。
+func NewIPRuleHandler(db *gorm.DB, access *service.IPAccessService) *IPRuleHandler {
+	h := &IPRuleHandler{access: access}
+	h.DB = db
+	h.NotFoundMsg = "规则不存在"
+
+	h.BuildListQuery = func(db *gorm.DB, req *ipRuleListReq) *gorm.DB {
+		query := db.Model(&model.AdminIPRule{})
+		if req.CIDR != "" {
+			query = query.Where("cidr LIKE ?", req.CIDR+"%")
+		}
+		if req.Action != "" {
+			query = query.Where("action = ?", req.Action)
+		}
+		if req.Enabled != nil {
+			query = query.Where("enabled = ?", *req.Enabled)
+		}
+		return query
+	}
+
+	h.NewModelFromCreate = func(req *createIPRuleReq) (*model.AdminIPRule, error) {
+		cidr, err := service.NormalizeIPRule(req.CIDR)
+		if err != nil {
+			return nil, err
+		}
+		return &model.AdminIPRule{
+			CIDR:    cidr,
+			Action:  req.Action,
+			Remark:  strings.TrimSpace(req.Remark),
+			Enabled: req.Enabled == nil || *req.Enabled,
+		}, nil
+	}
+
+	h.BuildUpdates = func(req *updateIPRuleReq, existing *model.AdminIPRule) (map[string]interface{}, error) {
+		updates := map[string]interface{}{}
+		if req.CIDR != "" {
+			cidr, err := service.NormalizeIPRule(req.CIDR)
+			if err != nil {
+				return nil, err
+			}
+			updates["cidr"] = cidr
+		}
+		if req.Action != "" {
+			updates["action"] = req.Action
+		}
+		if req.Remark != nil {
+			updates["remark"] = strings.TrimSpace(*req.Remark)
+		}
+		if req.Enabled != nil {
+			updates["enabled"] = *req.Enabled
+		}
+		return updates, nil
+	}
+
+	h.AfterCreate = func(tx *gorm.DB, item *model.AdminIPRule, req *createIPRuleReq) error {
+		return ensureClientReachable(tx)
+	}
+	h.AfterUpdate = func(tx *gorm.DB, id uint, existing *model.AdminIPRule, req *updateIPRuleReq) error {
+		return ensureClientReachable(tx)
+	}
+	h.AfterDelete = func(tx *gorm.DB, id uint) error {
+		return ensureClientReachable(tx)
+	}
+	h.AfterDeleteBatch = func(tx *gorm.DB, ids []uint) error {
+		return ensureClientReachable(tx)
+	}
+
+	return h
+}
+
+func (h *IPRuleHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:             "ip_rule",
+		Group:            "/ip-rules",
+		Description:      "IP 访问控制",
+		ParentPermission: PermSystemManage,
+		Permissions:      ipRulePerms.Tree,
+		Routes:           ipRulePerms.Routes(),
+		Swagger: crud.SwaggerConfig{
+			Model:         model.AdminIPRule{},
+			ListRequest:   ipRuleListReq{},
+			CreateRequest: createIPRuleReq{},
+			UpdateRequest: updateIPRuleReq{},
+		},
+	}
+}
+
+// 请求结构
+//
+// cidr 可填写单个 IP（如 203.0.113.7）或网段（如 10.0.0.0/8），保存时统一为规范形式。
+type (
+	ipRuleListReq struct {
+		CIDR    string `form:"cidr"`
+		Action  string `form:"action"`
+		Enabled *bool  `form:"enabled"`
+	}
+	createIPRuleReq struct {
+		CIDR    string `json:"cidr" binding:"required,max=64"`
+		Action  string `json:"action" binding:"required,oneof=allow deny"`
+		Remark  string `json:"remark" binding:"max=255"`
+		Enabled *bool  `json:"enabled"`
+	}
+	updateIPRuleReq struct {
+		CIDR    string  `json:"cidr" binding:"max=64"`
+		Action  string  `json:"action" binding:"omitempty,oneof=allow deny"`
+		Remark  *string `json:"remark" binding:"omitempty,max=255"`
+		Enabled *bool   `json:"enabled"`
+	}
+)
+
+// Create 创建规则，提交后重新加载规则。
+func (h *IPRuleHandler) Create(c *gin.Context) {
+	defer h.reload()
+	h.withClientIP(c).Create(c)
+}
+
+// Update 更新规则，提交后重新加载规则。
+func (h *IPRuleHandler) Update(c *gin.Context) {
+	defer h.reload()
+	h.withClientIP(c).Update(c)
+}
+
+// Delete 删除规则，提交后重新加载规则。
+func (h *IPRuleHandler) Delete(c *gin.Context) {
+	defer h.reload()
+	h.withClientIP(c).Delete(c)
+}
+
+// DeleteBatch 批量删除规则，提交后重新加载规则。
+func (h *IPRuleHandler) DeleteBatch(c *gin.Context) {
+	defer h.reload()
+	h.withClientIP(c).DeleteBatch(c)
+}
+
+// withClientIP 返回数据库上下文携带操作者 IP 的处理器副本，事务内的钩子由此取得请求来源。
+func (h *IPRuleHandler) withClientIP(c *gin.Context) *crud.CRUDHandler[model.AdminIPRule, ipRuleListReq, createIPRuleReq, updateIPRuleReq] {
+	handler := h.CRUDHandler
+	handler.DB = h.DB.WithContext(context.WithValue(c.Request.Context(), clientIPContextKey{}, c.ClientIP()))
+	return &handler
+}
+
+// reload 通知访问控制服务规则已变更，失败的请求同样触发，重新加载不影响结果。
+func (h *IPRuleHandler) reload() {
+	if h.access != nil {
+		h.access.Invalidate()
+	}
+}
+
+// ensureClientReachable 检查变更后的规则是否仍允许操作者当前 IP，否则回滚。
+func ensureClientReachable(tx *gorm.DB) error {
+	ip, _ := tx.Statement.Context.Value(clientIPContextKey{}).(string)
+	if ip == "" {
+		return nil
+	}
+	var rules []model.AdminIPRule
+	if err := tx.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return err
+	}
+	if !service.IPRulesAllow(rules, ip) {
+		return fmt.Errorf("保存后当前 IP %s 将无法访问后台，请先添加允许该 IP 的规则", ip)
+	}
+	return nil
+}
+
+var _ crud.Module = (*IPRuleHandler)(nil)
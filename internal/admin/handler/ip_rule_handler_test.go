@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestIPRuleLockoutProtection 验证规则保存后立即生效，且不允许保存会拒绝操作者当前 IP 的变更。
+func TestIPRuleLockoutProtection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminIPRule{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	access := service.NewIPAccessService(db, memoryCache)
+	if err := access.Load(); err != nil {
+		t.Fatalf("加载规则失败: %v", err)
+	}
+
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewIPRuleHandler(db, access))
+	router.RegisterModule(&engine.RouterGroup, NewAdminRoleHandler(db, nil))
+	// httptest 请求的来源地址固定为 192.0.2.1。
+	expectLockout := func(method, path, body string) {
+		t.Helper()
+		resp := requestJSON(engine, method, path, body)
+		if resp["code"] == float64(0) || !strings.Contains(resp["msg"].(string), "192.0.2.1") {
+			t.Fatalf("%s %s 应因拒绝当前 IP 而失败，实际: %v", method, path, resp)
+		}
+	}
+
+	expectLockout(http.MethodPost, "/ip-rules", `{"cidr": "10.0.0.0/8", "action": "allow"}`)
+	office := doJSON(t, engine, http.MethodPost, "/ip-rules", `{"cidr": " 192.0.2.7/24 ", "action": "allow", "remark": "办公网"}`)
+	if cidr := office["data"].(map[string]interface{})["cidr"]; cidr != "192.0.2.0/24" {
+		t.Fatalf("网段应规范为网络地址，实际 %v", cidr)
+	}
+	doJSON(t, engine, http.MethodPost, "/ip-rules", `{"cidr": "10.0.0.0/8", "action": "allow"}`)
+	expectLockout(http.MethodPost, "/ip-rules", `{"cidr": "192.0.2.1", "action": "deny"}`)
+	expectLockout(http.MethodDelete, "/ip-rules/1", "")
+	if resp := requestJSON(engine, http.MethodPost, "/ip-rules", `{"cidr": "10.0.0.300", "action": "allow"}`); resp["code"] == float64(0) {
+		t.Fatal("非法地址不应保存")
+	}
+
+	for ip, want := range map[string]bool{"192.0.2.99": true, "10.1.2.3": true, "198.51.100.1": false, "": false} {
+		if got := access.Allowed(ip); got != want {
+			t.Fatalf("IP %q 期望 allowed=%v，实际 %v", ip, want, got)
+		}
+	}
+	doJSON(t, engine, http.MethodPost, "/ip-rules", `{"cidr": "10.9.0.0/16", "action": "deny"}`)
+	if access.Allowed("10.9.1.1") {
+		t.Fatal("拒绝规则应优先于允许规则")
+	}
+
+	role := model.AdminRole{Name: "运维", Code: "role_ops", Enabled: true}
+	db.Create(&role)
+	doJSON(t, engine, http.MethodPut, "/admin-roles/1", `{"allowed_ips": ["10.0.0.1", "172.16.0.0/12"]}`)
+	db.First(&role, role.ID)
+	if len(role.AllowedIPs) != 2 || role.AllowedIPs[1] != "172.16.0.0/12" {
+		t.Fatalf("角色来源限制未保存: %v", role.AllowedIPs)
+	}
+	doJSON(t, engine, http.MethodPut, "/admin-roles/1", `{"allowed_ips": []}`)
+	db.First(&role, role.ID)
+	if len(role.AllowedIPs) != 0 {
+		t.Fatalf("传空数组应取消角色来源限制: %v", role.AllowedIPs)
+	}
+}
@@ -0,0 +1,524 @@
+package handler
+
+import (
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/pagination"
+	"bico-admin/internal/pkg/response"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListViewHandler 列表视图处理器。
+//
+// 说明：
+// - 视图按 crud.ModuleConfig.Name 区分模块，保存筛选、排序、可见列和分页大小
+// - 未配置共享角色的视图为私有视图，配置后拥有这些角色的用户可以使用
+// - 只有创建者可以修改或删除视图
+// - 默认视图优先取自己的，其次取共享给自己的
+type ListViewHandler struct {
+	crud.BaseHandler
+	db *gorm.DB
+}
+
+// NewListViewHandler 创建列表视图处理器。
+func NewListViewHandler(db *gorm.DB) *ListViewHandler {
+	return &ListViewHandler{db: db}
+}
+
+func (h *ListViewHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:        "list_view",
+		Group:       "/views",
+		Description: "列表视图",
+		Routes: []crud.Route{
+			crud.AuthRoute("GET", "", "List"),
+			crud.AuthRoute("GET", "/default", "Default"),
+			crud.AuthRoute("GET", "/:id", "Get"),
+			crud.AuthRoute("POST", "", "Create"),
+			crud.AuthRoute("PUT", "/:id", "Update"),
+			crud.AuthRoute("DELETE", "/:id", "Delete"),
+		},
+	}
+}
+
+// 请求结构
+type (
+	listViewQueryReq struct {
+		Module string `form:"module" binding:"required"`
+	}
+	createListViewReq struct {
+		Module    string            `json:"module" binding:"required"`
+		Name      string            `json:"name" binding:"required"`
+		Filters   map[string]string `json:"filters"`
+		SortField string            `json:"sort_field"`
+		SortOrder string            `json:"sort_order"`
+		Columns   []string          `json:"columns"`
+		PageSize  int               `json:"page_size"`
+		IsDefault bool              `json:"is_default"`
+		RoleIDs   []uint            `json:"role_ids"`
+	}
+	// updateListViewReq 字段为空表示不修改，role_ids 传空数组表示取消共享。
+	updateListViewReq struct {
+		Name      *string           `json:"name"`
+		Filters   map[string]string `json:"filters"`
+		SortField *string           `json:"sort_field"`
+		SortOrder *string           `json:"sort_order"`
+		Columns   []string          `json:"columns"`
+		PageSize  *int              `json:"page_size"`
+		IsDefault *bool             `json:"is_default"`
+		RoleIDs   []uint            `json:"role_ids"`
+	}
+)
+
+// List 获取可用视图。
+// @Summary 获取列表视图
+// @Description 获取当前用户在指定模块下自己创建和共享给自己的视图
+// @Tags 列表视图
+// @Produce json
+// @Security BearerAuth
+// @Param module query string true "模块名，即 crud.ModuleConfig.Name"
+// @Success 200 {object} adminResponse{data=[]listViewDocItem}
+// @Router /views [get]
+func (h *ListViewHandler) List(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req listViewQueryReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	query, err := h.accessible(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	var views []model.ListView
+	if err := query.Where("module = ?", req.Module).Order("id ASC").Find(&views).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if err := h.fill(views, userID); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, views)
+}
+
+// Default 获取默认视图。
+// @Summary 获取默认列表视图
+// @Description 优先返回自己的默认视图，其次返回共享给自己的默认视图，都没有时 data 为 null
+// @Tags 列表视图
+// @Produce json
+// @Security BearerAuth
+// @Param module query string true "模块名，即 crud.ModuleConfig.Name"
+// @Success 200 {object} adminResponse{data=listViewDocItem}
+// @Router /views/default [get]
+func (h *ListViewHandler) Default(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req listViewQueryReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+
+	query, err := h.accessible(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	var views []model.ListView
+	// 自己的默认视图排在共享视图之前。
+	if err := query.Where("module = ? AND is_default = ?", req.Module, true).
+		Order(gorm.Expr("CASE WHEN user_id = ? THEN 0 ELSE 1 END, id ASC", userID)).
+		Limit(1).Find(&views).Error; err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if len(views) == 0 {
+		h.Success(c, nil)
+		return
+	}
+	if err := h.fill(views, userID); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, views[0])
+}
+
+// Get 获取视图详情。
+// @Summary 获取列表视图详情
+// @Tags 列表视图
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图 ID"
+// @Success 200 {object} adminResponse{data=listViewDocItem}
+// @Router /views/{id} [get]
+func (h *ListViewHandler) Get(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	query, err := h.accessible(userID)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	var view model.ListView
+	if !h.QueryOne(c, query.Where("id = ?", id), &view, crud.ErrListViewNotFound.Error()) {
+		return
+	}
+	views := []model.ListView{view}
+	if err := h.fill(views, userID); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	h.Success(c, views[0])
+}
+
+// Create 创建视图。
+// @Summary 创建列表视图
+// @Tags 列表视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body createListViewReq true "视图参数"
+// @Success 200 {object} adminResponse{data=listViewDocItem}
+// @Router /views [post]
+func (h *ListViewHandler) Create(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req createListViewReq
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	view := &model.ListView{
+		UserID:    userID,
+		Module:    req.Module,
+		Name:      strings.TrimSpace(req.Name),
+		Filters:   req.Filters,
+		SortField: req.SortField,
+		SortOrder: req.SortOrder,
+		Columns:   req.Columns,
+		PageSize:  req.PageSize,
+		IsDefault: req.IsDefault,
+		RoleIDs:   crud.UniqueUints(req.RoleIDs),
+		Owned:     true,
+	}
+	if !crud.IsModuleRegistered(view.Module) {
+		h.Error(c, "模块不存在")
+		return
+	}
+	if err := h.validate(view); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.ExecTx(c, h.db, func(tx *gorm.DB) error {
+		if err := h.checkName(tx, view); err != nil {
+			return err
+		}
+		if err := tx.Create(view).Error; err != nil {
+			return err
+		}
+		if err := h.saveRoles(tx, view.ID, view.RoleIDs); err != nil {
+			return err
+		}
+		return h.resetDefault(tx, view)
+	}, "创建成功", view)
+}
+
+// Update 更新视图。
+// @Summary 更新列表视图
+// @Description 只有创建者可以修改
+// @Tags 列表视图
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图 ID"
+// @Param body body updateListViewReq true "视图参数"
+// @Success 200 {object} adminResponse{data=listViewDocItem}
+// @Router /views/{id} [put]
+func (h *ListViewHandler) Update(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+	var req updateListViewReq
+	if err := h.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	var view model.ListView
+	if !h.QueryOne(c, h.db.Where("id = ? AND user_id = ?", id, userID), &view, crud.ErrListViewNotFound.Error()) {
+		return
+	}
+	if req.Name != nil {
+		view.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Filters != nil {
+		view.Filters = req.Filters
+	}
+	if req.SortField != nil {
+		view.SortField = *req.SortField
+	}
+	if req.SortOrder != nil {
+		view.SortOrder = *req.SortOrder
+	}
+	if req.Columns != nil {
+		view.Columns = req.Columns
+	}
+	if req.PageSize != nil {
+		view.PageSize = *req.PageSize
+	}
+	if req.IsDefault != nil {
+		view.IsDefault = *req.IsDefault
+	}
+	if err := h.validate(&view); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+
+	h.ExecTx(c, h.db, func(tx *gorm.DB) error {
+		if err := h.checkName(tx, &view); err != nil {
+			return err
+		}
+		// 整条保存，确保 json 字段走序列化器。
+		if err := tx.Save(&view).Error; err != nil {
+			return err
+		}
+		if req.RoleIDs != nil {
+			if err := tx.Where("view_id = ?", view.ID).Delete(&model.ListViewRole{}).Error; err != nil {
+				return err
+			}
+			if err := h.saveRoles(tx, view.ID, crud.UniqueUints(req.RoleIDs)); err != nil {
+				return err
+			}
+		}
+		if err := h.resetDefault(tx, &view); err != nil {
+			return err
+		}
+		views := []model.ListView{view}
+		if err := h.fillWith(tx, views, userID); err != nil {
+			return err
+		}
+		view = views[0]
+		return nil
+	}, "更新成功", &view)
+}
+
+// Delete 删除视图。
+// @Summary 删除列表视图
+// @Description 只有创建者可以删除
+// @Tags 列表视图
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "视图 ID"
+// @Success 200 {object} adminResponse
+// @Router /views/{id} [delete]
+func (h *ListViewHandler) Delete(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	id, err := h.ParseID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.ListView{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("view_id = ?", id).Delete(&model.ListViewRole{}).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			h.NotFound(c, crud.ErrListViewNotFound.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// NewListViewResolver 创建 CRUDHandler.List 使用的视图读取函数，只能读取当前用户可用的视图。
+func NewListViewResolver(db *gorm.DB) crud.ListViewResolver {
+	h := NewListViewHandler(db)
+	return func(c *gin.Context, module string, id uint) (*crud.ListView, error) {
+		userID, _ := c.Get("user_id")
+		uid, ok := userID.(uint)
+		if !ok {
+			return nil, crud.ErrListViewNotFound
+		}
+		query, err := h.accessible(uid)
+		if err != nil {
+			return nil, err
+		}
+		var view model.ListView
+		if err := query.Where("id = ? AND module = ?", id, module).First(&view).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, crud.ErrListViewNotFound
+			}
+			return nil, err
+		}
+		return &crud.ListView{
+			Filters:   view.Filters,
+			SortField: view.SortField,
+			SortOrder: view.SortOrder,
+			PageSize:  view.PageSize,
+		}, nil
+	}
+}
+
+// accessible 返回当前用户可用视图的查询：自己创建的，或共享给自己任一启用角色的。
+func (h *ListViewHandler) accessible(userID uint) (*gorm.DB, error) {
+	var roleIDs []uint
+	if err := h.db.Table("admin_user_roles").
+		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
+		Where("admin_user_roles.user_id = ? AND admin_roles.enabled = ?", userID, true).
+		Scopes(service.ActiveUserRoles(time.Now())).
+		Pluck("admin_user_roles.role_id", &roleIDs).Error; err != nil {
+		return nil, err
+	}
+
+	query := h.db.Model(&model.ListView{})
+	if len(roleIDs) == 0 {
+		return query.Where("user_id = ?", userID), nil
+	}
+	shared := h.db.Model(&model.ListViewRole{}).Select("view_id").Where("role_id IN ?", roleIDs)
+	return query.Where("user_id = ? OR id IN (?)", userID, shared), nil
+}
+
+// validate 校验视图参数。
+func (h *ListViewHandler) validate(view *model.ListView) error {
+	if view.Name == "" {
+		return errors.New("视图名称不能为空")
+	}
+	if view.SortOrder != "" && view.SortOrder != "ascend" && view.SortOrder != "descend" {
+		return errors.New("排序方式只能是 ascend 或 descend")
+	}
+	if view.PageSize < 0 || view.PageSize > pagination.MaxPageSize {
+		return errors.New("每页数量超出范围")
+	}
+	// 视图参数由 List 直接作为 query 使用，view 本身不能嵌套。
+	if _, ok := view.Filters["view"]; ok {
+		return errors.New("筛选条件不能包含 view")
+	}
+	return nil
+}
+
+// checkName 校验同一用户同一模块下视图名称唯一。
+func (h *ListViewHandler) checkName(tx *gorm.DB, view *model.ListView) error {
+	exists, err := crud.Exists(tx, &model.ListView{}, "user_id = ? AND module = ? AND name = ? AND id != ?",
+		view.UserID, view.Module, view.Name, view.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("视图名称已存在")
+	}
+	return nil
+}
+
+// saveRoles 写入视图共享角色。
+func (h *ListViewHandler) saveRoles(tx *gorm.DB, viewID uint, roleIDs []uint) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&model.AdminRole{}).Where("id IN ?", roleIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(roleIDs)) {
+		return errors.New("共享角色不存在")
+	}
+	rows := make([]model.ListViewRole, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		rows = append(rows, model.ListViewRole{ViewID: viewID, RoleID: roleID})
+	}
+	return tx.Create(&rows).Error
+}
+
+// resetDefault 设为默认时取消同一用户同一模块下的其他默认视图。
+func (h *ListViewHandler) resetDefault(tx *gorm.DB, view *model.ListView) error {
+	if !view.IsDefault {
+		return nil
+	}
+	return tx.Model(&model.ListView{}).
+		Where("user_id = ? AND module = ? AND id != ?", view.UserID, view.Module, view.ID).
+		Update("is_default", false).Error
+}
+
+// fill 补齐共享角色与归属标记。
+func (h *ListViewHandler) fill(views []model.ListView, userID uint) error {
+	return h.fillWith(h.db, views, userID)
+}
+
+func (h *ListViewHandler) fillWith(db *gorm.DB, views []model.ListView, userID uint) error {
+	if len(views) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(views))
+	for i := range views {
+		ids = append(ids, views[i].ID)
+	}
+	var rows []model.ListViewRole
+	if err := db.Where("view_id IN ?", ids).Order("role_id ASC").Find(&rows).Error; err != nil {
+		return err
+	}
+	roles := make(map[uint][]uint, len(views))
+	for _, row := range rows {
+		roles[row.ViewID] = append(roles[row.ViewID], row.RoleID)
+	}
+	for i := range views {
+		views[i].RoleIDs = roles[views[i].ID]
+		if views[i].RoleIDs == nil {
+			views[i].RoleIDs = []uint{}
+		}
+		views[i].Owned = views[i].UserID == userID
+	}
+	return nil
+}
+
+// requireUserID 获取当前登录用户 ID，缺失时直接返回 401。
+func requireUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("user_id")
+	// 未登录或上下文缺失时统一返回 401。
+	if !exists || userID == nil {
+		response.ErrorWithCode(c, 401, "未授权")
+		return 0, false
+	}
+	uid, ok := userID.(uint)
+	// user_id 类型异常时按未授权处理，避免 panic。
+	if !ok {
+		response.ErrorWithCode(c, 401, "未授权")
+		return 0, false
+	}
+	return uid, true
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestListViewSharing 验证私有视图、角色共享视图与默认视图的可见范围。
+func TestListViewSharing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.ListView{}, &model.ListViewRole{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	role := model.AdminRole{Name: "运营", Code: "role_ops", Enabled: true}
+	if err := db.Create(&role).Error; err != nil {
+		t.Fatalf("创建测试角色失败: %v", err)
+	}
+	// 用户 2 属于共享角色，用户 3 不属于。
+	if err := db.Create(&model.AdminUserRole{UserID: 2, RoleID: role.ID}).Error; err != nil {
+		t.Fatalf("创建用户角色失败: %v", err)
+	}
+
+	engines := make(map[uint]*gin.Engine)
+	for _, userID := range []uint{1, 2, 3} {
+		uid := userID
+		engine := gin.New()
+		router := crud.NewModuleRouter(func(c *gin.Context) { c.Set("user_id", uid) }, nil, nil)
+		router.RegisterModule(&engine.RouterGroup, NewAdminRoleHandler(db, nil))
+		router.RegisterModule(&engine.RouterGroup, NewListViewHandler(db))
+		engines[uid] = engine
+	}
+
+	doJSON(t, engines[1], http.MethodPost, "/views", `{"module": "admin_role", "name": "私有", "is_default": true}`)
+	doJSON(t, engines[1], http.MethodPost, "/views", `{"module": "admin_role", "name": "启用角色", "filters": {"enabled": "true"}, "is_default": true, "role_ids": [1]}`)
+	doJSON(t, engines[2], http.MethodPost, "/views", `{"module": "admin_role", "name": "我的", "page_size": 20}`)
+
+	// 新的默认视图会取消同一用户的旧默认视图。
+	var defaults int64
+	db.Model(&model.ListView{}).Where("user_id = ? AND is_default = ?", 1, true).Count(&defaults)
+	if defaults != 1 {
+		t.Fatalf("同一模块只能有一个默认视图，实际 %d", defaults)
+	}
+
+	counts := map[uint]int{1: 2, 2: 2, 3: 0}
+	for userID, expected := range counts {
+		resp := doJSON(t, engines[userID], http.MethodGet, "/views?module=admin_role", "")
+		if views := resp["data"].([]interface{}); len(views) != expected {
+			t.Fatalf("用户 %d 期望可见 %d 个视图，实际 %d", userID, expected, len(views))
+		}
+	}
+
+	// 用户 2 自己没有默认视图时使用共享的默认视图。
+	resp := doJSON(t, engines[2], http.MethodGet, "/views/default?module=admin_role", "")
+	if view := resp["data"].(map[string]interface{}); view["name"] != "启用角色" || view["owned"] != false {
+		t.Fatalf("默认视图不正确: %v", view)
+	}
+
+	// 共享视图不能被非创建者修改。
+	if resp := requestJSON(engines[2], http.MethodPut, "/views/2", `{"name": "改名"}`); resp["code"] != float64(404) {
+		t.Fatalf("非创建者修改视图应返回 404: %v", resp)
+	}
+
+	// 列表接口通过 ?view=<id> 应用共享视图，未共享的用户不可用。
+	crud.SetListViewResolver(NewListViewResolver(db))
+	defer crud.SetListViewResolver(nil)
+	doJSON(t, engines[2], http.MethodGet, "/admin-roles?view=2", "")
+	if resp := requestJSON(engines[3], http.MethodGet, "/admin-roles?view=2", ""); resp["code"] != float64(404) {
+		t.Fatalf("未共享的视图应不可用: %v", resp)
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 权限定义
+const (
+	permLoginLockMenu   = "system:login_lock:menu"
+	permLoginLockList   = "system:login_lock:list"
+	permLoginLockUnlock = "system:login_lock:unlock"
+)
+
+// LoginLockHandler 登录锁定处理器。
+//
+// 锁定由认证服务在登录失败时写入，这里只提供查看与提前解锁。
+type LoginLockHandler struct {
+	crud.CRUDHandler[model.AdminLoginLock, loginLockListReq, struct{}, struct{}]
+	lock *service.LoginLockService
+}
+
+// NewLoginLockHandler 创建登录锁定处理器，lock 为 nil 时只能查看不能解锁。
+func NewLoginLockHandler(db *gorm.DB, lock *service.LoginLockService) *LoginLockHandler {
+	h := &LoginLockHandler{lock: lock}
+	h.DB = db
+	h.NotFoundMsg = "锁定记录不存在"
+
+	// 只列出锁定期内的记录，过期记录在下次锁定时覆盖。
+	h.BuildListQuery = func(db *gorm.DB, req *loginLockListReq) *gorm.DB {
+		query := db.Model(&model.AdminLoginLock{}).Where("locked_until > ?", time.Now())
+		if req.Kind != "" {
+			query = query.Where("kind = ?", req.Kind)
+		}
+		if req.Subject != "" {
+			query = query.Where("subject LIKE ?", "%"+req.Subject+"%")
+		}
+		return query
+	}
+	return h
+}
+
+func (h *LoginLockHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:             "login_lock",
+		Group:            "/security/lockouts",
+		Description:      "登录锁定",
+		ParentPermission: PermSystemManage,
+		Permissions: []crud.Permission{{
+			Key:   permLoginLockMenu,
+			Label: "登录锁定",
+			Children: []crud.Permission{
+				{Key: permLoginLockList, Label: "查看列表"},
+				{Key: permLoginLockUnlock, Label: "解锁"},
+			},
+		}},
+		Routes: []crud.Route{
+			{Method: "GET", Path: "", Handler: "List", Permission: permLoginLockList},
+			{Method: "DELETE", Path: "/:username", Handler: "Unlock", Permission: permLoginLockUnlock},
+		},
+		Swagger: crud.SwaggerConfig{
+			Model:       model.AdminLoginLock{},
+			ListRequest: loginLockListReq{},
+		},
+	}
+}
+
+// 请求结构
+type loginLockListReq struct {
+	Kind    string `form:"kind"`
+	Subject string `form:"subject"`
+}
+
+// Unlock 解除登录锁定。
+// @Summary 解除登录锁定
+// @Description 提前解除账号或来源 IP 的登录锁定并清零失败次数；kind=ip 时路径参数为 IP
+// @Tags 登录锁定
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "用户名或 IP"
+// @Param kind query string false "锁定类型：username（默认）/ ip"
+// @Success 200 {object} adminResponse
+// @Router /security/lockouts/{username} [delete]
+func (h *LoginLockHandler) Unlock(c *gin.Context) {
+	kind := c.DefaultQuery("kind", model.LoginLockUsername)
+	if kind != model.LoginLockUsername && kind != model.LoginLockIP {
+		h.Error(c, "锁定类型错误")
+		return
+	}
+	if h.lock == nil {
+		h.Error(c, "登录锁定未启用")
+		return
+	}
+
+	if err := h.lock.Unlock(kind, c.Param("username")); err != nil {
+		if errors.Is(err, service.ErrLoginLockNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	h.SuccessWithMessage(c, "已解除锁定", nil)
+}
+
+var _ crud.Module = (*LoginLockHandler)(nil)
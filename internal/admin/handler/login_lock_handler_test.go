@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestLoginLockList 验证只列出锁定期内的记录，并可按类型解除锁定。
+func TestLoginLockList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminLoginLock{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	now := time.Now()
+	locks := []model.AdminLoginLock{
+		{Kind: model.LoginLockUsername, Subject: "alice", Failures: 5, IP: "10.0.0.1", LockedUntil: now.Add(10 * time.Minute)},
+		{Kind: model.LoginLockIP, Subject: "10.0.0.1", Failures: 20, IP: "10.0.0.1", LockedUntil: now.Add(10 * time.Minute)},
+		{Kind: model.LoginLockUsername, Subject: "bob", Failures: 5, LockedUntil: now.Add(-time.Minute)},
+	}
+	if err := db.Create(&locks).Error; err != nil {
+		t.Fatalf("创建锁定记录失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewLoginLockHandler(db, service.NewLoginLockService(db, memoryCache)))
+
+	resp := doJSON(t, engine, http.MethodGet, "/security/lockouts", "")
+	if total := resp["data"].(map[string]interface{})["total"]; total != float64(2) {
+		t.Fatalf("期望列出 2 条锁定中的记录，实际 %v", total)
+	}
+
+	// 路径参数默认按用户名解除，IP 锁定需指定 kind=ip。
+	if resp := requestJSON(engine, http.MethodDelete, "/security/lockouts/10.0.0.1", ""); resp["code"] != float64(404) {
+		t.Fatalf("未指定类型时不应解除 IP 锁定: %v", resp)
+	}
+	if resp := requestJSON(engine, http.MethodDelete, "/security/lockouts/10.0.0.1?kind=ip", ""); resp["code"] != float64(0) {
+		t.Fatalf("解除 IP 锁定失败: %v", resp)
+	}
+	if resp := requestJSON(engine, http.MethodDelete, "/security/lockouts/bob", ""); resp["code"] != float64(404) {
+		t.Fatalf("已过期的锁定应返回不存在: %v", resp)
+	}
+	if resp := requestJSON(engine, http.MethodDelete, "/security/lockouts/alice", ""); resp["code"] != float64(0) {
+		t.Fatalf("解除账号锁定失败: %v", resp)
+	}
+
+	resp = doJSON(t, engine, http.MethodGet, "/security/lockouts", "")
+	if total := resp["data"].(map[string]interface{})["total"]; total != float64(0) {
+		t.Fatalf("解锁后不应再有锁定记录，实际 %v", total)
+	}
+}
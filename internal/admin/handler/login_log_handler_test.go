@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestLoginLogReadOnly 验证登录日志筛选、导出与只读路由。
+func TestLoginLogReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.LoginLog{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	now := time.Now()
+	logs := []model.LoginLog{
+		{Username: "admin", UserID: 1, Result: model.LoginResultSuccess, IP: "10.0.0.1", CreatedAt: now.Add(-48 * time.Hour)},
+		{Username: "admin", UserID: 1, Result: model.LoginResultFailure, Reason: model.LoginReasonBadPassword, IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{Username: "ghost", Result: model.LoginResultFailure, Reason: model.LoginReasonUserNotFound, IP: "192.168.1.9", CreatedAt: now},
+	}
+	if err := db.Create(&logs).Error; err != nil {
+		t.Fatalf("创建登录日志失败: %v", err)
+	}
+
+	engine := gin.New()
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(&engine.RouterGroup, NewLoginLogHandler(db))
+
+	resp := doJSON(t, engine, http.MethodGet, "/login-logs?result=failure&ip=10.0.0", "")
+	if total := resp["data"].(map[string]interface{})["total"]; total != float64(1) {
+		t.Fatalf("期望筛选出 1 条失败记录，实际 %v", total)
+	}
+	since := url.QueryEscape(now.Add(-2 * time.Hour).Format("2006-01-02 15:04:05"))
+	resp = doJSON(t, engine, http.MethodGet, "/login-logs?start_time="+since, "")
+	if total := resp["data"].(map[string]interface{})["total"]; total != float64(2) {
+		t.Fatalf("期望按时间筛选出 2 条记录，实际 %v", total)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/login-logs/export?username=admin", nil)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Fatalf("导出失败: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// 登录日志不允许通过接口修改。
+	req = httptest.NewRequest(http.MethodDelete, "/login-logs/1", nil)
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("登录日志不应开放删除接口: status=%d", w.Code)
+	}
+}
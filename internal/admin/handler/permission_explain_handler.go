@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 权限定义
+const (
+	permPermissionExplainMenu  = "system:permission_explain:menu"
+	permPermissionExplainQuery = "system:permission_explain:query"
+)
+
+// PermissionExplainHandler 权限排查处理器。
+//
+// 用于回答“某个用户为什么能或不能访问”，判定与权限中间件一致，不会写入或刷新任何缓存。
+type PermissionExplainHandler struct {
+	crud.BaseHandler
+	explainer service.PermissionExplainer
+}
+
+// NewPermissionExplainHandler 创建权限排查处理器，explainer 为 nil 时接口返回未启用。
+func NewPermissionExplainHandler(explainer service.PermissionExplainer) *PermissionExplainHandler {
+	return &PermissionExplainHandler{explainer: explainer}
+}
+
+func (h *PermissionExplainHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:             "permission_explain",
+		Group:            "/security/explain",
+		Description:      "权限排查",
+		ParentPermission: PermSystemManage,
+		Permissions: []crud.Permission{{
+			Key:   permPermissionExplainMenu,
+			Label: "权限排查",
+			Children: []crud.Permission{
+				{Key: permPermissionExplainQuery, Label: "排查"},
+			},
+		}},
+		Routes: []crud.Route{
+			{Method: "GET", Path: "", Handler: "Explain", Permission: permPermissionExplainQuery},
+			{Method: "GET", Path: "/route", Handler: "ExplainRoute", Permission: permPermissionExplainQuery},
+		},
+	}
+}
+
+// 请求结构
+type (
+	explainPermissionReq struct {
+		UserID     uint   `form:"user_id" binding:"required"`
+		Permission string `form:"permission" binding:"required"`
+	}
+	// explainRouteReq path 可省略后台接口前缀，如 /admin-users/1。
+	explainRouteReq struct {
+		UserID uint   `form:"user_id" binding:"required"`
+		Method string `form:"method"`
+		Path   string `form:"path" binding:"required"`
+	}
+)
+
+// Explain 解释用户能否使用权限。
+// @Summary 权限排查
+// @Description 说明用户能否使用指定权限：判定结果与原因、参与计算的角色与授权（含禁用角色和未生效授权）、超级管理员、用户状态与缓存情况
+// @Tags 权限排查
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int true "用户 ID"
+// @Param permission query string true "权限 key，如 system:admin_user:list"
+// @Success 200 {object} adminResponse{data=permissionExplanationDocItem}
+// @Router /security/explain [get]
+func (h *PermissionExplainHandler) Explain(c *gin.Context) {
+	var req explainPermissionReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+	h.explain(c, req.UserID, req.Permission, nil)
+}
+
+// ExplainRoute 解释用户能否访问接口。
+// @Summary 按接口路径排查权限
+// @Description 按请求方法与路径找到模块路由声明的权限后进行排查，路由权限中的 {param} 会替换为路径中的值；只能匹配通过模块注册的路由
+// @Tags 权限排查
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int true "用户 ID"
+// @Param method query string false "请求方法，默认 GET"
+// @Param path query string true "请求路径，如 /admin-api/admin-users/1，可省略 /admin-api 前缀"
+// @Success 200 {object} adminResponse{data=permissionExplanationDocItem}
+// @Router /security/explain/route [get]
+func (h *PermissionExplainHandler) ExplainRoute(c *gin.Context) {
+	var req explainRouteReq
+	if err := h.BindQuery(c, &req); err != nil {
+		return
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	path, _, _ := strings.Cut(req.Path, "?")
+
+	route, ok := crud.MatchRoute(req.Method, path)
+	if !ok {
+		// 兼容省略前缀的路径，前缀取自当前接口所在分组。
+		prefix := strings.TrimSuffix(c.FullPath(), "/security/explain/route")
+		route, ok = crud.MatchRoute(req.Method, prefix+"/"+strings.TrimPrefix(path, "/"))
+	}
+	if !ok {
+		h.Error(c, "未找到匹配的模块路由")
+		return
+	}
+	h.explain(c, req.UserID, route.Permission, &route)
+}
+
+func (h *PermissionExplainHandler) explain(c *gin.Context, userID uint, permission string, route *crud.RouteInfo) {
+	if h.explainer == nil {
+		h.Error(c, "权限排查未启用")
+		return
+	}
+	result, err := h.explainer.ExplainPermission(userID, permission)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.NotFound(c, "用户不存在")
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+	if route != nil {
+		result.Route = route
+		if route.Public {
+			// 公开路由不经过登录与权限校验。
+			result.Allowed = true
+			result.Reason = "公开路由，无需登录"
+			result.Cache.Stale = false
+		}
+	}
+	h.Success(c, result)
+}
+
+var _ crud.Module = (*PermissionExplainHandler)(nil)
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestPermissionExplainRoute 验证按权限 key 与按接口路径排查，包括省略后台接口前缀的路径。
+func TestPermissionExplainRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	role := model.AdminRole{Name: "客服", Code: "support", Enabled: true}
+	db.Create(&role)
+	db.Create(&model.AdminRolePermission{RoleID: role.ID, Permission: "system:admin_user:list"})
+	user := model.AdminUser{Username: "support", Password: "x", Enabled: true}
+	db.Create(&user)
+	db.Create(&model.AdminUserRole{UserID: user.ID, RoleID: role.ID})
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	authSvc := service.NewAuthService(db, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	engine := gin.New()
+	api := engine.Group("/admin-api")
+	router := crud.NewModuleRouter(nil, &recordingChecker{}, nil)
+	router.RegisterModule(api, NewAdminUserHandler(db, nil, nil))
+	router.RegisterModule(api, NewPermissionExplainHandler(authSvc))
+
+	explain := func(query string) map[string]interface{} {
+		t.Helper()
+		return doJSON(t, engine, http.MethodGet, "/admin-api/security/explain"+query, "")["data"].(map[string]interface{})
+	}
+	if got := explain(fmt.Sprintf("?user_id=%d&permission=system:admin_user:list", user.ID)); got["allowed"] != true {
+		t.Fatalf("应允许已授权的权限: %v", got)
+	}
+
+	got := explain(fmt.Sprintf("/route?user_id=%d&method=delete&path=/admin-api/admin-users/5", user.ID))
+	route := got["route"].(map[string]interface{})
+	if got["allowed"] != false || got["permission"] != "system:admin_user:delete" || route["path"] != "/admin-api/admin-users/:id" {
+		t.Fatalf("应按路由声明的权限判定: %v", got)
+	}
+	if got := explain(fmt.Sprintf("/route?user_id=%d&path=admin-users/role-grants/expiring", user.ID)); got["allowed"] != true {
+		t.Fatalf("省略前缀的路径应能匹配: %v", got)
+	}
+
+	if resp := requestJSON(engine, http.MethodGet, fmt.Sprintf("/admin-api/security/explain/route?user_id=%d&path=/not-found", user.ID), ""); resp["code"] == float64(0) {
+		t.Fatal("未注册的路径不应匹配")
+	}
+	if resp := requestJSON(engine, http.MethodGet, "/admin-api/security/explain?user_id=999&permission=system:admin_user:list", ""); resp["code"] != float64(404) {
+		t.Fatalf("用户不存在时应返回 404: %v", resp)
+	}
+}
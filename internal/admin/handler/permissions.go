@@ -4,4 +4,5 @@ package handler
 const (
 	PermDashboardMenu = "dashboard:menu"
 	PermSystemManage  = "system:manage"
+	PermDynamicData   = "dynamic:data"
 )
@@ -9,18 +9,41 @@ type adminResponse struct {
 	Data interface{} `json:"data,omitempty"`
 }
 
-// loginRequest 登录请求参数。
+// loginRequest 登录请求参数，按需验证码模式下未要求验证码时可不传。
 type loginRequest struct {
 	Username    string `json:"username" binding:"required"`
 	Password    string `json:"password" binding:"required"`
+	CaptchaID   string `json:"captchaId"`
+	CaptchaCode string `json:"captchaCode"`
+}
+
+// forgotPasswordRequest 找回密码请求参数，account 为用户名或邮箱。
+type forgotPasswordRequest struct {
+	Account     string `json:"account" binding:"required,max=128"`
+	CaptchaID   string `json:"captchaId" binding:"required"`
+	CaptchaCode string `json:"captchaCode" binding:"required"`
+}
+
+// resetPasswordRequest 重置密码请求参数。
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
 	CaptchaID   string `json:"captchaId" binding:"required"`
 	CaptchaCode string `json:"captchaCode" binding:"required"`
 }
 
-// captchaResponse 验证码响应数据。
+// captchaResponse 验证码响应数据，与 captcha.Challenge 一致。
+//
+// type 为 slider 时 image 为缺口背景图，piece 为拼图块，pieceY 为拼图块顶部位置。
+// 滑块验证码的 captchaCode 提交拼图块横坐标与拖动轨迹的 JSON，格式见 docs/auth-api.md。
 type captchaResponse struct {
-	ID    string `json:"id"`
-	Image string `json:"image"`
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Image  string `json:"image"`
+	Piece  string `json:"piece,omitempty"`
+	PieceY int    `json:"pieceY,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 // uploadResponse 上传响应数据。
@@ -33,6 +56,47 @@ type rolePermissionsResponse struct {
 	Permissions []string `json:"permissions"`
 }
 
+// roleParentsResponse 角色继承响应数据。
+type roleParentsResponse struct {
+	ParentIDs []uint `json:"parent_ids"`
+}
+
+// departmentTreeDocItem 部门树节点，仅用于 Swagger 文档。
+type departmentTreeDocItem struct {
+	ID         uint                    `json:"id"`
+	ParentID   uint                    `json:"parent_id"`
+	Name       string                  `json:"name"`
+	Sort       int                     `json:"sort"`
+	LeaderID   *uint                   `json:"leader_id"`
+	LeaderName string                  `json:"leader_name,omitempty"`
+	Enabled    bool                    `json:"enabled"`
+	Children   []departmentTreeDocItem `json:"children,omitempty"`
+}
+
+// permissionExplanationDocItem 权限排查结果，仅用于 Swagger 文档。
+type permissionExplanationDocItem struct {
+	UserID      uint                         `json:"user_id"`
+	Username    string                       `json:"username"`
+	Permission  string                       `json:"permission"`
+	Allowed     bool                         `json:"allowed"`
+	Reason      string                       `json:"reason"`
+	Registered  bool                         `json:"registered"`
+	UserEnabled bool                         `json:"user_enabled"`
+	SuperAdmin  bool                         `json:"super_admin"`
+	Roles       []service.RoleExplanation    `json:"roles"`
+	Cache       service.PermissionCacheState `json:"cache"`
+	Route       *routeDocItem                `json:"route,omitempty"`
+}
+
+// routeDocItem 匹配到的模块路由，仅用于 Swagger 文档。
+type routeDocItem struct {
+	Module     string `json:"module"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Permission string `json:"permission"`
+	Public     bool   `json:"public"`
+}
+
 // permissionDocItem 权限树节点，仅用于 Swagger 文档。
 type permissionDocItem struct {
 	Key      string              `json:"key"`
@@ -56,11 +120,116 @@ type demoExcelImportResponse struct {
 	Preview [][]string `json:"preview"`
 }
 
+// dynamicPageDoc 动态表分页数据，记录字段由表定义决定。
+type dynamicPageDoc struct {
+	List  []map[string]interface{} `json:"list"`
+	Total int64                    `json:"total"`
+}
+
+// dynamicImportResponse 动态表导入响应数据。
+type dynamicImportResponse struct {
+	Total int `json:"total"`
+}
+
+// deleteBatchDocRequest 批量删除请求参数。
+type deleteBatchDocRequest struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
+
+// listViewDocItem 列表视图，仅用于 Swagger 文档。
+type listViewDocItem struct {
+	ID        uint              `json:"id"`
+	UserID    uint              `json:"user_id"`
+	Module    string            `json:"module"`
+	Name      string            `json:"name"`
+	Filters   map[string]string `json:"filters"`
+	SortField string            `json:"sort_field"`
+	SortOrder string            `json:"sort_order"`
+	Columns   []string          `json:"columns"`
+	PageSize  int               `json:"page_size"`
+	IsDefault bool              `json:"is_default"`
+	RoleIDs   []uint            `json:"role_ids"`
+	Owned     bool              `json:"owned"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
 // appConfigDocResponse 应用配置文档响应。
 type appConfigDocResponse = service.AppConfigResponse
 
 // loginDocResponse 登录文档响应。
 type loginDocResponse = service.LoginResponse
 
+// twoFactorKeyResponse 两步验证绑定密钥响应数据。
+type twoFactorKeyResponse struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+	Image  string `json:"image"`
+}
+
+// recoveryCodesResponse 恢复码响应数据。
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// sessionDocItem 登录会话文档结构。
+type sessionDocItem struct {
+	ID         uint   `json:"id"`
+	UserID     uint   `json:"user_id"`
+	Device     string `json:"device"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+	CreatedAt  string `json:"created_at"`
+	Current    bool   `json:"current"`
+}
+
+// apiTokenDocItem 访问令牌文档结构。
+type apiTokenDocItem struct {
+	ID          uint     `json:"id"`
+	UserID      uint     `json:"user_id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Permissions []string `json:"permissions"`
+	AllowedIPs  []string `json:"allowed_ips"`
+	ExpiresAt   string   `json:"expires_at"`
+	LastUsedAt  string   `json:"last_used_at"`
+	LastUsedIP  string   `json:"last_used_ip"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// apiTokenCreatedDoc 创建访问令牌响应文档，token 为明文令牌，仅返回一次。
+type apiTokenCreatedDoc struct {
+	apiTokenDocItem
+	Token string `json:"token"`
+}
+
+// passkeyDocItem 通行密钥文档结构。
+type passkeyDocItem struct {
+	ID         uint     `json:"id"`
+	UserID     uint     `json:"user_id"`
+	Name       string   `json:"name"`
+	Transports []string `json:"transports"`
+	SignCount  uint32   `json:"sign_count"`
+	LastUsedAt string   `json:"last_used_at"`
+	LastUsedIP string   `json:"last_used_ip"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
 // currentUserDocResponse 当前用户文档响应。
 type currentUserDocResponse = service.UserInfo
+
+// graphqlRequest GraphQL 请求体，仅用于 Swagger 文档。
+type graphqlRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphqlResponse GraphQL 响应体，仅用于 Swagger 文档。
+type graphqlResponse struct {
+	Data   interface{}   `json:"data"`
+	Errors []interface{} `json:"errors,omitempty"`
+}
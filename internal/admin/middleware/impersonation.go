@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DenyImpersonation 拒绝模拟登录会话访问修改密码、两步验证、访问令牌等敏感接口。
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("impersonator_id") != 0 {
+			response.ErrorWithCode(c, 403, service.ErrImpersonationActive.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyAPIToken 拒绝个人访问令牌访问账号资料、会话、凭据与两步验证等接口，这些接口不受令牌权限范围约束。
+func DenyAPIToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("api_token") {
+			response.ErrorWithCode(c, 403, service.ErrAPITokenAccount.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"bico-admin/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPAccessMiddleware 全局 IP 访问控制中间件
+type IPAccessMiddleware struct {
+	checker interface {
+		Allowed(ip string) bool
+	}
+}
+
+// NewIPAccessMiddleware 创建 IP 访问控制中间件
+func NewIPAccessMiddleware(checker interface {
+	Allowed(ip string) bool
+}) *IPAccessMiddleware {
+	return &IPAccessMiddleware{checker: checker}
+}
+
+// Check 拒绝不满足全局 IP 规则的请求，登录等公开接口同样受约束。
+func (m *IPAccessMiddleware) Check() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.checker.Allowed(c.ClientIP()) {
+			response.ErrorWithCode(c, 403, "当前 IP 不允许访问")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
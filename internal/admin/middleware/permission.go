@@ -1,22 +1,26 @@
 package middleware
 
 import (
+	"slices"
+
+	"bico-admin/internal/pkg/permission"
 	"bico-admin/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// PermissionProvider 提供用户编译后的权限集合
+type PermissionProvider interface {
+	GetPermissionSet(userID uint) (*permission.Set, error)
+}
+
 // PermissionMiddleware 权限检查中间件
 type PermissionMiddleware struct {
-	userService interface {
-		GetUserPermissions(userID uint) ([]string, error)
-	}
+	userService PermissionProvider
 }
 
 // NewPermissionMiddleware 创建权限中间件
-func NewPermissionMiddleware(userService interface {
-	GetUserPermissions(userID uint) ([]string, error)
-}) *PermissionMiddleware {
+func NewPermissionMiddleware(userService PermissionProvider) *PermissionMiddleware {
 	return &PermissionMiddleware{
 		userService: userService,
 	}
@@ -24,37 +28,7 @@ func NewPermissionMiddleware(userService interface {
 
 // RequirePermission 要求指定权限
 func (pm *PermissionMiddleware) RequirePermission(permission string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		userID, exists := c.Get("user_id")
-		if !exists {
-			response.ErrorWithCode(c, 401, "未授权")
-			c.Abort()
-			return
-		}
-
-		permissions, err := pm.userService.GetUserPermissions(userID.(uint))
-		if err != nil {
-			response.ErrorWithCode(c, 500, "获取权限失败")
-			c.Abort()
-			return
-		}
-
-		hasPermission := false
-		for _, perm := range permissions {
-			if perm == permission {
-				hasPermission = true
-				break
-			}
-		}
-
-		if !hasPermission {
-			response.ErrorWithCode(c, 403, "无权访问")
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
+	return pm.RequireAnyPermission(permission)
 }
 
 // RequireAnyPermission 要求任意一个权限
@@ -67,7 +41,7 @@ func (pm *PermissionMiddleware) RequireAnyPermission(requiredPermissions ...stri
 			return
 		}
 
-		permissions, err := pm.userService.GetUserPermissions(userID.(uint))
+		set, err := pm.userService.GetPermissionSet(userID.(uint))
 		if err != nil {
 			response.ErrorWithCode(c, 500, "获取权限失败")
 			c.Abort()
@@ -75,14 +49,9 @@ func (pm *PermissionMiddleware) RequireAnyPermission(requiredPermissions ...stri
 		}
 
 		hasPermission := false
-		for _, userPerm := range permissions {
-			for _, reqPerm := range requiredPermissions {
-				if userPerm == reqPerm {
-					hasPermission = true
-					break
-				}
-			}
-			if hasPermission {
+		for _, perm := range requiredPermissions {
+			if allowed(c, set, perm) {
+				hasPermission = true
 				break
 			}
 		}
@@ -96,3 +65,12 @@ func (pm *PermissionMiddleware) RequireAnyPermission(requiredPermissions ...stri
 		c.Next()
 	}
 }
+
+// allowed 判断当前请求是否可使用权限，访问令牌限定了权限范围时还必须位于范围内。
+func allowed(c *gin.Context, set *permission.Set, perm string) bool {
+	if !set.Allows(perm) {
+		return false
+	}
+	scopes := c.GetStringSlice("token_scopes")
+	return len(scopes) == 0 || slices.Contains(scopes, perm)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bico-admin/internal/pkg/permission"
+
+	"github.com/gin-gonic/gin"
+)
+
+// staticPermissions 固定返回权限列表的测试用户服务。
+type staticPermissions []string
+
+func (p staticPermissions) GetPermissionSet(uint) (*permission.Set, error) {
+	return permission.Compile(p, []string{"demo:admin:*"}), nil
+}
+
+// TestRequirePermissionWithTokenScopes 验证访问令牌的权限范围与用户权限取交集。
+func TestRequirePermissionWithTokenScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pm := NewPermissionMiddleware(staticPermissions{"demo:read", "demo:write", "demo:item:*", "demo:admin:*"})
+
+	cases := []struct {
+		name       string
+		scopes     []string
+		permission string
+		allowed    bool
+	}{
+		{"无范围继承用户权限", nil, "demo:write", true},
+		{"范围内权限", []string{"demo:read"}, "demo:read", true},
+		{"范围外权限", []string{"demo:read"}, "demo:write", false},
+		{"范围不能超出用户权限", []string{"demo:delete"}, "demo:delete", false},
+		{"通配符授权", nil, "demo:item:delete", true},
+		{"范围限定通配符授权", []string{"demo:item:list"}, "demo:item:delete", false},
+		{"拒绝项优先", nil, "demo:admin:list", false},
+	}
+	for _, tc := range cases {
+		engine := gin.New()
+		engine.GET("/", func(c *gin.Context) {
+			c.Set("user_id", uint(1))
+			if tc.scopes != nil {
+				c.Set("token_scopes", tc.scopes)
+			}
+		}, pm.RequirePermission(tc.permission), func(c *gin.Context) {
+			c.String(http.StatusOK, "ok")
+		})
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if got := rec.Body.String() == "ok"; got != tc.allowed {
+			t.Fatalf("%s: 期望放行 %v，实际响应 %s", tc.name, tc.allowed, rec.Body.String())
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package model
 
-import "bico-admin/internal/core/model"
+import (
+	"time"
+
+	"bico-admin/internal/core/model"
+)
 
 const SuperAdminRoleCode = "super_admin"
 
 // AdminRole 角色模型
+//
+// AllowedIPs 不为空时，拥有该角色的用户只能从列表中的 IP 或 CIDR 登录与访问后台。
+// 角色可继承多个父角色（ParentIDs）的权限，Denies 中的权限即使被授予也不可用。
 type AdminRole struct {
 	model.BaseModel
-	Name        string   `gorm:"size:64;uniqueIndex;not null" json:"name"`
-	Code        string   `gorm:"size:64;uniqueIndex;not null" json:"-"`
-	System      bool     `gorm:"-" json:"system"`
-	Description string   `gorm:"size:255" json:"description"`
-	Enabled     bool     `gorm:"default:true" json:"enabled"`
-	Permissions []string `gorm:"-" json:"permissions"`
+	Name              string   `gorm:"size:64;uniqueIndex;not null" json:"name"`
+	Code              string   `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	System            bool     `gorm:"-" json:"system"`
+	Description       string   `gorm:"size:255" json:"description"`
+	Enabled           bool     `gorm:"default:true" json:"enabled"`
+	TwoFactorRequired bool     `gorm:"default:false" json:"two_factor_required"`
+	AllowedIPs        []string `gorm:"serializer:json;type:text" json:"allowed_ips"`
+	Permissions       []string `gorm:"-" json:"permissions"`
+	Denies            []string `gorm:"-" json:"denies"`
+	ParentIDs         []uint   `gorm:"-" json:"parent_ids"`
 }
 
 // TableName 指定表名
@@ -32,11 +43,43 @@ func (AdminRolePermission) TableName() string {
 	return "admin_role_permissions"
 }
 
+// AdminRoleDeny 角色拒绝权限，优先于该角色及其继承链上的所有授权。
+// Permission 可以是具体权限或以 :* 结尾的通配符。
+type AdminRoleDeny struct {
+	ID         uint   `gorm:"primarykey" json:"id"`
+	RoleID     uint   `gorm:"not null;index" json:"role_id"`
+	Permission string `gorm:"size:128;not null" json:"permission"`
+}
+
+// TableName 指定表名
+func (AdminRoleDeny) TableName() string {
+	return "admin_role_denies"
+}
+
+// AdminRoleParent 角色继承关系，RoleID 继承 ParentID 的授权与拒绝项。
+type AdminRoleParent struct {
+	ID       uint `gorm:"primarykey" json:"id"`
+	RoleID   uint `gorm:"not null;index:idx_role_parent,unique" json:"role_id"`
+	ParentID uint `gorm:"not null;index:idx_role_parent,unique;index" json:"parent_id"`
+}
+
+// TableName 指定表名
+func (AdminRoleParent) TableName() string {
+	return "admin_role_parents"
+}
+
 // AdminUserRole 用户角色关联表
+//
+// ValidFrom、ValidUntil 为空表示不限，授权只在 [ValidFrom, ValidUntil) 内生效，到期后由定时任务删除。
+// Reason 记录授权原因，GrantedBy 为授权的管理员，0 表示系统或未记录。
 type AdminUserRole struct {
-	ID     uint `gorm:"primarykey" json:"id"`
-	UserID uint `gorm:"not null;index:idx_user_role,unique" json:"user_id"`
-	RoleID uint `gorm:"not null;index:idx_user_role,unique" json:"role_id"`
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index:idx_user_role,unique" json:"user_id"`
+	RoleID     uint       `gorm:"not null;index:idx_user_role,unique" json:"role_id"`
+	ValidFrom  *time.Time `gorm:"index" json:"valid_from"`
+	ValidUntil *time.Time `gorm:"index" json:"valid_until"`
+	Reason     string     `gorm:"size:255" json:"reason"`
+	GrantedBy  uint       `gorm:"not null;default:0" json:"granted_by"`
 }
 
 // TableName 指定表名
@@ -1,20 +1,78 @@
 package model
 
-import "bico-admin/internal/core/model"
+import (
+	"time"
+
+	"bico-admin/internal/core/model"
+)
 
 // AdminUser 后台用户模型
+//
+// MustChangePassword 为 true 时下次登录必须先修改密码，PasswordChangedAt 用于判断密码是否过期。
+// Email 用于接收找回密码邮件，由管理员维护，统一保存为小写。
+// AllowedIPs 为空时不限制来源，否则只能从列表中的 IP 或 CIDR 登录与访问后台。
+// DepartmentIDs 与 PrimaryDepartmentID 来自 admin_user_departments，RoleGrants 为带有效期的角色授权，均仅在用户管理接口中填充。
 type AdminUser struct {
 	model.BaseModel
-	Username     string       `gorm:"size:64;uniqueIndex;not null" json:"username"`
-	Password     string       `gorm:"size:255;not null" json:"-"`
-	Name         string       `gorm:"size:64" json:"name"`
-	Avatar       string       `gorm:"size:255" json:"avatar"`
-	Enabled      bool         `gorm:"default:true" json:"enabled"`
-	TokenVersion uint         `gorm:"not null;default:0" json:"-"`
-	Roles        []*AdminRole `gorm:"many2many:admin_user_roles;foreignKey:ID;joinForeignKey:user_id;References:ID;joinReferences:role_id" json:"roles,omitempty"`
+	Username            string           `gorm:"size:64;uniqueIndex;not null" json:"username"`
+	Password            string           `gorm:"size:255;not null" json:"-"`
+	Name                string           `gorm:"size:64" json:"name"`
+	Email               string           `gorm:"size:128;index" json:"email"`
+	Avatar              string           `gorm:"size:255" json:"avatar"`
+	Enabled             bool             `gorm:"default:true" json:"enabled"`
+	TokenVersion        uint             `gorm:"not null;default:0" json:"-"`
+	TwoFactorEnabled    bool             `gorm:"default:false" json:"two_factor_enabled"`
+	TwoFactorSecret     string           `gorm:"size:64" json:"-"`
+	MustChangePassword  bool             `gorm:"default:false" json:"must_change_password"`
+	PasswordChangedAt   *time.Time       `json:"password_changed_at"`
+	AllowedIPs          []string         `gorm:"serializer:json;type:text" json:"allowed_ips"`
+	Roles               []*AdminRole     `gorm:"many2many:admin_user_roles;foreignKey:ID;joinForeignKey:user_id;References:ID;joinReferences:role_id" json:"roles,omitempty"`
+	DepartmentIDs       []uint           `gorm:"-" json:"department_ids,omitempty"`
+	PrimaryDepartmentID uint             `gorm:"-" json:"primary_department_id,omitempty"`
+	RoleGrants          []*AdminUserRole `gorm:"-" json:"role_grants,omitempty"`
 }
 
 // TableName 指定表名
 func (AdminUser) TableName() string {
 	return "admin_users"
 }
+
+// AdminUserRecoveryCode 两步验证恢复码，仅保存摘要，使用后标记时间。
+type AdminUserRecoveryCode struct {
+	ID       uint       `gorm:"primarykey" json:"id"`
+	UserID   uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash string     `gorm:"size:64;not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
+
+// TableName 指定表名
+func (AdminUserRecoveryCode) TableName() string {
+	return "admin_user_recovery_codes"
+}
+
+// AdminPasswordHistory 用户历史密码摘要，用于禁止复用最近使用过的密码。
+type AdminPasswordHistory struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	PasswordHash string    `gorm:"size:255;not null" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminPasswordHistory) TableName() string {
+	return "admin_password_histories"
+}
+
+// AdminUserIdentity 用户与外部身份提供方账号的绑定，同一提供方下 Subject 唯一。
+type AdminUserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"size:32;not null;uniqueIndex:idx_identity_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminUserIdentity) TableName() string {
+	return "admin_user_identities"
+}
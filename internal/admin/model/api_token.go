@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// AdminAPIToken 个人访问令牌，供脚本与 CI 调用后台接口，仅保存摘要。
+//
+// Permissions 为空时继承所属用户的全部权限，否则只允许其中与用户当前权限的交集；
+// AllowedIPs 为空时不限制来源，否则只允许列表中的 IP 或 CIDR；
+// CreatedBy 为创建令牌的用户，管理员为他人创建时记录管理员，0 表示未记录。
+type AdminAPIToken struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"size:64;not null" json:"name"`
+	Prefix      string     `gorm:"size:32;not null" json:"prefix"`
+	TokenHash   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Permissions []string   `gorm:"serializer:json;type:text" json:"permissions"`
+	AllowedIPs  []string   `gorm:"serializer:json;type:text" json:"allowed_ips"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `gorm:"size:64" json:"last_used_ip"`
+	CreatedBy   uint       `gorm:"not null;default:0" json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminAPIToken) TableName() string {
+	return "admin_api_tokens"
+}
@@ -0,0 +1,35 @@
+package model
+
+import "bico-admin/internal/core/model"
+
+// AdminDepartment 部门模型
+//
+// ParentID 为 0 表示顶级部门，同级按 Sort 升序排列。LeaderID 为部门负责人，须为已存在的用户。
+type AdminDepartment struct {
+	model.BaseModel
+	ParentID   uint               `gorm:"default:0;index" json:"parent_id"`
+	Name       string             `gorm:"size:64;not null" json:"name"`
+	Sort       int                `gorm:"default:0" json:"sort"`
+	LeaderID   *uint              `gorm:"index" json:"leader_id"`
+	LeaderName string             `gorm:"-" json:"leader_name,omitempty"`
+	Enabled    bool               `gorm:"default:true" json:"enabled"`
+	Children   []*AdminDepartment `gorm:"-" json:"children,omitempty"`
+}
+
+// TableName 指定表名
+func (AdminDepartment) TableName() string {
+	return "admin_departments"
+}
+
+// AdminUserDepartment 用户所属部门，每个用户至多一个 Primary 主部门。
+type AdminUserDepartment struct {
+	ID           uint `gorm:"primarykey" json:"id"`
+	UserID       uint `gorm:"not null;index:idx_user_department,unique" json:"user_id"`
+	DepartmentID uint `gorm:"not null;index:idx_user_department,unique;index" json:"department_id"`
+	Primary      bool `gorm:"column:is_primary;default:false" json:"primary"`
+}
+
+// TableName 指定表名
+func (AdminUserDepartment) TableName() string {
+	return "admin_user_departments"
+}
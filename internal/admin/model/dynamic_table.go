@@ -0,0 +1,27 @@
+package model
+
+import (
+	"bico-admin/internal/core/model"
+	"bico-admin/internal/pkg/dynamic"
+)
+
+// DynamicTable 低代码动态表定义
+type DynamicTable struct {
+	model.BaseModel
+	Name        string          `gorm:"size:48;uniqueIndex;not null" json:"name"`
+	Label       string          `gorm:"size:64;not null" json:"label"`
+	Description string          `gorm:"size:255" json:"description"`
+	Fields      []dynamic.Field `gorm:"serializer:json;type:text" json:"fields"`
+	Enabled     bool            `gorm:"default:true" json:"enabled"`
+	MenuID      uint            `gorm:"default:0" json:"menu_id"`
+}
+
+// TableName 指定表名
+func (DynamicTable) TableName() string {
+	return "dynamic_tables"
+}
+
+// Schema 返回运行时表结构
+func (t DynamicTable) Schema() dynamic.Table {
+	return dynamic.Table{Name: t.Name, Fields: t.Fields}
+}
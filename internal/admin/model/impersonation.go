@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// AdminImpersonation 模拟登录审计记录，每次开始模拟写入一条，结束时补充结束时间。
+//
+// OriginFamilyID 为操作者发起模拟时所在的登录，退出模拟时据此恢复其原会话。
+type AdminImpersonation struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	ImpersonatorID   uint       `gorm:"not null;index" json:"impersonator_id"`
+	ImpersonatorName string     `gorm:"size:64" json:"impersonator_name"`
+	TargetID         uint       `gorm:"not null;index" json:"target_id"`
+	TargetName       string     `gorm:"size:64" json:"target_name"`
+	FamilyID         string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	OriginFamilyID   string     `gorm:"size:64" json:"-"`
+	IP               string     `gorm:"size:64" json:"ip"`
+	UserAgent        string     `gorm:"size:512" json:"user_agent"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	EndedAt          *time.Time `json:"ended_at"`
+	CreatedAt        time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminImpersonation) TableName() string {
+	return "admin_impersonations"
+}
@@ -0,0 +1,25 @@
+package model
+
+import "bico-admin/internal/core/model"
+
+// IP 访问规则动作
+const (
+	IPRuleAllow = "allow"
+	IPRuleDeny  = "deny"
+)
+
+// AdminIPRule 后台接口的全局 IP 访问规则，CIDR 保存单个 IP 或网段。
+//
+// 命中任一拒绝规则即拒绝访问；存在启用的允许规则时，只有命中允许规则的来源可以访问。
+type AdminIPRule struct {
+	model.BaseModel
+	CIDR    string `gorm:"column:cidr;size:64;not null" json:"cidr"`
+	Action  string `gorm:"size:8;not null;index" json:"action"`
+	Remark  string `gorm:"size:255" json:"remark"`
+	Enabled bool   `gorm:"default:true" json:"enabled"`
+}
+
+// TableName 指定表名
+func (AdminIPRule) TableName() string {
+	return "admin_ip_rules"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "bico-admin/internal/core/model"
+
+// ListView 用户保存的列表视图
+type ListView struct {
+	model.BaseModel
+	UserID    uint              `gorm:"not null;index" json:"user_id"`
+	Module    string            `gorm:"size:64;not null;index" json:"module"`
+	Name      string            `gorm:"size:64;not null" json:"name"`
+	Filters   map[string]string `gorm:"serializer:json;type:text" json:"filters"`
+	SortField string            `gorm:"size:64" json:"sort_field"`
+	SortOrder string            `gorm:"size:16" json:"sort_order"`
+	Columns   []string          `gorm:"serializer:json;type:text" json:"columns"`
+	PageSize  int               `gorm:"default:0" json:"page_size"`
+	IsDefault bool              `gorm:"default:false" json:"is_default"`
+	RoleIDs   []uint            `gorm:"-" json:"role_ids"`
+	Owned     bool              `gorm:"-" json:"owned"`
+}
+
+// TableName 指定表名
+func (ListView) TableName() string {
+	return "list_views"
+}
+
+// ListViewRole 视图共享角色关联表
+type ListViewRole struct {
+	ID     uint `gorm:"primarykey" json:"id"`
+	ViewID uint `gorm:"not null;index:idx_view_role,unique" json:"view_id"`
+	RoleID uint `gorm:"not null;index:idx_view_role,unique;index" json:"role_id"`
+}
+
+// TableName 指定表名
+func (ListViewRole) TableName() string {
+	return "list_view_roles"
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// 登录锁定类型
+const (
+	LoginLockUsername = "username"
+	LoginLockIP       = "ip"
+)
+
+// AdminLoginLock 登录锁定记录，同一账号或来源 IP 连续登录失败达到阈值时写入。
+//
+// 每个锁定对象只保留一条记录，再次锁定时覆盖；LockedUntil 之前拒绝登录，解锁即删除记录。
+type AdminLoginLock struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Kind        string    `gorm:"size:16;not null;uniqueIndex:idx_login_lock_subject" json:"kind"`
+	Subject     string    `gorm:"size:64;not null;uniqueIndex:idx_login_lock_subject" json:"subject"`
+	Failures    int       `gorm:"not null;default:0" json:"failures"`
+	IP          string    `gorm:"size:64" json:"ip"`
+	LockedUntil time.Time `gorm:"not null;index" json:"locked_until"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminLoginLock) TableName() string {
+	return "admin_login_locks"
+}
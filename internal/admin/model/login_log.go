@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// 登录结果
+const (
+	LoginResultSuccess = "success"
+	LoginResultFailure = "failure"
+)
+
+// 登录失败原因
+const (
+	LoginReasonBadPassword   = "bad_password"
+	LoginReasonUserNotFound  = "user_not_found"
+	LoginReasonLocked        = "locked"
+	LoginReasonDisabled      = "disabled"
+	LoginReasonBadCaptcha    = "bad_captcha"
+	LoginReasonBadTwoFactor  = "bad_two_factor"
+	LoginReasonSSOFailed     = "sso_failed"
+	LoginReasonSSOUnlinked   = "sso_unlinked"
+	LoginReasonProviderError = "provider_error"
+	LoginReasonIPDenied      = "ip_denied"
+	LoginReasonBadPasskey    = "bad_passkey"
+)
+
+// LoginLog 登录日志，记录每次登录尝试的结果，只追加不修改。
+type LoginLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Username  string    `gorm:"size:64;index" json:"username"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	Result    string    `gorm:"size:16;not null;index" json:"result"`
+	Reason    string    `gorm:"size:32" json:"reason"`
+	Device    string    `gorm:"size:128" json:"device"`
+	IP        string    `gorm:"size:64" json:"ip"`
+	UserAgent string    `gorm:"size:512" json:"user_agent"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginLog) TableName() string {
+	return "login_logs"
+}
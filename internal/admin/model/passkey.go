@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// AdminPasskey 用户绑定的通行密钥（WebAuthn 凭据），只保存公钥。
+//
+// CredentialID 为凭据 ID 的 base64url 编码；SignCount 为认证器上报的签名计数，
+// 计数回退说明凭据可能被复制，此时拒绝登录。Flags 为注册时认证器标志位的原始值。
+type AdminPasskey struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	Name            string     `gorm:"size:64;not null" json:"name"`
+	CredentialID    string     `gorm:"size:255;not null;uniqueIndex" json:"-"`
+	PublicKey       []byte     `gorm:"not null" json:"-"`
+	AttestationType string     `gorm:"size:32" json:"-"`
+	Transports      []string   `gorm:"serializer:json;type:text" json:"transports"`
+	AAGUID          []byte     `json:"-"`
+	Flags           uint8      `json:"-"`
+	SignCount       uint32     `json:"sign_count"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	LastUsedIP      string     `gorm:"size:64" json:"last_used_ip"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AdminPasskey) TableName() string {
+	return "admin_passkeys"
+}
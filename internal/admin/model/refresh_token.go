@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// AdminRefreshToken 刷新令牌，仅保存摘要。
+// 同一次登录轮换出的令牌共享 FamilyID，发现重复使用时整族吊销。
+// ImpersonatorID 非零表示模拟登录签发的令牌，刷新后的访问令牌沿用操作者身份。
+type AdminRefreshToken struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	UserID         uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID       string     `gorm:"size:64;not null;index" json:"family_id"`
+	TokenHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	TokenVersion   uint       `gorm:"not null;default:0" json:"-"`
+	ImpersonatorID uint       `gorm:"not null;default:0" json:"-"`
+	ExpiresAt      time.Time  `gorm:"not null;index" json:"expires_at"`
+	UsedAt         *time.Time `json:"used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminRefreshToken) TableName() string {
+	return "admin_refresh_tokens"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// AdminSession 登录会话，与一次登录的刷新令牌族一一对应。
+type AdminSession struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	FamilyID   string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Device     string    `gorm:"size:128" json:"device"`
+	UserAgent  string    `gorm:"size:512" json:"user_agent"`
+	IP         string    `gorm:"size:64" json:"ip"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `gorm:"-" json:"current"`
+}
+
+// TableName 指定表名
+func (AdminSession) TableName() string {
+	return "admin_sessions"
+}
@@ -5,9 +5,17 @@ import (
 	"bico-admin/internal/admin/middleware"
 	"bico-admin/internal/admin/service"
 	"bico-admin/internal/core/app"
+	"bico-admin/internal/core/config"
+	"bico-admin/internal/core/logger"
 	coreMiddleware "bico-admin/internal/core/middleware"
 	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/graphql"
+	"bico-admin/internal/pkg/ldapauth"
+	"bico-admin/internal/pkg/oidc"
+	"bico-admin/internal/pkg/password"
 
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -27,30 +35,196 @@ func (m *Module) Name() string {
 // Register 注册 admin 模块
 func (m *Module) Register(ctx *app.AppContext) error {
 	authSvc := service.NewAuthService(ctx.DB, ctx.JWT, ctx.Cache)
+	authSvc.SetTwoFactorIssuer(ctx.Cfg.App.Name)
+	authSvc.SetRefreshTokenTTL(ctx.Cfg.JWT.RefreshExpire())
+	authSvc.SetMaxSessions(ctx.Cfg.JWT.MaxSessions)
+	service.SetPasswordPolicy(password.Policy{
+		MinLength:     ctx.Cfg.Password.MinLength,
+		RequireUpper:  ctx.Cfg.Password.RequireUpper,
+		RequireLower:  ctx.Cfg.Password.RequireLower,
+		RequireDigit:  ctx.Cfg.Password.RequireDigit,
+		RequireSymbol: ctx.Cfg.Password.RequireSymbol,
+		DenyCommon:    ctx.Cfg.Password.DenyCommon,
+		DenyList:      ctx.Cfg.Password.DenyList,
+		History:       ctx.Cfg.Password.History,
+		MaxAge:        ctx.Cfg.Password.MaxAge(),
+	})
+	hasher, err := password.NewHasher(ctx.Cfg.Password.HashAlgorithm, ctx.Cfg.Password.BcryptCost, password.Argon2idHasher{
+		Memory:  ctx.Cfg.Password.Argon2MemoryKiB,
+		Time:    ctx.Cfg.Password.Argon2Time,
+		Threads: ctx.Cfg.Password.Argon2Threads,
+	})
+	if err != nil {
+		return err
+	}
+	password.SetHasher(hasher)
+	loginLock := service.NewLoginLockService(ctx.DB, ctx.Cache)
+	loginLock.SetPolicy(service.LoginLockPolicy{
+		MaxFailures:   ctx.Cfg.LoginLock.Failures(),
+		LockTTL:       ctx.Cfg.LoginLock.LockTTL(),
+		Window:        ctx.Cfg.LoginLock.Window(),
+		IPMaxFailures: ctx.Cfg.LoginLock.IPFailures(),
+		IPLockTTL:     ctx.Cfg.LoginLock.IPLockTTL(),
+	})
+	loginLock.OnLock(func(e service.LoginLockEvent) {
+		logger.Warn("登录失败次数过多，已锁定",
+			zap.String("kind", e.Kind),
+			zap.String("subject", e.Subject),
+			zap.Int("failures", e.Failures),
+			zap.String("ip", e.IP),
+			zap.Time("locked_until", e.LockedUntil),
+		)
+	})
+	authSvc.SetLoginLock(loginLock)
+	authSvc.SetAdaptiveCaptcha(ctx.Cfg.Captcha.AdaptiveFailures)
+	registerSSOProviders(authSvc, ctx.Cfg.SSO)
+	registerLDAPProvider(authSvc, ctx.Cfg.LDAP)
+	if err := enableWebAuthn(authSvc, ctx.Cfg.WebAuthn, ctx.Cfg.App.Name); err != nil {
+		return err
+	}
+	if ctx.Mailer != nil && ctx.Cfg.Password.ResetURL != "" {
+		authSvc.SetPasswordReset(ctx.Mailer, ctx.Cfg.Password.ResetURL, ctx.Cfg.Password.ResetTTL())
+	}
 	cfgSvc := service.NewConfigService(ctx.ConfigManager)
+	ipAccess := service.NewIPAccessService(ctx.DB, ctx.Cache)
+	if err := ipAccess.Load(); err != nil {
+		return err
+	}
 
 	jwtAuth := coreMiddleware.JWTAuth(ctx.JWT, authSvc)
 	permMiddleware := middleware.NewPermissionMiddleware(authSvc)
 	userStatusMiddleware := middleware.NewUserStatusMiddleware(authSvc)
+	ipAccessMiddleware := middleware.NewIPAccessMiddleware(ipAccess)
 
 	authHandler := handler.NewAuthHandler(authSvc, ctx.Uploader, ctx.Captcha)
 	uploadHandler := handler.NewUploadHandler(ctx.Uploader)
 	commonHandler := handler.NewCommonHandler(cfgSvc)
 	dashboardHandler := handler.NewDashboardHandler(ctx.Cfg, ctx.DB)
 
-	modules := NewCRUDModules(ctx.DB, authSvc)
-	r := NewRouter(authHandler, uploadHandler, commonHandler, dashboardHandler, jwtAuth, permMiddleware, userStatusMiddleware, ctx.DB, modules)
+	modules := NewCRUDModules(ctx.DB, authSvc, authSvc, ipAccess, loginLock, authSvc)
+
+	// GraphQL schema 与 REST 共用同一份模块列表，字段权限复用权限中间件。
+	var graphqlHandler *handler.GraphQLHandler
+	if ctx.Cfg.GraphQL.Enabled {
+		executor, err := graphql.NewExecutor(modules, permMiddleware)
+		if err != nil {
+			return err
+		}
+		graphqlHandler = handler.NewGraphQLHandler(executor)
+	}
+
+	r := NewRouter(authHandler, uploadHandler, commonHandler, dashboardHandler, graphqlHandler, jwtAuth, permMiddleware, userStatusMiddleware, ipAccessMiddleware, ctx.DB, modules)
 	r.Register(ctx.Engine)
 
+	// CRUDHandler.List 的 ?view=<id> 通过该函数读取当前用户可用的视图。
+	crud.SetListViewResolver(handler.NewListViewResolver(ctx.DB))
+
+	// 动态表权限依赖基础权限树，需在路由注册之后加载。
+	if err := handler.LoadDynamicTablePermissions(ctx.DB); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// registerSSOProviders 按配置注册单点登录提供方，提供方在首次登录时才请求发现文档。
+func registerSSOProviders(authSvc *service.AuthService, cfg config.SSOConfig) {
+	for _, p := range cfg.OIDC {
+		provider := service.NewOIDCProvider(p.Name, p.Label, oidc.Config{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, service.OIDCClaimMapping{
+			Username: p.UsernameClaim,
+			Name:     p.NameClaim,
+			Groups:   p.GroupsClaim,
+		})
+		authSvc.RegisterSSOProvider(provider, service.IdentityPolicy{
+			AutoProvision: p.AutoProvision,
+			LinkExisting:  p.LinkExisting,
+			DefaultRoles:  p.DefaultRoles,
+			RoleMapping:   groupRoleMapping(p.RoleMapping),
+		})
+	}
+}
+
+// registerLDAPProvider 启用 LDAP 时由目录服务校验账号密码，连接在每次登录时建立。
+func registerLDAPProvider(authSvc *service.AuthService, cfg config.LDAPConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	provider := service.NewLDAPProvider(ldapauth.Config{
+		URL:                cfg.URL,
+		StartTLS:           cfg.StartTLS,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		BindDN:             cfg.BindDN,
+		BindPassword:       cfg.BindPassword,
+		BaseDN:             cfg.BaseDN,
+		UserFilter:         cfg.UserFilter,
+		UsernameAttr:       cfg.UsernameAttr,
+		NameAttr:           cfg.NameAttr,
+		GroupAttr:          cfg.GroupAttr,
+		GroupBaseDN:        cfg.GroupBaseDN,
+		GroupFilter:        cfg.GroupFilter,
+		Timeout:            cfg.Timeout(),
+	})
+	authSvc.SetPasswordProvider(provider, service.IdentityPolicy{
+		AutoProvision: cfg.AutoProvision,
+		LinkExisting:  cfg.LinkExisting,
+		DefaultRoles:  cfg.DefaultRoles,
+		RoleMapping:   groupRoleMapping(cfg.RoleMapping),
+	}, cfg.LocalUsers)
+}
+
+// enableWebAuthn 启用通行密钥，认证器中显示的名称未配置时使用应用名称。
+func enableWebAuthn(authSvc *service.AuthService, cfg config.WebAuthnConfig, appName string) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	name := cfg.RPName
+	if name == "" {
+		name = appName
+	}
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: name,
+		RPOrigins:     cfg.Origins,
+	})
+	if err != nil {
+		return err
+	}
+	authSvc.SetWebAuthn(w)
 	return nil
 }
 
+// groupRoleMapping 将配置中的用户组映射列表合并为 map，同一用户组出现多次时角色累加。
+func groupRoleMapping(items []config.GroupRoleMapping) map[string][]string {
+	if len(items) == 0 {
+		return nil
+	}
+	mapping := make(map[string][]string, len(items))
+	for _, item := range items {
+		mapping[item.Group] = append(mapping[item.Group], item.Roles...)
+	}
+	return mapping
+}
+
 // NewCRUDModules 创建后台声明式 CRUD 模块列表。
 //
 // 说明：运行时路由注册和 Swagger 文档增强共用同一份模块配置，避免接口路径与文档漂移。
-func NewCRUDModules(db *gorm.DB, cacheInvalidator service.AuthCacheInvalidator) []crud.Module {
+func NewCRUDModules(db *gorm.DB, cacheInvalidator service.AuthCacheInvalidator, impersonator service.Impersonator, ipAccess *service.IPAccessService, loginLock *service.LoginLockService, explainer service.PermissionExplainer) []crud.Module {
 	return []crud.Module{
-		handler.NewAdminUserHandler(db, cacheInvalidator),
+		handler.NewAdminUserHandler(db, cacheInvalidator, impersonator),
 		handler.NewAdminRoleHandler(db, cacheInvalidator),
+		handler.NewDepartmentHandler(db),
+		handler.NewDynamicTableHandler(db, cacheInvalidator),
+		handler.NewDynamicDataHandler(db),
+		handler.NewListViewHandler(db),
+		handler.NewLoginLogHandler(db),
+		handler.NewIPRuleHandler(db, ipAccess),
+		handler.NewLoginLockHandler(db, loginLock),
+		handler.NewPermissionExplainHandler(explainer),
 	}
 }
@@ -1,8 +1,11 @@
 package admin
 
 import (
+	"time"
+
 	"bico-admin/internal/admin/handler"
 	"bico-admin/internal/admin/middleware"
+	coreMiddleware "bico-admin/internal/core/middleware"
 	"bico-admin/internal/pkg/crud"
 
 	"github.com/gin-gonic/gin"
@@ -15,9 +18,11 @@ type Router struct {
 	uploadHandler        *handler.UploadHandler
 	commonHandler        *handler.CommonHandler
 	dashboardHandler     *handler.DashboardHandler
+	graphqlHandler       *handler.GraphQLHandler
 	jwtAuth              gin.HandlerFunc
 	permMiddleware       *middleware.PermissionMiddleware
 	userStatusMiddleware *middleware.UserStatusMiddleware
+	ipAccessMiddleware   *middleware.IPAccessMiddleware
 	db                   *gorm.DB
 	modules              []crud.Module
 }
@@ -28,9 +33,11 @@ func NewRouter(
 	uploadHandler *handler.UploadHandler,
 	commonHandler *handler.CommonHandler,
 	dashboardHandler *handler.DashboardHandler,
+	graphqlHandler *handler.GraphQLHandler,
 	jwtAuth gin.HandlerFunc,
 	permMiddleware *middleware.PermissionMiddleware,
 	userStatusMiddleware *middleware.UserStatusMiddleware,
+	ipAccessMiddleware *middleware.IPAccessMiddleware,
 	db *gorm.DB,
 	modules []crud.Module,
 ) *Router {
@@ -42,9 +49,11 @@ func NewRouter(
 		uploadHandler:        uploadHandler,
 		commonHandler:        commonHandler,
 		dashboardHandler:     dashboardHandler,
+		graphqlHandler:       graphqlHandler,
 		jwtAuth:              jwtAuth,
 		permMiddleware:       permMiddleware,
 		userStatusMiddleware: userStatusMiddleware,
+		ipAccessMiddleware:   ipAccessMiddleware,
 		db:                   db,
 		modules:              modules,
 	}
@@ -52,23 +61,51 @@ func NewRouter(
 
 // initBasePermissions 初始化基础权限树
 func initBasePermissions() {
-	crud.SetBasePermissions([]crud.Permission{
+	crud.SetBasePermissions(BasePermissions())
+}
+
+// BasePermissions 返回不属于 CRUD 模块的基础权限树，CRUD 模块权限会挂载到其中的节点下。
+func BasePermissions() []crud.Permission {
+	return []crud.Permission{
 		{Key: handler.PermDashboardMenu, Label: "工作台"},
 		{
 			Key:      handler.PermSystemManage,
 			Label:    "系统管理",
 			Children: []crud.Permission{},
 		},
-	})
+		// 动态表的数据权限在运行时挂载到该节点下。
+		{
+			Key:      handler.PermDynamicData,
+			Label:    "动态数据",
+			Children: []crud.Permission{},
+		},
+	}
 }
 
 // Register 注册路由
 func (r *Router) Register(engine *gin.Engine) {
-	admin := engine.Group("/admin-api")
+	engine.GET("/.well-known/jwks.json", r.authHandler.JWKS)
+
+	// 全局 IP 规则作用于全部后台接口，包括登录等公开接口。
+	admin := engine.Group("/admin-api", r.ipAccessMiddleware.Check())
 
 	// 公开路由
 	{
 		admin.POST("/auth/login", r.authHandler.Login)
+		admin.POST("/auth/refresh", r.authHandler.Refresh)
+		admin.POST("/auth/2fa/verify", r.authHandler.VerifyTwoFactor)
+		admin.POST("/auth/2fa/pending/setup", r.authHandler.SetupPendingTwoFactor)
+		admin.POST("/auth/2fa/passkey/begin", r.authHandler.BeginPasskeyTwoFactor)
+		admin.POST("/auth/2fa/passkey/verify", r.authHandler.VerifyTwoFactorPasskey)
+		admin.POST("/auth/passkey/login/begin", r.authHandler.BeginPasskeyLogin)
+		admin.POST("/auth/passkey/login/finish", r.authHandler.FinishPasskeyLogin)
+		admin.POST("/auth/password/expired", r.authHandler.ChangeExpiredPassword)
+		// 找回密码按 IP 限流：突发 5 次，之后每 2 分钟 1 次。
+		passwordResetLimit := coreMiddleware.NewRateLimiterEvery(2*time.Minute, 5).RateLimit()
+		admin.POST("/auth/password/forgot", passwordResetLimit, r.authHandler.ForgotPassword)
+		admin.POST("/auth/password/reset", passwordResetLimit, r.authHandler.ResetPassword)
+		admin.GET("/auth/sso/:provider/authorize", r.authHandler.SSOAuthorize)
+		admin.POST("/auth/sso/callback", r.authHandler.SSOCallback)
 		admin.GET("/captcha", r.authHandler.GetCaptcha)
 		admin.GET("/app-config", r.commonHandler.GetAppConfig)
 	}
@@ -96,11 +133,40 @@ func (r *Router) Register(engine *gin.Engine) {
 		{
 			auth.POST("/logout", r.authHandler.Logout)
 			auth.GET("/current-user", r.authHandler.CurrentUser)
-			auth.PUT("/profile", r.authHandler.UpdateProfile)
-			auth.PUT("/password", r.authHandler.ChangePassword)
-			auth.POST("/avatar", r.authHandler.UploadAvatar)
+			auth.POST("/impersonate/stop", r.authHandler.StopImpersonation)
+		}
+		// 账号与安全设置只供交互登录使用，个人访问令牌的权限范围不覆盖这些接口。
+		security := auth.Group("", middleware.DenyAPIToken())
+		{
+			security.GET("/sessions", r.authHandler.ListSessions)
+			security.GET("/api-tokens", r.authHandler.ListAPITokens)
+			security.GET("/passkeys", r.authHandler.ListPasskeys)
+			security.GET("/2fa", r.authHandler.TwoFactorStatus)
+		}
+		// 模拟登录会话只能查看，不能修改被模拟用户的资料、凭据与安全设置。
+		account := security.Group("", middleware.DenyImpersonation())
+		{
+			account.PUT("/profile", r.authHandler.UpdateProfile)
+			account.PUT("/password", r.authHandler.ChangePassword)
+			account.POST("/avatar", r.authHandler.UploadAvatar)
+			account.DELETE("/sessions/:id", r.authHandler.RevokeSession)
+			account.POST("/api-tokens", r.authHandler.CreateAPIToken)
+			account.DELETE("/api-tokens/:id", r.authHandler.RevokeAPIToken)
+			account.POST("/passkeys/register/begin", r.authHandler.BeginPasskeyRegistration)
+			account.POST("/passkeys/register/finish", r.authHandler.FinishPasskeyRegistration)
+			account.PUT("/passkeys/:id", r.authHandler.RenamePasskey)
+			account.DELETE("/passkeys/:id", r.authHandler.DeletePasskey)
+			account.POST("/2fa/setup", r.authHandler.SetupTwoFactor)
+			account.POST("/2fa/enable", r.authHandler.EnableTwoFactor)
+			account.POST("/2fa/disable", r.authHandler.DisableTwoFactor)
+			account.POST("/2fa/recovery-codes", r.authHandler.RegenerateRecoveryCodes)
 		}
 
+		// GraphQL 为可选能力，未启用时不注册路由。
+		if r.graphqlHandler != nil {
+			authorized.GET("/graphql", r.graphqlHandler.Query)
+			authorized.POST("/graphql", r.graphqlHandler.Query)
+		}
 	}
 
 	// CRUD 模块由模块路由器自行处理公开/鉴权路由，避免重复挂载中间件。
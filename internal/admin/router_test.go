@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/handler"
+	"bico-admin/internal/admin/middleware"
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+	coreMiddleware "bico-admin/internal/core/middleware"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestAccountRoutesRejectAPIToken 验证个人访问令牌不能访问账号与安全设置接口，无论其权限范围如何。
+func TestAccountRoutesRejectAPIToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.AdminAPIToken{}, &model.AdminIPRule{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	superRole := model.AdminRole{Name: "超级管理员", Code: model.SuperAdminRoleCode, Enabled: true}
+	user := model.AdminUser{Username: "admin", Password: "x", Enabled: true, Roles: []*model.AdminRole{&superRole}}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute)
+	authSvc := service.NewAuthService(database, manager, memoryCache)
+	ipAccess := service.NewIPAccessService(database, memoryCache)
+	if err := ipAccess.Load(); err != nil {
+		t.Fatalf("加载 IP 规则失败: %v", err)
+	}
+	engine := gin.New()
+	NewRouter(handler.NewAuthHandler(authSvc, nil, nil), handler.NewUploadHandler(nil), handler.NewCommonHandler(nil),
+		handler.NewDashboardHandler(nil, database), nil, coreMiddleware.JWTAuth(manager, authSvc),
+		middleware.NewPermissionMiddleware(authSvc), middleware.NewUserStatusMiddleware(authSvc),
+		middleware.NewIPAccessMiddleware(ipAccess), database, nil).Register(engine)
+
+	// 路由注册后基础权限树才可用，令牌只授予工作台权限。
+	created, err := service.CreateUserAPIToken(database, user.ID, user.ID, &service.CreateAPITokenRequest{
+		Name:        "ci",
+		Permissions: []string{handler.PermDashboardMenu},
+	})
+	if err != nil {
+		t.Fatalf("创建访问令牌失败: %v", err)
+	}
+
+	routes := []string{
+		"GET /auth/sessions",
+		"GET /auth/api-tokens",
+		"GET /auth/passkeys",
+		"GET /auth/2fa",
+		"PUT /auth/profile",
+		"PUT /auth/password",
+		"POST /auth/avatar",
+		"DELETE /auth/sessions/1",
+		"POST /auth/api-tokens",
+		"DELETE /auth/api-tokens/1",
+		"POST /auth/passkeys/register/begin",
+		"POST /auth/passkeys/register/finish",
+		"PUT /auth/passkeys/1",
+		"DELETE /auth/passkeys/1",
+		"POST /auth/2fa/setup",
+		"POST /auth/2fa/enable",
+		"POST /auth/2fa/disable",
+		"POST /auth/2fa/recovery-codes",
+	}
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		if code := serveWithToken(engine, method, "/admin-api"+path, created.Token); code != 403 {
+			t.Fatalf("%s: 访问令牌应被拒绝，实际响应码 %d", route, code)
+		}
+	}
+	// 当前用户信息仍可读取，供脚本确认令牌身份。
+	if code := serveWithToken(engine, http.MethodGet, "/admin-api/auth/current-user", created.Token); code != 0 {
+		t.Fatalf("访问令牌应能读取当前用户，实际响应码 %d", code)
+	}
+}
+
+// serveWithToken 携带访问令牌发起请求，返回响应体中的业务码。
+func serveWithToken(engine *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	var body struct {
+		Code int `json:"code"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	return body.Code
+}
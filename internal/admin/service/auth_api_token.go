@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/logger"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/jwt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPITokenNotFound = errors.New("访问令牌不存在")
+	ErrAPITokenInvalid  = errors.New("访问令牌无效或已过期")
+	ErrAPITokenIPDenied = errors.New("当前 IP 不允许使用该访问令牌")
+	ErrAPITokenExpiry   = errors.New("过期时间必须晚于当前时间")
+	ErrAPITokenScope    = errors.New("访问令牌的权限不能超出用户当前拥有的权限")
+	ErrAPITokenAccount  = errors.New("访问令牌不能访问账号与安全设置")
+	ErrAPITokenTarget   = errors.New("不能为超级管理员或拥有自己所没有权限的用户创建访问令牌")
+)
+
+// apiTokenTouchInterval 最近使用时间的最小更新间隔，避免每次请求都写库。
+const apiTokenTouchInterval = time.Minute
+
+// CreateAPITokenRequest 创建访问令牌请求
+//
+// ExpiresAt 为空表示永不过期；Permissions 为空表示继承用户全部权限；AllowedIPs 支持 IP 与 CIDR。
+type CreateAPITokenRequest struct {
+	Name        string     `json:"name" binding:"required,max=64"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	Permissions []string   `json:"permissions"`
+	AllowedIPs  []string   `json:"allowed_ips"`
+}
+
+// APITokenCreated 创建访问令牌响应，明文令牌只在此时返回一次。
+type APITokenCreated struct {
+	model.AdminAPIToken
+	Token string `json:"token"`
+}
+
+// ListAPITokens 获取当前用户的访问令牌。
+func (s *AuthService) ListAPITokens(userID uint) ([]model.AdminAPIToken, error) {
+	return ListUserAPITokens(s.db, userID)
+}
+
+// CreateAPIToken 为当前用户创建访问令牌。
+func (s *AuthService) CreateAPIToken(userID uint, req *CreateAPITokenRequest) (*APITokenCreated, error) {
+	return CreateUserAPIToken(s.db, userID, userID, req)
+}
+
+// RevokeAPIToken 吊销当前用户的访问令牌。
+func (s *AuthService) RevokeAPIToken(userID, tokenID uint) error {
+	return RevokeUserAPIToken(s.db, userID, tokenID)
+}
+
+// AuthenticateAPIToken 校验访问令牌，返回令牌所属用户与权限范围。
+//
+// 用户禁用由用户状态中间件负责拦截，此处只校验令牌本身的有效期与来源 IP。
+func (s *AuthService) AuthenticateAPIToken(token, ip string) (*jwt.Claims, error) {
+	var record model.AdminAPIToken
+	if err := s.db.Where("token_hash = ?", hashToken(token)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPITokenInvalid
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if record.ExpiresAt != nil && !record.ExpiresAt.After(now) {
+		return nil, ErrAPITokenInvalid
+	}
+	if !ipListAllows(record.AllowedIPs, ip) {
+		return nil, ErrAPITokenIPDenied
+	}
+
+	var user model.AdminUser
+	if err := s.db.Select("id", "username").First(&user, record.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPITokenInvalid
+		}
+		return nil, err
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= apiTokenTouchInterval || record.LastUsedIP != ip {
+		_ = s.db.Model(&model.AdminAPIToken{}).Where("id = ?", record.ID).Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error
+	}
+
+	return &jwt.Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Scopes:   record.Permissions,
+		Exp:      expiresUnix(record.ExpiresAt),
+	}, nil
+}
+
+// ListUserAPITokens 查询用户的访问令牌，供个人中心与用户管理共用。
+func ListUserAPITokens(db *gorm.DB, userID uint) ([]model.AdminAPIToken, error) {
+	var tokens []model.AdminAPIToken
+	err := db.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// CreateUserAPIToken 为用户创建访问令牌。
+//
+// 权限范围必须是用户当前权限的子集；实际生效权限在每次请求时仍与用户当前权限取交集，
+// 因此用户权限收回后令牌随之失去相应能力。createdBy 为创建令牌的用户。
+func CreateUserAPIToken(db *gorm.DB, userID, createdBy uint, req *CreateAPITokenRequest) (*APITokenCreated, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("令牌名称不能为空")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPITokenExpiry
+	}
+	permissions, err := normalizeTokenPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) > 0 {
+		owned, err := loadUserPermissionSet(db, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range permissions {
+			if !owned.Allows(permission) {
+				return nil, fmt.Errorf("%w: %s", ErrAPITokenScope, permission)
+			}
+		}
+	}
+	allowedIPs, err := NormalizeAllowedIPs(req.AllowedIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	token := jwt.APITokenPrefix + secret
+	created := &APITokenCreated{
+		AdminAPIToken: model.AdminAPIToken{
+			UserID:      userID,
+			Name:        name,
+			Prefix:      token[:len(jwt.APITokenPrefix)+8],
+			TokenHash:   hashToken(token),
+			Permissions: permissions,
+			AllowedIPs:  allowedIPs,
+			ExpiresAt:   req.ExpiresAt,
+			CreatedBy:   createdBy,
+		},
+		Token: token,
+	}
+	if err := db.Create(&created.AdminAPIToken).Error; err != nil {
+		return nil, err
+	}
+	if createdBy != userID {
+		logger.Info("为用户创建访问令牌",
+			zap.Uint("operator_id", createdBy),
+			zap.Uint("user_id", userID),
+			zap.Uint("token_id", created.ID),
+			zap.String("name", name),
+		)
+	}
+	return created, nil
+}
+
+// CheckAPITokenTarget 校验操作者能否为目标用户创建访问令牌。
+//
+// 访问令牌不经过目标用户的两步验证，因此非超级管理员不能为超级管理员或拥有自己所没有权限的用户创建，
+// 避免借此获得高于自身的权限。
+func CheckAPITokenTarget(db *gorm.DB, operatorID, targetID uint) error {
+	superOperator, err := isSuperAdminUser(db, operatorID)
+	if err != nil || superOperator {
+		return err
+	}
+	superTarget, err := isSuperAdminUser(db, targetID)
+	if err != nil {
+		return err
+	}
+	if superTarget {
+		return ErrAPITokenTarget
+	}
+	operator, err := loadUserPermissionSet(db, operatorID)
+	if err != nil {
+		return err
+	}
+	target, err := loadUserPermissionSet(db, targetID)
+	if err != nil {
+		return err
+	}
+	for _, permission := range target.Expand(crud.GetAllPermissionKeys()) {
+		if !operator.Allows(permission) {
+			return fmt.Errorf("%w: %s", ErrAPITokenTarget, permission)
+		}
+	}
+	return nil
+}
+
+// RevokeUserAPIToken 吊销用户的指定访问令牌，令牌每次请求都查库校验，删除后立即失效。
+func RevokeUserAPIToken(db *gorm.DB, userID, tokenID uint) error {
+	result := db.Where("id = ? AND user_id = ?", tokenID, userID).Delete(&model.AdminAPIToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// RemoveAPITokenPermissions 从访问令牌的权限范围中移除 keys，供权限被删除（如删除动态表）时在同一事务内调用。
+// 范围被全部移除的令牌随之删除，避免空范围退化为继承用户全部权限。
+func RemoveAPITokenPermissions(tx *gorm.DB, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var tokens []model.AdminAPIToken
+	if err := tx.Select("id", "permissions").Find(&tokens).Error; err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		remaining := slices.DeleteFunc(slices.Clone(token.Permissions), func(permission string) bool {
+			return slices.Contains(keys, permission)
+		})
+		switch {
+		case len(remaining) == len(token.Permissions):
+			continue
+		case len(remaining) == 0:
+			if err := tx.Delete(&model.AdminAPIToken{}, token.ID).Error; err != nil {
+				return err
+			}
+		default:
+			if err := tx.Model(&model.AdminAPIToken{}).Where("id = ?", token.ID).
+				Select("permissions").Updates(&model.AdminAPIToken{Permissions: remaining}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeTokenPermissions 去重并校验权限标识存在。
+func normalizeTokenPermissions(permissions []string) ([]string, error) {
+	if len(permissions) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]bool)
+	for _, key := range crud.GetAllPermissionKeys() {
+		known[key] = true
+	}
+	seen := make(map[string]bool, len(permissions))
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if permission == "" || seen[permission] {
+			continue
+		}
+		if !known[permission] {
+			return nil, fmt.Errorf("权限 %s 不存在", permission)
+		}
+		seen[permission] = true
+		result = append(result, permission)
+	}
+	return result, nil
+}
+
+// expiresUnix 返回令牌过期时间戳，永不过期时为 0。
+func expiresUnix(expiresAt *time.Time) int64 {
+	if expiresAt == nil {
+		return 0
+	}
+	return expiresAt.Unix()
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	coreMiddleware "bico-admin/internal/core/middleware"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestAPITokenAuthentication 验证访问令牌经认证中间件识别后的权限范围、IP 白名单、过期与吊销。
+func TestAPITokenAuthentication(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{}, &model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.AdminAPIToken{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	defer crud.SetBasePermissions(crud.GetAllPermissions())
+	crud.SetBasePermissions([]crud.Permission{{Key: "demo:read", Label: "读取"}, {Key: "demo:write", Label: "写入"}, {Key: "demo:admin", Label: "管理"}})
+
+	user := model.AdminUser{Username: "ci", Password: "x", Enabled: true}
+	role := model.AdminRole{Name: "演示", Code: "demo", Enabled: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+	if err := database.Create(&role).Error; err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	database.Create(&model.AdminUserRole{UserID: user.ID, RoleID: role.ID})
+	database.Create(&[]model.AdminRolePermission{{RoleID: role.ID, Permission: "demo:read"}, {RoleID: role.ID, Permission: "demo:write"}})
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	jwtManager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour)
+	service := NewAuthService(database, jwtManager, memoryCache)
+
+	if _, err := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{Name: "ci", Permissions: []string{"demo:unknown"}}); err == nil {
+		t.Fatal("不存在的权限应拒绝创建")
+	}
+	if _, err := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{Name: "ci", Permissions: []string{"demo:read", "demo:admin"}}); !errors.Is(err, ErrAPITokenScope) {
+		t.Fatalf("超出用户当前权限的范围应返回 ErrAPITokenScope，实际: %v", err)
+	}
+	if _, err := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{Name: "ci", AllowedIPs: []string{"not-an-ip"}}); err == nil {
+		t.Fatal("非法 IP 白名单应拒绝创建")
+	}
+	past := time.Now().Add(-time.Minute)
+	if _, err := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{Name: "ci", ExpiresAt: &past}); !errors.Is(err, ErrAPITokenExpiry) {
+		t.Fatalf("过去的过期时间应返回 ErrAPITokenExpiry，实际: %v", err)
+	}
+
+	readOnly, err := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{
+		Name:        "只读",
+		Permissions: []string{"demo:read"},
+		AllowedIPs:  []string{"192.0.2.0/24"},
+	})
+	if err != nil {
+		t.Fatalf("创建访问令牌失败: %v", err)
+	}
+	var stored model.AdminAPIToken
+	database.First(&stored, readOnly.ID)
+	if stored.TokenHash == readOnly.Token || stored.TokenHash != hashToken(readOnly.Token) {
+		t.Fatal("库中应只保存令牌摘要")
+	}
+	otherNetwork, _ := service.CreateAPIToken(user.ID, &CreateAPITokenRequest{Name: "内网", AllowedIPs: []string{"10.0.0.0/8"}})
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/me", coreMiddleware.JWTAuth(jwtManager, service), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("user_id"), "scopes": c.GetStringSlice("token_scopes")})
+	})
+	// call 业务错误同样以 HTTP 200 返回，以响应体判断请求是否放行。
+	call := func(token string) string {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.RemoteAddr = "192.0.2.10:40000"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	if body := call(readOnly.Token); body != `{"scopes":["demo:read"],"user_id":1}` {
+		t.Fatalf("访问令牌应识别为所属用户并携带权限范围，实际: %s", body)
+	}
+	if body := call(otherNetwork.Token); !strings.Contains(body, ErrAPITokenIPDenied.Error()) {
+		t.Fatalf("白名单之外的 IP 应拒绝访问，实际: %s", body)
+	}
+	database.First(&stored, readOnly.ID)
+	if stored.LastUsedAt == nil || stored.LastUsedIP != "192.0.2.10" {
+		t.Fatalf("未记录最近使用: %+v", stored)
+	}
+
+	if err := service.RevokeAPIToken(user.ID+1, readOnly.ID); !errors.Is(err, ErrAPITokenNotFound) {
+		t.Fatalf("不能吊销他人的令牌，实际: %v", err)
+	}
+	if err := service.RevokeAPIToken(user.ID, readOnly.ID); err != nil {
+		t.Fatalf("吊销令牌失败: %v", err)
+	}
+	if body := call(readOnly.Token); !strings.Contains(body, ErrAPITokenInvalid.Error()) {
+		t.Fatalf("吊销后的令牌应拒绝访问，实际: %s", body)
+	}
+
+	database.Model(&model.AdminAPIToken{}).Where("id = ?", otherNetwork.ID).Update("expires_at", past)
+	if _, err := service.AuthenticateAPIToken(otherNetwork.Token, "10.0.0.1"); !errors.Is(err, ErrAPITokenInvalid) {
+		t.Fatalf("过期令牌应返回 ErrAPITokenInvalid，实际: %v", err)
+	}
+}
+
+// TestCheckAPITokenTarget 验证非超级管理员不能为超级管理员或权限高于自己的用户创建访问令牌。
+func TestCheckAPITokenTarget(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{}, &model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.AdminAPIToken{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	defer crud.SetBasePermissions(crud.GetAllPermissions())
+	crud.SetBasePermissions([]crud.Permission{{Key: "demo:read", Label: "读取"}, {Key: "demo:admin", Label: "管理"}})
+
+	superRole := model.AdminRole{Name: "超级管理员", Code: model.SuperAdminRoleCode, Enabled: true}
+	reader := model.AdminRole{Name: "只读", Code: "reader", Enabled: true}
+	manager := model.AdminRole{Name: "管理", Code: "manager", Enabled: true}
+	database.Create(&[]*model.AdminRole{&superRole, &reader, &manager})
+	database.Create(&[]model.AdminRolePermission{{RoleID: reader.ID, Permission: "demo:read"}, {RoleID: manager.ID, Permission: "demo:*"}})
+	root := model.AdminUser{Username: "root", Password: "x", Enabled: true, Roles: []*model.AdminRole{&superRole}}
+	operator := model.AdminUser{Username: "operator", Password: "x", Enabled: true, Roles: []*model.AdminRole{&reader}}
+	ci := model.AdminUser{Username: "ci", Password: "x", Enabled: true, Roles: []*model.AdminRole{&reader}}
+	boss := model.AdminUser{Username: "boss", Password: "x", Enabled: true, Roles: []*model.AdminRole{&manager}}
+	database.Create(&[]*model.AdminUser{&root, &operator, &ci, &boss})
+
+	if err := CheckAPITokenTarget(database, operator.ID, root.ID); !errors.Is(err, ErrAPITokenTarget) {
+		t.Fatalf("非超级管理员不能为超级管理员创建令牌，实际: %v", err)
+	}
+	if err := CheckAPITokenTarget(database, operator.ID, boss.ID); !errors.Is(err, ErrAPITokenTarget) {
+		t.Fatalf("不能为拥有自己所没有权限的用户创建令牌，实际: %v", err)
+	}
+	if err := CheckAPITokenTarget(database, operator.ID, ci.ID); err != nil {
+		t.Fatalf("目标权限不超过自己时应允许: %v", err)
+	}
+	if err := CheckAPITokenTarget(database, root.ID, boss.ID); err != nil {
+		t.Fatalf("超级管理员不受限制: %v", err)
+	}
+
+	created, err := CreateUserAPIToken(database, ci.ID, operator.ID, &CreateAPITokenRequest{Name: "ci"})
+	if err != nil {
+		t.Fatalf("创建访问令牌失败: %v", err)
+	}
+	var stored model.AdminAPIToken
+	if database.First(&stored, created.ID); stored.CreatedBy != operator.ID {
+		t.Fatalf("应记录创建令牌的管理员: %+v", stored)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+// SetAdaptiveCaptcha 设置按需验证码阈值，大于 0 时账号或来源 IP 失败达到该次数才要求登录验证码。
+func (s *AuthService) SetAdaptiveCaptcha(failures int) {
+	s.captchaFailures = failures
+}
+
+// CaptchaRequired 判断本次登录是否需要验证码，未启用按需验证时始终需要。
+//
+// 失败次数与登录锁定共用计数，登录成功后账号计数清零，来源 IP 的计数在统计窗口过期后清零。
+func (s *AuthService) CaptchaRequired(username, ip string) bool {
+	if s.captchaFailures <= 0 {
+		return true
+	}
+	return s.loginLock.Failures(normalizeLoginUsername(username), ip) >= s.captchaFailures
+}
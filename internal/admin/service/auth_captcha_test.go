@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestAdaptiveCaptcha 验证按需验证码在账号或来源 IP 失败达到阈值后才要求验证码。
+func TestAdaptiveCaptcha(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.LoginLog{}, &model.AdminLoginLock{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	if !service.CaptchaRequired("alice", "10.0.0.1") {
+		t.Fatal("未启用按需验证时每次登录都需要验证码")
+	}
+
+	service.SetAdaptiveCaptcha(2)
+	if service.CaptchaRequired("alice", "10.0.0.1") {
+		t.Fatal("没有失败记录时不需要验证码")
+	}
+	service.recordLoginFailure("alice", ClientInfo{IP: "10.0.0.1"})
+	service.recordLoginFailure("bob", ClientInfo{IP: "10.0.0.1"})
+	if !service.CaptchaRequired(" carol ", "10.0.0.1") {
+		t.Fatal("来源 IP 失败达到阈值后应需要验证码")
+	}
+	if service.CaptchaRequired("alice", "10.0.0.2") {
+		t.Fatal("账号失败未达阈值时其他来源不需要验证码")
+	}
+	service.recordLoginFailure("alice", ClientInfo{IP: "10.0.0.2"})
+	if !service.CaptchaRequired("alice", "10.0.0.3") {
+		t.Fatal("账号失败达到阈值后任意来源都需要验证码")
+	}
+}
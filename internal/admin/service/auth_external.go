@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/password"
+
+	"gorm.io/gorm"
+)
+
+// ExternalIdentity 身份提供方返回的用户身份。
+type ExternalIdentity struct {
+	Subject  string
+	Username string
+	Name     string
+	Avatar   string
+	Groups   []string
+}
+
+// IdentityPolicy 外部身份映射为本地用户的规则。
+//
+// RoleMapping 为外部用户组到角色编码的映射；配置后每次登录都会按用户组同步映射中出现的角色，
+// 不在映射中的角色不受影响，便于管理员另行授予。
+type IdentityPolicy struct {
+	AutoProvision bool
+	LinkExisting  bool
+	DefaultRoles  []string
+	RoleMapping   map[string][]string
+}
+
+// completeExternalLogin 外部身份校验通过后完成登录。
+// 本地两步验证仍然生效；密码有效期与强制修改密码只约束本地密码登录，不在此处检查。
+func (s *AuthService) completeExternalLogin(user *model.AdminUser, client ClientInfo) (*LoginResponse, error) {
+	if err := s.checkLoginIP(user, client); err != nil {
+		return nil, err
+	}
+	required, err := s.secondFactorRequired(user)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		resp, err := s.beginTwoFactorLogin(user)
+		if err != nil {
+			return nil, err
+		}
+		// 标记该待验证令牌来自外部身份，验证通过后直接签发令牌。
+		_ = s.cache.Set(twoFactorPendingKey(resp.TwoFactorToken)+":external", true, twoFactorPendingTTL)
+		return resp, nil
+	}
+	return s.issueTokens(user, client)
+}
+
+// resolveExternalUser 按绑定关系查找本地用户，未绑定时按规则绑定同名用户或自动创建。
+func (s *AuthService) resolveExternalUser(provider string, identity *ExternalIdentity, policy IdentityPolicy) (*model.AdminUser, error) {
+	var user model.AdminUser
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var link model.AdminUserIdentity
+		err := tx.Where("provider = ? AND subject = ?", provider, identity.Subject).First(&link).Error
+		switch {
+		case err == nil:
+			if err := tx.First(&user, link.UserID).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := s.linkExternalUser(tx, provider, identity, policy, &user); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+		if len(policy.RoleMapping) == 0 {
+			return nil
+		}
+		return syncMappedRoles(tx, user.ID, identity.Groups, policy.RoleMapping)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(policy.RoleMapping) > 0 || len(policy.DefaultRoles) > 0 {
+		s.InvalidateUserPermissionCache(user.ID)
+	}
+	return &user, nil
+}
+
+// linkExternalUser 为首次登录的外部身份绑定同名用户或创建新用户。
+func (s *AuthService) linkExternalUser(tx *gorm.DB, provider string, identity *ExternalIdentity, policy IdentityPolicy, user *model.AdminUser) error {
+	username := normalizeLoginUsername(identity.Username)
+	if username == "" {
+		return ErrSSOUserNotLinked
+	}
+
+	err := tx.Where("username = ?", username).First(user).Error
+	switch {
+	case err == nil:
+		if !policy.LinkExisting {
+			return ErrSSOUsernameTaken
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !policy.AutoProvision {
+			return ErrSSOUserNotLinked
+		}
+		if err := createExternalUser(tx, username, identity, policy.DefaultRoles, user); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	return tx.Create(&model.AdminUserIdentity{UserID: user.ID, Provider: provider, Subject: identity.Subject}).Error
+}
+
+// createExternalUser 创建外部身份对应的本地用户，本地密码为随机值，只能通过外部身份登录。
+func createExternalUser(tx *gorm.DB, username string, identity *ExternalIdentity, roleCodes []string, user *model.AdminUser) error {
+	plain, err := randomToken()
+	if err != nil {
+		return err
+	}
+	hashed, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	name := identity.Name
+	if name == "" {
+		name = username
+	}
+	*user = model.AdminUser{
+		Username:          username,
+		Password:          hashed,
+		Name:              truncate(name, 64),
+		Avatar:            truncate(identity.Avatar, 255),
+		Enabled:           true,
+		PasswordChangedAt: &now,
+	}
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	roleIDs, err := roleIDsByCodes(tx, roleCodes)
+	if err != nil || len(roleIDs) == 0 {
+		return err
+	}
+	records := make([]model.AdminUserRole, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		records = append(records, model.AdminUserRole{UserID: user.ID, RoleID: roleID})
+	}
+	return tx.Create(&records).Error
+}
+
+// syncMappedRoles 按外部用户组同步映射中出现的角色：命中的补齐，未命中的移除。
+func syncMappedRoles(tx *gorm.DB, userID uint, groups []string, mapping map[string][]string) error {
+	var managedCodes, desiredCodes []string
+	memberOf := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		memberOf[group] = true
+	}
+	for group, codes := range mapping {
+		managedCodes = append(managedCodes, codes...)
+		if memberOf[group] {
+			desiredCodes = append(desiredCodes, codes...)
+		}
+	}
+
+	managedIDs, err := roleIDsByCodes(tx, managedCodes)
+	if err != nil {
+		return err
+	}
+	desiredIDs, err := roleIDsByCodes(tx, desiredCodes)
+	if err != nil {
+		return err
+	}
+	desired := make(map[uint]bool, len(desiredIDs))
+	for _, id := range desiredIDs {
+		desired[id] = true
+	}
+
+	// 已到期或尚未生效的授权不算已拥有，命中时改为长期授权。
+	var grants []model.AdminUserRole
+	if err := tx.Where("user_id = ?", userID).Find(&grants).Error; err != nil {
+		return err
+	}
+	current := make(map[uint]*model.AdminUserRole, len(grants))
+	for i := range grants {
+		current[grants[i].RoleID] = &grants[i]
+	}
+
+	var removeIDs []uint
+	for _, id := range managedIDs {
+		if current[id] != nil && !desired[id] {
+			removeIDs = append(removeIDs, id)
+		}
+	}
+	if len(removeIDs) > 0 {
+		if err := tx.Where("user_id = ? AND role_id IN ?", userID, removeIDs).Delete(&model.AdminUserRole{}).Error; err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	for _, id := range desiredIDs {
+		grant := current[id]
+		switch {
+		case grant == nil:
+			if err := tx.Create(&model.AdminUserRole{UserID: userID, RoleID: id}).Error; err != nil {
+				return err
+			}
+		case !grantActive(grant.ValidFrom, grant.ValidUntil, now):
+			if err := tx.Model(grant).Updates(map[string]interface{}{"valid_from": nil, "valid_until": nil}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// roleIDsByCodes 按角色编码查询角色 ID，不存在的编码被忽略。
+func roleIDsByCodes(tx *gorm.DB, codes []string) ([]uint, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	err := tx.Model(&model.AdminRole{}).Where("code IN ?", codes).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// isExternalPending 判断待验证令牌是否来自外部身份登录。
+func (s *AuthService) isExternalPending(token string) bool {
+	return s.cache.Exists(twoFactorPendingKey(token) + ":external")
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/logger"
+	"bico-admin/internal/pkg/jwt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImpersonationForbidden = errors.New("仅超级管理员可以模拟登录")
+	ErrImpersonateSelf        = errors.New("不能模拟自己")
+	ErrImpersonationActive    = errors.New("模拟登录期间不能执行该操作")
+	ErrNotImpersonating       = errors.New("当前未处于模拟登录")
+	ErrImpersonationOriginEnd = errors.New("发起模拟的登录已失效，请重新登录")
+)
+
+// impersonationTTL 一次模拟登录的最长有效期，到期后需重新发起。
+const impersonationTTL = time.Hour
+
+// Impersonator 模拟登录接口，用户管理通过它签发模拟令牌。
+type Impersonator interface {
+	Impersonate(impersonatorID, targetID uint, originFamily string, client ClientInfo) (*LoginResponse, error)
+}
+
+// ImpersonatorInfo 模拟登录的实际操作者
+type ImpersonatorInfo struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// Impersonate 超级管理员以目标用户身份登录，用于排查目标用户看到的页面与权限。
+//
+// 签发的令牌同时携带目标用户与操作者身份，不创建会话、不占用目标用户的会话名额；
+// originFamily 为操作者当前登录，退出模拟时据此恢复。
+func (s *AuthService) Impersonate(impersonatorID, targetID uint, originFamily string, client ClientInfo) (*LoginResponse, error) {
+	actor, err := s.impersonationActor(impersonatorID)
+	if err != nil {
+		return nil, err
+	}
+	if impersonatorID == targetID {
+		return nil, ErrImpersonateSelf
+	}
+	var target model.AdminUser
+	if err := s.db.First(&target, targetID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !target.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	family, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(impersonationTTL)
+
+	var resp *LoginResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.AdminImpersonation{
+			ImpersonatorID:   actor.ID,
+			ImpersonatorName: actor.Username,
+			TargetID:         target.ID,
+			TargetName:       target.Username,
+			FamilyID:         family,
+			OriginFamilyID:   originFamily,
+			IP:               client.IP,
+			UserAgent:        truncate(client.UserAgent, 512),
+			ExpiresAt:        expiresAt,
+		}).Error; err != nil {
+			return err
+		}
+		var err error
+		resp, err = s.issueTokensInFamily(tx, &target, actor, family, expiresAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("开始模拟登录",
+		zap.Uint("impersonator_id", actor.ID),
+		zap.String("impersonator", actor.Username),
+		zap.Uint("target_id", target.ID),
+		zap.String("target", target.Username),
+		zap.String("ip", client.IP),
+	)
+	return resp, nil
+}
+
+// StopImpersonation 结束模拟登录并返回操作者自己的令牌。
+//
+// 只在发起模拟时的登录中签发新令牌；原登录已失效时返回 ErrImpersonationOriginEnd，
+// 操作者需重新登录，模拟令牌不能换取一次新的完整登录。
+func (s *AuthService) StopImpersonation(family string, client ClientInfo) (*LoginResponse, error) {
+	var record model.AdminImpersonation
+	if family == "" || s.db.Where("family_id = ?", family).First(&record).Error != nil {
+		return nil, ErrNotImpersonating
+	}
+	s.RevokeTokenFamily(family)
+	s.endImpersonation(family)
+
+	var actor model.AdminUser
+	if err := s.db.First(&actor, record.ImpersonatorID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !actor.Enabled {
+		return nil, ErrUserDisabled
+	}
+	resp, ok, err := s.resumeLogin(&actor, record.OriginFamilyID, client)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrImpersonationOriginEnd
+	}
+	return resp, nil
+}
+
+// resumeLogin 在原登录中为用户签发新令牌，原登录已失效时 ok 为 false。
+func (s *AuthService) resumeLogin(user *model.AdminUser, family string, client ClientInfo) (*LoginResponse, bool, error) {
+	if family == "" || s.IsTokenFamilyRevoked(family) {
+		return nil, false, nil
+	}
+	var latest model.AdminRefreshToken
+	if err := s.db.Where("family_id = ? AND user_id = ? AND revoked_at IS NULL", family, user.ID).
+		Order("id DESC").First(&latest).Error; err != nil {
+		return nil, false, nil
+	}
+	if latest.TokenVersion != user.TokenVersion || time.Now().After(latest.ExpiresAt) {
+		return nil, false, nil
+	}
+	if !s.IsUserIPAllowed(user.ID, client.IP) {
+		return nil, false, ErrIPDenied
+	}
+
+	var resp *LoginResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 发起模拟前持有的刷新令牌已被前端丢弃，作废后由新令牌接续。
+		if err := tx.Model(&model.AdminRefreshToken{}).
+			Where("family_id = ? AND used_at IS NULL AND revoked_at IS NULL", family).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		var err error
+		resp, err = s.issueTokensInFamily(tx, user, nil, family, latest.ExpiresAt)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	s.touchSession(family)
+	return resp, true, nil
+}
+
+// endImpersonation 记录模拟登录结束，退出模拟与退出登录都会调用。
+func (s *AuthService) endImpersonation(family string) {
+	var record model.AdminImpersonation
+	if err := s.db.Where("family_id = ? AND ended_at IS NULL", family).First(&record).Error; err != nil {
+		return
+	}
+	if err := s.db.Model(&record).Update("ended_at", time.Now()).Error; err != nil {
+		return
+	}
+	logger.Info("结束模拟登录",
+		zap.Uint("impersonator_id", record.ImpersonatorID),
+		zap.String("impersonator", record.ImpersonatorName),
+		zap.Uint("target_id", record.TargetID),
+		zap.String("target", record.TargetName),
+	)
+}
+
+// revokeDerivedImpersonations 吊销从 originFamily 发起且尚未结束的模拟登录，操作者的登录失效后模拟随之终止。
+func (s *AuthService) revokeDerivedImpersonations(originFamily string) {
+	var families []string
+	if err := s.db.Model(&model.AdminImpersonation{}).
+		Where("origin_family_id = ? AND ended_at IS NULL", originFamily).
+		Pluck("family_id", &families).Error; err != nil {
+		return
+	}
+	for _, family := range families {
+		s.RevokeTokenFamily(family)
+		s.endImpersonation(family)
+	}
+}
+
+// impersonationActor 校验操作者为启用的超级管理员，刷新模拟令牌时同样校验，撤销超级管理员后模拟随之终止。
+func (s *AuthService) impersonationActor(userID uint) (*model.AdminUser, error) {
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil || !user.Enabled {
+		return nil, ErrImpersonationForbidden
+	}
+	superAdmin, err := s.isSuperAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !superAdmin {
+		return nil, ErrImpersonationForbidden
+	}
+	return &user, nil
+}
+
+// jwtActor 将操作者转换为令牌中的 act 声明。
+func jwtActor(actor *model.AdminUser) *jwt.Actor {
+	if actor == nil {
+		return nil
+	}
+	return &jwt.Actor{UserID: actor.ID, Username: actor.Username}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestImpersonation 验证模拟登录的权限校验、令牌身份、刷新、审计记录、退出后恢复原会话，以及原登录失效后模拟终止。
+func TestImpersonation(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}, &model.AdminImpersonation{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	superRole := model.AdminRole{Name: "超级管理员", Code: model.SuperAdminRoleCode, Enabled: true}
+	admin := model.AdminUser{Username: "admin", Password: hashed, Enabled: true, Roles: []*model.AdminRole{&superRole}}
+	target := model.AdminUser{Username: "operator", Password: hashed, Enabled: true}
+	if err := database.Create(&admin).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+	if err := database.Create(&target).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute)
+	service := NewAuthService(database, manager, memoryCache)
+	client := ClientInfo{IP: "10.0.0.1"}
+
+	login, err := service.Login(&LoginRequest{Username: "admin", Password: "secret-password", Client: client})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	origin, _ := manager.ParseToken(login.Token)
+
+	if _, err := service.Impersonate(target.ID, admin.ID, "", client); !errors.Is(err, ErrImpersonationForbidden) {
+		t.Fatalf("非超级管理员不能模拟登录，实际: %v", err)
+	}
+	if _, err := service.Impersonate(admin.ID, admin.ID, origin.Family, client); !errors.Is(err, ErrImpersonateSelf) {
+		t.Fatalf("不能模拟自己，实际: %v", err)
+	}
+
+	impersonated, err := service.Impersonate(admin.ID, target.ID, origin.Family, client)
+	if err != nil {
+		t.Fatalf("模拟登录失败: %v", err)
+	}
+	claims, err := manager.ParseToken(impersonated.Token)
+	if err != nil || claims.UserID != target.ID || claims.Actor == nil || claims.Actor.UserID != admin.ID {
+		t.Fatalf("模拟令牌应同时携带目标用户与操作者: %+v err=%v", claims, err)
+	}
+	refreshed, err := service.Refresh(&RefreshRequest{RefreshToken: impersonated.RefreshToken, Client: client})
+	if err != nil {
+		t.Fatalf("刷新模拟令牌失败: %v", err)
+	}
+	if claims, _ := manager.ParseToken(refreshed.Token); claims.Actor == nil || claims.Actor.Username != "admin" {
+		t.Fatalf("刷新后应保留操作者身份: %+v", claims)
+	}
+	var sessions int64
+	database.Model(&model.AdminSession{}).Where("user_id = ?", target.ID).Count(&sessions)
+	if sessions != 0 {
+		t.Fatal("模拟登录不应占用目标用户的会话")
+	}
+
+	back, err := service.StopImpersonation(claims.Family, client)
+	if err != nil {
+		t.Fatalf("退出模拟失败: %v", err)
+	}
+	restored, _ := manager.ParseToken(back.Token)
+	if restored.UserID != admin.ID || restored.Actor != nil || restored.Family != origin.Family {
+		t.Fatalf("退出模拟后应回到操作者原会话: %+v", restored)
+	}
+	if !service.IsTokenFamilyRevoked(claims.Family) {
+		t.Fatal("退出模拟后模拟令牌应失效")
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: login.RefreshToken, Client: client}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("发起模拟前的刷新令牌应作废，实际: %v", err)
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: back.RefreshToken, Client: client}); err != nil {
+		t.Fatalf("恢复后的刷新令牌应可用: %v", err)
+	}
+
+	var record model.AdminImpersonation
+	database.First(&record)
+	if record.ImpersonatorID != admin.ID || record.TargetName != "operator" || record.EndedAt == nil {
+		t.Fatalf("审计记录不正确: %+v", record)
+	}
+
+	// 操作者的登录被下线后，由其发起的模拟随之终止。
+	derived, err := service.Impersonate(admin.ID, target.ID, origin.Family, client)
+	if err != nil {
+		t.Fatalf("模拟登录失败: %v", err)
+	}
+	derivedClaims, _ := manager.ParseToken(derived.Token)
+	if err := service.RevokeUserSessions(admin.ID); err != nil {
+		t.Fatalf("下线会话失败: %v", err)
+	}
+	if !service.IsTokenFamilyRevoked(derivedClaims.Family) {
+		t.Fatal("原登录下线后模拟令牌应失效")
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: derived.RefreshToken, Client: client}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("原登录下线后模拟令牌不能刷新，实际: %v", err)
+	}
+
+	// 原登录已过期时退出模拟需要重新登录，不能换取新的完整登录。
+	login, err = service.Login(&LoginRequest{Username: "admin", Password: "secret-password", Client: client})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	origin, _ = manager.ParseToken(login.Token)
+	expired, err := service.Impersonate(admin.ID, target.ID, origin.Family, client)
+	if err != nil {
+		t.Fatalf("模拟登录失败: %v", err)
+	}
+	expiredClaims, _ := manager.ParseToken(expired.Token)
+	database.Model(&model.AdminRefreshToken{}).Where("family_id = ?", origin.Family).Update("expires_at", time.Now().Add(-time.Minute))
+	var before int64
+	database.Model(&model.AdminSession{}).Where("user_id = ?", admin.ID).Count(&before)
+	if resp, err := service.StopImpersonation(expiredClaims.Family, client); !errors.Is(err, ErrImpersonationOriginEnd) || resp != nil {
+		t.Fatalf("原登录失效时应要求重新登录，实际: %+v err=%v", resp, err)
+	}
+	var after int64
+	database.Model(&model.AdminSession{}).Where("user_id = ?", admin.ID).Count(&after)
+	if after != before || !service.IsTokenFamilyRevoked(expiredClaims.Family) {
+		t.Fatalf("退出模拟不应创建新会话，且模拟令牌应失效: before=%d after=%d", before, after)
+	}
+
+	// 操作者失去超级管理员角色后，模拟令牌不能再刷新。
+	second, err := service.Impersonate(admin.ID, target.ID, origin.Family, client)
+	if err != nil {
+		t.Fatalf("模拟登录失败: %v", err)
+	}
+	database.Model(&admin).Association("Roles").Clear()
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: second.RefreshToken, Client: client}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("撤销超级管理员后模拟应终止，实际: %v", err)
+	}
+	var open int64
+	database.Model(&model.AdminImpersonation{}).Where("ended_at IS NULL").Count(&open)
+	if open != 0 {
+		t.Fatal("模拟终止后应记录结束时间")
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/ldapauth"
+)
+
+// ErrPasswordProviderUnavailable 外部目录连接或查询失败。
+var ErrPasswordProviderUnavailable = errors.New("认证服务暂不可用，请稍后重试")
+
+// PasswordProvider 替代本地密码校验的外部目录，如 LDAP / Active Directory。
+//
+// 用户不存在时返回 ErrUserNotFound，密码错误时返回 ErrInvalidCredentials，二者计入登录失败次数；
+// 其余错误视为目录服务不可用，不计入失败次数。
+type PasswordProvider interface {
+	Name() string
+	Authenticate(username, password string) (*ExternalIdentity, error)
+}
+
+// passwordProviderEntry 已启用的外部目录及其身份映射规则。
+type passwordProviderEntry struct {
+	provider   PasswordProvider
+	policy     IdentityPolicy
+	localUsers map[string]bool
+}
+
+// SetPasswordProvider 启用外部目录校验账号密码，localUsers 中的账号仍校验本地密码，用于应急登录。
+func (s *AuthService) SetPasswordProvider(provider PasswordProvider, policy IdentityPolicy, localUsers []string) {
+	entry := &passwordProviderEntry{provider: provider, policy: policy, localUsers: make(map[string]bool, len(localUsers))}
+	for _, username := range localUsers {
+		entry.localUsers[normalizeLoginUsername(username)] = true
+	}
+	s.passwordProvider = entry
+}
+
+// usesPasswordProvider 判断该账号是否由外部目录校验密码。
+func (s *AuthService) usesPasswordProvider(username string) bool {
+	return s.passwordProvider != nil && !s.passwordProvider.localUsers[username]
+}
+
+// loginWithPasswordProvider 由外部目录校验密码，映射为本地用户后完成登录。
+func (s *AuthService) loginWithPasswordProvider(username string, req *LoginRequest) (*LoginResponse, error) {
+	entry := s.passwordProvider
+	identity, err := entry.provider.Authenticate(username, req.Password)
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		s.recordLoginFailure(username, req.Client)
+		s.logLogin(username, 0, model.LoginReasonUserNotFound, req.Client)
+		return nil, ErrInvalidCredentials
+	case errors.Is(err, ErrInvalidCredentials):
+		s.recordLoginFailure(username, req.Client)
+		s.logLogin(username, 0, model.LoginReasonBadPassword, req.Client)
+		return nil, ErrInvalidCredentials
+	case err != nil:
+		s.logLogin(username, 0, model.LoginReasonProviderError, req.Client)
+		return nil, ErrPasswordProviderUnavailable
+	}
+
+	user, err := s.resolveExternalUser(entry.provider.Name(), identity, entry.policy)
+	if err != nil {
+		if errors.Is(err, ErrSSOUserNotLinked) || errors.Is(err, ErrSSOUsernameTaken) {
+			s.logLogin(username, 0, model.LoginReasonSSOUnlinked, req.Client)
+		}
+		return nil, err
+	}
+	if !user.Enabled {
+		s.logLogin(user.Username, user.ID, model.LoginReasonDisabled, req.Client)
+		return nil, ErrUserDisabled
+	}
+
+	s.clearLoginFailures(username)
+	return s.completeExternalLogin(user, req.Client)
+}
+
+// LDAPProvider 基于 LDAP 绑定认证的外部目录，以目录用户名作为身份标识。
+type LDAPProvider struct {
+	client *ldapauth.Client
+}
+
+// NewLDAPProvider 创建 LDAP 外部目录。
+func NewLDAPProvider(cfg ldapauth.Config) *LDAPProvider {
+	return &LDAPProvider{client: ldapauth.New(cfg)}
+}
+
+// Name 身份提供方标识
+func (p *LDAPProvider) Name() string {
+	return "ldap"
+}
+
+// Authenticate 绑定校验密码并读取用户组。
+func (p *LDAPProvider) Authenticate(username, password string) (*ExternalIdentity, error) {
+	entry, err := p.client.Authenticate(username, password)
+	switch {
+	case errors.Is(err, ldapauth.ErrUserNotFound):
+		return nil, ErrUserNotFound
+	case errors.Is(err, ldapauth.ErrInvalidCredentials):
+		return nil, ErrInvalidCredentials
+	case err != nil:
+		return nil, err
+	}
+	return &ExternalIdentity{
+		Subject:  entry.Username,
+		Username: entry.Username,
+		Name:     entry.Name,
+		Groups:   entry.Groups,
+	}, nil
+}
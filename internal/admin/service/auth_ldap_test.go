@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/ldapauth"
+	"bico-admin/internal/pkg/ldapauth/ldaptest"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestLoginWithLDAP 验证目录认证登录、自动创建用户、组角色同步与应急本地账号。
+func TestLoginWithLDAP(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminUserIdentity{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	for _, role := range []model.AdminRole{{Name: "运维", Code: "ops", Enabled: true}, {Name: "访客", Code: "viewer", Enabled: true}} {
+		if err := database.Create(&role).Error; err != nil {
+			t.Fatalf("创建角色失败: %v", err)
+		}
+	}
+	hashed, err := password.Hash("local-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	for _, username := range []string{"admin", "bob"} {
+		if err := database.Create(&model.AdminUser{Username: username, Password: hashed, Enabled: true}).Error; err != nil {
+			t.Fatalf("创建本地用户失败: %v", err)
+		}
+	}
+
+	directory := ldaptest.Start(t)
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour), memoryCache)
+	service.SetPasswordProvider(NewLDAPProvider(ldapauth.Config{
+		URL:          ldaptest.URL(directory),
+		BindDN:       ldaptest.BindDN,
+		BindPassword: ldaptest.BindPassword,
+		BaseDN:       ldaptest.BaseDN,
+		UserFilter:   ldaptest.UserFilter,
+		UsernameAttr: "cn",
+		NameAttr:     "displayName",
+		GroupAttr:    "memberOf",
+	}), IdentityPolicy{
+		AutoProvision: true,
+		DefaultRoles:  []string{"viewer"},
+		RoleMapping:   map[string][]string{"staff": {"ops"}},
+	}, []string{"admin"})
+
+	if _, err := service.Login(&LoginRequest{Username: "alice", Password: "wrong"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("目录密码错误应返回 ErrInvalidCredentials，实际: %v", err)
+	}
+	resp, err := service.Login(&LoginRequest{Username: "alice", Password: "alice-pass"})
+	if err != nil || resp.Token == "" {
+		t.Fatalf("目录认证登录失败: resp=%+v err=%v", resp, err)
+	}
+	var alice model.AdminUser
+	if err := database.Preload("Roles").Where("username = ?", "alice").First(&alice).Error; err != nil {
+		t.Fatalf("未自动创建用户: %v", err)
+	}
+	if alice.Name != "Alice Liddell" || !hasRoleCode(alice.Roles, "ops") || !hasRoleCode(alice.Roles, "viewer") {
+		t.Fatalf("自动创建的用户资料或角色不正确: %+v", alice)
+	}
+	if password.Verify(alice.Password, "alice-pass") {
+		t.Fatal("目录密码不应写入本地密码")
+	}
+
+	// 应急账号跳过目录服务校验本地密码，其余本地账号必须通过目录认证。
+	if _, err := service.Login(&LoginRequest{Username: "admin", Password: "local-password"}); err != nil {
+		t.Fatalf("应急账号登录失败: %v", err)
+	}
+	if _, err := service.Login(&LoginRequest{Username: "bob", Password: "local-password"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("非应急本地账号应由目录认证，实际: %v", err)
+	}
+
+	var reasons []string
+	database.Model(&model.LoginLog{}).Order("id").Pluck("reason", &reasons)
+	expected := []string{model.LoginReasonBadPassword, "", "", model.LoginReasonUserNotFound}
+	if len(reasons) != len(expected) {
+		t.Fatalf("期望登录日志 %v，实际 %v", expected, reasons)
+	}
+	for i := range expected {
+		if reasons[i] != expected[i] {
+			t.Fatalf("期望登录日志 %v，实际 %v", expected, reasons)
+		}
+	}
+
+	// 目录服务不可用时返回明确错误，且不计入失败次数。
+	service.SetPasswordProvider(NewLDAPProvider(ldapauth.Config{
+		URL:        "ldap://127.0.0.1:1",
+		BaseDN:     ldaptest.BaseDN,
+		UserFilter: ldaptest.UserFilter,
+		Timeout:    time.Second,
+	}), IdentityPolicy{}, []string{"admin"})
+	if _, err := service.Login(&LoginRequest{Username: "alice", Password: "alice-pass"}); !errors.Is(err, ErrPasswordProviderUnavailable) {
+		t.Fatalf("目录服务不可用应返回 ErrPasswordProviderUnavailable，实际: %v", err)
+	}
+	if _, err := service.Login(&LoginRequest{Username: "admin", Password: "local-password"}); err != nil {
+		t.Fatalf("目录服务不可用时应急账号应可登录: %v", err)
+	}
+}
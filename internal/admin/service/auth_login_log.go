@@ -0,0 +1,29 @@
+package service
+
+import (
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/useragent"
+)
+
+// LogCaptchaFailure 记录验证码错误的登录尝试，验证码在处理器层校验。
+func (s *AuthService) LogCaptchaFailure(username string, client ClientInfo) {
+	s.logLogin(normalizeLoginUsername(username), 0, model.LoginReasonBadCaptcha, client)
+}
+
+// logLogin 写入登录日志，reason 为空表示登录成功。
+// 日志写入失败不影响登录本身。
+func (s *AuthService) logLogin(username string, userID uint, reason string, client ClientInfo) {
+	result := model.LoginResultSuccess
+	if reason != "" {
+		result = model.LoginResultFailure
+	}
+	_ = s.db.Create(&model.LoginLog{
+		Username:  truncate(username, 64),
+		UserID:    userID,
+		Result:    result,
+		Reason:    reason,
+		Device:    useragent.Parse(client.UserAgent),
+		IP:        client.IP,
+		UserAgent: truncate(client.UserAgent, 512),
+	}).Error
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestLoginLogRecordsAttempts 验证登录成功与各类失败都会写入登录日志。
+func TestLoginLogRecordsAttempts(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "tester", Password: hashed, Enabled: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour), memoryCache)
+	client := ClientInfo{IP: "10.0.0.1", UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"}
+
+	service.LogCaptchaFailure(" tester ", client)
+	_, _ = service.Login(&LoginRequest{Username: "nobody", Password: "secret-password", Client: client})
+	_, _ = service.Login(&LoginRequest{Username: "tester", Password: "wrong-password", Client: client})
+	if _, err := service.Login(&LoginRequest{Username: "tester", Password: "secret-password", Client: client}); err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+
+	var logs []model.LoginLog
+	if err := database.Order("id").Find(&logs).Error; err != nil {
+		t.Fatalf("查询登录日志失败: %v", err)
+	}
+	expected := []struct {
+		username string
+		userID   uint
+		reason   string
+	}{
+		{"tester", 0, model.LoginReasonBadCaptcha},
+		{"nobody", 0, model.LoginReasonUserNotFound},
+		{"tester", user.ID, model.LoginReasonBadPassword},
+		{"tester", user.ID, ""},
+	}
+	if len(logs) != len(expected) {
+		t.Fatalf("期望 %d 条登录日志，实际 %d", len(expected), len(logs))
+	}
+	for i, want := range expected {
+		got := logs[i]
+		if got.Username != want.username || got.UserID != want.userID || got.Reason != want.reason {
+			t.Fatalf("第 %d 条登录日志不正确: %+v", i+1, got)
+		}
+		if (want.reason == "") != (got.Result == model.LoginResultSuccess) {
+			t.Fatalf("第 %d 条登录结果不正确: %+v", i+1, got)
+		}
+	}
+	if logs[3].IP != "10.0.0.1" || logs[3].Device != "Firefox / Linux" {
+		t.Fatalf("客户端信息未记录: %+v", logs[3])
+	}
+}
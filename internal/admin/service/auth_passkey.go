@@ -0,0 +1,448 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"bico-admin/internal/admin/model"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPasskeyDisabled = errors.New("未启用通行密钥")
+	ErrPasskeyNotFound = errors.New("通行密钥不存在")
+	ErrPasskeyExpired  = errors.New("通行密钥验证已过期，请重试")
+	ErrPasskeyInvalid  = errors.New("通行密钥验证失败")
+)
+
+// passkeyCeremonyTTL 注册与登录仪式的有效期，与浏览器默认超时一致。
+const passkeyCeremonyTTL = 5 * time.Minute
+
+// 登录时可用的两步验证方式
+const (
+	TwoFactorMethodTOTP    = "totp"
+	TwoFactorMethodPasskey = "passkey"
+)
+
+// PasskeyCeremony 下发给浏览器的注册或登录参数。
+//
+// Options 原样传给 navigator.credentials.create / get，完成后连同 Token 提交浏览器返回的凭据。
+type PasskeyCeremony struct {
+	Token   string `json:"token,omitempty"`
+	Options any    `json:"options" swaggertype:"object"`
+}
+
+// PasskeyRegisterRequest 完成通行密钥注册请求
+type PasskeyRegisterRequest struct {
+	Token string `json:"token" binding:"required"`
+	Name  string `json:"name" binding:"required,max=64"`
+	// Credential 为 navigator.credentials.create 返回的凭据 JSON。
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// PasskeyLoginRequest 通行密钥登录请求
+type PasskeyLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+	// Credential 为 navigator.credentials.get 返回的凭据 JSON。
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+	Client     ClientInfo      `json:"-"`
+}
+
+// PasskeyTwoFactorRequest 使用通行密钥完成两步验证的请求
+type PasskeyTwoFactorRequest struct {
+	TwoFactorToken string          `json:"twoFactorToken" binding:"required"`
+	Credential     json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+	Client         ClientInfo      `json:"-"`
+}
+
+// RenamePasskeyRequest 重命名通行密钥请求
+type RenamePasskeyRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// passkeyCeremonyState 服务端暂存的仪式状态，UserID 为 0 表示不限定用户的通行密钥登录。
+type passkeyCeremonyState struct {
+	UserID  uint                 `json:"user_id"`
+	Session webauthn.SessionData `json:"session"`
+}
+
+// passkeyUser 将后台用户适配为 WebAuthn 用户。
+type passkeyUser struct {
+	user        *model.AdminUser
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return passkeyUserHandle(u.user.ID)
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	if u.user.Name != "" {
+		return u.user.Name
+	}
+	return u.user.Username
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// SetWebAuthn 启用通行密钥注册、登录与两步验证。
+func (s *AuthService) SetWebAuthn(w *webauthn.WebAuthn) {
+	s.webauthn = w
+}
+
+// ListPasskeys 获取当前用户的通行密钥。
+func (s *AuthService) ListPasskeys(userID uint) ([]model.AdminPasskey, error) {
+	passkeys := make([]model.AdminPasskey, 0)
+	err := s.db.Where("user_id = ?", userID).Order("id DESC").Find(&passkeys).Error
+	return passkeys, err
+}
+
+// RenamePasskey 重命名当前用户的通行密钥。
+func (s *AuthService) RenamePasskey(userID, passkeyID uint, req *RenamePasskeyRequest) error {
+	result := s.db.Model(&model.AdminPasskey{}).
+		Where("id = ? AND user_id = ?", passkeyID, userID).
+		Update("name", strings.TrimSpace(req.Name))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// DeletePasskey 删除当前用户的通行密钥，删除后该密钥不能再登录。
+func (s *AuthService) DeletePasskey(userID, passkeyID uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", passkeyID, userID).Delete(&model.AdminPasskey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// BeginPasskeyRegistration 生成注册参数。已绑定的密钥列入排除列表，避免同一认证器重复注册；
+// 优先创建可发现凭据，这样密钥既可作为两步验证，也可免密码登录。
+func (s *AuthService) BeginPasskeyRegistration(userID uint) (*PasskeyCeremony, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	user, err := s.loadPasskeyUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	options, session, err := s.webauthn.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.savePasskeyCeremony(passkeyRegisterKey(token), userID, session); err != nil {
+		return nil, err
+	}
+	return &PasskeyCeremony{Token: token, Options: options}, nil
+}
+
+// FinishPasskeyRegistration 校验浏览器返回的凭据并保存公钥。
+func (s *AuthService) FinishPasskeyRegistration(userID uint, req *PasskeyRegisterRequest) (*model.AdminPasskey, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	state, ok := s.takePasskeyCeremony(passkeyRegisterKey(req.Token))
+	if !ok || state.UserID != userID {
+		return nil, ErrPasskeyExpired
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+	user, err := s.loadPasskeyUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.webauthn.CreateCredential(user, state.Session, parsed)
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	passkey := model.AdminPasskey{
+		UserID:          userID,
+		Name:            strings.TrimSpace(req.Name),
+		CredentialID:    encodeCredentialID(credential.ID),
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		Flags:           uint8(credential.Flags.ProtocolValue()),
+		SignCount:       credential.Authenticator.SignCount,
+	}
+	if err := s.db.Create(&passkey).Error; err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+// BeginPasskeyLogin 生成免密码登录参数，不限定用户，由认证器选择可发现凭据并要求用户验证。
+func (s *AuthService) BeginPasskeyLogin() (*PasskeyCeremony, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	options, session, err := s.webauthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.savePasskeyCeremony(passkeyLoginKey(token), 0, session); err != nil {
+		return nil, err
+	}
+	return &PasskeyCeremony{Token: token, Options: options}, nil
+}
+
+// FinishPasskeyLogin 使用通行密钥免密码登录。
+//
+// 认证器已完成持有与用户验证两个因素，因此不再要求两步验证；与单点登录一样，
+// 不检查密码有效期，也不受密码失败锁定影响，来源 IP 限制与「下次登录修改密码」照常生效。
+func (s *AuthService) FinishPasskeyLogin(req *PasskeyLoginRequest) (*LoginResponse, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	state, ok := s.takePasskeyCeremony(passkeyLoginKey(req.Token))
+	if !ok {
+		return nil, ErrPasskeyExpired
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+
+	var user *passkeyUser
+	_, credential, err := s.webauthn.ValidatePasskeyLogin(func(_, userHandle []byte) (webauthn.User, error) {
+		userID, err := strconv.ParseUint(string(userHandle), 10, 64)
+		if err != nil {
+			return nil, ErrPasskeyInvalid
+		}
+		user, err = s.loadPasskeyUser(uint(userID))
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}, state.Session, parsed)
+	if err == nil && credential.Authenticator.CloneWarning {
+		err = ErrPasskeyInvalid
+	}
+	if err != nil {
+		if user != nil {
+			s.logLogin(user.user.Username, user.user.ID, model.LoginReasonBadPasskey, req.Client)
+		}
+		return nil, ErrPasskeyInvalid
+	}
+
+	if !user.user.Enabled {
+		s.logLogin(user.user.Username, user.user.ID, model.LoginReasonDisabled, req.Client)
+		return nil, ErrUserDisabled
+	}
+	s.touchPasskey(credential, req.Client)
+	if user.user.MustChangePassword {
+		return s.issuePasswordChangeToken(user.user)
+	}
+	return s.issueTokens(user.user, req.Client)
+}
+
+// BeginPasskeyTwoFactor 为登录中待两步验证的用户生成通行密钥验证参数，只允许其已绑定的密钥。
+func (s *AuthService) BeginPasskeyTwoFactor(req *TwoFactorPendingRequest) (*PasskeyCeremony, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	userID, ok := s.pendingUserID(req.TwoFactorToken)
+	if !ok {
+		return nil, ErrTwoFactorTokenInvalid
+	}
+	user, err := s.loadPasskeyUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user.credentials) == 0 {
+		return nil, ErrPasskeyNotFound
+	}
+	options, session, err := s.webauthn.BeginLogin(user)
+	if err != nil {
+		return nil, err
+	}
+	// 两步验证沿用待验证令牌，不另发仪式令牌。
+	if err := s.savePasskeyCeremony(twoFactorPendingKey(req.TwoFactorToken)+":passkey", userID, session); err != nil {
+		return nil, err
+	}
+	return &PasskeyCeremony{Options: options}, nil
+}
+
+// VerifyTwoFactorPasskey 使用通行密钥完成两步验证，失败次数与动态码共用同一上限。
+func (s *AuthService) VerifyTwoFactorPasskey(req *PasskeyTwoFactorRequest) (*LoginResponse, error) {
+	if s.webauthn == nil {
+		return nil, ErrPasskeyDisabled
+	}
+	userID, ok := s.pendingUserID(req.TwoFactorToken)
+	if !ok {
+		return nil, ErrTwoFactorTokenInvalid
+	}
+	state, ok := s.takePasskeyCeremony(twoFactorPendingKey(req.TwoFactorToken) + ":passkey")
+	if !ok || state.UserID != userID {
+		return nil, ErrPasskeyExpired
+	}
+	user, err := s.loadPasskeyUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.user.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	var credential *webauthn.Credential
+	if err == nil {
+		credential, err = s.webauthn.ValidateLogin(user, state.Session, parsed)
+	}
+	if err == nil && credential.Authenticator.CloneWarning {
+		err = ErrPasskeyInvalid
+	}
+	if err != nil {
+		s.recordTwoFactorFailure(req.TwoFactorToken)
+		s.logLogin(user.user.Username, user.user.ID, model.LoginReasonBadPasskey, req.Client)
+		return nil, ErrPasskeyInvalid
+	}
+
+	s.touchPasskey(credential, req.Client)
+	return s.finishTwoFactorLogin(user.user, req.TwoFactorToken, req.Client)
+}
+
+// hasPasskeys 判断用户是否绑定了通行密钥，未启用通行密钥时视为没有。
+func (s *AuthService) hasPasskeys(userID uint) (bool, error) {
+	if s.webauthn == nil {
+		return false, nil
+	}
+	var count int64
+	err := s.db.Model(&model.AdminPasskey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// loadPasskeyUser 加载用户及其已绑定的通行密钥。
+func (s *AuthService) loadPasskeyUser(userID uint) (*passkeyUser, error) {
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	var passkeys []model.AdminPasskey
+	if err := s.db.Where("user_id = ?", userID).Find(&passkeys).Error; err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		id, err := base64.RawURLEncoding.DecodeString(passkey.CredentialID)
+		if err != nil {
+			continue
+		}
+		transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+		for _, transport := range passkey.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              id,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transport:       transports,
+			Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(passkey.Flags)),
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    passkey.AAGUID,
+				SignCount: passkey.SignCount,
+			},
+		})
+	}
+	return &passkeyUser{user: &user, credentials: credentials}, nil
+}
+
+// touchPasskey 登录成功后记录签名计数与最近使用信息。
+func (s *AuthService) touchPasskey(credential *webauthn.Credential, client ClientInfo) {
+	_ = s.db.Model(&model.AdminPasskey{}).
+		Where("credential_id = ?", encodeCredentialID(credential.ID)).
+		Updates(map[string]interface{}{
+			"sign_count":   credential.Authenticator.SignCount,
+			"last_used_at": time.Now(),
+			"last_used_ip": client.IP,
+		}).Error
+}
+
+// savePasskeyCeremony 暂存仪式状态，序列化为字符串保存，内存缓存与 Redis 读出的格式一致。
+func (s *AuthService) savePasskeyCeremony(key string, userID uint, session *webauthn.SessionData) error {
+	data, err := json.Marshal(passkeyCeremonyState{UserID: userID, Session: *session})
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(key, string(data), passkeyCeremonyTTL)
+}
+
+// takePasskeyCeremony 读取并删除仪式状态，每次挑战只能提交一次。
+func (s *AuthService) takePasskeyCeremony(key string) (*passkeyCeremonyState, bool) {
+	value, err := s.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	_ = s.cache.Delete(key)
+	data, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var state passkeyCeremonyState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, false
+	}
+	return &state, true
+}
+
+// passkeyUserHandle 认证器中保存的用户标识，使用用户 ID 而非用户名，改名后仍可登录。
+func passkeyUserHandle(userID uint) []byte {
+	return []byte(strconv.FormatUint(uint64(userID), 10))
+}
+
+func encodeCredentialID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+func passkeyRegisterKey(token string) string {
+	return "auth:passkey:register:" + token
+}
+
+func passkeyLoginKey(token string) string {
+	return "auth:passkey:login:" + token
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+	"bico-admin/internal/pkg/totp"
+	"bico-admin/internal/pkg/webauthntest"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+// TestPasskeyFlow 验证通行密钥注册、作为两步验证、免密码登录、签名计数回退检测与管理。
+func TestPasskeyFlow(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{},
+		&model.AdminSession{}, &model.LoginLog{}, &model.AdminLoginLock{}, &model.AdminPasskey{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "alice", Name: "Alice", Password: hashed, Enabled: true}
+	other := model.AdminUser{Username: "bob", Password: hashed, Enabled: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+	if err := database.Create(&other).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	const origin = "http://localhost:8000"
+	w, err := webauthn.New(&webauthn.Config{RPID: "localhost", RPDisplayName: "Bico Admin", RPOrigins: []string{origin}})
+	if err != nil {
+		t.Fatalf("创建 WebAuthn 失败: %v", err)
+	}
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	service.SetWebAuthn(w)
+	authenticator := webauthntest.New(origin)
+	client := ClientInfo{IP: "10.0.0.1"}
+
+	// 注册
+	ceremony, err := service.BeginPasskeyRegistration(user.ID)
+	if err != nil {
+		t.Fatalf("获取注册参数失败: %v", err)
+	}
+	created, err := webauthntest.New(origin).Create(mustJSON(t, ceremony.Options))
+	if err != nil {
+		t.Fatalf("认证器创建凭据失败: %v", err)
+	}
+	register := &PasskeyRegisterRequest{Token: ceremony.Token, Name: "MacBook", Credential: created}
+	if _, err := service.FinishPasskeyRegistration(other.ID, register); !errors.Is(err, ErrPasskeyExpired) {
+		t.Fatalf("其他用户不能完成该注册: %v", err)
+	}
+	ceremony, _ = service.BeginPasskeyRegistration(user.ID)
+	created, _ = authenticator.Create(mustJSON(t, ceremony.Options))
+	register = &PasskeyRegisterRequest{Token: ceremony.Token, Name: "MacBook", Credential: created}
+	passkey, err := service.FinishPasskeyRegistration(user.ID, register)
+	if err != nil {
+		t.Fatalf("注册通行密钥失败: %v", err)
+	}
+	if _, err := service.FinishPasskeyRegistration(user.ID, register); !errors.Is(err, ErrPasskeyExpired) {
+		t.Fatalf("注册挑战只能使用一次: %v", err)
+	}
+
+	// 绑定后密码登录需通过通行密钥完成两步验证。
+	resp, err := service.Login(&LoginRequest{Username: "alice", Password: "secret-password", Client: client})
+	if err != nil {
+		t.Fatalf("密码登录失败: %v", err)
+	}
+	if resp.Token != "" || resp.TwoFactorSetup || !slices.Equal(resp.TwoFactorMethods, []string{TwoFactorMethodPasskey}) {
+		t.Fatalf("期望要求通行密钥两步验证: %+v", resp)
+	}
+	// 只绑定了通行密钥时，持有密码不能在验证阶段改绑动态码来绕过通行密钥。
+	pending := &TwoFactorPendingRequest{TwoFactorToken: resp.TwoFactorToken}
+	if _, err := service.SetupPendingTwoFactor(pending); !errors.Is(err, ErrTwoFactorSetupForbidden) {
+		t.Fatalf("已绑定通行密钥时不应允许绑定动态码: %v", err)
+	}
+	key, err := service.SetupTwoFactor(user.ID)
+	if err != nil {
+		t.Fatalf("生成动态码密钥失败: %v", err)
+	}
+	code, _ := totp.GenerateCode(key.Secret, time.Now())
+	bypass, err := service.VerifyTwoFactor(&TwoFactorVerifyRequest{TwoFactorToken: resp.TwoFactorToken, Code: code, Client: client})
+	if !errors.Is(err, ErrTwoFactorSetupForbidden) || bypass != nil {
+		t.Fatalf("只绑定通行密钥时动态码不应完成登录: %+v err=%v", bypass, err)
+	}
+	if database.First(&user, user.ID); user.TwoFactorEnabled {
+		t.Fatal("验证阶段不应绑定动态码")
+	}
+
+	ceremony, err = service.BeginPasskeyTwoFactor(pending)
+	if err != nil {
+		t.Fatalf("获取两步验证参数失败: %v", err)
+	}
+	assertion, err := authenticator.Get(mustJSON(t, ceremony.Options))
+	if err != nil {
+		t.Fatalf("认证器签名失败: %v", err)
+	}
+	verified, err := service.VerifyTwoFactorPasskey(&PasskeyTwoFactorRequest{TwoFactorToken: resp.TwoFactorToken, Credential: assertion, Client: client})
+	if err != nil || verified.Token == "" {
+		t.Fatalf("通行密钥两步验证失败: %v", err)
+	}
+
+	// 免密码登录，克隆的认证器在原认证器使用后计数回退，应被拒绝。
+	cloned := authenticator.Clone()
+	if resp := passkeyLogin(t, service, authenticator, client); resp.Token == "" {
+		t.Fatalf("通行密钥登录未返回令牌: %+v", resp)
+	}
+	ceremony, _ = service.BeginPasskeyLogin()
+	assertion, _ = cloned.Get(mustJSON(t, ceremony.Options))
+	if _, err := service.FinishPasskeyLogin(&PasskeyLoginRequest{Token: ceremony.Token, Credential: assertion, Client: client}); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Fatalf("签名计数回退应拒绝登录: %v", err)
+	}
+	var stored model.AdminPasskey
+	database.First(&stored, passkey.ID)
+	if stored.SignCount != 2 || stored.LastUsedAt == nil || stored.LastUsedIP != client.IP {
+		t.Fatalf("应记录签名计数与最近使用: %+v", stored)
+	}
+	var failures int64
+	database.Model(&model.LoginLog{}).Where("reason = ?", model.LoginReasonBadPasskey).Count(&failures)
+	if failures != 1 {
+		t.Fatalf("期望记录 1 条通行密钥失败日志，实际 %d", failures)
+	}
+
+	// 被要求修改密码的用户通过通行密钥登录时同样需要先修改密码。
+	database.Model(&user).Update("must_change_password", true)
+	if resp := passkeyLogin(t, service, authenticator, client); resp.Token != "" || resp.PasswordChangeToken == "" {
+		t.Fatalf("需要修改密码时不应直接签发令牌: %+v", resp)
+	}
+	database.Model(&user).Update("must_change_password", false)
+
+	// 管理：只能操作自己的通行密钥，删除后密码登录不再要求两步验证。
+	if err := service.RenamePasskey(other.ID, passkey.ID, &RenamePasskeyRequest{Name: "x"}); !errors.Is(err, ErrPasskeyNotFound) {
+		t.Fatalf("不能重命名他人的通行密钥: %v", err)
+	}
+	if err := service.RenamePasskey(user.ID, passkey.ID, &RenamePasskeyRequest{Name: "工作电脑"}); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if list, _ := service.ListPasskeys(user.ID); len(list) != 1 || list[0].Name != "工作电脑" {
+		t.Fatalf("列表应包含重命名后的通行密钥: %+v", list)
+	}
+	if err := service.DeletePasskey(other.ID, passkey.ID); !errors.Is(err, ErrPasskeyNotFound) {
+		t.Fatalf("不能删除他人的通行密钥: %v", err)
+	}
+	if err := service.DeletePasskey(user.ID, passkey.ID); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	resp, err = service.Login(&LoginRequest{Username: "alice", Password: "secret-password", Client: client})
+	if err != nil || resp.Token == "" {
+		t.Fatalf("删除通行密钥后应直接登录: %+v, %v", resp, err)
+	}
+}
+
+// passkeyLogin 使用认证器完成一次免密码登录。
+func passkeyLogin(t *testing.T, service *AuthService, authenticator *webauthntest.Authenticator, client ClientInfo) *LoginResponse {
+	t.Helper()
+	ceremony, err := service.BeginPasskeyLogin()
+	if err != nil {
+		t.Fatalf("获取登录参数失败: %v", err)
+	}
+	assertion, err := authenticator.Get(mustJSON(t, ceremony.Options))
+	if err != nil {
+		t.Fatalf("认证器签名失败: %v", err)
+	}
+	resp, err := service.FinishPasskeyLogin(&PasskeyLoginRequest{Token: ceremony.Token, Credential: assertion, Client: client})
+	if err != nil {
+		t.Fatalf("通行密钥登录失败: %v", err)
+	}
+	return resp
+}
+
+func mustJSON(t *testing.T, value any) []byte {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/logger"
+	"bico-admin/internal/pkg/password"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPasswordReused             = errors.New("新密码不能与最近使用过的密码相同")
+	ErrPasswordChangeTokenInvalid = errors.New("修改密码已过期，请重新登录")
+)
+
+const passwordChangePendingTTL = 10 * time.Minute
+
+// passwordPolicy 全局密码策略，启动时由配置设置，认证服务与用户管理共用。
+var passwordPolicy password.Policy
+
+// SetPasswordPolicy 设置密码策略。
+func SetPasswordPolicy(policy password.Policy) {
+	passwordPolicy = policy
+}
+
+// ExpiredPasswordRequest 登录时强制修改密码请求
+type ExpiredPasswordRequest struct {
+	PasswordChangeToken string     `json:"passwordChangeToken" binding:"required"`
+	NewPassword         string     `json:"newPassword" binding:"required,min=8"`
+	Client              ClientInfo `json:"-"`
+}
+
+// CheckNewPassword 按密码策略校验新密码，已有用户还会检查最近使用过的密码。
+func CheckNewPassword(db *gorm.DB, user *model.AdminUser, plain string) error {
+	if err := passwordPolicy.Validate(plain, user.Username); err != nil {
+		return err
+	}
+	if user.ID == 0 || passwordPolicy.History <= 0 {
+		return nil
+	}
+	// 历史只保存被替换的旧密码，当前密码单独比较。
+	if user.Password != "" && password.Verify(user.Password, plain) {
+		return ErrPasswordReused
+	}
+	var hashes []string
+	if err := db.Model(&model.AdminPasswordHistory{}).
+		Where("user_id = ?", user.ID).
+		Order("id DESC").
+		Limit(passwordPolicy.History).
+		Pluck("password_hash", &hashes).Error; err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if password.Verify(hash, plain) {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// RecordPasswordHistory 将用户即将被替换的当前密码写入历史，并只保留策略要求的条数。
+// 需在更新密码之前调用：当前密码单独比较，历史只保存此前的 N 次密码。
+func RecordPasswordHistory(tx *gorm.DB, userID uint) error {
+	if passwordPolicy.History <= 0 {
+		return nil
+	}
+	var user model.AdminUser
+	if err := tx.Select("id", "password").First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Password == "" {
+		return nil
+	}
+	if err := tx.Create(&model.AdminPasswordHistory{UserID: userID, PasswordHash: user.Password}).Error; err != nil {
+		return err
+	}
+	var keepIDs []uint
+	if err := tx.Model(&model.AdminPasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(passwordPolicy.History).
+		Pluck("id", &keepIDs).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ? AND id NOT IN ?", userID, keepIDs).Delete(&model.AdminPasswordHistory{}).Error
+}
+
+// PasswordChangeRequired 判断用户登录前是否必须修改密码：管理员要求修改或密码已过期。
+func PasswordChangeRequired(user *model.AdminUser) bool {
+	if user.MustChangePassword {
+		return true
+	}
+	changedAt := time.Time(user.CreatedAt)
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	return passwordPolicy.Expired(changedAt, time.Now())
+}
+
+// rehashPassword 在密码校验通过后按当前哈希配置重新生成哈希。
+// 只替换同一密码的哈希，不视为修改密码，因此不更新修改时间、历史记录与令牌版本；失败时仅记录日志。
+func (s *AuthService) rehashPassword(user *model.AdminUser, plain string) {
+	if !password.NeedsRehash(user.Password) {
+		return
+	}
+	hashed, err := password.Hash(plain)
+	if err == nil {
+		err = s.db.Model(&model.AdminUser{}).Where("id = ? AND password = ?", user.ID, user.Password).
+			UpdateColumn("password", hashed).Error
+	}
+	if err != nil {
+		logger.Warn("重新生成密码哈希失败", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+	user.Password = hashed
+}
+
+// ChangeExpiredPassword 使用登录返回的 passwordChangeToken 设置新密码并完成登录。
+func (s *AuthService) ChangeExpiredPassword(req *ExpiredPasswordRequest) (*LoginResponse, error) {
+	key := passwordChangePendingKey(req.PasswordChangeToken)
+	userID, ok := s.cachedUserID(key)
+	if !ok {
+		return nil, ErrPasswordChangeTokenInvalid
+	}
+
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Enabled {
+		return nil, ErrUserDisabled
+	}
+	if err := s.setPassword(&user, req.NewPassword); err != nil {
+		return nil, err
+	}
+	_ = s.cache.Delete(key)
+
+	// 修改密码会递增令牌版本，需要按新版本签发令牌。
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return s.issueTokens(&user, req.Client)
+}
+
+// completeLogin 身份校验全部通过后签发令牌；需要修改密码时改为签发修改密码令牌。
+func (s *AuthService) completeLogin(user *model.AdminUser, client ClientInfo) (*LoginResponse, error) {
+	if !PasswordChangeRequired(user) {
+		return s.issueTokens(user, client)
+	}
+	return s.issuePasswordChangeToken(user)
+}
+
+// issuePasswordChangeToken 签发修改密码令牌，修改密码后才签发登录令牌。
+func (s *AuthService) issuePasswordChangeToken(user *model.AdminUser) (*LoginResponse, error) {
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(passwordChangePendingKey(token), user.ID, passwordChangePendingTTL); err != nil {
+		return nil, err
+	}
+	return &LoginResponse{
+		PasswordChangeToken: token,
+		PasswordExpired:     !user.MustChangePassword,
+	}, nil
+}
+
+// setPassword 校验并保存新密码，同时使旧登录失效。
+func (s *AuthService) setPassword(user *model.AdminUser, plain string) error {
+	if err := CheckNewPassword(s.db, user, plain); err != nil {
+		return err
+	}
+	hashed, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := RecordPasswordHistory(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Model(&model.AdminUser{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"password":             hashed,
+			"password_changed_at":  time.Now(),
+			"must_change_password": false,
+			"token_version":        gorm.Expr("token_version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.InvalidateUserTokenVersionCache(user.ID)
+	return nil
+}
+
+// passwordChangePendingKey 构建修改密码令牌缓存 key。
+func passwordChangePendingKey(token string) string {
+	return "auth:pwd:pending:" + token
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/logger"
+	"bico-admin/internal/pkg/mail"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPasswordResetDisabled = errors.New("未开启找回密码，请联系管理员")
+	ErrPasswordResetInvalid  = errors.New("重置链接无效或已过期，请重新申请")
+	ErrEmailInvalid          = errors.New("邮箱格式错误")
+)
+
+const (
+	// passwordResetCooldown 同一用户两次发送重置邮件的最小间隔，避免邮箱被刷。
+	passwordResetCooldown    = time.Minute
+	passwordResetSendTimeout = 30 * time.Second
+)
+
+// ForgotPasswordRequest 找回密码请求，Account 可填写用户名或邮箱。
+type ForgotPasswordRequest struct {
+	Account string `json:"account" binding:"required,max=128"`
+}
+
+// ResetPasswordRequest 凭邮件中的重置令牌设置新密码。
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
+}
+
+// passwordResetEntry 找回密码的发信方式与链接配置。
+type passwordResetEntry struct {
+	mailer mail.Mailer
+	url    string
+	ttl    time.Duration
+}
+
+// SetPasswordReset 开启找回密码，url 中的 {token} 会替换为重置令牌。
+func (s *AuthService) SetPasswordReset(mailer mail.Mailer, url string, ttl time.Duration) {
+	s.passwordReset = &passwordResetEntry{mailer: mailer, url: url, ttl: ttl}
+}
+
+// ForgotPassword 为匹配的用户签发一次性重置令牌并发送邮件。
+//
+// 无论账号是否存在、是否绑定邮箱都返回成功，避免借此枚举账号；邮件异步发送，响应耗时同样不暴露结果。
+// 使用目录服务登录的账号密码不由本系统管理，不发送重置邮件。
+func (s *AuthService) ForgotPassword(req *ForgotPasswordRequest) error {
+	if s.passwordReset == nil {
+		return ErrPasswordResetDisabled
+	}
+	account := strings.TrimSpace(req.Account)
+	var users []model.AdminUser
+	if err := s.db.Where("username = ? OR email = ?", normalizeLoginUsername(account), NormalizeEmail(account)).Find(&users).Error; err != nil {
+		return err
+	}
+	for i := range users {
+		user := &users[i]
+		if !user.Enabled || user.Email == "" || s.usesPasswordProvider(user.Username) {
+			continue
+		}
+		if s.cache.Exists(passwordResetSentKey(user.ID)) {
+			continue
+		}
+		token, err := s.issuePasswordResetToken(user.ID)
+		if err != nil {
+			return err
+		}
+		go s.sendPasswordResetMail(*user, token)
+	}
+	return nil
+}
+
+// ResetPassword 校验重置令牌并设置新密码。
+//
+// 新密码不符合策略时令牌保留，可修改后重试；设置成功后令牌作废，令牌版本递增使全部已登录会话失效，
+// 并解除登录锁定。
+func (s *AuthService) ResetPassword(req *ResetPasswordRequest) error {
+	if s.passwordReset == nil {
+		return ErrPasswordResetDisabled
+	}
+	key := passwordResetKey(hashToken(req.Token))
+	userID, ok := s.cachedUserID(key)
+	if !ok {
+		return ErrPasswordResetInvalid
+	}
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil || !user.Enabled {
+		return ErrPasswordResetInvalid
+	}
+	if err := s.setPassword(&user, req.NewPassword); err != nil {
+		return err
+	}
+	_ = s.cache.Delete(key)
+	_ = s.cache.Delete(passwordResetUserKey(user.ID))
+	s.clearLoginFailures(user.Username)
+	return nil
+}
+
+// issuePasswordResetToken 签发重置令牌，缓存中只保存摘要；同一用户重新申请时旧令牌作废。
+func (s *AuthService) issuePasswordResetToken(userID uint) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	hash := hashToken(token)
+	userKey := passwordResetUserKey(userID)
+	if value, err := s.cache.Get(userKey); err == nil {
+		if previous, ok := value.(string); ok {
+			_ = s.cache.Delete(passwordResetKey(previous))
+		}
+	}
+	ttl := s.passwordReset.ttl
+	if err := s.cache.Set(passwordResetKey(hash), userID, ttl); err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(userKey, hash, ttl); err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(passwordResetSentKey(userID), true, passwordResetCooldown); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// sendPasswordResetMail 发送重置邮件，失败只记录日志，用户可在冷却时间后重新申请。
+func (s *AuthService) sendPasswordResetMail(user model.AdminUser, token string) {
+	entry := s.passwordReset
+	link := strings.ReplaceAll(entry.url, "{token}", token)
+	appName := s.issuer
+	if appName == "" {
+		appName = "Bico Admin"
+	}
+	body := fmt.Sprintf("%s，您好：\n\n我们收到了账号 %s 的找回密码申请，请在 %d 分钟内打开以下链接设置新密码：\n\n%s\n\n链接只能使用一次。如果不是您本人操作，请忽略本邮件，您的密码不会改变。\n",
+		displayName(&user), user.Username, int(entry.ttl.Minutes()), link)
+
+	ctx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+	defer cancel()
+	err := entry.mailer.Send(ctx, &mail.Message{
+		To:      []string{user.Email},
+		Subject: appName + " 找回密码",
+		Body:    body,
+	})
+	if err != nil {
+		logger.Error("发送找回密码邮件失败", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+}
+
+// NormalizeEmail 去除首尾空白并转为小写，保存与查询邮箱时统一使用。
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidEmail 判断是否为不带显示名的邮箱地址。
+func ValidEmail(email string) bool {
+	addr, err := netmail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+// displayName 返回邮件中对用户的称呼。
+func displayName(user *model.AdminUser) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Username
+}
+
+// passwordResetKey 构建重置令牌缓存 key，key 中只包含令牌摘要。
+func passwordResetKey(hash string) string {
+	return "auth:pwd:reset:" + hash
+}
+
+// passwordResetUserKey 构建用户当前有效重置令牌的缓存 key。
+func passwordResetUserKey(userID uint) string {
+	return fmt.Sprintf("auth:pwd:reset:user:%d", userID)
+}
+
+// passwordResetSentKey 构建重置邮件发送冷却缓存 key。
+func passwordResetSentKey(userID uint) string {
+	return fmt.Sprintf("auth:pwd:reset:sent:%d", userID)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/mail"
+	"bico-admin/internal/pkg/mail/mailtest"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+var resetTokenPattern = regexp.MustCompile(`reset_token=([0-9a-f]{64})`)
+
+// TestPasswordResetByEmail 验证经 SMTP 发送重置邮件、令牌一次性使用、重新申请作废旧令牌，以及重置后旧登录失效并解除锁定。
+func TestPasswordResetByEmail(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.AdminPasswordHistory{}, &model.AdminLoginLock{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("initial-pass1")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	alice := model.AdminUser{Username: "alice", Name: "Alice", Email: "alice@example.com", Password: hashed, Enabled: true}
+	bob := model.AdminUser{Username: "bob", Password: hashed, Enabled: true}
+	if err := database.Create(&[]*model.AdminUser{&alice, &bob}).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	if err := service.ForgotPassword(&ForgotPasswordRequest{Account: "alice"}); !errors.Is(err, ErrPasswordResetDisabled) {
+		t.Fatalf("未配置邮件时应返回 ErrPasswordResetDisabled，实际: %v", err)
+	}
+
+	server := mailtest.Start(t, "", "")
+	mailer := mail.NewSMTPMailer(mail.SMTPConfig{Host: server.Host(), Port: server.Port(), From: "noreply@example.com", Encryption: mail.EncryptionNone})
+	service.SetPasswordReset(mailer, "https://admin.example.com/#/auth/login?reset_token={token}", 30*time.Minute)
+
+	login, err := service.Login(&LoginRequest{Username: "alice", Password: "initial-pass1"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	// requestToken 申请找回密码并从收到的邮件中取出重置令牌。
+	requestToken := func(account string) string {
+		t.Helper()
+		if err := service.ForgotPassword(&ForgotPasswordRequest{Account: account}); err != nil {
+			t.Fatalf("申请找回密码失败: %v", err)
+		}
+		msg, ok := server.Next(2 * time.Second)
+		if !ok {
+			t.Fatal("未收到重置邮件")
+		}
+		if len(msg.To) != 1 || msg.To[0] != alice.Email {
+			t.Fatalf("收件人错误: %v", msg.To)
+		}
+		match := resetTokenPattern.FindStringSubmatch(msg.Body)
+		if match == nil {
+			t.Fatalf("邮件中没有重置链接: %s", msg.Body)
+		}
+		return match[1]
+	}
+
+	first := requestToken(" Alice@Example.com ")
+	// 冷却时间内重复申请与未绑定邮箱的账号都不发信，但同样返回成功。
+	for _, account := range []string{"alice", "bob", "nobody"} {
+		if err := service.ForgotPassword(&ForgotPasswordRequest{Account: account}); err != nil {
+			t.Fatalf("申请找回密码应始终返回成功，实际: %v", err)
+		}
+	}
+	if _, ok := server.Next(200 * time.Millisecond); ok {
+		t.Fatal("冷却时间内或未绑定邮箱时不应发信")
+	}
+
+	_ = memoryCache.Delete(passwordResetSentKey(alice.ID))
+	second := requestToken("alice")
+	if err := service.ResetPassword(&ResetPasswordRequest{Token: first, NewPassword: "reset-pass2"}); !errors.Is(err, ErrPasswordResetInvalid) {
+		t.Fatalf("重新申请后旧令牌应作废，实际: %v", err)
+	}
+
+	for i := 0; i < maxLoginFailCount; i++ {
+		service.recordLoginFailure(alice.Username, ClientInfo{})
+	}
+	SetPasswordPolicy(password.Policy{MinLength: 8, RequireDigit: true})
+	defer SetPasswordPolicy(password.Policy{})
+	if err := service.ResetPassword(&ResetPasswordRequest{Token: second, NewPassword: "no-digits-here"}); err == nil {
+		t.Fatal("不符合策略的新密码不应通过")
+	}
+	if err := service.ResetPassword(&ResetPasswordRequest{Token: second, NewPassword: "reset-pass2"}); err != nil {
+		t.Fatalf("重置密码失败: %v", err)
+	}
+	if err := service.ResetPassword(&ResetPasswordRequest{Token: second, NewPassword: "reset-pass3"}); !errors.Is(err, ErrPasswordResetInvalid) {
+		t.Fatalf("重置令牌只能使用一次，实际: %v", err)
+	}
+
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: login.RefreshToken}); err == nil {
+		t.Fatal("重置密码后原登录应失效")
+	}
+	if _, err := service.Login(&LoginRequest{Username: "alice", Password: "reset-pass2"}); err != nil {
+		t.Fatalf("重置后应解除锁定并可用新密码登录: %v", err)
+	}
+}
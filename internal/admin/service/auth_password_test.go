@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestPasswordPolicyAndForcedChange 验证密码策略、历史密码限制与登录时强制修改密码。
+func TestPasswordPolicyAndForcedChange(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.AdminPasswordHistory{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	SetPasswordPolicy(password.Policy{MinLength: 8, RequireDigit: true, DenyCommon: true, History: 2, MaxAge: 24 * time.Hour})
+	defer SetPasswordPolicy(password.Policy{})
+
+	hashed, err := password.Hash("initial-pass1")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "tester", Password: hashed, Enabled: true, MustChangePassword: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute)
+	service := NewAuthService(database, manager, memoryCache)
+
+	// 管理员要求修改密码时登录不返回 token。
+	resp, err := service.Login(&LoginRequest{Username: user.Username, Password: "initial-pass1"})
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if resp.Token != "" || resp.PasswordChangeToken == "" || resp.PasswordExpired {
+		t.Fatalf("应要求修改密码: %+v", resp)
+	}
+
+	change := &ExpiredPasswordRequest{PasswordChangeToken: resp.PasswordChangeToken}
+	for _, weak := range []string{"no-digits-here", "password1", "initial-pass1"} {
+		change.NewPassword = weak
+		if _, err := service.ChangeExpiredPassword(change); err == nil {
+			t.Fatalf("不符合策略的密码 %q 不应通过", weak)
+		}
+	}
+	change.NewPassword = "second-pass2"
+	resp, err = service.ChangeExpiredPassword(change)
+	if err != nil || resp.Token == "" {
+		t.Fatalf("修改密码后应完成登录: %+v err=%v", resp, err)
+	}
+	if _, err := service.ChangeExpiredPassword(change); !errors.Is(err, ErrPasswordChangeTokenInvalid) {
+		t.Fatalf("修改密码令牌只能使用一次: %v", err)
+	}
+
+	// 当前密码与最近 2 次使用过的密码不能复用，超出历史条数后可以再次使用。
+	if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "second-pass2", NewPassword: "initial-pass1"}); !errors.Is(err, ErrPasswordReused) {
+		t.Fatalf("上一次使用的密码不应复用: %v", err)
+	}
+	if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "second-pass2", NewPassword: "third-pass3"}); err != nil {
+		t.Fatalf("修改密码失败: %v", err)
+	}
+	for _, reused := range []string{"third-pass3", "second-pass2", "initial-pass1"} {
+		if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "third-pass3", NewPassword: reused}); !errors.Is(err, ErrPasswordReused) {
+			t.Fatalf("密码 %q 仍在历史条数内，不应复用: %v", reused, err)
+		}
+	}
+	if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "third-pass3", NewPassword: "fourth-pass4"}); err != nil {
+		t.Fatalf("修改密码失败: %v", err)
+	}
+	if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "fourth-pass4", NewPassword: "initial-pass1"}); err != nil {
+		t.Fatalf("超出历史条数的密码应允许复用: %v", err)
+	}
+	var historyCount int64
+	database.Model(&model.AdminPasswordHistory{}).Where("user_id = ?", user.ID).Count(&historyCount)
+	if historyCount != 2 {
+		t.Fatalf("历史密码应只保留 2 条，实际 %d", historyCount)
+	}
+
+	// 超过最长使用期限后登录时要求修改。
+	database.Model(&model.AdminUser{}).Where("id = ?", user.ID).Update("password_changed_at", time.Now().Add(-48*time.Hour))
+	resp, err = service.Login(&LoginRequest{Username: user.Username, Password: "initial-pass1"})
+	if err != nil || resp.Token != "" || !resp.PasswordExpired {
+		t.Fatalf("密码过期应要求修改: %+v err=%v", resp, err)
+	}
+}
+
+// TestLoginRehashesOutdatedPassword 验证切换哈希算法后登录成功时自动重新生成哈希，且不视为修改密码。
+func TestLoginRehashesOutdatedPassword(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}, &model.AdminLoginLock{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	legacy, err := password.BcryptHasher{Cost: 4}.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	changedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	user := model.AdminUser{Username: "tester", Password: legacy, Enabled: true, PasswordChangedAt: &changedAt}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	password.SetHasher(password.Argon2idHasher{Memory: 1024, Time: 1, Threads: 1})
+	defer password.SetHasher(password.BcryptHasher{})
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	if _, err := service.Login(&LoginRequest{Username: "tester", Password: "wrong-password"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("错误密码应登录失败: %v", err)
+	}
+	var stored model.AdminUser
+	database.First(&stored, user.ID)
+	if stored.Password != legacy {
+		t.Fatal("密码错误时不应重新生成哈希")
+	}
+
+	resp, err := service.Login(&LoginRequest{Username: "tester", Password: "secret-password"})
+	if err != nil || resp.Token == "" {
+		t.Fatalf("旧哈希应能登录: %+v err=%v", resp, err)
+	}
+	database.First(&stored, user.ID)
+	if password.NeedsRehash(stored.Password) || !password.Verify(stored.Password, "secret-password") {
+		t.Fatalf("登录后应按 argon2id 重新生成哈希: %s", stored.Password)
+	}
+	if stored.TokenVersion != user.TokenVersion || stored.PasswordChangedAt == nil || !stored.PasswordChangedAt.Equal(changedAt) {
+		t.Fatalf("重新生成哈希不应视为修改密码: %+v", stored)
+	}
+	if resp, err := service.Login(&LoginRequest{Username: "tester", Password: "secret-password"}); err != nil || resp.Token == "" {
+		t.Fatalf("新哈希应能登录: %+v err=%v", resp, err)
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/permission"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleParentInvalid = errors.New("继承的角色不存在或不可继承")
+	ErrRoleParentCycle   = errors.New("角色继承关系存在循环")
+)
+
+// GetPermissionSet 获取用户编译后的权限集合，结果按用户缓存，缓存不跨越限时授权的生效或到期时刻。
+//
+// 超级管理员拥有全部权限；普通用户合并所有启用角色及其启用祖先角色的授权与拒绝项，
+// 任一角色的拒绝项都优先于其他角色的授权。
+func (s *AuthService) GetPermissionSet(userID uint) (*permission.Set, error) {
+	if set, ok := s.getPermissionSetCache(userID); ok {
+		return set, nil
+	}
+
+	boundary, err := nextGrantBoundary(s.db, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.loadPermissionSet(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.cacheUntilGrantBoundary(permissionCacheKey(userID), set, boundary)
+	return set, nil
+}
+
+func (s *AuthService) loadPermissionSet(userID uint) (*permission.Set, error) {
+	return loadUserPermissionSet(s.db, userID)
+}
+
+// loadUserPermissionSet 按数据库当前数据编译用户权限集合，不读写缓存。
+func loadUserPermissionSet(db *gorm.DB, userID uint) (*permission.Set, error) {
+	superAdmin, err := isSuperAdminUser(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if superAdmin {
+		return permission.Compile([]string{permission.Wildcard}, nil), nil
+	}
+
+	var roleIDs []uint
+	err = db.Table("admin_user_roles").
+		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
+		Where("admin_user_roles.user_id = ? AND admin_roles.enabled = ?", userID, true).
+		Scopes(ActiveUserRoles(time.Now())).
+		Pluck("admin_user_roles.role_id", &roleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err = roleAncestors(db, roleIDs, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return permission.Compile(nil, nil), nil
+	}
+
+	var grants, denies []string
+	if err := db.Model(&model.AdminRolePermission{}).Where("role_id IN ?", roleIDs).Pluck("permission", &grants).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&model.AdminRoleDeny{}).Where("role_id IN ?", roleIDs).Pluck("permission", &denies).Error; err != nil {
+		return nil, err
+	}
+	return permission.Compile(grants, denies), nil
+}
+
+// getPermissionSetCache 读取权限集合缓存，内存缓存直接返回编译结果，Redis 缓存按授权与拒绝项重新编译。
+func (s *AuthService) getPermissionSetCache(userID uint) (*permission.Set, bool) {
+	value, err := s.cache.Get(permissionCacheKey(userID))
+	if err != nil {
+		return nil, false
+	}
+	switch v := value.(type) {
+	case *permission.Set:
+		return v, true
+	case map[string]interface{}:
+		grants, ok := parseStringSlice(v["grants"])
+		if !ok {
+			return nil, false
+		}
+		denies, ok := parseStringSlice(v["denies"])
+		if !ok {
+			return nil, false
+		}
+		return permission.Compile(grants, denies), true
+	}
+	return nil, false
+}
+
+// CheckRoleParents 校验角色可以继承 parentIDs：父角色必须存在、不能是自身或超级管理员，且不能形成循环。
+func CheckRoleParents(db *gorm.DB, roleID uint, parentIDs []uint) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+	if slices.Contains(parentIDs, roleID) {
+		return ErrRoleParentCycle
+	}
+	var count int64
+	if err := db.Model(&model.AdminRole{}).
+		Where("id IN ? AND code <> ?", parentIDs, model.SuperAdminRoleCode).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(parentIDs) {
+		return ErrRoleParentInvalid
+	}
+	// 父角色的祖先中出现当前角色即构成循环，检查时不区分角色是否启用。
+	ancestors, err := roleAncestors(db, parentIDs, false)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ancestors, roleID) {
+		return ErrRoleParentCycle
+	}
+	return nil
+}
+
+// roleAncestors 返回角色及其全部祖先角色，enabledOnly 时不经过禁用的父角色。
+// 按层遍历并记录已访问角色，数据中存在循环时也能终止。
+func roleAncestors(db *gorm.DB, roleIDs []uint, enabledOnly bool) ([]uint, error) {
+	return walkGraph(roleIDs, func(frontier []uint) ([]uint, error) {
+		query := db.Table("admin_role_parents").Where("admin_role_parents.role_id IN ?", frontier)
+		if enabledOnly {
+			query = query.Joins("JOIN admin_roles ON admin_role_parents.parent_id = admin_roles.id").
+				Where("admin_roles.enabled = ?", true)
+		}
+		var parents []uint
+		err := query.Pluck("admin_role_parents.parent_id", &parents).Error
+		return parents, err
+	})
+}
+
+// roleDescendants 返回角色及其全部直接或间接继承它的子角色。
+func roleDescendants(db *gorm.DB, roleIDs []uint) ([]uint, error) {
+	return walkGraph(roleIDs, func(frontier []uint) ([]uint, error) {
+		var children []uint
+		err := db.Model(&model.AdminRoleParent{}).Where("parent_id IN ?", frontier).Pluck("role_id", &children).Error
+		return children, err
+	})
+}
+
+// walkGraph 从 start 出发按层遍历，next 返回下一层节点，结果包含起点且不重复。
+func walkGraph(start []uint, next func(frontier []uint) ([]uint, error)) ([]uint, error) {
+	visited := make(map[uint]struct{}, len(start))
+	frontier := make([]uint, 0, len(start))
+	for _, id := range start {
+		if _, ok := visited[id]; !ok {
+			visited[id] = struct{}{}
+			frontier = append(frontier, id)
+		}
+	}
+	result := slices.Clone(frontier)
+	for len(frontier) > 0 {
+		ids, err := next(frontier)
+		if err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, id := range crud.UniqueUints(ids) {
+			if _, ok := visited[id]; ok {
+				continue
+			}
+			visited[id] = struct{}{}
+			frontier = append(frontier, id)
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
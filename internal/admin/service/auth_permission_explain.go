@@ -0,0 +1,258 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/permission"
+
+	"gorm.io/gorm"
+)
+
+// PermissionExplainer 解释用户为什么能或不能使用某个权限。
+type PermissionExplainer interface {
+	ExplainPermission(userID uint, perm string) (*PermissionExplanation, error)
+}
+
+// PermissionExplanation 权限判定说明。
+//
+// Allowed 与权限中间件的判定一致：先看用户状态，再看权限集合，两者都优先使用缓存。
+// 缓存与数据库当前数据的判定不一致时 Cache.Stale 为 true，等待缓存过期或失效后才按新数据判定。
+type PermissionExplanation struct {
+	UserID      uint                 `json:"user_id"`
+	Username    string               `json:"username"`
+	Permission  string               `json:"permission"`
+	Allowed     bool                 `json:"allowed"`
+	Reason      string               `json:"reason"`
+	Registered  bool                 `json:"registered"` // 权限 key 是否在权限树中
+	UserEnabled bool                 `json:"user_enabled"`
+	SuperAdmin  bool                 `json:"super_admin"`
+	Roles       []RoleExplanation    `json:"roles"`
+	Cache       PermissionCacheState `json:"cache"`
+	Route       *crud.RouteInfo      `json:"route,omitempty"`
+}
+
+// RoleExplanation 用户直接或经继承获得的角色对判定的影响。
+type RoleExplanation struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Code          string     `json:"code"`
+	Enabled       bool       `json:"enabled"`
+	Inherited     bool       `json:"inherited"`
+	GrantID       uint       `json:"grant_id,omitempty"`
+	ValidFrom     *time.Time `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time `json:"valid_until,omitempty"`
+	GrantActive   bool       `json:"grant_active"`
+	Effective     bool       `json:"effective"` // 是否参与权限计算
+	MatchedGrants []string   `json:"matched_grants,omitempty"`
+	MatchedDenies []string   `json:"matched_denies,omitempty"`
+}
+
+// PermissionCacheState 用户状态与权限集合的缓存情况。
+type PermissionCacheState struct {
+	StatusCached     bool `json:"status_cached"`
+	PermissionCached bool `json:"permission_cached"`
+	Stale            bool `json:"stale"`
+}
+
+// ExplainPermission 解释用户能否使用 perm，perm 为空时只判定用户状态。
+// 只读取缓存不写入，排查本身不会改变后续判定。
+func (s *AuthService) ExplainPermission(userID uint, perm string) (*PermissionExplanation, error) {
+	var user model.AdminUser
+	if err := s.db.Select("id", "username", "enabled").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	perm = strings.TrimSpace(perm)
+	result := &PermissionExplanation{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Permission:  perm,
+		Registered:  perm == "" || slices.Contains(crud.GetAllPermissionKeys(), perm),
+		UserEnabled: user.Enabled,
+	}
+
+	superAdmin, err := s.isSuperAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+	result.SuperAdmin = superAdmin
+	fresh, err := s.loadPermissionSet(userID)
+	if err != nil {
+		return nil, err
+	}
+	if result.Roles, err = s.explainRoles(userID, perm); err != nil {
+		return nil, err
+	}
+
+	// 与 UserStatusMiddleware、PermissionMiddleware 一致，命中缓存时按缓存判定。
+	enabled := user.Enabled
+	if cached, ok := s.getUserStatusCache(userID); ok {
+		result.Cache.StatusCached = true
+		enabled = cached
+	}
+	set := fresh
+	if cached, ok := s.getPermissionSetCache(userID); ok {
+		result.Cache.PermissionCached = true
+		set = cached
+	}
+	freshAllowed := user.Enabled && (perm == "" || fresh.Allows(perm))
+	result.Allowed = enabled && (perm == "" || set.Allows(perm))
+	result.Cache.Stale = result.Allowed != freshAllowed
+
+	result.Reason = explainReason(result, fresh)
+	if result.Cache.Stale {
+		result.Reason = fmt.Sprintf("缓存尚未失效，当前按缓存判定为%s；按最新数据：%s", decisionText(result.Allowed), result.Reason)
+	}
+	return result, nil
+}
+
+// explainRoles 列出用户的直接授权角色与继承角色，并标出与 perm 匹配的授权项和拒绝项。
+// 禁用角色与未生效授权同样列出，便于发现被其截断的继承链。
+func (s *AuthService) explainRoles(userID uint, perm string) ([]RoleExplanation, error) {
+	var direct []struct {
+		GrantID    uint
+		RoleID     uint
+		Name       string
+		Code       string
+		Enabled    bool
+		ValidFrom  *time.Time
+		ValidUntil *time.Time
+	}
+	err := s.db.Table("admin_user_roles").
+		Select("admin_user_roles.id AS grant_id, admin_user_roles.role_id, admin_roles.name, admin_roles.code, admin_roles.enabled, "+
+			"admin_user_roles.valid_from, admin_user_roles.valid_until").
+		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
+		Where("admin_user_roles.user_id = ?", userID).
+		Order("admin_user_roles.id").
+		Scan(&direct).Error
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	roles := make([]RoleExplanation, 0, len(direct))
+	var activeIDs, effectiveIDs []uint
+	for _, d := range direct {
+		active := grantActive(d.ValidFrom, d.ValidUntil, now)
+		if active {
+			activeIDs = append(activeIDs, d.RoleID)
+			if d.Enabled {
+				effectiveIDs = append(effectiveIDs, d.RoleID)
+			}
+		}
+		roles = append(roles, RoleExplanation{
+			ID: d.RoleID, Name: d.Name, Code: d.Code, Enabled: d.Enabled,
+			GrantID: d.GrantID, ValidFrom: d.ValidFrom, ValidUntil: d.ValidUntil, GrantActive: active,
+		})
+	}
+	if effectiveIDs, err = roleAncestors(s.db, effectiveIDs, true); err != nil {
+		return nil, err
+	}
+	reachable, err := roleAncestors(s.db, activeIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	var inherited []model.AdminRole
+	inheritedIDs := slices.DeleteFunc(reachable, func(id uint) bool {
+		return slices.ContainsFunc(roles, func(r RoleExplanation) bool { return r.ID == id })
+	})
+	if len(inheritedIDs) > 0 {
+		if err := s.db.Select("id", "name", "code", "enabled").Where("id IN ?", inheritedIDs).Order("id").Find(&inherited).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, role := range inherited {
+		roles = append(roles, RoleExplanation{
+			ID: role.ID, Name: role.Name, Code: role.Code, Enabled: role.Enabled, Inherited: true, GrantActive: true,
+		})
+	}
+
+	roleIDs := make([]uint, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	grants, err := matchedRolePermissions(s.db, &model.AdminRolePermission{}, roleIDs, perm)
+	if err != nil {
+		return nil, err
+	}
+	denies, err := matchedRolePermissions(s.db, &model.AdminRoleDeny{}, roleIDs, perm)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Effective = slices.Contains(effectiveIDs, roles[i].ID)
+		roles[i].MatchedGrants = grants[roles[i].ID]
+		roles[i].MatchedDenies = denies[roles[i].ID]
+	}
+	return roles, nil
+}
+
+// matchedRolePermissions 返回各角色中与 perm 匹配的授权项或拒绝项。
+func matchedRolePermissions(db *gorm.DB, table interface{}, roleIDs []uint, perm string) (map[uint][]string, error) {
+	result := make(map[uint][]string)
+	if len(roleIDs) == 0 || perm == "" {
+		return result, nil
+	}
+	var rows []struct {
+		RoleID     uint
+		Permission string
+	}
+	if err := db.Model(table).Select("role_id", "permission").Where("role_id IN ?", roleIDs).Order("permission").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if permission.Match(row.Permission, perm) {
+			result[row.RoleID] = append(result[row.RoleID], row.Permission)
+		}
+	}
+	return result, nil
+}
+
+// explainReason 按最新数据说明判定结果，依次为用户状态、超级管理员、拒绝项与授权项。
+func explainReason(result *PermissionExplanation, fresh *permission.Set) string {
+	if !result.UserEnabled {
+		return "账户已被禁用"
+	}
+	if result.Permission == "" {
+		return "该路由无需权限，登录且账户启用即可访问"
+	}
+	if result.SuperAdmin {
+		return "超级管理员拥有全部权限"
+	}
+	if fresh.Denied(result.Permission) {
+		return "被" + describeMatches(result.Roles, func(r RoleExplanation) []string { return r.MatchedDenies }, "拒绝项") + " 拒绝"
+	}
+	if fresh.Allows(result.Permission) {
+		return "由" + describeMatches(result.Roles, func(r RoleExplanation) []string { return r.MatchedGrants }, "授权项") + " 允许"
+	}
+	if !result.Registered {
+		return "没有生效的角色授予该权限，且该权限不在权限树中，请确认权限 key 是否正确"
+	}
+	return "没有生效的角色授予该权限"
+}
+
+// describeMatches 描述参与计算的角色中匹配的授权项或拒绝项，如「角色 运营 的授权项 system:*」。
+func describeMatches(roles []RoleExplanation, items func(RoleExplanation) []string, kind string) string {
+	parts := make([]string, 0)
+	for _, role := range roles {
+		if role.Effective && len(items(role)) > 0 {
+			parts = append(parts, fmt.Sprintf("角色 %s 的%s %s", role.Name, kind, strings.Join(items(role), "、")))
+		}
+	}
+	return strings.Join(parts, "；")
+}
+
+func decisionText(allowed bool) string {
+	if allowed {
+		return "允许"
+	}
+	return "拒绝"
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestPermissionSetInheritance 验证多级继承、停用角色截断继承链、数据中的循环不会导致死循环与超级管理员通配。
+func TestPermissionSetInheritance(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	roles := []model.AdminRole{
+		{Name: "a", Code: "role_a", Enabled: true},
+		{Name: "b", Code: "role_b", Enabled: true},
+		{Name: "c", Code: "role_c", Enabled: true},
+		{Name: "super", Code: model.SuperAdminRoleCode, Enabled: true},
+	}
+	database.Create(&roles)
+	a, b, c, super := roles[0].ID, roles[1].ID, roles[2].ID, roles[3].ID
+	// a 继承 b，b 继承 c，c 又继承 a（历史脏数据形成的循环）。
+	database.Create(&[]model.AdminRoleParent{{RoleID: a, ParentID: b}, {RoleID: b, ParentID: c}, {RoleID: c, ParentID: a}})
+	database.Create(&[]model.AdminRolePermission{{RoleID: b, Permission: "demo:b"}, {RoleID: c, Permission: "demo:*"}})
+	database.Create(&model.AdminRoleDeny{RoleID: c, Permission: "demo:secret"})
+	users := []model.AdminUser{{Username: "u1", Password: "x", Enabled: true}, {Username: "root", Password: "x", Enabled: true}}
+	database.Create(&users)
+	database.Create(&[]model.AdminUserRole{{UserID: users[0].ID, RoleID: a}, {UserID: users[1].ID, RoleID: super}})
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	set, err := service.GetPermissionSet(users[0].ID)
+	if err != nil {
+		t.Fatalf("获取权限集合失败: %v", err)
+	}
+	if !set.Allows("demo:b") || !set.Allows("demo:other") || set.Allows("demo:secret") {
+		t.Fatalf("应继承多级祖先的授权与拒绝项: %+v", set)
+	}
+
+	database.Model(&model.AdminRole{}).Where("id = ?", b).Update("enabled", false)
+	service.InvalidateRoleUsersPermissionCache(b)
+	if set, _ := service.GetPermissionSet(users[0].ID); set.Allows("demo:b") || set.Allows("demo:other") {
+		t.Fatalf("停用的中间角色应截断继承链: %+v", set)
+	}
+
+	if set, _ := service.GetPermissionSet(users[1].ID); !set.Allows("anything:at:all") {
+		t.Fatal("超级管理员应拥有全部权限")
+	}
+
+	if err := CheckRoleParents(database, a, []uint{c}); !errors.Is(err, ErrRoleParentCycle) {
+		t.Fatalf("继承祖先链中包含自身的角色应判定为循环: %v", err)
+	}
+	if err := CheckRoleParents(database, a, []uint{super}); !errors.Is(err, ErrRoleParentInvalid) {
+		t.Fatalf("不能继承超级管理员: %v", err)
+	}
+}
+
+// TestExplainPermission 验证权限排查的判定原因、被禁用角色截断的继承链、缓存过期前的判定与用户状态。
+func TestExplainPermission(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	roles := []model.AdminRole{
+		{Name: "运营", Code: "ops", Enabled: true},
+		{Name: "基础", Code: "base", Enabled: true},
+		{Name: "审计", Code: "audit", Enabled: true},
+	}
+	database.Create(&roles)
+	ops, base, audit := roles[0].ID, roles[1].ID, roles[2].ID
+	database.Model(&model.AdminRole{}).Where("id = ?", base).Update("enabled", false)
+	database.Create(&model.AdminRoleParent{RoleID: ops, ParentID: base})
+	database.Create(&[]model.AdminRolePermission{{RoleID: ops, Permission: "system:admin_user:*"}, {RoleID: base, Permission: "demo:item:list"}})
+	database.Create(&model.AdminRoleDeny{RoleID: audit, Permission: "system:admin_user:delete"})
+	user := model.AdminUser{Username: "u1", Password: "x", Enabled: true}
+	database.Create(&user)
+	database.Create(&[]model.AdminUserRole{{UserID: user.ID, RoleID: ops}, {UserID: user.ID, RoleID: audit}})
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	result, err := service.ExplainPermission(user.ID, "system:admin_user:list")
+	if err != nil {
+		t.Fatalf("权限排查失败: %v", err)
+	}
+	if !result.Allowed || result.Reason != "由角色 运营 的授权项 system:admin_user:* 允许" || result.Cache.PermissionCached {
+		t.Fatalf("授权判定说明错误: %+v", result)
+	}
+	if len(result.Roles) != 3 || !result.Roles[2].Inherited || result.Roles[2].Effective || result.Roles[2].Enabled {
+		t.Fatalf("应列出被禁用且不参与计算的继承角色: %+v", result.Roles)
+	}
+	if result, _ := service.ExplainPermission(user.ID, "demo:item:list"); result.Allowed || len(result.Roles[2].MatchedGrants) != 1 {
+		t.Fatalf("禁用角色的授权项不应生效但应标出: %+v", result)
+	}
+	if result, _ := service.ExplainPermission(user.ID, "system:admin_user:delete"); result.Allowed || result.Reason != "被角色 审计 的拒绝项 system:admin_user:delete 拒绝" {
+		t.Fatalf("拒绝判定说明错误: %+v", result)
+	}
+
+	// 缓存未失效时按缓存判定，并提示与最新数据不一致。
+	if _, err := service.GetPermissionSet(user.ID); err != nil {
+		t.Fatalf("获取权限集合失败: %v", err)
+	}
+	database.Where("role_id = ?", ops).Delete(&model.AdminRolePermission{})
+	if result, _ := service.ExplainPermission(user.ID, "system:admin_user:list"); !result.Allowed || !result.Cache.PermissionCached || !result.Cache.Stale {
+		t.Fatalf("应按缓存判定并标记缓存过期: %+v", result)
+	}
+
+	if _, err := service.IsUserEnabled(user.ID); err != nil {
+		t.Fatalf("获取用户状态失败: %v", err)
+	}
+	database.Model(&user).Update("enabled", false)
+	if result, _ := service.ExplainPermission(user.ID, ""); !result.Allowed || !result.Cache.StatusCached || !result.Cache.Stale {
+		t.Fatalf("状态缓存失效前禁用用户仍可访问，应标记缓存过期: %+v", result)
+	}
+	service.InvalidateUserStatusCache(user.ID)
+	if result, _ := service.ExplainPermission(user.ID, "system:admin_user:list"); result.Allowed || result.Reason != "账户已被禁用" {
+		t.Fatalf("禁用用户应被拒绝: %+v", result)
+	}
+	if _, err := service.ExplainPermission(999, "system:admin_user:list"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("用户不存在时应返回 ErrUserNotFound: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("登录已过期，请重新登录")
+	ErrRefreshTokenReused  = errors.New("登录凭证已被使用，请重新登录")
+)
+
+const defaultRefreshTokenTTL = 7 * 24 * time.Hour
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string     `json:"refreshToken" binding:"required"`
+	Client       ClientInfo `json:"-"`
+}
+
+// SetRefreshTokenTTL 设置刷新令牌有效期，即一次登录的最长有效期。
+func (s *AuthService) SetRefreshTokenTTL(ttl time.Duration) {
+	s.refreshTTL = ttl
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌与刷新令牌。
+//
+// 每个刷新令牌只能使用一次：已使用过的令牌再次出现，说明令牌可能已泄露，
+// 此时吊销整个令牌族，攻击者与合法用户都需要重新登录。
+func (s *AuthService) Refresh(req *RefreshRequest) (*LoginResponse, error) {
+	var record model.AdminRefreshToken
+	if err := s.db.Where("token_hash = ?", hashToken(req.RefreshToken)).First(&record).Error; err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if record.UsedAt != nil {
+		s.RevokeTokenFamily(record.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+	if record.RevokedAt != nil || time.Now().After(record.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	var user model.AdminUser
+	if err := s.db.First(&user, record.UserID).Error; err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if !user.Enabled {
+		return nil, ErrUserDisabled
+	}
+	if user.TokenVersion != record.TokenVersion {
+		// 修改密码后旧登录的刷新令牌全部失效。
+		s.RevokeTokenFamily(record.FamilyID)
+		return nil, ErrRefreshTokenInvalid
+	}
+	// 模拟登录的令牌需操作者仍为启用的超级管理员，来源 IP 按操作者校验。
+	var actor *model.AdminUser
+	ipUserID := user.ID
+	if record.ImpersonatorID != 0 {
+		impersonator, err := s.impersonationActor(record.ImpersonatorID)
+		if err != nil {
+			s.RevokeTokenFamily(record.FamilyID)
+			s.endImpersonation(record.FamilyID)
+			return nil, ErrRefreshTokenInvalid
+		}
+		actor, ipUserID = impersonator, impersonator.ID
+	}
+	if !s.IsUserIPAllowed(ipUserID, req.Client.IP) {
+		return nil, ErrIPDenied
+	}
+
+	var resp *LoginResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证并发刷新时只有一个请求成功。
+		result := tx.Model(&model.AdminRefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		var err error
+		// 轮换出的令牌沿用原到期时间，登录有效期不会因刷新而无限延长。
+		resp, err = s.issueTokensInFamily(tx, &user, actor, record.FamilyID, record.ExpiresAt)
+		return err
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		s.RevokeTokenFamily(record.FamilyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.touchSession(record.FamilyID)
+	return resp, nil
+}
+
+// RevokeTokenFamily 吊销令牌族下全部刷新令牌并删除对应会话，使已签发的访问令牌立即失效，由该登录发起的模拟登录一并终止。
+func (s *AuthService) RevokeTokenFamily(familyID string) {
+	if familyID == "" {
+		return
+	}
+	_ = s.db.Model(&model.AdminRefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	_ = s.db.Where("family_id = ?", familyID).Delete(&model.AdminSession{}).Error
+	// 访问令牌无状态，只需在其最长有效期内记住该令牌族已吊销。
+	_ = s.cache.Set(tokenFamilyRevokedKey(familyID), true, s.jwtManager.Expire())
+	s.revokeDerivedImpersonations(familyID)
+}
+
+// IsTokenFamilyRevoked 检查访问令牌所属令牌族是否已吊销。
+func (s *AuthService) IsTokenFamilyRevoked(familyID string) bool {
+	return familyID != "" && s.cache.Exists(tokenFamilyRevokedKey(familyID))
+}
+
+// issueTokens 为一次新登录创建令牌族与会话，签发访问令牌与刷新令牌并记录登录成功。
+func (s *AuthService) issueTokens(user *model.AdminUser, client ClientInfo) (*LoginResponse, error) {
+	// 两步验证与修改密码是独立的请求，签发前按当前来源再次校验。
+	if err := s.checkLoginIP(user, client); err != nil {
+		return nil, err
+	}
+	family, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl := s.refreshTTL
+	if ttl <= 0 {
+		ttl = defaultRefreshTokenTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	var resp *LoginResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := createSession(tx, user.ID, family, client, expiresAt); err != nil {
+			return err
+		}
+		var err error
+		resp, err = s.issueTokensInFamily(tx, user, nil, family, expiresAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.enforceSessionLimit(user.ID)
+	s.logLogin(user.Username, user.ID, "", client)
+	return resp, nil
+}
+
+// issueTokensInFamily 在指定令牌族下签发令牌，刷新令牌入库时仅保存摘要；actor 非空时签发模拟登录令牌。
+func (s *AuthService) issueTokensInFamily(db *gorm.DB, user, actor *model.AdminUser, family string, expiresAt time.Time) (*LoginResponse, error) {
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	record := model.AdminRefreshToken{
+		UserID:       user.ID,
+		FamilyID:     family,
+		TokenHash:    hashToken(refreshToken),
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    expiresAt,
+	}
+	if actor != nil {
+		record.ImpersonatorID = actor.ID
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	token, err := s.jwtManager.GenerateTokenWithActor(user.ID, user.Username, user.TokenVersion, family, jwtActor(actor))
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtManager.Expire().Seconds()),
+	}, nil
+}
+
+// hashToken 计算刷新令牌与访问令牌的摘要，库中只保存摘要。
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenFamilyRevokedKey 构建令牌族吊销标记缓存 key。
+func tokenFamilyRevokedKey(familyID string) string {
+	return "auth:family:revoked:" + familyID
+}
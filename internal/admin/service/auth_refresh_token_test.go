@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestRefreshTokenRotation 验证刷新令牌轮换、重复使用检测与退出吊销。
+func TestRefreshTokenRotation(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "tester", Password: hashed, Enabled: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute)
+	service := NewAuthService(database, manager, memoryCache)
+	login := &LoginRequest{Username: user.Username, Password: "secret-password"}
+
+	first, err := service.Login(login)
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if first.RefreshToken == "" || first.ExpiresIn != 900 {
+		t.Fatalf("登录应返回刷新令牌与访问令牌有效期: %+v", first)
+	}
+	claims, err := manager.ParseToken(first.Token)
+	if err != nil || claims.Family == "" {
+		t.Fatalf("访问令牌应携带令牌族: %+v err=%v", claims, err)
+	}
+
+	second, err := service.Refresh(&RefreshRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("刷新失败: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatalf("刷新后应轮换刷新令牌")
+	}
+
+	// 旧刷新令牌被重放时吊销整个令牌族，包括刚轮换出的令牌和访问令牌。
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: first.RefreshToken}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("重复使用刷新令牌应被识别: %v", err)
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: second.RefreshToken}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("令牌族吊销后新刷新令牌应失效: %v", err)
+	}
+	if !service.IsTokenFamilyRevoked(claims.Family) {
+		t.Fatalf("令牌族吊销后访问令牌应失效")
+	}
+
+	// 退出登录吊销本次登录的刷新令牌。
+	third, err := service.Login(login)
+	if err != nil {
+		t.Fatalf("重新登录失败: %v", err)
+	}
+	if err := service.Logout(third.Token); err != nil {
+		t.Fatalf("退出登录失败: %v", err)
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: third.RefreshToken}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("退出后刷新令牌应失效: %v", err)
+	}
+
+	// 修改密码后此前登录的刷新令牌不能再换取访问令牌。
+	fourth, err := service.Login(login)
+	if err != nil {
+		t.Fatalf("重新登录失败: %v", err)
+	}
+	if err := service.ChangePassword(user.ID, &ChangePasswordRequest{OldPassword: "secret-password", NewPassword: "new-password"}); err != nil {
+		t.Fatalf("修改密码失败: %v", err)
+	}
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: fourth.RefreshToken}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("修改密码后刷新令牌应失效: %v", err)
+	}
+}
@@ -12,7 +12,10 @@ import (
 	"bico-admin/internal/pkg/crud"
 	"bico-admin/internal/pkg/jwt"
 	"bico-admin/internal/pkg/password"
+	"bico-admin/internal/pkg/permission"
+	"bico-admin/internal/pkg/totp"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"gorm.io/gorm"
 )
 
@@ -21,29 +24,43 @@ var (
 	ErrInvalidCredentials = errors.New("用户名或密码错误")
 	ErrUserDisabled       = errors.New("用户已被禁用")
 	ErrOldPasswordWrong   = errors.New("原密码错误")
-	ErrLoginLocked        = errors.New("登录失败次数过多，请15分钟后再试")
+	ErrLoginLocked        = errors.New("登录失败次数过多，请稍后再试")
 	ErrUsernameRequired   = errors.New("用户名不能为空")
 	ErrUsernameExists     = errors.New("用户名已存在")
 )
 
 const (
-	permissionCacheTTL   = 5 * time.Minute
+	// PermissionCacheTTL 权限与来源 IP 限制缓存的有效期。
+	PermissionCacheTTL   = 5 * time.Minute
 	userStatusCacheTTL   = 1 * time.Minute
 	tokenVersionCacheTTL = 1 * time.Minute
-	loginFailTTL         = 15 * time.Minute
-	loginLockTTL         = 15 * time.Minute
-	maxLoginFailCount    = 5
 )
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string     `json:"username" binding:"required"`
+	Password string     `json:"password" binding:"required"`
+	Client   ClientInfo `json:"-"`
 }
 
 // LoginResponse 登录响应
+//
+// Token 为短期访问令牌，ExpiresIn 为其有效秒数，过期前凭 RefreshToken 调用 /auth/refresh 轮换。
+// 需要两步验证时 Token 为空，前端凭 TwoFactorToken 调用 /auth/2fa/verify 完成登录；
+// TwoFactorSetup 为 true 表示所属角色强制要求但尚未绑定，需先获取二维码；
+// TwoFactorMethods 列出可用的验证方式（totp / passkey）。
+// 需要修改密码时 Token 同样为空，前端凭 PasswordChangeToken 调用 /auth/password/expired 设置新密码，
+// PasswordExpired 区分密码过期与管理员要求修改。
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token               string   `json:"token"`
+	RefreshToken        string   `json:"refreshToken,omitempty"`
+	ExpiresIn           int64    `json:"expiresIn,omitempty"`
+	TwoFactorToken      string   `json:"twoFactorToken,omitempty"`
+	TwoFactorSetup      bool     `json:"twoFactorSetup,omitempty"`
+	TwoFactorMethods    []string `json:"twoFactorMethods,omitempty"`
+	RecoveryCodes       []string `json:"recoveryCodes,omitempty"`
+	PasswordChangeToken string   `json:"passwordChangeToken,omitempty"`
+	PasswordExpired     bool     `json:"passwordExpired,omitempty"`
 }
 
 // UserInfo 用户信息
@@ -51,14 +68,19 @@ type UserInfo struct {
 	ID          uint     `json:"id"`
 	Username    string   `json:"username"`
 	Name        string   `json:"name"`
+	Email       string   `json:"email"`
 	Avatar      string   `json:"avatar"`
 	Enabled     bool     `json:"enabled"`
+	SuperAdmin  bool     `json:"super_admin"`
 	Permissions []string `json:"permissions"`
+	// 模拟登录时由处理器填充，Impersonator 为实际操作者。
+	Impersonated bool              `json:"impersonated"`
+	Impersonator *ImpersonatorInfo `json:"impersonator,omitempty"`
 }
 
 // UpdateProfileRequest 更新用户资料请求
 type UpdateProfileRequest struct {
-	Username *string `json:"username"`
+	Username *string `json:"username" extensions:"x-nullable"`
 	Name     string  `json:"name"`
 	Avatar   string  `json:"avatar"`
 }
@@ -73,13 +95,48 @@ type ChangePasswordRequest struct {
 type IAuthService interface {
 	Login(req *LoginRequest) (*LoginResponse, error)
 	Logout(token string) error
+	Refresh(req *RefreshRequest) (*LoginResponse, error)
 	IsTokenBlacklisted(token string) bool
+	IsTokenFamilyRevoked(familyID string) bool
 	IsTokenVersionValid(userID uint, version uint) bool
 	GetUserByID(userID uint) (*UserInfo, error)
 	UpdateProfile(userID uint, req *UpdateProfileRequest) (*UserInfo, error)
 	ChangePassword(userID uint, req *ChangePasswordRequest) error
 	GetUserPermissions(userID uint) ([]string, error)
+	GetPermissionSet(userID uint) (*permission.Set, error)
 	IsUserEnabled(userID uint) (bool, error)
+	IsUserIPAllowed(userID uint, ip string) bool
+	VerifyTwoFactor(req *TwoFactorVerifyRequest) (*LoginResponse, error)
+	BeginPasskeyTwoFactor(req *TwoFactorPendingRequest) (*PasskeyCeremony, error)
+	VerifyTwoFactorPasskey(req *PasskeyTwoFactorRequest) (*LoginResponse, error)
+	BeginPasskeyLogin() (*PasskeyCeremony, error)
+	FinishPasskeyLogin(req *PasskeyLoginRequest) (*LoginResponse, error)
+	ListPasskeys(userID uint) ([]model.AdminPasskey, error)
+	BeginPasskeyRegistration(userID uint) (*PasskeyCeremony, error)
+	FinishPasskeyRegistration(userID uint, req *PasskeyRegisterRequest) (*model.AdminPasskey, error)
+	RenamePasskey(userID, passkeyID uint, req *RenamePasskeyRequest) error
+	DeletePasskey(userID, passkeyID uint) error
+	SetupPendingTwoFactor(req *TwoFactorPendingRequest) (*totp.Key, error)
+	GetTwoFactorStatus(userID uint) (*TwoFactorStatus, error)
+	SetupTwoFactor(userID uint) (*totp.Key, error)
+	EnableTwoFactor(userID uint, code string) ([]string, error)
+	DisableTwoFactor(userID uint, req *TwoFactorDisableRequest) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	ListSessions(userID uint, currentFamily string) ([]model.AdminSession, error)
+	RevokeSession(userID, sessionID uint) error
+	ListAPITokens(userID uint) ([]model.AdminAPIToken, error)
+	CreateAPIToken(userID uint, req *CreateAPITokenRequest) (*APITokenCreated, error)
+	RevokeAPIToken(userID, tokenID uint) error
+	ChangeExpiredPassword(req *ExpiredPasswordRequest) (*LoginResponse, error)
+	LogCaptchaFailure(username string, client ClientInfo)
+	CaptchaRequired(username, ip string) bool
+	SSOAuthorize(provider string) (*SSOAuthorizeResponse, error)
+	SSOLogin(req *SSOCallbackRequest) (*LoginResponse, error)
+	JWKS() jwt.JWKSet
+	ForgotPassword(req *ForgotPasswordRequest) error
+	ResetPassword(req *ResetPasswordRequest) error
+	Impersonate(impersonatorID, targetID uint, originFamily string, client ClientInfo) (*LoginResponse, error)
+	StopImpersonation(family string, client ClientInfo) (*LoginResponse, error)
 }
 
 // AuthCacheInvalidator 认证缓存失效接口
@@ -91,13 +148,24 @@ type AuthCacheInvalidator interface {
 	InvalidateUserStatusCache(userID uint)
 	InvalidateUserTokenVersionCache(userID uint)
 	InvalidateUsersAuthCache(userIDs []uint)
+	// RevokeTokenFamily 吊销一次登录（会话）的全部令牌。
+	RevokeTokenFamily(familyID string)
 }
 
 // AuthService 认证服务
 type AuthService struct {
-	db         *gorm.DB
-	jwtManager *jwt.JWTManager
-	cache      cache.Cache
+	db               *gorm.DB
+	jwtManager       *jwt.JWTManager
+	cache            cache.Cache
+	issuer           string
+	refreshTTL       time.Duration
+	maxSessions      int
+	ssoProviders     map[string]ssoProviderEntry
+	passwordProvider *passwordProviderEntry
+	passwordReset    *passwordResetEntry
+	loginLock        *LoginLockService
+	captchaFailures  int
+	webauthn         *webauthn.WebAuthn
 }
 
 // NewAuthService 创建认证服务
@@ -106,6 +174,7 @@ func NewAuthService(db *gorm.DB, jwtManager *jwt.JWTManager, cache cache.Cache)
 		db:         db,
 		jwtManager: jwtManager,
 		cache:      cache,
+		loginLock:  NewLoginLockService(db, cache),
 	}
 }
 
@@ -114,82 +183,66 @@ func (s *AuthService) Login(req *LoginRequest) (*LoginResponse, error) {
 	var user model.AdminUser
 	username := normalizeLoginUsername(req.Username)
 
-	if s.isLoginLocked(username) {
+	if s.loginLock.IsLocked(username, req.Client.IP) {
+		s.logLogin(username, 0, model.LoginReasonLocked, req.Client)
 		return nil, ErrLoginLocked
 	}
 
+	if s.usesPasswordProvider(username) {
+		return s.loginWithPasswordProvider(username, req)
+	}
+
 	err := s.db.Where("username = ?", username).First(&user).Error
 	if err != nil {
-		s.recordLoginFailure(username)
+		s.recordLoginFailure(username, req.Client)
+		s.logLogin(username, 0, model.LoginReasonUserNotFound, req.Client)
 		return nil, ErrInvalidCredentials
 	}
 
 	if !user.Enabled {
+		s.logLogin(username, user.ID, model.LoginReasonDisabled, req.Client)
 		return nil, ErrUserDisabled
 	}
 
 	if !password.Verify(user.Password, req.Password) {
-		s.recordLoginFailure(username)
+		s.recordLoginFailure(username, req.Client)
+		s.logLogin(username, user.ID, model.LoginReasonBadPassword, req.Client)
 		return nil, ErrInvalidCredentials
 	}
-
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.TokenVersion)
-	if err != nil {
+	// 来源限制在密码校验之后检查，避免未知密码者借此探测账号的 IP 限制。
+	if err := s.checkLoginIP(&user, req.Client); err != nil {
 		return nil, err
 	}
 
 	s.clearLoginFailures(username)
+	s.rehashPassword(&user, req.Password)
 
-	return &LoginResponse{Token: token}, nil
-}
+	// 已绑定动态码或通行密钥、或所属角色强制要求两步验证时，先签发待验证令牌。
+	required, err := s.secondFactorRequired(&user)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return s.beginTwoFactorLogin(&user)
+	}
 
-// isLoginLocked 判断账号是否处于登录锁定期。
-// 使用独立锁定 key，避免失败次数过期后仍误判。
-func (s *AuthService) isLoginLocked(username string) bool {
-	return s.cache.Exists(buildLoginLockKey(username))
+	return s.completeLogin(&user, req.Client)
 }
 
-// recordLoginFailure 记录账号密码错误次数。
-// 达到阈值后写入锁定 key，后续请求在查库前直接拒绝。
-func (s *AuthService) recordLoginFailure(username string) {
-	failKey := buildLoginFailKey(username)
-	failCount := s.getLoginFailCount(failKey) + 1
-
-	if failCount >= maxLoginFailCount {
-		// 达到锁定阈值时删除计数 key，锁定期结束后重新计数。
-		_ = s.cache.Delete(failKey)
-		_ = s.cache.Set(buildLoginLockKey(username), true, loginLockTTL)
-		return
-	}
+// SetLoginLock 替换登录锁定服务，用于应用配置中的锁定策略并与管理接口共用。
+func (s *AuthService) SetLoginLock(lock *LoginLockService) {
+	s.loginLock = lock
+}
 
-	_ = s.cache.Set(failKey, failCount, loginFailTTL)
+// recordLoginFailure 记录账号密码错误，账号与来源 IP 分别计数。
+func (s *AuthService) recordLoginFailure(username string, client ClientInfo) {
+	s.loginLock.RecordFailure(username, client.IP)
 }
 
 // clearLoginFailures 在登录成功后清理失败记录。
 // 成功登录说明当前密码已通过校验，历史失败次数不应继续影响该账号。
 func (s *AuthService) clearLoginFailures(username string) {
-	_ = s.cache.Delete(buildLoginFailKey(username))
-	_ = s.cache.Delete(buildLoginLockKey(username))
-}
-
-// getLoginFailCount 读取账号当前失败次数。
-// 内存缓存保持 int，Redis JSON 反序列化数字为 float64，因此需要兼容两种形态。
-func (s *AuthService) getLoginFailCount(key string) int {
-	value, err := s.cache.Get(key)
-	if err != nil {
-		return 0
-	}
-
-	switch count := value.(type) {
-	case int:
-		return count
-	case int64:
-		return int(count)
-	case float64:
-		return int(count)
-	default:
-		return 0
-	}
+	s.loginLock.Clear(username)
 }
 
 // normalizeLoginUsername 统一登录账号格式。
@@ -198,16 +251,6 @@ func normalizeLoginUsername(username string) string {
 	return strings.TrimSpace(username)
 }
 
-// buildLoginFailKey 构建登录失败次数缓存 key。
-func buildLoginFailKey(username string) string {
-	return "auth:login:fail:" + username
-}
-
-// buildLoginLockKey 构建登录锁定缓存 key。
-func buildLoginLockKey(username string) string {
-	return "auth:login:lock:" + username
-}
-
 // Logout 用户退出登录
 func (s *AuthService) Logout(token string) error {
 	if token == "" {
@@ -220,6 +263,12 @@ func (s *AuthService) Logout(token string) error {
 		return nil
 	}
 
+	// 退出时一并吊销该次登录的刷新令牌。
+	s.RevokeTokenFamily(claims.Family)
+	if claims.Actor != nil {
+		s.endImpersonation(claims.Family)
+	}
+
 	ttl := time.Until(time.Unix(claims.Exp, 0))
 	if ttl <= 0 {
 		// 已过期令牌不会再通过鉴权，无需写入缓存。
@@ -234,6 +283,11 @@ func (s *AuthService) IsTokenBlacklisted(token string) bool {
 	return s.cache.Exists(blacklistKey)
 }
 
+// JWKS 返回校验访问令牌的公钥集合，供其他服务离线校验令牌。
+func (s *AuthService) JWKS() jwt.JWKSet {
+	return s.jwtManager.JWKS()
+}
+
 // IsTokenVersionValid 校验令牌版本是否仍与用户一致。
 // 修改密码会递增数据库版本，从而立即废弃该用户此前签发的全部令牌。
 func (s *AuthService) IsTokenVersionValid(userID uint, version uint) bool {
@@ -275,6 +329,11 @@ func (s *AuthService) GetUserByID(userID uint) (*UserInfo, error) {
 		return nil, err
 	}
 
+	superAdmin, err := s.isSuperAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 命中用户查询后同步刷新状态缓存，减少后续中间件查库频率。
 	s.setUserStatusCache(userID, user.Enabled)
 
@@ -282,8 +341,10 @@ func (s *AuthService) GetUserByID(userID uint) (*UserInfo, error) {
 		ID:          user.ID,
 		Username:    user.Username,
 		Name:        user.Name,
+		Email:       user.Email,
 		Avatar:      user.Avatar,
 		Enabled:     user.Enabled,
+		SuperAdmin:  superAdmin,
 		Permissions: permissions,
 	}, nil
 }
@@ -353,6 +414,7 @@ func (s *AuthService) UpdateProfile(userID uint, req *UpdateProfileRequest) (*Us
 		ID:          user.ID,
 		Username:    user.Username,
 		Name:        user.Name,
+		Email:       user.Email,
 		Avatar:      user.Avatar,
 		Enabled:     user.Enabled,
 		Permissions: permissions,
@@ -376,59 +438,33 @@ func (s *AuthService) ChangePassword(userID uint, req *ChangePasswordRequest) er
 		return ErrOldPasswordWrong
 	}
 
-	hashedPassword, err := password.Hash(req.NewPassword)
-	if err != nil {
-		return err
-	}
+	return s.setPassword(&user, req.NewPassword)
+}
 
-	err = s.db.Model(&user).Updates(map[string]interface{}{
-		"password":      hashedPassword,
-		"token_version": gorm.Expr("token_version + 1"),
-	}).Error
+// GetUserPermissions 获取用户拥有的全部具体权限，通配符按已注册权限展开并排除拒绝项。
+func (s *AuthService) GetUserPermissions(userID uint) ([]string, error) {
+	set, err := s.GetPermissionSet(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.InvalidateUserTokenVersionCache(userID)
-
-	return nil
+	return set.Expand(crud.GetAllPermissionKeys()), nil
 }
 
-// GetUserPermissions 获取用户的所有权限
-func (s *AuthService) GetUserPermissions(userID uint) ([]string, error) {
-	// 先读缓存，命中则直接返回，降低鉴权链路查库频率。
-	if cachedPerms, ok := s.getPermissionsCache(userID); ok {
-		return cachedPerms, nil
-	}
+// isSuperAdmin 判断用户是否拥有启用的超级管理员角色。
+// 超级管理员能力由保留角色授予，不再依赖固定用户名。
+func (s *AuthService) isSuperAdmin(userID uint) (bool, error) {
+	return isSuperAdminUser(s.db, userID)
+}
 
-	// 超级管理员能力由保留角色授予，不再依赖固定用户名。
-	var superAdminCount int64
-	if err := s.db.Table("admin_user_roles").
+// isSuperAdminUser 判断用户当前是否持有启用且生效的超级管理员角色。
+func isSuperAdminUser(db *gorm.DB, userID uint) (bool, error) {
+	var count int64
+	err := db.Table("admin_user_roles").
 		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
 		Where("admin_user_roles.user_id = ? AND admin_roles.code = ? AND admin_roles.enabled = ?", userID, model.SuperAdminRoleCode, true).
-		Count(&superAdminCount).Error; err != nil {
-		return nil, err
-	}
-	if superAdminCount > 0 {
-		adminPerms := crud.GetAllPermissionKeys()
-		s.setPermissionsCache(userID, adminPerms)
-		return adminPerms, nil
-	}
-
-	// 从数据库查询普通用户权限
-	var permissions []string
-	err := s.db.Table("admin_user_roles").
-		Select("DISTINCT admin_role_permissions.permission").
-		Joins("JOIN admin_role_permissions ON admin_user_roles.role_id = admin_role_permissions.role_id").
-		Joins("JOIN admin_roles ON admin_role_permissions.role_id = admin_roles.id").
-		Where("admin_user_roles.user_id = ? AND admin_roles.enabled = ?", userID, true).
-		Pluck("permission", &permissions).Error
-
-	if err != nil {
-		return nil, err
-	}
-
-	s.setPermissionsCache(userID, permissions)
-	return permissions, nil
+		Scopes(ActiveUserRoles(time.Now())).
+		Count(&count).Error
+	return count > 0, err
 }
 
 // IsUserEnabled 获取用户启用状态（优先读取缓存）
@@ -450,9 +486,9 @@ func (s *AuthService) IsUserEnabled(userID uint) (bool, error) {
 	return user.Enabled, nil
 }
 
-// InvalidateUserPermissionCache 失效指定用户权限缓存
+// InvalidateUserPermissionCache 失效指定用户权限缓存（含来源 IP 限制）
 func (s *AuthService) InvalidateUserPermissionCache(userID uint) {
-	_ = s.cache.Delete(permissionCacheKey(userID))
+	_ = s.cache.DeleteMany([]string{permissionCacheKey(userID), ipRestrictionCacheKey(userID)})
 }
 
 // InvalidateRoleUsersPermissionCache 失效指定角色下所有用户的权限缓存
@@ -467,7 +503,11 @@ func (s *AuthService) InvalidateRolesUsersPermissionCache(roleIDs []uint) {
 		return
 	}
 
-	uniqueRoleIDs := crud.UniqueUints(roleIDs)
+	// 继承这些角色的子角色同样受影响。
+	uniqueRoleIDs, err := roleDescendants(s.db, crud.UniqueUints(roleIDs))
+	if err != nil {
+		return
+	}
 	var userIDs []uint
 	if err := s.db.Table("admin_user_roles").
 		Where("role_id IN ?", uniqueRoleIDs).
@@ -475,11 +515,7 @@ func (s *AuthService) InvalidateRolesUsersPermissionCache(roleIDs []uint) {
 		Pluck("user_id", &userIDs).Error; err != nil {
 		return
 	}
-	keys := make([]string, 0, len(userIDs))
-	for _, userID := range userIDs {
-		keys = append(keys, permissionCacheKey(userID))
-	}
-	_ = s.cache.DeleteMany(keys)
+	InvalidateUsersPermissionCache(s.cache, userIDs)
 }
 
 // InvalidateUserStatusCache 失效指定用户状态缓存
@@ -494,27 +530,13 @@ func (s *AuthService) InvalidateUserTokenVersionCache(userID uint) {
 
 // InvalidateUsersAuthCache 批量失效用户权限与状态缓存。
 func (s *AuthService) InvalidateUsersAuthCache(userIDs []uint) {
-	keys := make([]string, 0, len(userIDs)*2)
+	keys := make([]string, 0, len(userIDs)*3)
 	for _, userID := range crud.UniqueUints(userIDs) {
-		keys = append(keys, permissionCacheKey(userID), userStatusCacheKey(userID))
+		keys = append(keys, permissionCacheKey(userID), ipRestrictionCacheKey(userID), userStatusCacheKey(userID))
 	}
 	_ = s.cache.DeleteMany(keys)
 }
 
-// getPermissionsCache 获取用户权限缓存
-func (s *AuthService) getPermissionsCache(userID uint) ([]string, bool) {
-	value, err := s.cache.Get(permissionCacheKey(userID))
-	if err != nil {
-		return nil, false
-	}
-	return parseStringSlice(value)
-}
-
-// setPermissionsCache 写入用户权限缓存
-func (s *AuthService) setPermissionsCache(userID uint, permissions []string) {
-	_ = s.cache.Set(permissionCacheKey(userID), permissions, permissionCacheTTL)
-}
-
 // getUserStatusCache 获取用户状态缓存
 func (s *AuthService) getUserStatusCache(userID uint) (bool, bool) {
 	value, err := s.cache.Get(userStatusCacheKey(userID))
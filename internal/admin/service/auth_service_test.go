@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"bico-admin/internal/admin/model"
 	"bico-admin/internal/core/cache"
@@ -20,7 +21,7 @@ func TestChangePasswordInvalidatesExistingTokens(t *testing.T) {
 		// 数据库不可用时无法验证持久化令牌版本。
 		t.Fatalf("创建测试数据库失败: %v", err)
 	}
-	if err := database.AutoMigrate(&model.AdminUser{}); err != nil {
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}); err != nil {
 		// 缺少用户表时后续认证断言没有意义。
 		t.Fatalf("迁移测试数据库失败: %v", err)
 	}
@@ -38,7 +39,7 @@ func TestChangePasswordInvalidatesExistingTokens(t *testing.T) {
 
 	memoryCache := cache.NewMemoryCache()
 	defer memoryCache.Close()
-	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 1)
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour)
 	service := NewAuthService(database, manager, memoryCache)
 	login, err := service.Login(&LoginRequest{Username: user.Username, Password: "old-password"})
 	if err != nil {
@@ -94,7 +95,7 @@ func TestUpdateProfileUsername(t *testing.T) {
 
 	memoryCache := cache.NewMemoryCache()
 	defer memoryCache.Close()
-	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 1)
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour)
 	authService := NewAuthService(database, manager, memoryCache)
 
 	newUsername := "  renamed-user  "
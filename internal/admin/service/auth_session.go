@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/useragent"
+
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("会话不存在")
+
+// ClientInfo 登录客户端信息，由处理器从请求中填充。
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SetMaxSessions 设置每个用户同时在线的会话上限，0 表示不限制。
+func (s *AuthService) SetMaxSessions(max int) {
+	s.maxSessions = max
+}
+
+// ListSessions 获取用户未过期的会话，按最近活跃时间倒序，currentFamily 对应的会话标记为当前会话。
+func (s *AuthService) ListSessions(userID uint, currentFamily string) ([]model.AdminSession, error) {
+	sessions, err := ListUserSessions(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = currentFamily != "" && sessions[i].FamilyID == currentFamily
+	}
+	return sessions, nil
+}
+
+// RevokeSession 下线用户的指定会话。
+func (s *AuthService) RevokeSession(userID, sessionID uint) error {
+	var session model.AdminSession
+	if err := s.db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	s.RevokeTokenFamily(session.FamilyID)
+	return nil
+}
+
+// RevokeUserSessions 下线用户的全部会话。
+func (s *AuthService) RevokeUserSessions(userID uint) error {
+	var families []string
+	if err := s.db.Model(&model.AdminSession{}).Where("user_id = ?", userID).Pluck("family_id", &families).Error; err != nil {
+		return err
+	}
+	for _, family := range families {
+		s.RevokeTokenFamily(family)
+	}
+	return nil
+}
+
+// ListUserSessions 查询用户未过期的会话，供个人中心与用户管理共用。
+func ListUserSessions(db *gorm.DB, userID uint) ([]model.AdminSession, error) {
+	var sessions []model.AdminSession
+	err := db.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// createSession 记录一次新登录。
+func createSession(tx *gorm.DB, userID uint, family string, client ClientInfo, expiresAt time.Time) error {
+	now := time.Now()
+	return tx.Create(&model.AdminSession{
+		UserID:     userID,
+		FamilyID:   family,
+		Device:     useragent.Parse(client.UserAgent),
+		UserAgent:  truncate(client.UserAgent, 512),
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}).Error
+}
+
+// touchSession 刷新令牌时更新会话最近活跃时间。
+func (s *AuthService) touchSession(family string) {
+	_ = s.db.Model(&model.AdminSession{}).Where("family_id = ?", family).Update("last_seen_at", time.Now()).Error
+}
+
+// enforceSessionLimit 超出会话上限时下线最久未活跃的会话，新登录始终保留。
+func (s *AuthService) enforceSessionLimit(userID uint) {
+	if s.maxSessions <= 0 {
+		return
+	}
+	var families []string
+	if err := s.db.Model(&model.AdminSession{}).
+		Where("user_id = ?", userID).
+		Order("last_seen_at DESC").Order("id DESC").
+		Offset(s.maxSessions).
+		Pluck("family_id", &families).Error; err != nil {
+		return
+	}
+	for _, family := range families {
+		s.RevokeTokenFamily(family)
+	}
+}
+
+// truncate 按字节截断字符串，避免超长 User-Agent 写入失败。
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestSessionLimitAndRevoke 验证会话记录、并发上限与单个会话下线。
+func TestSessionLimitAndRevoke(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "tester", Password: hashed, Enabled: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	manager := jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute)
+	service := NewAuthService(database, manager, memoryCache)
+	service.SetMaxSessions(2)
+
+	agents := []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	}
+	logins := make([]*LoginResponse, 0, len(agents))
+	for _, agent := range agents {
+		resp, err := service.Login(&LoginRequest{
+			Username: user.Username,
+			Password: "secret-password",
+			Client:   ClientInfo{IP: "10.0.0.1", UserAgent: agent},
+		})
+		if err != nil {
+			t.Fatalf("登录失败: %v", err)
+		}
+		logins = append(logins, resp)
+		// 保证最近活跃时间有先后顺序。
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 超出上限时最久未活跃的会话被下线。
+	if _, err := service.Refresh(&RefreshRequest{RefreshToken: logins[0].RefreshToken}); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("超出会话上限的旧会话应被下线: %v", err)
+	}
+	current, err := manager.ParseToken(logins[2].Token)
+	if err != nil {
+		t.Fatalf("解析令牌失败: %v", err)
+	}
+	sessions, err := service.ListSessions(user.ID, current.Family)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("期望保留 2 个会话: %+v err=%v", sessions, err)
+	}
+	if !sessions[0].Current || sessions[0].Device != "Chrome / macOS" || sessions[0].IP != "10.0.0.1" {
+		t.Fatalf("会话信息不正确: %+v", sessions[0])
+	}
+
+	// 只能下线自己的会话。
+	if err := service.RevokeSession(user.ID+1, sessions[1].ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("下线他人会话应返回不存在: %v", err)
+	}
+	if err := service.RevokeSession(user.ID, sessions[1].ID); err != nil {
+		t.Fatalf("下线会话失败: %v", err)
+	}
+	second, _ := manager.ParseToken(logins[1].Token)
+	if !service.IsTokenFamilyRevoked(second.Family) {
+		t.Fatalf("下线会话后访问令牌应立即失效")
+	}
+	if sessions, _ := service.ListSessions(user.ID, ""); len(sessions) != 1 {
+		t.Fatalf("下线后应只剩 1 个会话: %+v", sessions)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/oidc"
+)
+
+var (
+	ErrSSOProviderNotFound = errors.New("登录方式不存在")
+	ErrSSOStateInvalid     = errors.New("登录已过期，请重新发起")
+	ErrSSOFailed           = errors.New("单点登录失败，请稍后重试")
+	ErrSSOUserNotLinked    = errors.New("该账号未绑定后台用户，请联系管理员")
+	ErrSSOUsernameTaken    = errors.New("同名后台用户已存在，请联系管理员绑定")
+)
+
+// SSOStateTTL 发起单点登录后完成回调的时限，浏览器绑定 Cookie 的有效期与之相同。
+const SSOStateTTL = 10 * time.Minute
+
+const ssoExchangeTimeout = 15 * time.Second
+
+// SSOProvider 与密码登录并列的第三方登录提供方。
+//
+// AuthService 负责生成并暂存 state、nonce 与 PKCE 校验码，提供方只负责与身份提供方交互，
+// 回调后返回外部身份，映射为本地用户的规则由注册时的 IdentityPolicy 决定。
+type SSOProvider interface {
+	Name() string
+	Label() string
+	AuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*ExternalIdentity, error)
+}
+
+// SSOProviderInfo 登录页展示的第三方登录方式。
+type SSOProviderInfo struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// SSOAuthorizeResponse 第三方登录跳转地址。
+//
+// Binding 将 state 绑定到发起登录的浏览器，由接口层写入 HttpOnly Cookie，不返回给前端。
+type SSOAuthorizeResponse struct {
+	URL     string `json:"url"`
+	Binding string `json:"-"`
+}
+
+// SSOCallbackRequest 身份提供方回调参数，Binding 由接口层从 Cookie 读取。
+type SSOCallbackRequest struct {
+	State   string     `json:"state" binding:"required"`
+	Code    string     `json:"code" binding:"required"`
+	Binding string     `json:"-"`
+	Client  ClientInfo `json:"-"`
+}
+
+// ssoProviderEntry 已注册的提供方及其身份映射规则。
+type ssoProviderEntry struct {
+	provider SSOProvider
+	policy   IdentityPolicy
+}
+
+// ssoState 发起登录时暂存的校验参数，以 JSON 字符串写入缓存以兼容内存与 Redis。
+// Binding 为浏览器绑定值的摘要。
+type ssoState struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Binding  string `json:"binding"`
+}
+
+// RegisterSSOProvider 注册第三方登录提供方，同名提供方后注册的覆盖先注册的。
+func (s *AuthService) RegisterSSOProvider(provider SSOProvider, policy IdentityPolicy) {
+	if s.ssoProviders == nil {
+		s.ssoProviders = make(map[string]ssoProviderEntry)
+	}
+	s.ssoProviders[provider.Name()] = ssoProviderEntry{provider: provider, policy: policy}
+}
+
+// SSOAuthorize 生成 state、nonce 与 PKCE 校验码并返回身份提供方授权地址。
+//
+// 同时生成浏览器绑定值，回调时必须带回，防止他人发起的登录在受害者浏览器中完成（登录 CSRF）。
+func (s *AuthService) SSOAuthorize(providerName string) (*SSOAuthorizeResponse, error) {
+	entry, ok := s.ssoProviders[providerName]
+	if !ok {
+		return nil, ErrSSOProviderNotFound
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	binding, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(ssoState{Provider: providerName, Verifier: verifier, Nonce: nonce, Binding: hashToken(binding)})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ssoStateKey(state), string(payload), SSOStateTTL); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ssoExchangeTimeout)
+	defer cancel()
+	url, err := entry.provider.AuthURL(ctx, state, nonce, verifier)
+	if err != nil {
+		_ = s.cache.Delete(ssoStateKey(state))
+		return nil, ErrSSOFailed
+	}
+	return &SSOAuthorizeResponse{URL: url, Binding: binding}, nil
+}
+
+// SSOLogin 校验回调 state 及其浏览器绑定后用授权码换取外部身份，映射为本地用户并完成登录。
+func (s *AuthService) SSOLogin(req *SSOCallbackRequest) (*LoginResponse, error) {
+	state, ok := s.takeSSOState(req.State, req.Binding)
+	if !ok {
+		return nil, ErrSSOStateInvalid
+	}
+	entry, ok := s.ssoProviders[state.Provider]
+	if !ok {
+		return nil, ErrSSOProviderNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ssoExchangeTimeout)
+	defer cancel()
+	identity, err := entry.provider.Exchange(ctx, req.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		s.logLogin(state.Provider, 0, model.LoginReasonSSOFailed, req.Client)
+		return nil, ErrSSOFailed
+	}
+
+	user, err := s.resolveExternalUser(state.Provider, identity, entry.policy)
+	if err != nil {
+		if errors.Is(err, ErrSSOUserNotLinked) || errors.Is(err, ErrSSOUsernameTaken) {
+			s.logLogin(identity.Username, 0, model.LoginReasonSSOUnlinked, req.Client)
+		}
+		return nil, err
+	}
+	if !user.Enabled {
+		s.logLogin(user.Username, user.ID, model.LoginReasonDisabled, req.Client)
+		return nil, ErrUserDisabled
+	}
+
+	return s.completeExternalLogin(user, req.Client)
+}
+
+// takeSSOState 校验浏览器绑定后读取并删除回调 state，state 只能使用一次；绑定不符时不消耗 state。
+func (s *AuthService) takeSSOState(state, binding string) (*ssoState, bool) {
+	key := ssoStateKey(state)
+	value, err := s.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	raw, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var parsed ssoState
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, false
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(hashToken(binding)), []byte(parsed.Binding)) != 1 {
+		return nil, false
+	}
+	_ = s.cache.Delete(key)
+	return &parsed, true
+}
+
+// ssoStateKey 构建单点登录 state 缓存 key。
+func ssoStateKey(state string) string {
+	return "auth:sso:state:" + state
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"bico-admin/internal/pkg/oidc"
+)
+
+// OIDCClaimMapping OIDC 声明到本地用户字段的映射，留空时使用标准声明名。
+type OIDCClaimMapping struct {
+	Username string
+	Name     string
+	Groups   string
+}
+
+// OIDCProvider 基于通用 OpenID Connect 授权码流程（PKCE）的登录提供方。
+type OIDCProvider struct {
+	name   string
+	label  string
+	client *oidc.Provider
+	claims OIDCClaimMapping
+}
+
+// NewOIDCProvider 创建 OIDC 登录提供方。
+func NewOIDCProvider(name, label string, cfg oidc.Config, claims OIDCClaimMapping) *OIDCProvider {
+	if label == "" {
+		label = name
+	}
+	if claims.Username == "" {
+		claims.Username = "preferred_username"
+	}
+	if claims.Name == "" {
+		claims.Name = "name"
+	}
+	if claims.Groups == "" {
+		claims.Groups = "groups"
+	}
+	return &OIDCProvider{name: name, label: label, client: oidc.NewProvider(cfg), claims: claims}
+}
+
+// Name 提供方标识
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+// Label 登录按钮文字
+func (p *OIDCProvider) Label() string {
+	return p.label
+}
+
+// AuthURL 返回携带 PKCE S256 挑战值的授权地址。
+func (p *OIDCProvider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	return p.client.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier))
+}
+
+// Exchange 换取并校验 ID Token，按声明映射返回外部身份。
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*ExternalIdentity, error) {
+	claims, err := p.client.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalIdentity{
+		Subject:  claims.String("sub"),
+		Username: claims.String(p.claims.Username),
+		Name:     claims.String(p.claims.Name),
+		Avatar:   claims.String("picture"),
+		Groups:   claims.Strings(p.claims.Groups),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/oidc"
+	"bico-admin/internal/pkg/oidc/oidctest"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestSSOLoginWithMockProvider 验证 OIDC 授权码登录、自动创建用户与用户组角色同步。
+func TestSSOLoginWithMockProvider(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminUserIdentity{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	opsRole := model.AdminRole{Name: "运维", Code: "ops", Enabled: true}
+	viewerRole := model.AdminRole{Name: "访客", Code: "viewer", Enabled: true}
+	if err := database.Create(&opsRole).Error; err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	if err := database.Create(&viewerRole).Error; err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	if err := database.Create(&model.AdminUser{Username: "bob", Password: "x", Enabled: true}).Error; err != nil {
+		t.Fatalf("创建本地用户失败: %v", err)
+	}
+
+	server := oidctest.NewServer("bico", "secret")
+	defer server.Close()
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour), memoryCache)
+	provider := NewOIDCProvider("mock", "模拟登录", oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     "bico",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/admin/auth/sso-callback",
+	}, OIDCClaimMapping{})
+	policy := IdentityPolicy{
+		DefaultRoles: []string{"viewer"},
+		RoleMapping:  map[string][]string{"ops-team": {"ops"}},
+	}
+	service.RegisterSSOProvider(provider, policy)
+
+	login := func() (*LoginResponse, error) {
+		resp, err := service.SSOAuthorize("mock")
+		if err != nil {
+			t.Fatalf("发起单点登录失败: %v", err)
+		}
+		code, state, err := server.Authorize(resp.URL)
+		if err != nil {
+			t.Fatalf("模拟授权失败: %v", err)
+		}
+		return service.SSOLogin(&SSOCallbackRequest{State: state, Code: code, Binding: resp.Binding})
+	}
+
+	if _, err := service.SSOAuthorize("unknown"); !errors.Is(err, ErrSSOProviderNotFound) {
+		t.Fatalf("未注册的登录方式应返回 ErrSSOProviderNotFound，实际: %v", err)
+	}
+
+	server.SetClaims(map[string]interface{}{"sub": "u-1", "preferred_username": "alice", "name": "Alice"})
+	server.SetUserinfo(map[string]interface{}{"groups": []string{"ops-team"}})
+	if _, err := login(); !errors.Is(err, ErrSSOUserNotLinked) {
+		t.Fatalf("未开启自动创建时应返回 ErrSSOUserNotLinked，实际: %v", err)
+	}
+
+	policy.AutoProvision = true
+	service.RegisterSSOProvider(provider, policy)
+	resp, err := login()
+	if err != nil || resp.Token == "" || resp.RefreshToken == "" {
+		t.Fatalf("单点登录失败: resp=%+v err=%v", resp, err)
+	}
+	var alice model.AdminUser
+	if err := database.Preload("Roles").Where("username = ?", "alice").First(&alice).Error; err != nil {
+		t.Fatalf("未自动创建用户: %v", err)
+	}
+	if alice.Name != "Alice" || !hasRoleCode(alice.Roles, "ops") || !hasRoleCode(alice.Roles, "viewer") {
+		t.Fatalf("自动创建的用户资料或角色不正确: %+v", alice)
+	}
+
+	// 离开映射用户组后再次登录，映射角色被移除，默认角色保留。
+	server.SetUserinfo(nil)
+	if _, err := login(); err != nil {
+		t.Fatalf("再次单点登录失败: %v", err)
+	}
+	alice.Roles = nil
+	if err := database.Preload("Roles").First(&alice, alice.ID).Error; err != nil {
+		t.Fatalf("查询用户失败: %v", err)
+	}
+	if hasRoleCode(alice.Roles, "ops") || !hasRoleCode(alice.Roles, "viewer") {
+		t.Fatalf("用户组角色未同步: %+v", alice.Roles)
+	}
+	var identityCount int64
+	database.Model(&model.AdminUserIdentity{}).Where("user_id = ?", alice.ID).Count(&identityCount)
+	if identityCount != 1 {
+		t.Fatalf("期望 1 条身份绑定，实际 %d", identityCount)
+	}
+
+	// 映射角色的授权已到期时，重新加入用户组后应恢复为生效的授权。
+	expiredAt := time.Now().Add(-time.Minute)
+	if err := database.Create(&model.AdminUserRole{UserID: alice.ID, RoleID: opsRole.ID, ValidUntil: &expiredAt}).Error; err != nil {
+		t.Fatalf("创建到期授权失败: %v", err)
+	}
+	server.SetUserinfo(map[string]interface{}{"groups": []string{"ops-team"}})
+	if _, err := login(); err != nil {
+		t.Fatalf("再次单点登录失败: %v", err)
+	}
+	var grant model.AdminUserRole
+	if err := database.Where("user_id = ? AND role_id = ?", alice.ID, opsRole.ID).First(&grant).Error; err != nil {
+		t.Fatalf("查询授权失败: %v", err)
+	}
+	if !grantActive(grant.ValidFrom, grant.ValidUntil, time.Now()) {
+		t.Fatalf("重新加入用户组后映射角色应生效: %+v", grant)
+	}
+
+	// 同名本地用户未开启绑定时拒绝登录，避免外部账号接管本地账号。
+	server.SetClaims(map[string]interface{}{"sub": "u-2", "preferred_username": "bob"})
+	if _, err := login(); !errors.Is(err, ErrSSOUsernameTaken) {
+		t.Fatalf("同名用户应返回 ErrSSOUsernameTaken，实际: %v", err)
+	}
+
+	// 他人发起的登录不能在未持有绑定值的浏览器中完成（登录 CSRF），且不会消耗 state。
+	server.SetClaims(map[string]interface{}{"sub": "u-1", "preferred_username": "alice"})
+	authorize, _ := service.SSOAuthorize("mock")
+	code, state, _ := server.Authorize(authorize.URL)
+	for _, binding := range []string{"", "other-browser"} {
+		if _, err := service.SSOLogin(&SSOCallbackRequest{State: state, Code: code, Binding: binding}); !errors.Is(err, ErrSSOStateInvalid) {
+			t.Fatalf("浏览器绑定不符时应返回 ErrSSOStateInvalid，实际: %v", err)
+		}
+	}
+	if _, err := service.SSOLogin(&SSOCallbackRequest{State: state, Code: code, Binding: authorize.Binding}); err != nil {
+		t.Fatalf("发起登录的浏览器应能完成登录: %v", err)
+	}
+	if _, err := service.SSOLogin(&SSOCallbackRequest{State: state, Code: code, Binding: authorize.Binding}); !errors.Is(err, ErrSSOStateInvalid) {
+		t.Fatalf("state 重复使用应返回 ErrSSOStateInvalid，实际: %v", err)
+	}
+}
+
+// hasRoleCode 判断角色列表是否包含指定编码。
+func hasRoleCode(roles []*model.AdminRole, code string) bool {
+	for _, role := range roles {
+		if role.Code == code {
+			return true
+		}
+	}
+	return false
+}
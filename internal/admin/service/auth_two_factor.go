@@ -0,0 +1,490 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/password"
+	"bico-admin/internal/pkg/totp"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorCodeInvalid     = errors.New("动态码错误")
+	ErrTwoFactorTokenInvalid    = errors.New("两步验证已过期，请重新登录")
+	ErrTwoFactorNotEnabled      = errors.New("未启用两步验证")
+	ErrTwoFactorAlreadyEnabled  = errors.New("已启用两步验证")
+	ErrTwoFactorEnrollExpired   = errors.New("绑定已过期，请重新获取二维码")
+	ErrTwoFactorRequiredByRole  = errors.New("所属角色要求启用两步验证，不能关闭")
+	ErrTwoFactorPasswordInvalid = errors.New("密码错误")
+	ErrTwoFactorSetupForbidden  = errors.New("当前登录不能绑定两步验证，请使用已绑定的验证方式")
+)
+
+const (
+	twoFactorPendingTTL    = 5 * time.Minute
+	twoFactorEnrollTTL     = 10 * time.Minute
+	twoFactorUsedCodeTTL   = 90 * time.Second
+	maxTwoFactorFailCount  = 5
+	recoveryCodeCount      = 10
+	defaultTwoFactorIssuer = "Bico Admin"
+)
+
+// TwoFactorVerifyRequest 登录两步验证请求
+type TwoFactorVerifyRequest struct {
+	TwoFactorToken string `json:"twoFactorToken" binding:"required"`
+	// Code 为 6 位动态码或恢复码。
+	Code   string     `json:"code" binding:"required"`
+	Client ClientInfo `json:"-"`
+}
+
+// TwoFactorPendingRequest 登录中待绑定两步验证的请求
+type TwoFactorPendingRequest struct {
+	TwoFactorToken string `json:"twoFactorToken" binding:"required"`
+}
+
+// TwoFactorCodeRequest 动态码请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorDisableRequest 关闭两步验证请求
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// TwoFactorStatus 当前用户两步验证状态
+type TwoFactorStatus struct {
+	Enabled bool `json:"enabled"`
+	// Required 表示所属角色强制要求两步验证。
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recoveryCodesRemaining"`
+}
+
+// SetTwoFactorIssuer 设置认证器 App 中显示的签发方名称。
+func (s *AuthService) SetTwoFactorIssuer(issuer string) {
+	s.issuer = issuer
+}
+
+// secondFactorRequired 判断登录是否需要两步验证：已绑定动态码或通行密钥，或所属角色强制要求。
+func (s *AuthService) secondFactorRequired(user *model.AdminUser) (bool, error) {
+	if user.TwoFactorEnabled {
+		return true, nil
+	}
+	hasPasskeys, err := s.hasPasskeys(user.ID)
+	if err != nil || hasPasskeys {
+		return hasPasskeys, err
+	}
+	return s.IsTwoFactorRequired(user.ID)
+}
+
+// beginTwoFactorLogin 密码校验通过后签发待验证令牌，完成两步验证前不返回登录 token。
+// 已绑定的通行密钥可代替动态码，只有两者都未绑定时才需要先绑定动态码。
+func (s *AuthService) beginTwoFactorLogin(user *model.AdminUser) (*LoginResponse, error) {
+	hasPasskeys, err := s.hasPasskeys(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(twoFactorPendingKey(token), user.ID, twoFactorPendingTTL); err != nil {
+		return nil, err
+	}
+
+	resp := &LoginResponse{
+		TwoFactorToken: token,
+		TwoFactorSetup: !user.TwoFactorEnabled && !hasPasskeys,
+	}
+	// 只有尚未绑定任何第二因素的登录可以在验证阶段绑定动态码，否则持有密码即可绕过已绑定的因素。
+	if resp.TwoFactorSetup {
+		if err := s.cache.Set(twoFactorPendingKey(token)+":setup", true, twoFactorPendingTTL); err != nil {
+			return nil, err
+		}
+	}
+	if user.TwoFactorEnabled || resp.TwoFactorSetup {
+		resp.TwoFactorMethods = append(resp.TwoFactorMethods, TwoFactorMethodTOTP)
+	}
+	if hasPasskeys {
+		resp.TwoFactorMethods = append(resp.TwoFactorMethods, TwoFactorMethodPasskey)
+	}
+	return resp, nil
+}
+
+// VerifyTwoFactor 使用动态码或恢复码完成登录。
+// 角色强制要求但尚未绑定的用户，需先调用 SetupPendingTwoFactor 获取二维码，
+// 此处校验首个动态码即完成绑定，并随登录结果返回恢复码；其他情况只接受已绑定的动态码或恢复码。
+func (s *AuthService) VerifyTwoFactor(req *TwoFactorVerifyRequest) (*LoginResponse, error) {
+	userID, ok := s.pendingUserID(req.TwoFactorToken)
+	if !ok {
+		return nil, ErrTwoFactorTokenInvalid
+	}
+
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	var recoveryCodes []string
+	if user.TwoFactorEnabled {
+		if err := s.verifySecondFactor(&user, req.Code); err != nil {
+			s.recordTwoFactorFailure(req.TwoFactorToken)
+			s.logLogin(user.Username, user.ID, model.LoginReasonBadTwoFactor, req.Client)
+			return nil, err
+		}
+	} else if !s.pendingSetupAllowed(req.TwoFactorToken) {
+		s.recordTwoFactorFailure(req.TwoFactorToken)
+		s.logLogin(user.Username, user.ID, model.LoginReasonBadTwoFactor, req.Client)
+		return nil, ErrTwoFactorSetupForbidden
+	} else {
+		codes, err := s.EnableTwoFactor(user.ID, req.Code)
+		if err != nil {
+			s.recordTwoFactorFailure(req.TwoFactorToken)
+			s.logLogin(user.Username, user.ID, model.LoginReasonBadTwoFactor, req.Client)
+			return nil, err
+		}
+		recoveryCodes = codes
+	}
+
+	resp, err := s.finishTwoFactorLogin(&user, req.TwoFactorToken, req.Client)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+// finishTwoFactorLogin 两步验证通过后作废待验证令牌并继续登录。
+// 外部身份登录未经过本地密码校验，不检查密码有效期。
+func (s *AuthService) finishTwoFactorLogin(user *model.AdminUser, token string, client ClientInfo) (*LoginResponse, error) {
+	external := s.isExternalPending(token)
+	s.clearPendingTwoFactor(token)
+	if external {
+		return s.issueTokens(user, client)
+	}
+	return s.completeLogin(user, client)
+}
+
+// SetupPendingTwoFactor 为登录中被强制要求两步验证的用户生成绑定二维码。
+// 仅限签发时未绑定任何第二因素（TwoFactorSetup 为 true）的待验证令牌。
+func (s *AuthService) SetupPendingTwoFactor(req *TwoFactorPendingRequest) (*totp.Key, error) {
+	userID, ok := s.pendingUserID(req.TwoFactorToken)
+	if !ok {
+		return nil, ErrTwoFactorTokenInvalid
+	}
+	if !s.pendingSetupAllowed(req.TwoFactorToken) {
+		return nil, ErrTwoFactorSetupForbidden
+	}
+	return s.SetupTwoFactor(userID)
+}
+
+// pendingSetupAllowed 判断待验证令牌签发时是否允许绑定动态码。
+func (s *AuthService) pendingSetupAllowed(token string) bool {
+	return s.cache.Exists(twoFactorPendingKey(token) + ":setup")
+}
+
+// GetTwoFactorStatus 获取用户两步验证状态。
+func (s *AuthService) GetTwoFactorStatus(userID uint) (*TwoFactorStatus, error) {
+	var user model.AdminUser
+	if err := s.db.Select("id", "two_factor_enabled").First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	required, err := s.IsTwoFactorRequired(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Enabled: user.TwoFactorEnabled, Required: required}
+	if user.TwoFactorEnabled {
+		if err := s.db.Model(&model.AdminUserRecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Count(&status.RecoveryCodesRemaining).Error; err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// SetupTwoFactor 生成新的 TOTP 密钥，确认动态码前仅暂存在缓存中。
+func (s *AuthService) SetupTwoFactor(userID uint) (*totp.Key, error) {
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	issuer := s.issuer
+	if issuer == "" {
+		issuer = defaultTwoFactorIssuer
+	}
+	key, err := totp.Generate(issuer, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(twoFactorEnrollKey(userID), key.Secret, twoFactorEnrollTTL); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EnableTwoFactor 校验绑定阶段的动态码并启用两步验证，返回一次性恢复码明文。
+func (s *AuthService) EnableTwoFactor(userID uint, code string) ([]string, error) {
+	value, err := s.cache.Get(twoFactorEnrollKey(userID))
+	if err != nil {
+		return nil, ErrTwoFactorEnrollExpired
+	}
+	secret, ok := value.(string)
+	if !ok || secret == "" {
+		return nil, ErrTwoFactorEnrollExpired
+	}
+	if !s.validateTOTP(userID, secret, code) {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AdminUser{}).
+			Where("id = ? AND two_factor_enabled = ?", userID, false).
+			Updates(map[string]interface{}{
+				"two_factor_enabled": true,
+				"two_factor_secret":  secret,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// 并发绑定时只允许第一个请求生效。
+			return ErrTwoFactorAlreadyEnabled
+		}
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cache.Delete(twoFactorEnrollKey(userID))
+	return codes, nil
+}
+
+// DisableTwoFactor 校验密码与动态码后关闭两步验证。
+func (s *AuthService) DisableTwoFactor(userID uint, req *TwoFactorDisableRequest) error {
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return ErrUserNotFound
+	}
+	if !user.TwoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if !password.Verify(user.Password, req.Password) {
+		return ErrTwoFactorPasswordInvalid
+	}
+	required, err := s.IsTwoFactorRequired(userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequiredByRole
+	}
+	if err := s.verifySecondFactor(&user, req.Code); err != nil {
+		return err
+	}
+	return ResetTwoFactor(s.db, userID)
+}
+
+// RegenerateRecoveryCodes 校验动态码后重新生成恢复码，旧恢复码全部作废。
+func (s *AuthService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	var user model.AdminUser
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.TwoFactorEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if !s.validateTOTP(user.ID, user.TwoFactorSecret, code) {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	var codes []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// IsTwoFactorRequired 判断用户是否属于要求两步验证的启用角色。
+func (s *AuthService) IsTwoFactorRequired(userID uint) (bool, error) {
+	var count int64
+	err := s.db.Table("admin_user_roles").
+		Joins("JOIN admin_roles ON admin_user_roles.role_id = admin_roles.id").
+		Where("admin_user_roles.user_id = ? AND admin_roles.enabled = ? AND admin_roles.two_factor_required = ?", userID, true, true).
+		Scopes(ActiveUserRoles(time.Now())).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ResetTwoFactor 清除用户的两步验证绑定与恢复码，供用户关闭和管理员重置共用。
+func ResetTwoFactor(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.AdminUser{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled": false,
+			"two_factor_secret":  "",
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.AdminUserRecoveryCode{}).Error
+	})
+}
+
+// ResetSecondFactors 清除用户的两步验证绑定、恢复码与通行密钥，供管理员在用户丢失设备时重置。
+func ResetSecondFactors(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := ResetTwoFactor(tx, userID); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.AdminPasskey{}).Error
+	})
+}
+
+// verifySecondFactor 校验动态码，6 位数字以外的输入按恢复码处理。
+func (s *AuthService) verifySecondFactor(user *model.AdminUser, code string) error {
+	code = strings.TrimSpace(code)
+	if isDigitCode(code) {
+		if s.validateTOTP(user.ID, user.TwoFactorSecret, code) {
+			return nil
+		}
+		return ErrTwoFactorCodeInvalid
+	}
+
+	// 条件更新保证同一恢复码并发提交时只有一个请求成功。
+	result := s.db.Model(&model.AdminUserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, totp.HashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// validateTOTP 校验动态码，并拒绝在有效期内重复使用同一动态码。
+func (s *AuthService) validateTOTP(userID uint, secret, code string) bool {
+	code = strings.TrimSpace(code)
+	if !totp.Validate(code, secret, time.Now()) {
+		return false
+	}
+	usedKey := fmt.Sprintf("auth:2fa:used:%d:%s", userID, code)
+	if s.cache.Exists(usedKey) {
+		return false
+	}
+	_ = s.cache.Set(usedKey, true, twoFactorUsedCodeTTL)
+	return true
+}
+
+// pendingUserID 读取待验证令牌对应的用户 ID。
+func (s *AuthService) pendingUserID(token string) (uint, bool) {
+	return s.cachedUserID(twoFactorPendingKey(token))
+}
+
+// cachedUserID 读取缓存中保存的用户 ID。
+func (s *AuthService) cachedUserID(key string) (uint, bool) {
+	value, err := s.cache.Get(key)
+	if err != nil {
+		return 0, false
+	}
+	// 内存缓存保存 uint，Redis 反序列化后为 float64。
+	switch id := value.(type) {
+	case uint:
+		return id, true
+	case float64:
+		return uint(id), true
+	default:
+		return 0, false
+	}
+}
+
+// recordTwoFactorFailure 记录待验证令牌的错误次数，超过阈值后作废令牌，需重新输入密码。
+func (s *AuthService) recordTwoFactorFailure(token string) {
+	failKey := twoFactorPendingKey(token) + ":fail"
+	failCount := getFailCount(s.cache, failKey) + 1
+	if failCount >= maxTwoFactorFailCount {
+		s.clearPendingTwoFactor(token)
+		return
+	}
+	_ = s.cache.Set(failKey, failCount, twoFactorPendingTTL)
+}
+
+// clearPendingTwoFactor 删除待验证令牌及其错误计数、来源与绑定标记、通行密钥挑战。
+func (s *AuthService) clearPendingTwoFactor(token string) {
+	key := twoFactorPendingKey(token)
+	_ = s.cache.DeleteMany([]string{key, key + ":fail", key + ":external", key + ":setup", key + ":passkey"})
+}
+
+// replaceRecoveryCodes 生成新的恢复码并覆盖旧记录，返回明文供用户保存。
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes, err := totp.RecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&model.AdminUserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	records := make([]model.AdminUserRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, model.AdminUserRecoveryCode{UserID: userID, CodeHash: totp.HashRecoveryCode(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// isDigitCode 判断输入是否为 6 位动态码。
+func isDigitCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// randomToken 生成待验证令牌。
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// twoFactorPendingKey 构建待验证令牌缓存 key。
+func twoFactorPendingKey(token string) string {
+	return "auth:2fa:pending:" + token
+}
+
+// twoFactorEnrollKey 构建绑定阶段密钥缓存 key。
+func twoFactorEnrollKey(userID uint) string {
+	return fmt.Sprintf("auth:2fa:enroll:%d", userID)
+}
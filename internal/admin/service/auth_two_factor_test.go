@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+	"bico-admin/internal/pkg/totp"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestTwoFactorLoginFlow 验证角色强制绑定、恢复码登录与关闭限制。
+func TestTwoFactorLoginFlow(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminUserRecoveryCode{}, &model.AdminRefreshToken{}, &model.AdminSession{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	user := model.AdminUser{Username: "auditor", Password: hashed, Enabled: true}
+	role := model.AdminRole{Name: "审计", Code: "role_audit", Enabled: true, TwoFactorRequired: true}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+	if err := database.Create(&role).Error; err != nil {
+		t.Fatalf("创建测试角色失败: %v", err)
+	}
+	if err := database.Create(&model.AdminUserRole{UserID: user.ID, RoleID: role.ID}).Error; err != nil {
+		t.Fatalf("创建用户角色失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", time.Hour), memoryCache)
+	login := &LoginRequest{Username: user.Username, Password: "secret-password"}
+
+	// 角色强制要求但未绑定时，登录只返回待验证令牌。
+	resp, err := service.Login(login)
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if resp.Token != "" || resp.TwoFactorToken == "" || !resp.TwoFactorSetup {
+		t.Fatalf("期望进入强制绑定流程: %+v", resp)
+	}
+	key, err := service.SetupPendingTwoFactor(&TwoFactorPendingRequest{TwoFactorToken: resp.TwoFactorToken})
+	if err != nil {
+		t.Fatalf("获取绑定二维码失败: %v", err)
+	}
+	if _, err := service.VerifyTwoFactor(&TwoFactorVerifyRequest{TwoFactorToken: resp.TwoFactorToken, Code: "000000"}); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("错误动态码应被拒绝: %v", err)
+	}
+	code, err := totp.GenerateCode(key.Secret, time.Now())
+	if err != nil {
+		t.Fatalf("生成动态码失败: %v", err)
+	}
+	verified, err := service.VerifyTwoFactor(&TwoFactorVerifyRequest{TwoFactorToken: resp.TwoFactorToken, Code: code})
+	if err != nil {
+		t.Fatalf("绑定并登录失败: %v", err)
+	}
+	if verified.Token == "" || len(verified.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("期望返回登录令牌与恢复码: %+v", verified)
+	}
+
+	// 待验证令牌只能使用一次。
+	if _, err := service.VerifyTwoFactor(&TwoFactorVerifyRequest{TwoFactorToken: resp.TwoFactorToken, Code: code}); !errors.Is(err, ErrTwoFactorTokenInvalid) {
+		t.Fatalf("已使用的待验证令牌应失效: %v", err)
+	}
+
+	// 已绑定用户可以使用恢复码登录，同一恢复码只能使用一次。
+	for i, expected := range []error{nil, ErrTwoFactorCodeInvalid} {
+		resp, err := service.Login(login)
+		if err != nil || resp.TwoFactorSetup {
+			t.Fatalf("第 %d 次登录状态不正确: %+v err=%v", i+1, resp, err)
+		}
+		_, err = service.VerifyTwoFactor(&TwoFactorVerifyRequest{TwoFactorToken: resp.TwoFactorToken, Code: verified.RecoveryCodes[0]})
+		if !errors.Is(err, expected) {
+			t.Fatalf("第 %d 次使用恢复码期望 %v，实际 %v", i+1, expected, err)
+		}
+	}
+	status, err := service.GetTwoFactorStatus(user.ID)
+	if err != nil || !status.Enabled || !status.Required || status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Fatalf("两步验证状态不正确: %+v err=%v", status, err)
+	}
+
+	// 角色强制要求时不能自行关闭，管理员重置后恢复为未绑定。
+	err = service.DisableTwoFactor(user.ID, &TwoFactorDisableRequest{Password: "secret-password", Code: verified.RecoveryCodes[1]})
+	if !errors.Is(err, ErrTwoFactorRequiredByRole) {
+		t.Fatalf("角色强制要求时应禁止关闭: %v", err)
+	}
+	if err := ResetTwoFactor(database, user.ID); err != nil {
+		t.Fatalf("重置两步验证失败: %v", err)
+	}
+	var remaining int64
+	database.Model(&model.AdminUserRecoveryCode{}).Where("user_id = ?", user.ID).Count(&remaining)
+	if status, _ := service.GetTwoFactorStatus(user.ID); status.Enabled || remaining != 0 {
+		t.Fatalf("重置后应清除绑定与恢复码: %+v remaining=%d", status, remaining)
+	}
+}
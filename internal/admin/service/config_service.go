@@ -13,10 +13,17 @@ type ConfigService struct {
 }
 
 // AppConfigResponse 应用配置响应
+//
+// SSOProviders 为登录页展示的第三方登录方式，PasswordReset 表示登录页是否展示找回密码入口，
+// CaptchaAdaptive 为 true 时登录页先不展示验证码，登录返回 428 后再展示；Passkey 表示是否启用通行密钥。
 type AppConfigResponse struct {
-	Name  string `json:"name"`
-	Logo  string `json:"logo"`
-	Debug bool   `json:"debug"`
+	Name            string            `json:"name"`
+	Logo            string            `json:"logo"`
+	Debug           bool              `json:"debug"`
+	SSOProviders    []SSOProviderInfo `json:"ssoProviders"`
+	PasswordReset   bool              `json:"passwordReset"`
+	CaptchaAdaptive bool              `json:"captchaAdaptive"`
+	Passkey         bool              `json:"passkey"`
 }
 
 // NewConfigService 创建配置服务
@@ -29,9 +36,21 @@ func NewConfigService(cm *config.ConfigManager) *ConfigService {
 // GetAppConfig 获取应用配置（支持热更新）
 func (s *ConfigService) GetAppConfig() *AppConfigResponse {
 	cfg := s.configManager.GetConfig()
+	providers := make([]SSOProviderInfo, 0, len(cfg.SSO.OIDC))
+	for _, p := range cfg.SSO.OIDC {
+		label := p.Label
+		if label == "" {
+			label = p.Name
+		}
+		providers = append(providers, SSOProviderInfo{Name: p.Name, Label: label})
+	}
 	return &AppConfigResponse{
-		Name:  cfg.App.Name,
-		Logo:  cfg.App.Logo,
-		Debug: cfg.Server.Mode == "debug",
+		Name:            cfg.App.Name,
+		Logo:            cfg.App.Logo,
+		Debug:           cfg.Server.Mode == "debug",
+		SSOProviders:    providers,
+		PasswordReset:   cfg.Mail.Enabled && cfg.Password.ResetURL != "",
+		CaptchaAdaptive: cfg.Captcha.AdaptiveFailures > 0,
+		Passkey:         cfg.WebAuthn.Enabled,
 	}
 }
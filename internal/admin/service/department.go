@@ -0,0 +1,183 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/pkg/crud"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDepartmentNameRequired   = errors.New("部门名称不能为空")
+	ErrDepartmentParentInvalid  = errors.New("上级部门不存在")
+	ErrDepartmentMoveCycle      = errors.New("不能移动到自身或下级部门下")
+	ErrDepartmentLeaderInvalid  = errors.New("部门负责人不存在")
+	ErrDepartmentHasChildren    = errors.New("部门下仍有子部门，不能删除")
+	ErrDepartmentHasMembers     = errors.New("部门下仍有成员，不能删除")
+	ErrDepartmentInvalid        = errors.New("存在无效部门 ID")
+	ErrPrimaryDepartmentInvalid = errors.New("主部门必须是所属部门之一")
+)
+
+// DepartmentDescendants 返回部门及其全部下级部门。
+func DepartmentDescendants(db *gorm.DB, ids []uint) ([]uint, error) {
+	return walkGraph(ids, func(frontier []uint) ([]uint, error) {
+		var children []uint
+		err := db.Model(&model.AdminDepartment{}).Where("parent_id IN ?", frontier).Pluck("id", &children).Error
+		return children, err
+	})
+}
+
+// BuildDepartmentTree 将部门列表组装为树，同级按 Sort、ID 升序。
+// 上级部门不在列表中的部门作为根节点返回，筛选后的列表也能完整展示。
+func BuildDepartmentTree(items []*model.AdminDepartment) []*model.AdminDepartment {
+	slices.SortFunc(items, func(a, b *model.AdminDepartment) int {
+		return cmp.Or(cmp.Compare(a.Sort, b.Sort), cmp.Compare(a.ID, b.ID))
+	})
+	byID := make(map[uint]*model.AdminDepartment, len(items))
+	for _, item := range items {
+		item.Children = nil
+		byID[item.ID] = item
+	}
+	roots := make([]*model.AdminDepartment, 0)
+	for _, item := range items {
+		if parent, ok := byID[item.ParentID]; ok && item.ParentID != item.ID {
+			parent.Children = append(parent.Children, item)
+			continue
+		}
+		roots = append(roots, item)
+	}
+	return roots
+}
+
+// CheckDepartmentParent 校验部门可以放到 parentID 下：上级部门必须存在，且不能是自身或下级部门。
+// id 为 0 表示新建部门。
+func CheckDepartmentParent(db *gorm.DB, id, parentID uint) error {
+	if parentID == 0 {
+		return nil
+	}
+	exists, err := crud.Exists(db, &model.AdminDepartment{}, "id = ?", parentID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDepartmentParentInvalid
+	}
+	if id == 0 {
+		return nil
+	}
+	descendants, err := DepartmentDescendants(db, []uint{id})
+	if err != nil {
+		return err
+	}
+	if slices.Contains(descendants, parentID) {
+		return ErrDepartmentMoveCycle
+	}
+	return nil
+}
+
+// CheckDepartmentLeader 校验部门负责人存在，leaderID 为 nil 表示不设置负责人。
+func CheckDepartmentLeader(db *gorm.DB, leaderID *uint) error {
+	if leaderID == nil {
+		return nil
+	}
+	exists, err := crud.Exists(db, &model.AdminUser{}, "id = ?", *leaderID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrDepartmentLeaderInvalid
+	}
+	return nil
+}
+
+// CheckDepartmentsDeletable 校验部门可以删除：不能有成员，也不能有本次未一并删除的子部门。
+func CheckDepartmentsDeletable(db *gorm.DB, ids []uint) error {
+	exists, err := crud.Exists(db, &model.AdminDepartment{}, "parent_id IN ? AND id NOT IN ?", ids, ids)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDepartmentHasChildren
+	}
+	exists, err = crud.Exists(db, &model.AdminUserDepartment{}, "department_id IN ?", ids)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDepartmentHasMembers
+	}
+	return nil
+}
+
+// SyncUserDepartments 覆盖用户所属部门。primaryID 为 0 时以第一个部门为主部门，非 0 时必须在 departmentIDs 中。
+func SyncUserDepartments(tx *gorm.DB, userID uint, departmentIDs []uint, primaryID uint) error {
+	ids := crud.UniqueUints(departmentIDs)
+	if primaryID != 0 && !slices.Contains(ids, primaryID) {
+		return ErrPrimaryDepartmentInvalid
+	}
+	if len(ids) > 0 {
+		var count int64
+		if err := tx.Model(&model.AdminDepartment{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(ids) {
+			return ErrDepartmentInvalid
+		}
+		if primaryID == 0 {
+			primaryID = ids[0]
+		}
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&model.AdminUserDepartment{}).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	items := make([]model.AdminUserDepartment, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, model.AdminUserDepartment{UserID: userID, DepartmentID: id, Primary: id == primaryID})
+	}
+	return tx.Create(&items).Error
+}
+
+// FillUserDepartments 为用户填充所属部门与主部门。
+func FillUserDepartments(db *gorm.DB, users ...*model.AdminUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	var memberships []model.AdminUserDepartment
+	if err := db.Where("user_id IN ?", userIDs).Order("id").Find(&memberships).Error; err != nil {
+		return err
+	}
+	byUser := make(map[uint][]model.AdminUserDepartment, len(users))
+	for _, m := range memberships {
+		byUser[m.UserID] = append(byUser[m.UserID], m)
+	}
+	for _, user := range users {
+		user.DepartmentIDs = []uint{}
+		user.PrimaryDepartmentID = 0
+		for _, m := range byUser[user.ID] {
+			user.DepartmentIDs = append(user.DepartmentIDs, m.DepartmentID)
+			if m.Primary {
+				user.PrimaryDepartmentID = m.DepartmentID
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveUsersFromDepartments 删除用户的部门成员关系，并清除其负责人身份。
+func RemoveUsersFromDepartments(tx *gorm.DB, userIDs []uint) error {
+	if err := tx.Where("user_id IN ?", userIDs).Delete(&model.AdminUserDepartment{}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.AdminDepartment{}).Where("leader_id IN ?", userIDs).Update("leader_id", nil).Error
+}
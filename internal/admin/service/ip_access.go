@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/core/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrIPDenied = errors.New("当前 IP 不允许访问")
+
+const (
+	// ipRuleReloadInterval 检查规则版本的最小间隔，其他实例修改规则后最迟在该间隔后生效。
+	ipRuleReloadInterval = 5 * time.Second
+	ipRuleVersionKey     = "auth:ip-rules:version"
+)
+
+// ipRuleSet 编译后的全局规则。
+type ipRuleSet struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// IPAccessService 全局 IP 访问控制。
+//
+// 规则编译后保存在内存中，每次请求不查库；修改规则时更新缓存中的版本号，
+// 各实例定期比对版本并重新加载，多实例部署需使用 Redis 缓存。
+type IPAccessService struct {
+	db    *gorm.DB
+	cache cache.Cache
+
+	mu        sync.RWMutex
+	rules     ipRuleSet
+	version   string
+	checkedAt time.Time
+}
+
+// NewIPAccessService 创建 IP 访问控制服务，需调用 Load 加载规则。
+func NewIPAccessService(db *gorm.DB, cache cache.Cache) *IPAccessService {
+	return &IPAccessService{db: db, cache: cache}
+}
+
+// Load 从数据库加载启用的规则。
+func (s *IPAccessService) Load() error {
+	version := s.currentVersion()
+	var rules []model.AdminIPRule
+	if err := s.db.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return err
+	}
+	compiled := compileIPRules(rules)
+
+	s.mu.Lock()
+	s.rules = compiled
+	s.version = version
+	s.checkedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Allowed 判断来源 IP 是否允许访问后台接口。
+func (s *IPAccessService) Allowed(ip string) bool {
+	s.reloadIfChanged()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules.allows(net.ParseIP(ip))
+}
+
+// Invalidate 在规则变更后更新版本号并立即重新加载，其他实例在下次检查时加载。
+func (s *IPAccessService) Invalidate() {
+	_ = s.cache.Set(ipRuleVersionKey, strconv.FormatInt(time.Now().UnixNano(), 10), 0)
+	if err := s.Load(); err != nil {
+		logger.Error("加载 IP 访问规则失败", zap.Error(err))
+	}
+}
+
+// reloadIfChanged 版本号变化时重新加载；加载失败时沿用原规则，下次检查时重试。
+func (s *IPAccessService) reloadIfChanged() {
+	s.mu.RLock()
+	fresh := time.Since(s.checkedAt) < ipRuleReloadInterval
+	s.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	version := s.currentVersion()
+	s.mu.Lock()
+	changed := version != s.version
+	s.checkedAt = time.Now()
+	s.mu.Unlock()
+	if !changed {
+		return
+	}
+	if err := s.Load(); err != nil {
+		logger.Error("加载 IP 访问规则失败", zap.Error(err))
+	}
+}
+
+// currentVersion 读取缓存中的规则版本号，缓存中不存在时为空。
+func (s *IPAccessService) currentVersion() string {
+	value, err := s.cache.Get(ipRuleVersionKey)
+	if err != nil {
+		return ""
+	}
+	version, _ := value.(string)
+	return version
+}
+
+// IPRulesAllow 判断规则集是否允许指定 IP，用于保存规则前检查是否会把操作者自己拒之门外。
+func IPRulesAllow(rules []model.AdminIPRule, ip string) bool {
+	return compileIPRules(rules).allows(net.ParseIP(ip))
+}
+
+// NormalizeIPRule 校验并规范化单条 IP 或 CIDR，CIDR 统一为网络地址形式。
+func NormalizeIPRule(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.String(), nil
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", fmt.Errorf("IP 或 CIDR 格式错误: %s", value)
+	}
+	return network.String(), nil
+}
+
+// compileIPRules 编译启用的规则，无法解析的规则忽略。
+func compileIPRules(rules []model.AdminIPRule) ipRuleSet {
+	var set ipRuleSet
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		network := parseIPNetwork(rule.CIDR)
+		if network == nil {
+			continue
+		}
+		if rule.Action == model.IPRuleDeny {
+			set.deny = append(set.deny, network)
+		} else {
+			set.allow = append(set.allow, network)
+		}
+	}
+	return set
+}
+
+// allows 拒绝规则优先；没有允许规则时默认放行，无法识别的来源地址只在没有任何规则时放行。
+func (r ipRuleSet) allows(ip net.IP) bool {
+	if len(r.allow) == 0 && len(r.deny) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.deny {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	if len(r.allow) == 0 {
+		return true
+	}
+	for _, network := range r.allow {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipListAllows 判断来源 IP 是否命中 IP 或 CIDR 列表，列表为空时不限制，无法解析的项忽略。
+func ipListAllows(items []string, ip string) bool {
+	if len(items) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, item := range items {
+		if network := parseIPNetwork(item); network != nil && network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPNetwork 将单个 IP 或 CIDR 解析为网段，单个 IP 视为 /32 或 /128。
+func parseIPNetwork(value string) *net.IPNet {
+	if ip := net.ParseIP(value); ip != nil {
+		bits := 128
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil
+	}
+	return network
+}
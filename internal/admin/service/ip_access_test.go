@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestIPAccessRulesReload 验证全局规则的判定顺序，以及修改规则后其他实例通过版本号重新加载。
+func TestIPAccessRulesReload(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminIPRule{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	local := NewIPAccessService(database, memoryCache)
+	remote := NewIPAccessService(database, memoryCache)
+	for _, service := range []*IPAccessService{local, remote} {
+		if err := service.Load(); err != nil {
+			t.Fatalf("加载规则失败: %v", err)
+		}
+	}
+	if !remote.Allowed("198.51.100.1") {
+		t.Fatal("没有规则时应放行")
+	}
+
+	database.Create(&[]model.AdminIPRule{
+		{CIDR: "10.0.0.0/8", Action: model.IPRuleAllow, Enabled: true},
+		{CIDR: "10.1.0.5", Action: model.IPRuleDeny, Enabled: true},
+		{CIDR: "192.0.2.0/24", Action: model.IPRuleAllow, Enabled: true},
+	})
+	database.Model(&model.AdminIPRule{}).Where("cidr = ?", "192.0.2.0/24").Update("enabled", false)
+	local.Invalidate()
+	// 模拟其他实例到达检查间隔。
+	remote.checkedAt = time.Now().Add(-ipRuleReloadInterval)
+
+	for ip, want := range map[string]bool{"10.2.3.4": true, "10.1.0.5": false, "192.0.2.1": false, "not-an-ip": false} {
+		if got := remote.Allowed(ip); got != want {
+			t.Fatalf("IP %q 期望 allowed=%v，实际 %v", ip, want, got)
+		}
+	}
+}
+
+// TestUserIPRestrictions 验证用户与角色的来源限制在登录与令牌校验时生效，并随角色变更失效缓存。
+func TestUserIPRestrictions(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{}, &model.AdminRoleDeny{}, &model.AdminRoleParent{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	role := model.AdminRole{Name: "运维", Code: "role_ops", Enabled: true, AllowedIPs: []string{"10.0.0.0/8"}}
+	user := model.AdminUser{Username: "tester", Password: hashed, Enabled: true, AllowedIPs: []string{"10.0.0.0/16", "192.0.2.1"}, Roles: []*model.AdminRole{&role}}
+	if err := database.Create(&user).Error; err != nil {
+		t.Fatalf("创建测试用户失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+
+	// 用户允许 192.0.2.1，但角色限制为 10.0.0.0/8，两者需同时满足。
+	for ip, want := range map[string]bool{"10.0.1.1": true, "10.1.0.1": false, "192.0.2.1": false} {
+		if got := service.IsUserIPAllowed(user.ID, ip); got != want {
+			t.Fatalf("IP %q 期望 allowed=%v，实际 %v", ip, want, got)
+		}
+	}
+
+	_, err = service.Login(&LoginRequest{Username: "tester", Password: "secret-password", Client: ClientInfo{IP: "192.0.2.1"}})
+	if !errors.Is(err, ErrIPDenied) {
+		t.Fatalf("受限 IP 登录应失败，实际: %v", err)
+	}
+	var log model.LoginLog
+	database.Last(&log)
+	if log.Reason != model.LoginReasonIPDenied || log.UserID != user.ID {
+		t.Fatalf("登录日志未记录来源受限: %+v", log)
+	}
+	if _, err := service.Login(&LoginRequest{Username: "tester", Password: "secret-password", Client: ClientInfo{IP: "10.0.9.9"}}); err != nil {
+		t.Fatalf("允许的 IP 登录失败: %v", err)
+	}
+
+	// 停用角色后其限制不再参与判断。
+	database.Model(&role).Update("enabled", false)
+	service.InvalidateRolesUsersPermissionCache([]uint{role.ID})
+	if !service.IsUserIPAllowed(user.ID, "192.0.2.1") {
+		t.Fatal("角色停用后应只按用户自身限制判断")
+	}
+}
+
+// TestNormalizeAllowedIPs 验证来源限制与全局规则使用同一套 IP/CIDR 解析与规范化。
+func TestNormalizeAllowedIPs(t *testing.T) {
+	got, err := NormalizeAllowedIPs([]string{" 192.0.2.7/24 ", "192.0.2.0/24", "10.0.0.1", ""})
+	if err != nil || strings.Join(got, ",") != "192.0.2.0/24,10.0.0.1" {
+		t.Fatalf("应规范为网络地址并去重，实际 %v err=%v", got, err)
+	}
+	if _, err := NormalizeAllowedIPs([]string{"10.0.0.300"}); err == nil {
+		t.Fatal("非法地址应返回错误")
+	}
+	if got, _ := NormalizeAllowedIPs([]string{" "}); got != nil {
+		t.Fatalf("空列表应返回 nil，实际 %v", got)
+	}
+	for ip, want := range map[string]bool{"192.0.2.99": true, "10.0.0.1": true, "10.0.0.2": false, "bad": false} {
+		if allowed := ipListAllows(got, ip); allowed != want {
+			t.Fatalf("IP %q 期望 allowed=%v，实际 %v", ip, want, allowed)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/password"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestLoginLock 验证账号与来源 IP 的失败锁定、锁定事件以及管理员提前解锁。
+func TestLoginLock(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRefreshToken{}, &model.AdminSession{}, &model.LoginLog{}, &model.AdminLoginLock{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	hashed, err := password.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("生成密码哈希失败: %v", err)
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if err := database.Create(&model.AdminUser{Username: username, Password: hashed, Enabled: true}).Error; err != nil {
+			t.Fatalf("创建测试用户失败: %v", err)
+		}
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	lock := NewLoginLockService(database, memoryCache)
+	lock.SetPolicy(LoginLockPolicy{MaxFailures: 3, LockTTL: time.Minute, Window: time.Minute, IPMaxFailures: 5, IPLockTTL: time.Minute})
+	var events []LoginLockEvent
+	lock.OnLock(func(e LoginLockEvent) { events = append(events, e) })
+	service.SetLoginLock(lock)
+
+	office := ClientInfo{IP: "10.0.0.1"}
+	for i := 0; i < 3; i++ {
+		if _, err := service.Login(&LoginRequest{Username: "alice", Password: "wrong", Client: office}); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("第 %d 次错误密码应返回凭证错误，实际: %v", i+1, err)
+		}
+	}
+	if _, err := service.Login(&LoginRequest{Username: "alice", Password: "secret-password", Client: ClientInfo{IP: "10.0.0.2"}}); !errors.Is(err, ErrLoginLocked) {
+		t.Fatalf("达到阈值后账号应锁定，实际: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != model.LoginLockUsername || events[0].Subject != "alice" || events[0].IP != "10.0.0.1" {
+		t.Fatalf("锁定时应触发一次账号锁定事件: %+v", events)
+	}
+
+	if err := lock.Unlock(model.LoginLockUsername, "alice"); err != nil {
+		t.Fatalf("解除锁定失败: %v", err)
+	}
+	if err := lock.Unlock(model.LoginLockUsername, "alice"); !errors.Is(err, ErrLoginLockNotFound) {
+		t.Fatalf("重复解锁应返回记录不存在，实际: %v", err)
+	}
+	if _, err := service.Login(&LoginRequest{Username: "alice", Password: "secret-password", Client: ClientInfo{IP: "10.0.0.2"}}); err != nil {
+		t.Fatalf("解锁后应可登录: %v", err)
+	}
+
+	// 同一来源轮换账号时按 IP 锁定，其他来源不受影响。
+	for _, username := range []string{"bob", "ghost"} {
+		_, _ = service.Login(&LoginRequest{Username: username, Password: "wrong", Client: office})
+	}
+	if !lock.IsLocked("bob", office.IP) || lock.IsLocked("bob", "10.0.0.2") {
+		t.Fatal("来源 IP 达到阈值后应只锁定该 IP")
+	}
+	if _, err := service.Login(&LoginRequest{Username: "bob", Password: "secret-password", Client: ClientInfo{IP: "10.0.0.2"}}); err != nil {
+		t.Fatalf("其他来源应可登录: %v", err)
+	}
+	if last := events[len(events)-1]; last.Kind != model.LoginLockIP || last.Subject != office.IP || last.Failures != 5 {
+		t.Fatalf("应触发 IP 锁定事件: %+v", last)
+	}
+	if err := lock.Unlock(model.LoginLockIP, office.IP); err != nil || lock.IsLocked("bob", office.IP) {
+		t.Fatalf("解除 IP 锁定失败: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleGrantNotFound      = errors.New("角色授权不存在")
+	ErrRoleGrantRoleInvalid   = errors.New("角色不存在")
+	ErrRoleGrantPeriodInvalid = errors.New("授权结束时间必须晚于开始时间与当前时间")
+)
+
+// maxExpiringWithin 即将到期授权的最大查询范围。
+const maxExpiringWithin = 90 * 24 * time.Hour
+
+// RoleGrantRequest 授予或调整用户角色的请求，起止时间为空表示不限。
+// 同一用户与角色只有一条授权，重复授予时覆盖有效期与原因。
+type RoleGrantRequest struct {
+	RoleID     uint       `json:"role_id" binding:"required"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	Reason     string     `json:"reason" binding:"max=255"`
+}
+
+// RoleGrantItem 角色授权展示信息。
+type RoleGrantItem struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	RoleID     uint       `json:"role_id"`
+	RoleName   string     `json:"role_name"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	Reason     string     `json:"reason"`
+	GrantedBy  uint       `json:"granted_by"`
+	Active     bool       `json:"active"`
+}
+
+// ActiveUserRoles 限定 admin_user_roles 中在 now 生效的授权，供所有按用户角色判定权限的查询使用。
+func ActiveUserRoles(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(admin_user_roles.valid_from IS NULL OR admin_user_roles.valid_from <= ?) AND (admin_user_roles.valid_until IS NULL OR admin_user_roles.valid_until > ?)", now, now)
+	}
+}
+
+// nextGrantBoundary 返回用户在 now 之后最近一次授权生效或到期的时间，没有时返回零值。
+func nextGrantBoundary(db *gorm.DB, userID uint, now time.Time) (time.Time, error) {
+	var grants []model.AdminUserRole
+	if err := db.Select("valid_from", "valid_until").
+		Where("user_id = ? AND (valid_from > ? OR valid_until > ?)", userID, now, now).
+		Find(&grants).Error; err != nil {
+		return time.Time{}, err
+	}
+	var next time.Time
+	for _, grant := range grants {
+		for _, at := range []*time.Time{grant.ValidFrom, grant.ValidUntil} {
+			if at != nil && at.After(now) && (next.IsZero() || at.Before(next)) {
+				next = *at
+			}
+		}
+	}
+	return next, nil
+}
+
+// cacheUntilGrantBoundary 缓存用户的权限相关数据，有效期不超过 boundary，
+// 限时授权在生效与到期时刻即时体现，不依赖定时任务失效缓存。
+func (s *AuthService) cacheUntilGrantBoundary(key string, value interface{}, boundary time.Time) {
+	ttl := PermissionCacheTTL
+	if !boundary.IsZero() {
+		ttl = min(ttl, time.Until(boundary))
+	}
+	if ttl <= 0 {
+		return
+	}
+	_ = s.cache.Set(key, value, ttl)
+}
+
+// SaveRoleGrant 授予用户角色或覆盖已有授权的有效期与原因，返回保存后的授权。
+func SaveRoleGrant(tx *gorm.DB, userID uint, req *RoleGrantRequest, grantedBy uint) (*model.AdminUserRole, error) {
+	if req.ValidUntil != nil && (!req.ValidUntil.After(time.Now()) || (req.ValidFrom != nil && !req.ValidUntil.After(*req.ValidFrom))) {
+		return nil, ErrRoleGrantPeriodInvalid
+	}
+	exists, err := crud.Exists(tx, &model.AdminRole{}, "id = ?", req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrRoleGrantRoleInvalid
+	}
+
+	var grant model.AdminUserRole
+	err = tx.Where("user_id = ? AND role_id = ?", userID, req.RoleID).First(&grant).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	grant.UserID = userID
+	grant.RoleID = req.RoleID
+	grant.ValidFrom = req.ValidFrom
+	grant.ValidUntil = req.ValidUntil
+	grant.Reason = req.Reason
+	grant.GrantedBy = grantedBy
+	if err := tx.Save(&grant).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// ListUserRoleGrants 获取用户的全部角色授权，含尚未生效的授权。
+func ListUserRoleGrants(db *gorm.DB, userID uint) ([]RoleGrantItem, error) {
+	return queryRoleGrants(db.Where("admin_user_roles.user_id = ?", userID).Order("admin_user_roles.id"))
+}
+
+// ListExpiringRoleGrants 获取 within 内将要到期的授权，按到期时间升序，within 最长 90 天。
+func ListExpiringRoleGrants(db *gorm.DB, within time.Duration) ([]RoleGrantItem, error) {
+	within = min(within, maxExpiringWithin)
+	now := time.Now()
+	return queryRoleGrants(db.
+		Where("admin_user_roles.valid_until > ? AND admin_user_roles.valid_until <= ?", now, now.Add(within)).
+		Order("admin_user_roles.valid_until"))
+}
+
+func queryRoleGrants(query *gorm.DB) ([]RoleGrantItem, error) {
+	items := make([]RoleGrantItem, 0)
+	err := query.Table("admin_user_roles").
+		Select("admin_user_roles.id, admin_user_roles.user_id, admin_users.username, admin_users.name, admin_user_roles.role_id, " +
+			"admin_roles.name AS role_name, admin_user_roles.valid_from, admin_user_roles.valid_until, admin_user_roles.reason, admin_user_roles.granted_by").
+		Joins("JOIN admin_users ON admin_users.id = admin_user_roles.user_id").
+		Joins("JOIN admin_roles ON admin_roles.id = admin_user_roles.role_id").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range items {
+		items[i].Active = grantActive(items[i].ValidFrom, items[i].ValidUntil, now)
+	}
+	return items, nil
+}
+
+// FillUserRoleGrants 为用户填充带有效期的角色授权，永久授权只体现在 Roles 中。
+func FillUserRoleGrants(db *gorm.DB, users ...*model.AdminUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	var grants []*model.AdminUserRole
+	if err := db.Where("user_id IN ? AND (valid_from IS NOT NULL OR valid_until IS NOT NULL)", userIDs).Order("id").Find(&grants).Error; err != nil {
+		return err
+	}
+	byUser := make(map[uint][]*model.AdminUserRole, len(users))
+	for _, grant := range grants {
+		byUser[grant.UserID] = append(byUser[grant.UserID], grant)
+	}
+	for _, user := range users {
+		user.RoleGrants = byUser[user.ID]
+	}
+	return nil
+}
+
+// ActivatedRoleGrantUsers 返回授权在 (since, now] 内开始生效的用户。
+func ActivatedRoleGrantUsers(db *gorm.DB, since, now time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := db.Model(&model.AdminUserRole{}).
+		Where("valid_from > ? AND valid_from <= ?", since, now).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// ExpireRoleGrants 删除 now 之前已到期的授权并返回，调用方据此失效对应用户的权限缓存。
+func ExpireRoleGrants(db *gorm.DB, now time.Time) ([]model.AdminUserRole, error) {
+	var expired []model.AdminUserRole
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("valid_until <= ?", now).Find(&expired).Error; err != nil {
+			return err
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+		ids := make([]uint, 0, len(expired))
+		for _, grant := range expired {
+			ids = append(ids, grant.ID)
+		}
+		return tx.Where("id IN ?", ids).Delete(&model.AdminUserRole{}).Error
+	})
+	return expired, err
+}
+
+// InvalidateUsersPermissionCache 失效用户的权限与来源 IP 限制缓存，供不持有 AuthService 的定时任务使用。
+func InvalidateUsersPermissionCache(c cache.Cache, userIDs []uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(userIDs)*2)
+	for _, userID := range crud.UniqueUints(userIDs) {
+		keys = append(keys, permissionCacheKey(userID), ipRestrictionCacheKey(userID))
+	}
+	_ = c.DeleteMany(keys)
+}
+
+func grantActive(validFrom, validUntil *time.Time, now time.Time) bool {
+	return (validFrom == nil || !validFrom.After(now)) && (validUntil == nil || validUntil.After(now))
+}
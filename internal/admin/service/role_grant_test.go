@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/admin/model"
+	"bico-admin/internal/core/cache"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/jwt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestRoleGrantValidity 验证未生效与已到期的授权不计入权限，以及定时任务依赖的生效、到期查询与缓存失效。
+func TestRoleGrantValidity(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	roles := []model.AdminRole{
+		{Name: "常驻", Code: "staff", Enabled: true},
+		{Name: "值班", Code: "oncall", Enabled: true},
+		{Name: "审计", Code: "audit", Enabled: true},
+	}
+	database.Create(&roles)
+	database.Create(&[]model.AdminRolePermission{
+		{RoleID: roles[0].ID, Permission: "demo:staff"},
+		{RoleID: roles[1].ID, Permission: "demo:oncall"},
+		{RoleID: roles[2].ID, Permission: "demo:audit"},
+	})
+	user := model.AdminUser{Username: "u1", Password: "x", Enabled: true}
+	database.Create(&user)
+
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Minute)
+	if _, err := SaveRoleGrant(database, user.ID, &RoleGrantRequest{RoleID: roles[0].ID}, 1); err != nil {
+		t.Fatalf("授予永久角色失败: %v", err)
+	}
+	if _, err := SaveRoleGrant(database, user.ID, &RoleGrantRequest{RoleID: roles[1].ID, ValidFrom: &later, Reason: "夜间值班"}, 1); err != nil {
+		t.Fatalf("授予未来生效的角色失败: %v", err)
+	}
+	if _, err := SaveRoleGrant(database, user.ID, &RoleGrantRequest{RoleID: roles[2].ID, ValidUntil: &earlier}, 1); !errors.Is(err, ErrRoleGrantPeriodInvalid) {
+		t.Fatalf("结束时间早于当前时间的授权应被拒绝: %v", err)
+	}
+	// 直接写入一条已到期但尚未被任务清理的授权。
+	database.Create(&model.AdminUserRole{UserID: user.ID, RoleID: roles[2].ID, ValidUntil: &earlier})
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	set, err := service.GetPermissionSet(user.ID)
+	if err != nil {
+		t.Fatalf("获取权限集合失败: %v", err)
+	}
+	if !set.Allows("demo:staff") || set.Allows("demo:oncall") || set.Allows("demo:audit") {
+		t.Fatalf("只有生效中的授权应计入权限: %+v", set)
+	}
+
+	// 模拟时间推进到值班授权生效之后。
+	database.Model(&model.AdminUserRole{}).Where("role_id = ?", roles[1].ID).Update("valid_from", earlier)
+	activated, err := ActivatedRoleGrantUsers(database, now.Add(-time.Hour), now)
+	if err != nil || len(activated) != 1 || activated[0] != user.ID {
+		t.Fatalf("应返回授权刚生效的用户: %v, %v", activated, err)
+	}
+	if set, _ := service.GetPermissionSet(user.ID); set.Allows("demo:oncall") {
+		t.Fatal("失效缓存前应沿用已缓存的权限")
+	}
+	InvalidateUsersPermissionCache(memoryCache, activated)
+	if set, _ := service.GetPermissionSet(user.ID); !set.Allows("demo:oncall") {
+		t.Fatalf("失效缓存后应包含刚生效的授权: %+v", set)
+	}
+
+	expired, err := ExpireRoleGrants(database, now)
+	if err != nil || len(expired) != 1 || expired[0].RoleID != roles[2].ID {
+		t.Fatalf("应删除已到期的授权: %v, %v", expired, err)
+	}
+	if exists, _ := crud.Exists(database, &model.AdminUserRole{}, "role_id = ?", roles[2].ID); exists {
+		t.Fatal("到期授权应已删除")
+	}
+
+	soon := now.Add(48 * time.Hour)
+	if _, err := SaveRoleGrant(database, user.ID, &RoleGrantRequest{RoleID: roles[1].ID, ValidUntil: &soon, Reason: "续期"}, 2); err != nil {
+		t.Fatalf("覆盖已有授权失败: %v", err)
+	}
+	if items, _ := ListExpiringRoleGrants(database, 24*time.Hour); len(items) != 0 {
+		t.Fatalf("范围外的授权不应列为即将到期: %v", items)
+	}
+	items, err := ListExpiringRoleGrants(database, 7*24*time.Hour)
+	if err != nil || len(items) != 1 || items[0].RoleName != "值班" || items[0].Reason != "续期" || !items[0].Active {
+		t.Fatalf("即将到期授权错误: %+v, %v", items, err)
+	}
+}
+
+// TestRoleGrantBoundary 验证限时授权在生效与到期时刻即时改变权限，不依赖定时任务失效缓存。
+func TestRoleGrantBoundary(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := database.AutoMigrate(&model.AdminUser{}, &model.AdminRole{}, &model.AdminUserRole{}, &model.AdminRolePermission{},
+		&model.AdminRoleDeny{}, &model.AdminRoleParent{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	role := model.AdminRole{Name: "值班", Code: "oncall", Enabled: true, AllowedIPs: []string{"10.0.0.0/8"}}
+	database.Create(&role)
+	database.Create(&model.AdminRolePermission{RoleID: role.ID, Permission: "demo:oncall"})
+	user := model.AdminUser{Username: "u1", Password: "x", Enabled: true}
+	database.Create(&user)
+
+	from := time.Now().Add(300 * time.Millisecond)
+	until := from.Add(300 * time.Millisecond)
+	if _, err := SaveRoleGrant(database, user.ID, &RoleGrantRequest{RoleID: role.ID, ValidFrom: &from, ValidUntil: &until}, 1); err != nil {
+		t.Fatalf("授予限时角色失败: %v", err)
+	}
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	service := NewAuthService(database, jwt.NewJWTManager("0123456789abcdef0123456789abcdef", 15*time.Minute), memoryCache)
+	allows := func() bool {
+		set, err := service.GetPermissionSet(user.ID)
+		if err != nil {
+			t.Fatalf("获取权限集合失败: %v", err)
+		}
+		return set.Allows("demo:oncall")
+	}
+
+	if allows() || !service.IsUserIPAllowed(user.ID, "192.168.1.1") {
+		t.Fatal("授权生效前不应拥有角色权限与来源限制")
+	}
+	time.Sleep(time.Until(from) + 20*time.Millisecond)
+	if !allows() || service.IsUserIPAllowed(user.ID, "192.168.1.1") {
+		t.Fatal("授权生效后应立即拥有角色权限与来源限制")
+	}
+	time.Sleep(time.Until(until) + 20*time.Millisecond)
+	if allows() || !service.IsUserIPAllowed(user.ID, "192.168.1.1") {
+		t.Fatal("授权到期后应立即失去角色权限与来源限制")
+	}
+}
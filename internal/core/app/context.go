@@ -14,6 +14,7 @@ import (
 	"bico-admin/internal/core/upload"
 	"bico-admin/internal/pkg/captcha"
 	"bico-admin/internal/pkg/jwt"
+	"bico-admin/internal/pkg/mail"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -35,6 +36,7 @@ type AppContext struct {
 	Uploader      upload.Uploader
 	Scheduler     *scheduler.Scheduler
 	Captcha       *captcha.Captcha
+	Mailer        mail.Mailer
 }
 
 // Module 业务模块接口
@@ -77,7 +79,10 @@ func BuildContext(configPath string) (*AppContext, error) {
 		return nil, err
 	}
 
-	jwtManager := jwt.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireHours)
+	jwtManager, err := newJWTManager(cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
 
 	rateLimiter := buildRateLimiter(cm)
 	engine := server.NewServer(&cfg.Server, rateLimiter, zapLogger)
@@ -89,7 +94,28 @@ func BuildContext(configPath string) (*AppContext, error) {
 
 	schedulerInstance := scheduler.NewScheduler(zapLogger)
 
-	cap := captcha.NewCaptcha(cacheInstance)
+	cap, err := captcha.NewCaptcha(cacheInstance, captcha.Config{
+		Driver: cfg.Captcha.Driver,
+		Length: cfg.Captcha.Length,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 未启用邮件时 Mailer 为 nil，依赖邮件的功能自行关闭。
+	var mailer mail.Mailer
+	if cfg.Mail.Enabled {
+		mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:               cfg.Mail.Host,
+			Port:               cfg.Mail.Port,
+			Username:           cfg.Mail.Username,
+			Password:           cfg.Mail.Password,
+			From:               cfg.Mail.From,
+			Encryption:         cfg.Mail.Encryption,
+			InsecureSkipVerify: cfg.Mail.InsecureSkipVerify,
+			Timeout:            cfg.Mail.Timeout(),
+		})
+	}
 
 	return &AppContext{
 		Cfg:           cfg,
@@ -102,9 +128,23 @@ func BuildContext(configPath string) (*AppContext, error) {
 		Uploader:      uploader,
 		Scheduler:     schedulerInstance,
 		Captcha:       cap,
+		Mailer:        mailer,
 	}, nil
 }
 
+// newJWTManager 配置了密钥环文件时使用密钥环签名，否则使用 jwt.secret 共享密钥。
+// 使用密钥环时 jwt.secret 只在开启 accept_legacy_tokens 时用于校验旧令牌。
+func newJWTManager(cfg config.JWTConfig) (*jwt.JWTManager, error) {
+	if cfg.KeyringFile == "" {
+		return jwt.NewJWTManager(cfg.Secret, cfg.AccessExpire()), nil
+	}
+	keyring, err := jwt.LoadKeyring(cfg.KeyringFile, cfg.LegacySecret())
+	if err != nil {
+		return nil, err
+	}
+	return jwt.NewJWTManagerWithKeyring(keyring, cfg.AccessExpire()), nil
+}
+
 func buildRateLimiter(cm *config.ConfigManager) *middleware.RateLimiter {
 	cfg := cm.GetConfig()
 	if !cfg.RateLimit.Enabled {
@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
@@ -19,17 +21,30 @@ type Config struct {
 	Log       LogConfig       `mapstructure:"log"`
 	Cache     CacheConfig     `mapstructure:"cache"`
 	JWT       JWTConfig       `mapstructure:"jwt"`
+	Password  PasswordConfig  `mapstructure:"password"`
+	LoginLog  LoginLogConfig  `mapstructure:"login_log"`
+	LoginLock LoginLockConfig `mapstructure:"login_lock"`
+	Captcha   CaptchaConfig   `mapstructure:"captcha"`
+	SSO       SSOConfig       `mapstructure:"sso"`
+	LDAP      LDAPConfig      `mapstructure:"ldap"`
+	WebAuthn  WebAuthnConfig  `mapstructure:"webauthn"`
+	Mail      MailConfig      `mapstructure:"mail"`
 	Upload    UploadConfig    `mapstructure:"upload"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	GraphQL   GraphQLConfig   `mapstructure:"graphql"`
 }
 
 // ServerConfig 服务器配置
+//
+// TrustedProxies 为可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才读取 X-Forwarded-For 获取客户端 IP；
+// 留空表示不信任任何代理，直接使用连接来源地址。
 type ServerConfig struct {
 	Port           int      `mapstructure:"port"`
 	Mode           string   `mapstructure:"mode"`
 	EmbedStatic    bool     `mapstructure:"embed_static"`
 	AdminPath      string   `mapstructure:"admin_path"`
 	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // AppConfig 应用配置
@@ -97,9 +112,240 @@ type RedisConfig struct {
 }
 
 // JWTConfig JWT配置
+//
+// ExpireHours 为登录有效期（刷新令牌有效期），AccessExpireMinutes 为访问令牌有效期，
+// MaxSessions 为每个用户同时在线的会话上限（0 表示不限制）。
+// KeyringFile 非空时改用密钥环文件签名，Secret 只在 AcceptLegacyTokens 开启时用于校验切换前签发的令牌；
+// Algorithm 为 `bico-admin jwt rotate` 生成新密钥时默认使用的算法。
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
+	Secret              string `mapstructure:"secret"`
+	ExpireHours         int    `mapstructure:"expire_hours"`
+	AccessExpireMinutes int    `mapstructure:"access_expire_minutes"`
+	MaxSessions         int    `mapstructure:"max_sessions"`
+	KeyringFile         string `mapstructure:"keyring_file"`
+	Algorithm           string `mapstructure:"algorithm"`
+	AcceptLegacyTokens  bool   `mapstructure:"accept_legacy_tokens"`
+}
+
+// LegacySecret 返回使用密钥环时仍用于校验旧令牌的共享密钥，未开启时为空。
+func (c JWTConfig) LegacySecret() string {
+	if c.KeyringFile == "" || !c.AcceptLegacyTokens {
+		return ""
+	}
+	return strings.TrimSpace(c.Secret)
+}
+
+// AccessExpire 返回访问令牌有效期，未配置时默认 15 分钟
+func (c JWTConfig) AccessExpire() time.Duration {
+	if c.AccessExpireMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(c.AccessExpireMinutes) * time.Minute
+}
+
+// RefreshExpire 返回刷新令牌有效期
+func (c JWTConfig) RefreshExpire() time.Duration {
+	return time.Duration(c.ExpireHours) * time.Hour
+}
+
+// PasswordConfig 密码策略配置
+type PasswordConfig struct {
+	MinLength     int      `mapstructure:"min_length"`
+	RequireUpper  bool     `mapstructure:"require_upper"`
+	RequireLower  bool     `mapstructure:"require_lower"`
+	RequireDigit  bool     `mapstructure:"require_digit"`
+	RequireSymbol bool     `mapstructure:"require_symbol"`
+	DenyCommon    bool     `mapstructure:"deny_common"`
+	DenyList      []string `mapstructure:"deny_list"`
+	History       int      `mapstructure:"history"`       // 禁止复用最近 N 次密码
+	MaxAgeDays    int      `mapstructure:"max_age_days"`  // 0 表示永不过期
+	ResetURL      string   `mapstructure:"reset_url"`     // 找回密码邮件中的链接，{token} 替换为重置令牌，留空关闭找回密码
+	ResetMinutes  int      `mapstructure:"reset_minutes"` // 找回密码链接有效期（分钟），默认 30
+
+	// 新密码的哈希算法，已有哈希在登录成功后按当前配置重新生成
+	HashAlgorithm   string `mapstructure:"hash_algorithm"`    // bcrypt / argon2id，默认 bcrypt
+	BcryptCost      int    `mapstructure:"bcrypt_cost"`       // 0 使用默认成本 10
+	Argon2MemoryKiB uint32 `mapstructure:"argon2_memory_kib"` // 0 使用默认 65536
+	Argon2Time      uint32 `mapstructure:"argon2_time"`       // 迭代次数，0 使用默认 3
+	Argon2Threads   uint8  `mapstructure:"argon2_threads"`    // 并行度，0 使用默认 2
+}
+
+// MaxAge 返回密码最长使用期限
+func (c PasswordConfig) MaxAge() time.Duration {
+	return time.Duration(c.MaxAgeDays) * 24 * time.Hour
+}
+
+// ResetTTL 返回找回密码链接有效期，未配置时默认 30 分钟
+func (c PasswordConfig) ResetTTL() time.Duration {
+	if c.ResetMinutes <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(c.ResetMinutes) * time.Minute
+}
+
+// LoginLogConfig 登录日志配置
+type LoginLogConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // 保留天数，0 表示永久保留
+}
+
+// LoginLockConfig 登录失败锁定配置
+//
+// 同一账号或同一来源 IP 在统计窗口内连续失败达到次数后锁定，锁定期内拒绝登录，
+// 管理员可在后台提前解锁。未配置的项使用默认值。
+type LoginLockConfig struct {
+	MaxFailures   int `mapstructure:"max_failures"`    // 账号连续失败次数，默认 5
+	LockMinutes   int `mapstructure:"lock_minutes"`    // 账号锁定时长（分钟），默认 15
+	WindowMinutes int `mapstructure:"window_minutes"`  // 失败次数统计窗口（分钟），默认 15
+	IPMaxFailures int `mapstructure:"ip_max_failures"` // 同一 IP 连续失败次数，默认 20
+	IPLockMinutes int `mapstructure:"ip_lock_minutes"` // IP 锁定时长（分钟），默认 30
+}
+
+// Failures 返回账号锁定阈值
+func (c LoginLockConfig) Failures() int {
+	if c.MaxFailures <= 0 {
+		return 5
+	}
+	return c.MaxFailures
+}
+
+// IPFailures 返回 IP 锁定阈值
+func (c LoginLockConfig) IPFailures() int {
+	if c.IPMaxFailures <= 0 {
+		return 20
+	}
+	return c.IPMaxFailures
+}
+
+// LockTTL 返回账号锁定时长
+func (c LoginLockConfig) LockTTL() time.Duration {
+	return minutesOr(c.LockMinutes, 15)
+}
+
+// Window 返回失败次数统计窗口
+func (c LoginLockConfig) Window() time.Duration {
+	return minutesOr(c.WindowMinutes, 15)
+}
+
+// IPLockTTL 返回 IP 锁定时长
+func (c LoginLockConfig) IPLockTTL() time.Duration {
+	return minutesOr(c.IPLockMinutes, 30)
+}
+
+// minutesOr 将分钟数转换为时长，未配置时使用默认分钟数。
+func minutesOr(minutes, fallback int) time.Duration {
+	if minutes <= 0 {
+		minutes = fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// CaptchaConfig 验证码配置
+//
+// AdaptiveFailures 大于 0 时登录改为按需验证：同一账号或来源 IP 在失败次数统计窗口内
+// 失败达到该次数后才要求验证码；找回密码与重置密码始终需要验证码。
+type CaptchaConfig struct {
+	Driver           string `mapstructure:"driver"`            // digit / string / math / slider，默认 digit
+	Length           int    `mapstructure:"length"`            // 数字与字符验证码的字符数，默认 4
+	AdaptiveFailures int    `mapstructure:"adaptive_failures"` // 0 表示每次登录都需要验证码
+}
+
+// SSOConfig 单点登录配置
+type SSOConfig struct {
+	OIDC []OIDCProviderConfig `mapstructure:"oidc"`
+}
+
+// OIDCProviderConfig OIDC 提供方配置
+//
+// Name 为登录路由中的提供方标识，Label 为登录页按钮文字。声明名未配置时依次使用
+// preferred_username、name、groups。AutoProvision 允许首次登录自动创建用户，
+// LinkExisting 允许按用户名绑定已有本地账号；DefaultRoles 与 RoleMapping 均填写角色编码。
+type OIDCProviderConfig struct {
+	Name          string             `mapstructure:"name"`
+	Label         string             `mapstructure:"label"`
+	Issuer        string             `mapstructure:"issuer"`
+	ClientID      string             `mapstructure:"client_id"`
+	ClientSecret  string             `mapstructure:"client_secret"`
+	RedirectURL   string             `mapstructure:"redirect_url"`
+	Scopes        []string           `mapstructure:"scopes"`
+	UsernameClaim string             `mapstructure:"username_claim"`
+	NameClaim     string             `mapstructure:"name_claim"`
+	GroupsClaim   string             `mapstructure:"groups_claim"`
+	AutoProvision bool               `mapstructure:"auto_provision"`
+	LinkExisting  bool               `mapstructure:"link_existing"`
+	DefaultRoles  []string           `mapstructure:"default_roles"`
+	RoleMapping   []GroupRoleMapping `mapstructure:"role_mapping"`
+}
+
+// GroupRoleMapping 外部用户组到角色编码的映射
+//
+// 使用列表而非 map，避免 viper 将组名统一转为小写。
+type GroupRoleMapping struct {
+	Group string   `mapstructure:"group"`
+	Roles []string `mapstructure:"roles"`
+}
+
+// LDAPConfig LDAP / Active Directory 认证配置
+//
+// 启用后账号密码登录先以服务账号按 UserFilter 查找用户，再以用户 DN 绑定校验密码；
+// UserFilter 与 GroupFilter 中的 {username} 替换为登录名，GroupFilter 中的 {dn} 替换为用户 DN。
+// LocalUsers 中的账号跳过目录服务、始终校验本地密码，用于目录服务不可用时的应急登录。
+// AutoProvision、LinkExisting、DefaultRoles 与 RoleMapping 的含义同单点登录，组名可填写组 DN 或其 cn。
+type LDAPConfig struct {
+	Enabled            bool               `mapstructure:"enabled"`
+	URL                string             `mapstructure:"url"`
+	StartTLS           bool               `mapstructure:"start_tls"`
+	InsecureSkipVerify bool               `mapstructure:"insecure_skip_verify"`
+	BindDN             string             `mapstructure:"bind_dn"`
+	BindPassword       string             `mapstructure:"bind_password"`
+	BaseDN             string             `mapstructure:"base_dn"`
+	UserFilter         string             `mapstructure:"user_filter"`
+	UsernameAttr       string             `mapstructure:"username_attr"`
+	NameAttr           string             `mapstructure:"name_attr"`
+	GroupAttr          string             `mapstructure:"group_attr"`
+	GroupBaseDN        string             `mapstructure:"group_base_dn"`
+	GroupFilter        string             `mapstructure:"group_filter"`
+	TimeoutSeconds     int                `mapstructure:"timeout_seconds"`
+	AutoProvision      bool               `mapstructure:"auto_provision"`
+	LinkExisting       bool               `mapstructure:"link_existing"`
+	DefaultRoles       []string           `mapstructure:"default_roles"`
+	RoleMapping        []GroupRoleMapping `mapstructure:"role_mapping"`
+	LocalUsers         []string           `mapstructure:"local_users"`
+}
+
+// Timeout 返回连接与查询超时时间
+func (c LDAPConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// WebAuthnConfig 通行密钥（WebAuthn）配置
+//
+// RPID 为后台访问域名（不含协议与端口），Origins 为浏览器地址栏中的完整来源，如 https://admin.example.com；
+// 两者与实际访问地址不一致时浏览器会拒绝注册与登录。
+type WebAuthnConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	RPID    string   `mapstructure:"rp_id"`
+	RPName  string   `mapstructure:"rp_name"` // 认证器中显示的名称，默认使用 app.name
+	Origins []string `mapstructure:"origins"`
+}
+
+// MailConfig 邮件发送配置
+//
+// Encryption 为 none、starttls 或 tls，未配置时使用 starttls；From 支持 "名称 <地址>" 格式。
+type MailConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	Host               string `mapstructure:"host"`
+	Port               int    `mapstructure:"port"`
+	Username           string `mapstructure:"username"`
+	Password           string `mapstructure:"password"`
+	From               string `mapstructure:"from"`
+	Encryption         string `mapstructure:"encryption"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	TimeoutSeconds     int    `mapstructure:"timeout_seconds"`
+}
+
+// Timeout 返回连接与发送超时时间
+func (c MailConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
 }
 
 // UploadConfig 文件上传配置
@@ -147,6 +393,11 @@ type RateLimitConfig struct {
 	Burst   int  `mapstructure:"burst"`   // 突发流量桶容量
 }
 
+// GraphQLConfig GraphQL 配置
+type GraphQLConfig struct {
+	Enabled bool `mapstructure:"enabled"` // 是否启用 /admin-api/graphql
+}
+
 // GetDriver 获取缓存驱动
 func (c *CacheConfig) GetDriver() string {
 	return c.Driver
@@ -287,6 +538,8 @@ func newViper(configPath string) *viper.Viper {
 	_ = v.BindEnv("database.mysql.password")
 	_ = v.BindEnv("database.postgres.password")
 	_ = v.BindEnv("cache.redis.password")
+	_ = v.BindEnv("ldap.bind_password")
+	_ = v.BindEnv("mail.password")
 	_ = v.BindEnv("upload.qiniu.access_key")
 	_ = v.BindEnv("upload.qiniu.secret_key")
 	_ = v.BindEnv("upload.aliyun.access_key_id")
@@ -316,9 +569,70 @@ func (c *Config) Validate() error {
 	if c.JWT.ExpireHours <= 0 {
 		return fmt.Errorf("jwt.expire_hours 必须大于 0")
 	}
+	if c.JWT.AccessExpire() > c.JWT.RefreshExpire() {
+		return fmt.Errorf("jwt.access_expire_minutes 不能超过 jwt.expire_hours")
+	}
+	if c.JWT.MaxSessions < 0 {
+		return fmt.Errorf("jwt.max_sessions 不能小于 0")
+	}
+	switch c.JWT.Algorithm {
+	case "", "HS256", "RS256", "ES256", "EdDSA":
+	default:
+		return fmt.Errorf("jwt.algorithm 仅支持 HS256、RS256、ES256 或 EdDSA")
+	}
+	if c.Password.MinLength < 0 || c.Password.History < 0 || c.Password.MaxAgeDays < 0 {
+		return fmt.Errorf("password.min_length、password.history、password.max_age_days 不能小于 0")
+	}
+	switch c.Password.HashAlgorithm {
+	case "", "bcrypt", "argon2id":
+	default:
+		return fmt.Errorf("password.hash_algorithm 仅支持 bcrypt 或 argon2id")
+	}
+	if c.Password.BcryptCost != 0 && (c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31) {
+		return fmt.Errorf("password.bcrypt_cost 取值范围为 4-31")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("server.trusted_proxies 格式错误: %s", proxy)
+			}
+		}
+	}
+	if c.LoginLog.RetentionDays < 0 {
+		return fmt.Errorf("login_log.retention_days 不能小于 0")
+	}
+	if c.LoginLock.MaxFailures < 0 || c.LoginLock.IPMaxFailures < 0 {
+		return fmt.Errorf("login_lock 失败次数不能小于 0")
+	}
+	if c.LoginLock.LockMinutes < 0 || c.LoginLock.WindowMinutes < 0 || c.LoginLock.IPLockMinutes < 0 {
+		return fmt.Errorf("login_lock 时长不能小于 0")
+	}
+	if err := c.Captcha.validate(); err != nil {
+		return err
+	}
+	if err := c.SSO.validate(); err != nil {
+		return err
+	}
+	if err := c.LDAP.validate(); err != nil {
+		return err
+	}
+	if err := c.WebAuthn.validate(); err != nil {
+		return err
+	}
+	if err := c.Mail.validate(); err != nil {
+		return err
+	}
+	if c.Password.ResetURL != "" && !strings.Contains(c.Password.ResetURL, "{token}") {
+		return fmt.Errorf("password.reset_url 必须包含 {token} 占位符")
+	}
 	if c.Server.Mode == "release" {
+		// 使用密钥环时 secret 只在校验旧令牌时使用，此时同样必须是强密钥。
 		secret := strings.TrimSpace(c.JWT.Secret)
-		if len(secret) < 32 || secret == "bico-admin-secret-key-change-in-production" {
+		if c.JWT.KeyringFile != "" {
+			secret = c.JWT.LegacySecret()
+		}
+		weak := len(secret) < 32 || secret == "bico-admin-secret-key-change-in-production"
+		if weak && (c.JWT.KeyringFile == "" || secret != "") {
 			return fmt.Errorf("release 模式必须通过 BICO_JWT_SECRET 配置至少 32 位的 JWT 密钥")
 		}
 		for _, origin := range c.Server.AllowedOrigins {
@@ -330,6 +644,84 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validate 校验单点登录提供方配置，提供方标识需唯一。
+func (c SSOConfig) validate() error {
+	names := make(map[string]struct{}, len(c.OIDC))
+	for i, p := range c.OIDC {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return fmt.Errorf("sso.oidc[%d] 的 name、issuer、client_id、redirect_url 不能为空", i)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("sso.oidc 提供方 %s 重复", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+	return nil
+}
+
+// validate 校验 LDAP 配置，未启用时不校验。
+func (c LDAPConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" || c.BaseDN == "" || c.UserFilter == "" {
+		return fmt.Errorf("ldap.url、ldap.base_dn、ldap.user_filter 不能为空")
+	}
+	if !strings.Contains(c.UserFilter, "{username}") {
+		return fmt.Errorf("ldap.user_filter 必须包含 {username} 占位符")
+	}
+	if c.TimeoutSeconds < 0 {
+		return fmt.Errorf("ldap.timeout_seconds 不能小于 0")
+	}
+	return nil
+}
+
+// validate 校验通行密钥配置，未启用时不校验。
+func (c WebAuthnConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RPID == "" || len(c.Origins) == 0 {
+		return fmt.Errorf("webauthn.rp_id、webauthn.origins 不能为空")
+	}
+	return nil
+}
+
+// validate 校验邮件配置，未启用时不校验。
+func (c MailConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Host == "" || c.Port <= 0 || c.From == "" {
+		return fmt.Errorf("mail.host、mail.port、mail.from 不能为空")
+	}
+	switch c.Encryption {
+	case "", "none", "starttls", "tls":
+	default:
+		return fmt.Errorf("mail.encryption 仅支持 none、starttls 或 tls")
+	}
+	if c.TimeoutSeconds < 0 {
+		return fmt.Errorf("mail.timeout_seconds 不能小于 0")
+	}
+	return nil
+}
+
+// validate 校验验证码配置
+func (c CaptchaConfig) validate() error {
+	switch c.Driver {
+	case "", "digit", "string", "math", "slider":
+	default:
+		return fmt.Errorf("captcha.driver 仅支持 digit、string、math 或 slider")
+	}
+	if c.Length < 0 || c.Length > 8 {
+		return fmt.Errorf("captcha.length 需在 0 到 8 之间")
+	}
+	if c.AdaptiveFailures < 0 {
+		return fmt.Errorf("captcha.adaptive_failures 不能小于 0")
+	}
+	return nil
+}
+
 // findConfigFile 查找配置文件
 func findConfigFile(configPath string) (string, error) {
 	// 如果指定了路径且文件存在，直接使用
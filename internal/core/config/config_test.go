@@ -46,3 +46,37 @@ func writeTestConfig(t *testing.T, mode string, secret string) string {
 	}
 	return path
 }
+
+// TestJWTLegacySecret 验证使用密钥环后共享密钥只在显式开启时参与校验。
+func TestJWTLegacySecret(t *testing.T) {
+	cfg := JWTConfig{Secret: "legacy-secret", KeyringFile: "/tmp/jwt-keys.json"}
+	if got := cfg.LegacySecret(); got != "" {
+		t.Fatalf("未开启 accept_legacy_tokens 时不应保留共享密钥，实际 %q", got)
+	}
+	cfg.AcceptLegacyTokens = true
+	if got := cfg.LegacySecret(); got != "legacy-secret" {
+		t.Fatalf("开启 accept_legacy_tokens 后应保留共享密钥，实际 %q", got)
+	}
+	cfg.KeyringFile = ""
+	if got := cfg.LegacySecret(); got != "" {
+		t.Fatalf("未使用密钥环时没有旧令牌密钥，实际 %q", got)
+	}
+
+	// 不再校验旧令牌时，release 模式不要求 secret 为强密钥。
+	path := writeTestConfig(t, "release", "short-secret")
+	content, _ := os.ReadFile(path)
+	content = append(content, "  keyring_file: /tmp/jwt-keys.json\n"...)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("写入测试配置失败: %v", err)
+	}
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatalf("未开启 accept_legacy_tokens 时不应校验 secret: %v", err)
+	}
+	content = append(content, "  accept_legacy_tokens: true\n"...)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("写入测试配置失败: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "JWT 密钥") {
+		t.Fatalf("开启 accept_legacy_tokens 时 secret 仍须为强密钥，实际: %v", err)
+	}
+}
@@ -9,9 +9,16 @@ import (
 )
 
 // JWTAuth JWT认证中间件
+//
+// 以 jwt.APITokenPrefix 开头的 Bearer 令牌按个人访问令牌校验，其权限范围写入上下文 token_scopes，
+// 由权限中间件与用户权限取交集。两种令牌都需满足所属用户的来源 IP 限制。
+// 模拟登录令牌的操作者写入上下文 impersonator_id / impersonator，来源 IP 按操作者校验。
 func JWTAuth(jwtManager *jwt.JWTManager, authService interface {
 	IsTokenBlacklisted(token string) bool
+	IsTokenFamilyRevoked(familyID string) bool
 	IsTokenVersionValid(userID uint, version uint) bool
+	AuthenticateAPIToken(token, ip string) (*jwt.Claims, error)
+	IsUserIPAllowed(userID uint, ip string) bool
 }) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取 Authorization header
@@ -32,6 +39,26 @@ func JWTAuth(jwtManager *jwt.JWTManager, authService interface {
 
 		token := parts[1]
 
+		if strings.HasPrefix(token, jwt.APITokenPrefix) {
+			claims, err := authService.AuthenticateAPIToken(token, c.ClientIP())
+			if err != nil {
+				response.ErrorWithCode(c, 401, err.Error())
+				c.Abort()
+				return
+			}
+			if !authService.IsUserIPAllowed(claims.UserID, c.ClientIP()) {
+				response.ErrorWithCode(c, 403, "当前 IP 不允许访问")
+				c.Abort()
+				return
+			}
+			c.Set("user_id", claims.UserID)
+			c.Set("username", claims.Username)
+			c.Set("api_token", true)
+			c.Set("token_scopes", claims.Scopes)
+			c.Next()
+			return
+		}
+
 		// 验证 token
 		claims, err := jwtManager.ParseToken(token)
 
@@ -45,16 +72,32 @@ func JWTAuth(jwtManager *jwt.JWTManager, authService interface {
 			c.Abort()
 			return
 		}
-		if authService.IsTokenBlacklisted(token) || !authService.IsTokenVersionValid(claims.UserID, claims.Version) {
-			// 黑名单和用户令牌版本共同控制主动失效。
+		if authService.IsTokenBlacklisted(token) || authService.IsTokenFamilyRevoked(claims.Family) ||
+			!authService.IsTokenVersionValid(claims.UserID, claims.Version) {
+			// 黑名单、令牌族吊销和用户令牌版本共同控制主动失效。
 			response.ErrorWithCode(c, 401, "token 已失效")
 			c.Abort()
 			return
 		}
+		// 用户与角色的来源 IP 限制在每次请求时校验，修改限制后已登录的会话同样受约束。
+		ipUserID := claims.UserID
+		if claims.Actor != nil {
+			ipUserID = claims.Actor.UserID
+		}
+		if !authService.IsUserIPAllowed(ipUserID, c.ClientIP()) {
+			response.ErrorWithCode(c, 403, "当前 IP 不允许访问")
+			c.Abort()
+			return
+		}
 
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("token_family", claims.Family)
+		if claims.Actor != nil {
+			c.Set("impersonator_id", claims.Actor.UserID)
+			c.Set("impersonator", claims.Actor.Username)
+		}
 
 		c.Next()
 	}
@@ -30,6 +30,16 @@ func NewRateLimiter(rps int, burst int) *RateLimiter {
 	}
 }
 
+// NewRateLimiterEvery 创建低频限流器，每 interval 补充一次请求额度
+// 用于找回密码等每秒一次都嫌多的敏感接口。
+func NewRateLimiterEvery(interval time.Duration, burst int) *RateLimiter {
+	return &RateLimiter{
+		limiters: make(map[string]*rate.Limiter),
+		rate:     rate.Every(interval),
+		burst:    burst,
+	}
+}
+
 // getLimiter 获取或创建限流器
 func (rl *RateLimiter) getLimiter(key string) *rate.Limiter {
 	rl.mu.RLock()
@@ -6,15 +6,18 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"bico-admin/internal/core/config"
 	"bico-admin/internal/core/middleware"
 	"bico-admin/internal/pkg/response"
+	swaggercrud "bico-admin/internal/pkg/swagger"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +28,8 @@ func NewServer(cfg *config.ServerConfig, rateLimiter *middleware.RateLimiter, za
 	engine := gin.New()
 	engine.RedirectTrailingSlash = false
 	engine.RedirectFixedPath = false
+	// 只信任配置的反向代理转发的客户端 IP，地址格式已在配置校验中检查。
+	_ = engine.SetTrustedProxies(cfg.TrustedProxies)
 	engine.Use(zapRecovery(zapLogger))
 	engine.Use(zapAccessLogger(zapLogger, cfg.Mode == "debug"))
 
@@ -120,8 +125,8 @@ func RegisterCoreRoutes(engine *gin.Engine, configManager *config.ConfigManager,
 	})
 
 	// Swagger 文档，分别暴露对外 API 与后台管理 API。
-	engine.GET("/swagger/api/*any", ginSwagger.WrapHandler(swaggerFiles.NewHandler(), ginSwagger.InstanceName("api")))
-	engine.GET("/swagger/admin/*any", ginSwagger.WrapHandler(swaggerFiles.NewHandler(), ginSwagger.InstanceName("admin")))
+	engine.GET("/swagger/api/*any", swaggerHandler("api"))
+	engine.GET("/swagger/admin/*any", swaggerHandler("admin"))
 
 	// 静态文件服务（用于访问上传的文件）
 	cfg := configManager.GetConfig()
@@ -135,6 +140,40 @@ func RegisterCoreRoutes(engine *gin.Engine, configManager *config.ConfigManager,
 	}
 }
 
+// swaggerHandler 返回 Swagger UI 处理器，并在同一前缀下提供 OpenAPI 3.1 文档。
+//
+// 说明：Gin 不允许在通配路由下再注册静态路径，因此 openapi.json 在通配处理器内分发。
+func swaggerHandler(instanceName string) gin.HandlerFunc {
+	uiHandler := ginSwagger.WrapHandler(swaggerFiles.NewHandler(), ginSwagger.InstanceName(instanceName))
+
+	var (
+		once    sync.Once
+		openAPI string
+		convErr error
+	)
+	return func(c *gin.Context) {
+		if c.Param("any") != "/openapi.json" {
+			uiHandler(c)
+			return
+		}
+
+		// 文档在进程内不会变化，首次请求时转换并复用结果。
+		once.Do(func() {
+			doc, err := swag.ReadDoc(instanceName)
+			if err != nil {
+				convErr = err
+				return
+			}
+			openAPI, convErr = swaggercrud.ConvertToOpenAPI31(doc)
+		})
+		if convErr != nil {
+			response.ErrorWithStatus(c, http.StatusInternalServerError, 500, "生成 OpenAPI 文档失败")
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(openAPI))
+	}
+}
+
 // serveEmbedStatic 服务嵌入的前端静态文件。
 //
 // 说明：Gin 的路由表无法在运行时增删，因此通过 NoRoute 分发后台入口并在每次请求时读取最新路径。
@@ -21,7 +21,7 @@ func (m *Module) Name() string {
 
 // Register 注册任务
 func (m *Module) Register(ctx *app.AppContext) error {
-	if err := RegisterJobs(ctx.Scheduler, ctx.DB, ctx.Cache, ctx.Logger); err != nil {
+	if err := RegisterJobs(ctx.Scheduler, ctx.DB, ctx.Cache, ctx.Logger, ctx.Cfg); err != nil {
 		return fmt.Errorf("register jobs failed: %w", err)
 	}
 	return nil
@@ -1,7 +1,10 @@
 package job
 
 import (
+	"time"
+
 	"bico-admin/internal/core/cache"
+	"bico-admin/internal/core/config"
 	"bico-admin/internal/core/scheduler"
 	"bico-admin/internal/job/task"
 
@@ -10,7 +13,7 @@ import (
 )
 
 // RegisterJobs 注册所有定时任务
-func RegisterJobs(scheduler *scheduler.Scheduler, db *gorm.DB, cache cache.Cache, logger *zap.Logger) error {
+func RegisterJobs(scheduler *scheduler.Scheduler, db *gorm.DB, cache cache.Cache, logger *zap.Logger, cfg *config.Config) error {
 	// 注意：cron 表达式格式：秒 分 时 日 月 周
 	// 例如：
 	// "0 0 2 * * *"  - 每天凌晘02:00:00执行
@@ -23,12 +26,31 @@ func RegisterJobs(scheduler *scheduler.Scheduler, db *gorm.DB, cache cache.Cache
 		return err
 	}
 
+	// 注册刷新令牌清理任务（每天凌晨 3 点 30 分执行）
+	refreshTokenCleanTask := task.NewRefreshTokenCleanTask(db, logger)
+	if err := scheduler.AddTask("0 30 3 * * *", refreshTokenCleanTask, "RefreshTokenCleanTask"); err != nil {
+		return err
+	}
+
+	// 注册登录日志清理任务（每天凌晨 4 点执行）
+	retention := time.Duration(cfg.LoginLog.RetentionDays) * 24 * time.Hour
+	loginLogCleanTask := task.NewLoginLogCleanTask(db, retention, logger)
+	if err := scheduler.AddTask("0 0 4 * * *", loginLogCleanTask, "LoginLogCleanTask"); err != nil {
+		return err
+	}
+
 	// 注册同步任务（每小时执行一次）
 	syncTask := task.NewSyncTask(db, logger)
 	if err := scheduler.AddTask("0 0 * * * *", syncTask, "SyncTask"); err != nil {
 		return err
 	}
 
+	// 注册限时角色授权任务（每分钟执行一次）
+	roleGrantTask := task.NewRoleGrantTask(db, cache, logger)
+	if err := scheduler.AddTask("0 * * * * *", roleGrantTask, "RoleGrantTask"); err != nil {
+		return err
+	}
+
 	// 可以继续注册更多任务...
 
 	return nil
@@ -0,0 +1,41 @@
+package task
+
+import (
+	"time"
+
+	"bico-admin/internal/admin/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// LoginLogCleanTask 清理超过保留期的登录日志
+type LoginLogCleanTask struct {
+	db        *gorm.DB
+	retention time.Duration
+	logger    *zap.Logger
+}
+
+// NewLoginLogCleanTask 创建登录日志清理任务，retention 不大于 0 时不清理
+func NewLoginLogCleanTask(db *gorm.DB, retention time.Duration, logger *zap.Logger) *LoginLogCleanTask {
+	return &LoginLogCleanTask{
+		db:        db,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Run 执行清理任务
+func (t *LoginLogCleanTask) Run() error {
+	if t.retention <= 0 {
+		return nil
+	}
+
+	result := t.db.Where("created_at < ?", time.Now().Add(-t.retention)).Delete(&model.LoginLog{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	t.logger.Info("登录日志清理完成", zap.Int64("cleaned_count", result.RowsAffected))
+	return nil
+}
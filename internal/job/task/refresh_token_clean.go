@@ -0,0 +1,44 @@
+package task
+
+import (
+	"time"
+
+	"bico-admin/internal/admin/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenCleanTask 清理已过期的刷新令牌与登录会话
+type RefreshTokenCleanTask struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+// NewRefreshTokenCleanTask 创建刷新令牌清理任务
+func NewRefreshTokenCleanTask(db *gorm.DB, logger *zap.Logger) *RefreshTokenCleanTask {
+	return &RefreshTokenCleanTask{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Run 执行清理任务
+func (t *RefreshTokenCleanTask) Run() error {
+	// 过期令牌已无法刷新，也不再参与重复使用检测，可以直接删除。
+	result := t.db.Where("expires_at < ?", time.Now()).Delete(&model.AdminRefreshToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	sessions := t.db.Where("expires_at < ?", time.Now()).Delete(&model.AdminSession{})
+	if sessions.Error != nil {
+		return sessions.Error
+	}
+
+	t.logger.Info("刷新令牌清理完成",
+		zap.Int64("cleaned_count", result.RowsAffected),
+		zap.Int64("session_count", sessions.RowsAffected),
+	)
+	return nil
+}
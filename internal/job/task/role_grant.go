@@ -0,0 +1,63 @@
+package task
+
+import (
+	"time"
+
+	"bico-admin/internal/admin/service"
+	"bico-admin/internal/core/cache"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RoleGrantTask 处理限时角色授权的生效与到期：失效相关用户的权限缓存，并删除已到期的授权
+type RoleGrantTask struct {
+	db      *gorm.DB
+	cache   cache.Cache
+	logger  *zap.Logger
+	lastRun time.Time
+}
+
+// NewRoleGrantTask 创建限时角色授权任务
+func NewRoleGrantTask(db *gorm.DB, cache cache.Cache, logger *zap.Logger) *RoleGrantTask {
+	return &RoleGrantTask{
+		db:     db,
+		cache:  cache,
+		logger: logger,
+		// 更早生效的授权对应的权限缓存已自然过期，无需处理。
+		lastRun: time.Now().Add(-service.PermissionCacheTTL),
+	}
+}
+
+// Run 执行限时角色授权任务
+func (t *RoleGrantTask) Run() error {
+	now := time.Now()
+	activated, err := service.ActivatedRoleGrantUsers(t.db, t.lastRun, now)
+	if err != nil {
+		return err
+	}
+	expired, err := service.ExpireRoleGrants(t.db, now)
+	if err != nil {
+		return err
+	}
+
+	userIDs := activated
+	for _, grant := range expired {
+		userIDs = append(userIDs, grant.UserID)
+		t.logger.Info("角色授权已到期",
+			zap.Uint("user_id", grant.UserID),
+			zap.Uint("role_id", grant.RoleID),
+			zap.Timep("valid_until", grant.ValidUntil),
+		)
+	}
+	service.InvalidateUsersPermissionCache(t.cache, userIDs)
+	t.lastRun = now
+
+	if len(activated) > 0 || len(expired) > 0 {
+		t.logger.Info("限时角色授权处理完成",
+			zap.Int("activated_user_count", len(activated)),
+			zap.Int("expired_count", len(expired)),
+		)
+	}
+	return nil
+}
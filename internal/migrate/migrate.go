@@ -19,7 +19,25 @@ func AutoMigrate(db *gorm.DB, mode string) error {
 		&adminModel.AdminUser{},
 		&adminModel.AdminRole{},
 		&adminModel.AdminRolePermission{},
+		&adminModel.AdminRoleDeny{},
+		&adminModel.AdminRoleParent{},
 		&adminModel.AdminUserRole{},
+		&adminModel.AdminDepartment{},
+		&adminModel.AdminUserDepartment{},
+		&adminModel.AdminUserRecoveryCode{},
+		&adminModel.AdminPasswordHistory{},
+		&adminModel.AdminUserIdentity{},
+		&adminModel.AdminRefreshToken{},
+		&adminModel.AdminSession{},
+		&adminModel.AdminAPIToken{},
+		&adminModel.LoginLog{},
+		&adminModel.AdminIPRule{},
+		&adminModel.AdminImpersonation{},
+		&adminModel.AdminLoginLock{},
+		&adminModel.AdminPasskey{},
+		&adminModel.DynamicTable{},
+		&adminModel.ListView{},
+		&adminModel.ListViewRole{},
 	); err != nil {
 		return err
 	}
@@ -88,6 +106,12 @@ func ensureInitialAdmin(db *gorm.DB, mode string) (*adminModel.AdminUser, error)
 			}
 			return nil, err
 		}
+		if !admin.MustChangePassword && admin.PasswordChangedAt == nil && password.Verify(admin.Password, "admin") {
+			// 升级前仍在使用默认密码的 admin 账号，下次登录时要求修改。
+			if err := db.Model(&admin).Update("must_change_password", true).Error; err != nil {
+				return nil, err
+			}
+		}
 		return &admin, nil
 	}
 
@@ -96,12 +120,14 @@ func ensureInitialAdmin(db *gorm.DB, mode string) (*adminModel.AdminUser, error)
 		return nil, fmt.Errorf("密码加密失败: %w", err)
 	}
 
+	// 默认凭据公开可知，首次登录必须修改。
 	admin := adminModel.AdminUser{
-		Username: "admin",
-		Password: hashedPassword,
-		Name:     "系统管理员",
-		Avatar:   "https://api.dicebear.com/9.x/thumbs/png?seed=slowlyo",
-		Enabled:  true,
+		Username:           "admin",
+		Password:           hashedPassword,
+		Name:               "系统管理员",
+		Avatar:             "https://api.dicebear.com/9.x/thumbs/png?seed=slowlyo",
+		Enabled:            true,
+		MustChangePassword: true,
 	}
 
 	if err := db.Create(&admin).Error; err != nil {
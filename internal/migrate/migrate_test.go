@@ -33,6 +33,10 @@ func TestAutoMigrateInitializesDefaultAdmin(t *testing.T) {
 		// 初始密码必须保持与系统默认登录凭据一致。
 		t.Fatal("默认管理员密码错误")
 	}
+	if !admin.MustChangePassword {
+		// 默认凭据公开可知，必须要求首次登录修改。
+		t.Fatal("默认管理员未要求修改密码")
+	}
 }
 
 // TestAutoMigrateAssignsSuperAdminRole 验证首个管理员通过保留角色获得权限。
@@ -1,11 +1,44 @@
 package captcha
 
 import (
+	"fmt"
+	"strings"
 	"time"
-	
+
 	"github.com/mojocn/base64Captcha"
 )
 
+// 验证码类型
+const (
+	DriverDigit  = "digit"
+	DriverString = "string"
+	DriverMath   = "math"
+	DriverSlider = "slider"
+)
+
+// Drivers 支持的验证码类型
+var Drivers = []string{DriverDigit, DriverString, DriverMath, DriverSlider}
+
+// Config 验证码配置
+type Config struct {
+	Driver string // 验证码类型，默认 digit
+	Length int    // 数字与字符验证码的字符数，默认 4
+}
+
+// Challenge 下发给前端的验证码。
+//
+// 图片类验证码只有 Image；滑块验证码的 Image 为缺口背景图，Piece 为拼图块，
+// 前端将拼图块放在 PieceY 高度并拖动到缺口，提交拼图块左边缘的横坐标与拖动轨迹作为答案。
+type Challenge struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Image  string `json:"image"`
+	Piece  string `json:"piece,omitempty"`
+	PieceY int    `json:"pieceY,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
 type Store interface {
 	Set(key string, value interface{}, expiration time.Duration) error
 	Get(key string) (interface{}, error)
@@ -24,61 +57,103 @@ func (s *storeAdapter) Set(id string, value string) error {
 
 func (s *storeAdapter) Get(id string, clear bool) string {
 	key := "captcha:" + id
-	
+
 	if !s.store.Exists(key) {
 		return ""
 	}
-	
+
 	val, err := s.store.Get(key)
 	if err != nil {
 		return ""
 	}
-	
+
 	if clear {
 		s.store.Delete(key)
 	}
-	
+
 	if str, ok := val.(string); ok {
 		return str
 	}
 	return ""
 }
 
+// Verify 字符验证码不区分大小写。
 func (s *storeAdapter) Verify(id, answer string, clear bool) bool {
-	return s.Get(id, clear) == answer
+	expected := s.Get(id, clear)
+	return expected != "" && strings.EqualFold(expected, strings.TrimSpace(answer))
 }
 
 type Captcha struct {
-	driver  base64Captcha.Driver
-	store   base64Captcha.Store
+	kind   string
+	driver base64Captcha.Driver
+	store  *storeAdapter
 }
 
-func NewCaptcha(store Store) *Captcha {
-	driver := base64Captcha.NewDriverDigit(80, 240, 4, 0.8, 120)
-	adapter := &storeAdapter{store: store}
-	
-	return &Captcha{
-		driver: driver,
-		store:  adapter,
+// NewCaptcha 按配置创建验证码，类型为空时使用数字验证码。
+func NewCaptcha(store Store, cfg Config) (*Captcha, error) {
+	length := cfg.Length
+	if length <= 0 {
+		length = 4
+	}
+	c := &Captcha{kind: cfg.Driver, store: &storeAdapter{store: store}}
+	switch cfg.Driver {
+	case "", DriverDigit:
+		c.kind = DriverDigit
+		c.driver = base64Captcha.NewDriverDigit(imageHeight, imageWidth, length, 0.8, 120)
+	case DriverString:
+		c.driver = base64Captcha.NewDriverString(imageHeight, imageWidth, 20, base64Captcha.OptionShowSlimeLine, length,
+			base64Captcha.TxtNumbers+base64Captcha.TxtAlphabet, nil, nil, []string{"wqy-microhei.ttc"})
+	case DriverMath:
+		c.driver = base64Captcha.NewDriverMath(imageHeight, imageWidth, 20, base64Captcha.OptionShowSlimeLine,
+			nil, nil, []string{"wqy-microhei.ttc"})
+	case DriverSlider:
+	default:
+		return nil, fmt.Errorf("不支持的验证码类型: %s", cfg.Driver)
 	}
+	return c, nil
+}
+
+// 图片验证码尺寸
+const (
+	imageWidth  = 240
+	imageHeight = 80
+)
+
+// Type 返回验证码类型
+func (c *Captcha) Type() string {
+	return c.kind
 }
 
-func (c *Captcha) Generate() (id string, b64s string, err error) {
+// Generate 生成验证码并保存答案，答案 5 分钟内有效。
+func (c *Captcha) Generate() (*Challenge, error) {
+	if c.kind == DriverSlider {
+		return c.generateSlider()
+	}
+
 	id, content, answer := c.driver.GenerateIdQuestionAnswer()
 	item, err := c.driver.DrawCaptcha(content)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	
-	err = c.store.Set(id, answer)
-	if err != nil {
-		return "", "", err
+	if err := c.store.Set(id, answer); err != nil {
+		return nil, err
 	}
-	
-	b64s = item.EncodeB64string()
-	return id, b64s, nil
+	return &Challenge{
+		ID:     id,
+		Type:   c.kind,
+		Image:  item.EncodeB64string(),
+		Width:  imageWidth,
+		Height: imageHeight,
+	}, nil
 }
 
+// Verify 校验答案，无论结果如何验证码都只能使用一次。
 func (c *Captcha) Verify(id string, answer string) bool {
+	if id == "" {
+		return false
+	}
+	if c.kind == DriverSlider {
+		return c.verifySlider(id, answer)
+	}
 	return c.store.Verify(id, answer, true)
 }
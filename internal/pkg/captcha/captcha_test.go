@@ -0,0 +1,128 @@
+package captcha
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"bico-admin/internal/core/cache"
+)
+
+// TestDrivers 验证各类型都能生成验证码，答案只能使用一次且字符验证码不区分大小写。
+func TestDrivers(t *testing.T) {
+	store := cache.NewMemoryCache()
+	defer store.Close()
+
+	for _, driver := range Drivers {
+		c, err := NewCaptcha(store, Config{Driver: driver})
+		if err != nil {
+			t.Fatalf("创建 %s 验证码失败: %v", driver, err)
+		}
+		challenge, err := c.Generate()
+		if err != nil {
+			t.Fatalf("生成 %s 验证码失败: %v", driver, err)
+		}
+		if challenge.Type != driver || !strings.HasPrefix(challenge.Image, "data:image/png;base64,") {
+			t.Fatalf("%s 验证码内容不正确: %+v", driver, challenge.Type)
+		}
+		if driver == DriverSlider {
+			continue
+		}
+		answer := c.store.Get(challenge.ID, false)
+		if !c.Verify(challenge.ID, strings.ToLower(answer)) {
+			t.Fatalf("%s 验证码答案应通过", driver)
+		}
+		if c.Verify(challenge.ID, answer) {
+			t.Fatalf("%s 验证码不应重复使用", driver)
+		}
+	}
+
+	if _, err := NewCaptcha(store, Config{Driver: "audio"}); err == nil {
+		t.Fatal("不支持的类型应返回错误")
+	}
+	c, _ := NewCaptcha(store, Config{})
+	if c.Verify("", "") {
+		t.Fatal("空验证码不应通过")
+	}
+}
+
+// TestSliderTolerance 验证滑块位置在容差内通过，超出容差或重复提交失败。
+func TestSliderTolerance(t *testing.T) {
+	store := cache.NewMemoryCache()
+	defer store.Close()
+	c, _ := NewCaptcha(store, Config{Driver: DriverSlider})
+
+	challenge, err := c.Generate()
+	if err != nil {
+		t.Fatalf("生成滑块验证码失败: %v", err)
+	}
+	if challenge.Piece == "" || challenge.Width != sliderWidth {
+		t.Fatalf("滑块验证码缺少拼图块: %+v", challenge.Width)
+	}
+	x, _ := strconv.Atoi(c.store.Get(challenge.ID, false))
+	if !c.Verify(challenge.ID, dragAnswer(float64(x)+2.5, dragTrace(float64(x)+2.5))) {
+		t.Fatal("容差内的位置应通过")
+	}
+	if c.Verify(challenge.ID, dragAnswer(float64(x), dragTrace(float64(x)))) {
+		t.Fatal("滑块验证码不应重复使用")
+	}
+
+	challenge, _ = c.Generate()
+	x, _ = strconv.Atoi(c.store.Get(challenge.ID, false))
+	far := float64(x + sliderTolerance + 1)
+	if c.Verify(challenge.ID, dragAnswer(far, dragTrace(far))) {
+		t.Fatal("超出容差的位置不应通过")
+	}
+}
+
+// TestSliderRequiresTrace 验证只提交横坐标或轨迹不像人工拖动时不通过，即使位置完全正确。
+func TestSliderRequiresTrace(t *testing.T) {
+	store := cache.NewMemoryCache()
+	defer store.Close()
+	c, _ := NewCaptcha(store, Config{Driver: DriverSlider})
+
+	straight := func(x float64) [][3]float64 {
+		trace := dragTrace(x)
+		for i := range trace {
+			trace[i][1] = 0
+		}
+		return trace
+	}
+	instant := func(x float64) [][3]float64 {
+		trace := dragTrace(x)
+		for i := range trace {
+			trace[i][2] = float64(i)
+		}
+		return trace
+	}
+	cases := map[string]func(x float64) string{
+		"仅横坐标":  func(x float64) string { return strconv.Itoa(int(x)) },
+		"无轨迹":   func(x float64) string { return dragAnswer(x, nil) },
+		"轨迹过短":  func(x float64) string { return dragAnswer(x, dragTrace(x)[:3]) },
+		"无纵向抖动": func(x float64) string { return dragAnswer(x, straight(x)) },
+		"耗时过短":  func(x float64) string { return dragAnswer(x, instant(x)) },
+		"终点不符":  func(x float64) string { return dragAnswer(x, dragTrace(x+2)) },
+	}
+	for name, answer := range cases {
+		challenge, _ := c.Generate()
+		x, _ := strconv.Atoi(c.store.Get(challenge.ID, false))
+		if c.Verify(challenge.ID, answer(float64(x))) {
+			t.Fatalf("%s的答案不应通过", name)
+		}
+	}
+}
+
+// dragTrace 模拟从起点拖动到 x 的轨迹，纵向有轻微抖动。
+func dragTrace(x float64) [][3]float64 {
+	trace := make([][3]float64, 0, 21)
+	for i := 0; i <= 20; i++ {
+		trace = append(trace, [3]float64{x * float64(i) / 20, float64(i % 3), float64(i * 40)})
+	}
+	return trace
+}
+
+func dragAnswer(x float64, trace [][3]float64) string {
+	data, _ := json.Marshal(sliderAnswer{X: x, Trace: trace})
+	return string(data)
+}
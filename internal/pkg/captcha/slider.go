@@ -0,0 +1,188 @@
+package captcha
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/mojocn/base64Captcha"
+)
+
+// 滑块验证码尺寸，拼图块为边长 pieceSide 的方块，顶部与右侧各带一个半径 pieceKnob 的凸起。
+const (
+	sliderWidth  = 300
+	sliderHeight = 160
+	pieceSide    = 42
+	pieceKnob    = 8
+	pieceSize    = pieceSide + pieceKnob + 1
+	// sliderTolerance 拖动位置与缺口的允许偏差（像素）
+	sliderTolerance = 3
+	// 拖动轨迹的点数范围与最短耗时（毫秒）
+	sliderMinTrace    = 8
+	sliderMaxTrace    = 1000
+	sliderMinDuration = 200
+)
+
+// sliderAnswer 滑块验证码答案：x 为拼图块左边缘的横坐标，trace 为拖动轨迹，
+// 每个点为 [拼图块横坐标, 指针相对按下位置的纵向偏移, 距按下时的毫秒数]。
+type sliderAnswer struct {
+	X     float64      `json:"x"`
+	Trace [][3]float64 `json:"trace"`
+}
+
+// generateSlider 生成随机背景并挖出拼图缺口，服务端只保存缺口横坐标。
+func (c *Captcha) generateSlider() (*Challenge, error) {
+	background := randomBackground(sliderWidth, sliderHeight)
+	x := pieceSize + 10 + rand.IntN(sliderWidth-2*pieceSize-20)
+	y := 5 + rand.IntN(sliderHeight-pieceSize-10)
+
+	piece := image.NewRGBA(image.Rect(0, 0, pieceSize, pieceSize))
+	for py := 0; py < pieceSize; py++ {
+		for px := 0; px < pieceSize; px++ {
+			if !inPiece(px, py) {
+				continue
+			}
+			bx, by := x+px, y+py
+			origin := background.RGBAAt(bx, by)
+			if onPieceEdge(px, py) {
+				piece.SetRGBA(px, py, color.RGBA{R: 255, G: 255, B: 255, A: 230})
+				background.SetRGBA(bx, by, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+				continue
+			}
+			piece.SetRGBA(px, py, origin)
+			background.SetRGBA(bx, by, color.RGBA{R: origin.R / 3, G: origin.G / 3, B: origin.B / 3, A: 255})
+		}
+	}
+
+	backgroundB64, err := encodePNG(background)
+	if err != nil {
+		return nil, err
+	}
+	pieceB64, err := encodePNG(piece)
+	if err != nil {
+		return nil, err
+	}
+	id := base64Captcha.RandomId()
+	if err := c.store.Set(id, strconv.Itoa(x)); err != nil {
+		return nil, err
+	}
+	return &Challenge{
+		ID:     id,
+		Type:   DriverSlider,
+		Image:  backgroundB64,
+		Piece:  pieceB64,
+		PieceY: y,
+		Width:  sliderWidth,
+		Height: sliderHeight,
+	}, nil
+}
+
+// verifySlider 答案为 sliderAnswer 的 JSON，位置与缺口相差不超过容差且拖动轨迹像人工操作才通过。
+//
+// 缺口在背景图中可见，只校验横坐标时脚本盲猜或识图即可通过，因此要求提交轨迹。
+func (c *Captcha) verifySlider(id, answer string) bool {
+	expected, err := strconv.Atoi(c.store.Get(id, true))
+	if err != nil {
+		return false
+	}
+	var parsed sliderAnswer
+	if err := json.Unmarshal([]byte(answer), &parsed); err != nil {
+		return false
+	}
+	return math.Abs(parsed.X-float64(expected)) <= sliderTolerance && humanTrace(parsed.Trace, parsed.X)
+}
+
+// humanTrace 校验拖动轨迹：从起点出发、终点与答案一致、时间递增且耗时不过短，
+// 纵向有抖动（人手拖动不会是一条水平直线）。
+func humanTrace(trace [][3]float64, x float64) bool {
+	if len(trace) < sliderMinTrace || len(trace) > sliderMaxTrace {
+		return false
+	}
+	first, last := trace[0], trace[len(trace)-1]
+	if first[0] > sliderTolerance || math.Abs(last[0]-x) > 1 || last[2]-first[2] < sliderMinDuration {
+		return false
+	}
+	jitter := false
+	for i := 1; i < len(trace); i++ {
+		if trace[i][2] < trace[i-1][2] {
+			return false
+		}
+		if trace[i][1] != first[1] {
+			jitter = true
+		}
+	}
+	return jitter
+}
+
+// inPiece 判断拼图块图片内的点是否属于拼图形状，方块位于左下，凸起在顶边与右边的中点。
+func inPiece(px, py int) bool {
+	sx, sy := float64(px), float64(py-pieceKnob)
+	if sx >= 0 && sx < pieceSide && sy >= 0 && sy < pieceSide {
+		return true
+	}
+	knob := float64(pieceKnob * pieceKnob)
+	half := float64(pieceSide) / 2
+	if dx, dy := sx-half, sy; dx*dx+dy*dy <= knob {
+		return true
+	}
+	dx, dy := sx-pieceSide, sy-half
+	return dx*dx+dy*dy <= knob
+}
+
+// onPieceEdge 判断点是否位于拼图形状的边缘，用于描边。
+func onPieceEdge(px, py int) bool {
+	return !inPiece(px-1, py) || !inPiece(px+1, py) || !inPiece(px, py-1) || !inPiece(px, py+1)
+}
+
+// randomBackground 生成渐变底色叠加随机色块的背景，每次请求都不同，避免缺口位置被图库比对。
+func randomBackground(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	from, to := randomColor(), randomColor()
+	for x := 0; x < width; x++ {
+		t := float64(x) / float64(width-1)
+		col := color.RGBA{
+			R: uint8(float64(from.R)*(1-t) + float64(to.R)*t),
+			G: uint8(float64(from.G)*(1-t) + float64(to.G)*t),
+			B: uint8(float64(from.B)*(1-t) + float64(to.B)*t),
+			A: 255,
+		}
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, col)
+		}
+	}
+
+	for i := 0; i < 12; i++ {
+		cx, cy := rand.IntN(width), rand.IntN(height)
+		r := 10 + rand.IntN(30)
+		col := randomColor()
+		for y := max(cy-r, 0); y < min(cy+r, height); y++ {
+			for x := max(cx-r, 0); x < min(cx+r, width); x++ {
+				if (x-cx)*(x-cx)+(y-cy)*(y-cy) > r*r {
+					continue
+				}
+				bg := img.RGBAAt(x, y)
+				img.SetRGBA(x, y, color.RGBA{R: (bg.R + col.R) / 2, G: (bg.G + col.G) / 2, B: (bg.B + col.B) / 2, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func randomColor() color.RGBA {
+	return color.RGBA{R: uint8(60 + rand.IntN(180)), G: uint8(60 + rand.IntN(180)), B: uint8(60 + rand.IntN(180)), A: 255}
+}
+
+// encodePNG 编码为与图片验证码相同的 data URI。
+func encodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
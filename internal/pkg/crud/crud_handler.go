@@ -52,6 +52,8 @@ type CRUDHandler[T any, L any, C any, U any] struct {
 	AfterCreate func(tx *gorm.DB, item *T, req *C) error
 	// ReloadAfterCreate 创建后重新加载返回数据（可选）
 	ReloadAfterCreate func(tx *gorm.DB, id uint, item *T) error
+	// AfterCreateCommit 创建事务提交后的逻辑（可选，不得返回业务错误）
+	AfterCreateCommit func(item *T, req *C)
 
 	// BuildUpdateQuery 构建更新时查询（可选，为 nil 则 tx.First）
 	BuildUpdateQuery func(tx *gorm.DB) *gorm.DB
@@ -80,10 +82,22 @@ type CRUDHandler[T any, L any, C any, U any] struct {
 	DeleteBatchInTx func(tx *gorm.DB, ids []uint) error
 	// AfterDeleteBatch 批量删除成功后的事务内逻辑（可选）
 	AfterDeleteBatch func(tx *gorm.DB, ids []uint) error
+	// AfterDeleteCommit 删除事务提交后的逻辑（可选，单条与批量删除共用，items 为删除前的记录，不得返回业务错误）
+	AfterDeleteCommit func(items []T)
 }
 
 // List 获取列表
 func (h *CRUDHandler[T, L, C, U]) List(c *gin.Context) {
+	// ?view=<id> 先合并保存的视图参数，再走统一绑定。
+	if err := applyListView(c); err != nil {
+		if errors.Is(err, ErrListViewNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.Error(c, err.Error())
+		return
+	}
+
 	var req L
 	if err := h.BindQuery(c, &req); err != nil {
 		return
@@ -158,7 +172,7 @@ func (h *CRUDHandler[T, L, C, U]) Create(c *gin.Context) {
 		successMsg = "创建成功"
 	}
 
-	h.ExecTx(c, h.DB, func(tx *gorm.DB) error {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
 		// 创建前 hook 适合做跨字段校验或补充审计字段。
 		if h.BeforeCreate != nil {
 			if err := h.BeforeCreate(tx, item, &req); err != nil {
@@ -185,7 +199,16 @@ func (h *CRUDHandler[T, L, C, U]) Create(c *gin.Context) {
 			return h.ReloadAfterCreate(tx, getID(item), item)
 		}
 		return nil
-	}, successMsg, item)
+	}); err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	if h.AfterCreateCommit != nil {
+		// 进程内状态（如权限树）只在事务提交后变更，回滚时保持与数据库一致。
+		h.AfterCreateCommit(item, &req)
+	}
+
+	h.SuccessWithMessage(c, successMsg, item)
 }
 
 // Update 更新记录
@@ -230,6 +253,7 @@ func (h *CRUDHandler[T, L, C, U]) Delete(c *gin.Context) {
 		successMsg = "删除成功"
 	}
 
+	var deleted []T
 	if err := h.DB.Transaction(func(tx *gorm.DB) error {
 		// 删除前 hook 适合做业务保护，例如禁止删除内置记录。
 		if h.BeforeDelete != nil {
@@ -243,6 +267,11 @@ func (h *CRUDHandler[T, L, C, U]) Delete(c *gin.Context) {
 				return err
 			}
 		}
+		if h.AfterDeleteCommit != nil {
+			if err := tx.Where("id = ?", id).Find(&deleted).Error; err != nil {
+				return err
+			}
+		}
 		var item T
 		result := tx.Delete(&item, id)
 		if result.Error != nil {
@@ -263,6 +292,9 @@ func (h *CRUDHandler[T, L, C, U]) Delete(c *gin.Context) {
 		h.handleRecordError(c, err)
 		return
 	}
+	if h.AfterDeleteCommit != nil {
+		h.AfterDeleteCommit(deleted)
+	}
 
 	h.SuccessWithMessage(c, successMsg, nil)
 }
@@ -289,6 +321,7 @@ func (h *CRUDHandler[T, L, C, U]) DeleteBatch(c *gin.Context) {
 		successMsg = "删除成功"
 	}
 
+	var deleted []T
 	if err := h.DB.Transaction(func(tx *gorm.DB) error {
 		// 批量删除前先跑整体验证，避免逐条 I/O。
 		if h.BeforeDeleteBatch != nil {
@@ -302,6 +335,11 @@ func (h *CRUDHandler[T, L, C, U]) DeleteBatch(c *gin.Context) {
 				return err
 			}
 		}
+		if h.AfterDeleteCommit != nil {
+			if err := tx.Where("id IN ?", ids).Find(&deleted).Error; err != nil {
+				return err
+			}
+		}
 		var item T
 		result := tx.Where("id IN ?", ids).Delete(&item)
 		if result.Error != nil {
@@ -321,6 +359,9 @@ func (h *CRUDHandler[T, L, C, U]) DeleteBatch(c *gin.Context) {
 		h.handleRecordError(c, err)
 		return
 	}
+	if h.AfterDeleteCommit != nil {
+		h.AfterDeleteCommit(deleted)
+	}
 
 	h.SuccessWithMessage(c, successMsg, nil)
 }
@@ -2,8 +2,10 @@ package crud
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -165,3 +167,88 @@ func TestCRUDDeleteBatchRequiresAllIDs(t *testing.T) {
 		t.Fatalf("批量删除部分失败时应回滚，剩余记录数: %d", count)
 	}
 }
+
+// TestCRUDListAppliesView 验证 ?view=<id> 合并保存的视图参数，请求中显式传入的参数优先。
+func TestCRUDListAppliesView(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, db := newTestCRUDHandler(t)
+
+	items := []testCRUDModel{{Name: "a", Enabled: true}, {Name: "b", Enabled: false}, {Name: "c", Enabled: false}}
+	if err := db.Create(&items).Error; err != nil {
+		t.Fatalf("创建测试数据失败: %v", err)
+	}
+
+	SetListViewResolver(func(c *gin.Context, module string, id uint) (*ListView, error) {
+		if module != "test" || id != 7 {
+			return nil, ErrListViewNotFound
+		}
+		return &ListView{Filters: map[string]string{"enabled": "false"}, SortField: "id", SortOrder: "ascend", PageSize: 1}, nil
+	})
+	defer SetListViewResolver(nil)
+
+	list := func(path string) map[string]interface{} {
+		w := performRequest(http.MethodGet, path, "", func(c *gin.Context) {
+			c.Set(ModuleContextKey, "test")
+			h.List(c)
+		})
+		return decodeResponse(t, w)
+	}
+
+	data := list("/test?view=7")["data"].(map[string]interface{})
+	rows := data["list"].([]interface{})
+	if data["total"].(float64) != 2 || len(rows) != 1 || rows[0].(map[string]interface{})["name"] != "b" {
+		t.Fatalf("视图筛选、排序或分页未生效: %v", data)
+	}
+
+	data = list("/test?view=7&enabled=true")["data"].(map[string]interface{})
+	if data["total"].(float64) != 1 {
+		t.Fatalf("请求参数应覆盖视图筛选: %v", data)
+	}
+
+	if resp := list("/test?view=8"); resp["code"].(float64) != 404 {
+		t.Fatalf("不可用的视图应返回业务 404: %v", resp)
+	}
+}
+
+// TestCRUDCommitHooks 验证提交后 hook 只在事务提交后执行，删除 hook 能拿到删除前的记录。
+func TestCRUDCommitHooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, db := newTestCRUDHandler(t)
+
+	var created []string
+	h.AfterCreateCommit = func(item *testCRUDModel, req *testCreateReq) {
+		created = append(created, item.Name)
+	}
+	h.AfterCreate = func(tx *gorm.DB, item *testCRUDModel, req *testCreateReq) error {
+		if item.Name == "bad" {
+			return errors.New("回滚")
+		}
+		return nil
+	}
+	performRequest(http.MethodPost, "/test", `{"name":"bad"}`, h.Create)
+	performRequest(http.MethodPost, "/test", `{"name":"a"}`, h.Create)
+	performRequest(http.MethodPost, "/test", `{"name":"b"}`, h.Create)
+	if len(created) != 2 || created[0] != "a" {
+		t.Fatalf("回滚的创建不应触发提交后 hook: %v", created)
+	}
+
+	var deleted []string
+	h.AfterDeleteCommit = func(items []testCRUDModel) {
+		for _, item := range items {
+			deleted = append(deleted, item.Name)
+		}
+	}
+	performRequest(http.MethodDelete, "/test/batch", `{"ids":[1,999]}`, h.DeleteBatch)
+	if len(deleted) != 0 {
+		t.Fatalf("回滚的删除不应触发提交后 hook: %v", deleted)
+	}
+	var first testCRUDModel
+	db.Where("name = ?", "a").First(&first)
+	performRequest(http.MethodDelete, "/test/1", "", func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(int(first.ID))}}
+		h.Delete(c)
+	})
+	if len(deleted) != 1 || deleted[0] != "a" {
+		t.Fatalf("删除提交后应传入删除前的记录: %v", deleted)
+	}
+}
@@ -3,6 +3,9 @@ package crud
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -20,10 +23,19 @@ type Route struct {
 	Method     string // GET, POST, PUT, DELETE
 	Path       string // 路由路径，如 "", "/:id", "/all"
 	Handler    string // Handler 方法名
-	Permission string // 权限 key，为空则不校验
+	Permission string // 权限 key，为空则不校验；可用 {param} 引用路由参数
 	Public     bool   // 是否公开（不需要登录）
 }
 
+// RouteInfo 已注册路由的完整路径与权限，供权限排查按请求路径反查
+type RouteInfo struct {
+	Module     string `json:"module"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`       // 完整路由路径，如 "/admin-api/admin-users/:id"
+	Permission string `json:"permission"` // 匹配请求时已替换路由参数
+	Public     bool   `json:"public"`
+}
+
 // ModuleConfig 模块配置（在 Handler 中定义）
 type ModuleConfig struct {
 	// 基础信息
@@ -61,6 +73,14 @@ type SwaggerConfig struct {
 var (
 	allPermissions []Permission
 	permissionsMu  sync.RWMutex
+
+	permissionParamPattern = regexp.MustCompile(`\{[A-Za-z_][A-Za-z0-9_]*\}`)
+
+	registeredModules   = make(map[string]struct{})
+	registeredModulesMu sync.RWMutex
+
+	registeredRoutes   = make(map[string]RouteInfo)
+	registeredRoutesMu sync.RWMutex
 )
 
 // AddPermissions 添加权限到全局权限树
@@ -97,6 +117,30 @@ func addToParent(tree *[]Permission, parentKey string, children []Permission) bo
 	return false
 }
 
+// RemovePermissions 从全局权限树中移除指定 key 及其子节点
+func RemovePermissions(keys ...string) {
+	permissionsMu.Lock()
+	defer permissionsMu.Unlock()
+
+	removed := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		removed[key] = struct{}{}
+	}
+	allPermissions = removeFromTree(allPermissions, removed)
+}
+
+func removeFromTree(tree []Permission, removed map[string]struct{}) []Permission {
+	result := make([]Permission, 0, len(tree))
+	for _, perm := range tree {
+		if _, ok := removed[perm.Key]; ok {
+			continue
+		}
+		perm.Children = removeFromTree(perm.Children, removed)
+		result = append(result, perm)
+	}
+	return result
+}
+
 // SetBasePermissions 设置基础权限树
 func SetBasePermissions(perms []Permission) {
 	permissionsMu.Lock()
@@ -176,6 +220,10 @@ func NewModuleRouterWithConfig(config RouterConfig) *ModuleRouter {
 func (r *ModuleRouter) RegisterModule(engine *gin.RouterGroup, module Module) {
 	config := module.ModuleConfig()
 
+	registeredModulesMu.Lock()
+	registeredModules[config.Name] = struct{}{}
+	registeredModulesMu.Unlock()
+
 	// 注册权限
 	if len(config.Permissions) > 0 {
 		AddPermissions(config.ParentPermission, config.Permissions)
@@ -184,8 +232,8 @@ func (r *ModuleRouter) RegisterModule(engine *gin.RouterGroup, module Module) {
 	// 获取 handler 的反射值
 	handlerVal := reflect.ValueOf(module)
 
-	// 创建路由分组
-	group := engine.Group(config.Group)
+	// 创建路由分组，并记录模块名供列表视图按模块读取
+	group := engine.Group(config.Group, withModuleName(config.Name))
 
 	// 是否需要认证
 	hasPublic := false
@@ -204,7 +252,7 @@ func (r *ModuleRouter) RegisterModule(engine *gin.RouterGroup, module Module) {
 			if !route.Public {
 				continue
 			}
-			r.registerRoute(group, handlerVal, route)
+			r.registerRoute(group, handlerVal, config.Name, route)
 		}
 	}
 
@@ -224,12 +272,12 @@ func (r *ModuleRouter) RegisterModule(engine *gin.RouterGroup, module Module) {
 			if route.Public {
 				continue
 			}
-			r.registerRoute(authGroup, handlerVal, route)
+			r.registerRoute(authGroup, handlerVal, config.Name, route)
 		}
 	}
 }
 
-func (r *ModuleRouter) registerRoute(group *gin.RouterGroup, handlerVal reflect.Value, route Route) {
+func (r *ModuleRouter) registerRoute(group *gin.RouterGroup, handlerVal reflect.Value, module string, route Route) {
 	// 获取 handler 方法
 	method := handlerVal.MethodByName(route.Handler)
 	if !method.IsValid() {
@@ -244,7 +292,7 @@ func (r *ModuleRouter) registerRoute(group *gin.RouterGroup, handlerVal reflect.
 	// 构建中间件链
 	handlers := make([]gin.HandlerFunc, 0)
 	if route.Permission != "" && r.config.PermMiddleware != nil {
-		handlers = append(handlers, r.config.PermMiddleware.RequirePermission(route.Permission))
+		handlers = append(handlers, requirePermission(r.config.PermMiddleware, route.Permission))
 	}
 	handlers = append(handlers, handlerFunc)
 
@@ -263,6 +311,111 @@ func (r *ModuleRouter) registerRoute(group *gin.RouterGroup, handlerVal reflect.
 	default:
 		panic(fmt.Sprintf("unsupported HTTP method: %s", route.Method))
 	}
+
+	fullPath := strings.TrimSuffix(group.BasePath(), "/") + route.Path
+	if fullPath == "" {
+		fullPath = "/"
+	}
+	registeredRoutesMu.Lock()
+	registeredRoutes[route.Method+" "+fullPath] = RouteInfo{
+		Module:     module,
+		Method:     route.Method,
+		Path:       fullPath,
+		Permission: route.Permission,
+		Public:     route.Public,
+	}
+	registeredRoutesMu.Unlock()
+}
+
+// MatchRoute 按请求方法与路径查找通过 ModuleRouter 注册的路由。
+// 与 gin 的匹配优先级一致，同一位置静态段优先于 :param，:param 优先于 *param。
+func MatchRoute(method, requestPath string) (RouteInfo, bool) {
+	method = strings.ToUpper(method)
+	segments := splitPath(requestPath)
+
+	registeredRoutesMu.RLock()
+	defer registeredRoutesMu.RUnlock()
+
+	var (
+		best      RouteInfo
+		bestRank  []int
+		bestParam map[string]string
+	)
+	for _, info := range registeredRoutes {
+		if info.Method != method {
+			continue
+		}
+		params, rank, ok := matchSegments(splitPath(info.Path), segments)
+		if !ok || (bestRank != nil && slices.Compare(rank, bestRank) >= 0) {
+			continue
+		}
+		best, bestRank, bestParam = info, rank, params
+	}
+	if bestRank == nil {
+		return RouteInfo{}, false
+	}
+	best.Permission = expandPermission(best.Permission, func(name string) string { return bestParam[name] })
+	return best, true
+}
+
+// matchSegments 逐段匹配路由，返回路由参数与每段的匹配等级（0 静态、1 :param、2 *param）。
+func matchSegments(pattern, segments []string) (map[string]string, []int, bool) {
+	params := make(map[string]string)
+	rank := make([]int, 0, len(pattern))
+	for i, seg := range pattern {
+		switch {
+		case strings.HasPrefix(seg, "*"):
+			params[seg[1:]] = "/" + strings.Join(segments[min(i, len(segments)):], "/")
+			return params, append(rank, 2), true
+		case i >= len(segments):
+			return nil, nil, false
+		case strings.HasPrefix(seg, ":"):
+			params[seg[1:]] = segments[i]
+			rank = append(rank, 1)
+		case seg == segments[i]:
+			rank = append(rank, 0)
+		default:
+			return nil, nil, false
+		}
+	}
+	return params, rank, len(pattern) == len(segments)
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// IsModuleRegistered 判断模块是否已通过 ModuleRouter 注册。
+func IsModuleRegistered(name string) bool {
+	registeredModulesMu.RLock()
+	defer registeredModulesMu.RUnlock()
+	_, ok := registeredModules[name]
+	return ok
+}
+
+// requirePermission 生成权限校验中间件，权限 key 中的 {param} 在请求时替换为路由参数。
+func requirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	if !strings.Contains(permission, "{") {
+		return checker.RequirePermission(permission)
+	}
+	return func(c *gin.Context) {
+		checker.RequirePermission(ExpandPermission(permission, c))(c)
+	}
+}
+
+// ExpandPermission 将权限模板中的 {param} 替换为路由参数，如 dynamic:{table}:list -> dynamic:feedback:list。
+func ExpandPermission(permission string, c *gin.Context) string {
+	return expandPermission(permission, c.Param)
+}
+
+func expandPermission(permission string, param func(name string) string) string {
+	return permissionParamPattern.ReplaceAllStringFunc(permission, func(segment string) string {
+		return param(strings.Trim(segment, "{}"))
+	})
 }
 
 // clonePermissions 深拷贝权限树，避免外部修改全局状态。
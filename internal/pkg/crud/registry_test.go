@@ -0,0 +1,54 @@
+package crud
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testRouteModule struct{}
+
+func (testRouteModule) Handle(*gin.Context) {}
+
+func (testRouteModule) ModuleConfig() ModuleConfig {
+	return ModuleConfig{
+		Name:  "route_match",
+		Group: "/route-match",
+		Routes: []Route{
+			{Method: "GET", Path: "", Handler: "Handle", Permission: "demo:item:list"},
+			{Method: "GET", Path: "/all", Handler: "Handle"},
+			{Method: "GET", Path: "/:id", Handler: "Handle", Permission: "demo:item:get"},
+			{Method: "DELETE", Path: "/:table/rows/:id", Handler: "Handle", Permission: "dynamic:{table}:delete"},
+			{Method: "GET", Path: "/files/*path", Handler: "Handle", Public: true},
+		},
+	}
+}
+
+// TestMatchRoute 验证按请求路径反查路由时的匹配优先级与权限参数替换。
+func TestMatchRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewModuleRouter(nil, nil, nil).RegisterModule(engine.Group("/api"), testRouteModule{})
+
+	cases := []struct {
+		method, path, pattern, permission string
+	}{
+		{"GET", "/api/route-match", "/api/route-match", "demo:item:list"},
+		{"get", "/api/route-match/all/", "/api/route-match/all", ""},
+		{"GET", "/api/route-match/5", "/api/route-match/:id", "demo:item:get"},
+		{"DELETE", "/api/route-match/feedback/rows/3", "/api/route-match/:table/rows/:id", "dynamic:feedback:delete"},
+		{"GET", "/api/route-match/files/a/b.png", "/api/route-match/files/*path", ""},
+	}
+	for _, tc := range cases {
+		info, ok := MatchRoute(tc.method, tc.path)
+		if !ok || info.Path != tc.pattern || info.Permission != tc.permission || info.Module != "route_match" {
+			t.Fatalf("MatchRoute(%s %s)=%+v, %v", tc.method, tc.path, info, ok)
+		}
+	}
+	if _, ok := MatchRoute("POST", "/api/route-match"); ok {
+		t.Fatal("方法不一致时不应匹配")
+	}
+	if _, ok := MatchRoute("GET", "/api/route-match/5/extra"); ok {
+		t.Fatal("段数不一致时不应匹配")
+	}
+}
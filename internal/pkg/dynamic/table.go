@@ -0,0 +1,258 @@
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"bico-admin/internal/core/model"
+
+	"gorm.io/gorm"
+)
+
+// 字段类型
+const (
+	FieldString   = "string"   // 单行文本，varchar
+	FieldText     = "text"     // 多行文本
+	FieldInt      = "int"      // 整数
+	FieldFloat    = "float"    // 小数
+	FieldBool     = "bool"     // 布尔
+	FieldDate     = "date"     // 日期，格式 2006-01-02
+	FieldDatetime = "datetime" // 日期时间，格式 2006-01-02 15:04:05
+	FieldSelect   = "select"   // 下拉选项，值必须在 Options 中
+)
+
+// 筛选方式
+const (
+	FilterEq    = "eq"    // 等值
+	FilterLike  = "like"  // 模糊匹配
+	FilterRange = "range" // 区间，查询参数为 {name}_start / {name}_end
+)
+
+// TablePrefix 动态表物理表名前缀，避免与内置表冲突。
+const TablePrefix = "dyn_"
+
+const defaultStringSize = 255
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,47}$`)
+
+	// reservedColumns 由系统维护的列，不允许在字段定义中重复声明。
+	reservedColumns = map[string]struct{}{"id": {}, "created_at": {}, "updated_at": {}}
+
+	fieldTypes = map[string]struct{}{
+		FieldString: {}, FieldText: {}, FieldInt: {}, FieldFloat: {},
+		FieldBool: {}, FieldDate: {}, FieldDatetime: {}, FieldSelect: {},
+	}
+	filterTypes = map[string]struct{}{"": {}, FilterEq: {}, FilterLike: {}, FilterRange: {}}
+)
+
+// Field 动态表字段定义
+type Field struct {
+	Name       string   `json:"name"`                 // 列名，小写字母开头的 snake_case
+	Label      string   `json:"label"`                // 显示名称，同时作为 Excel 表头
+	Type       string   `json:"type"`                 // 字段类型
+	Required   bool     `json:"required"`             // 是否必填
+	MaxLength  int      `json:"max_length,omitempty"` // 文本最大长度，string 默认 255
+	Min        *float64 `json:"min,omitempty"`        // 数值最小值
+	Max        *float64 `json:"max,omitempty"`        // 数值最大值
+	Pattern    string   `json:"pattern,omitempty"`    // 文本正则校验
+	Options    []string `json:"options,omitempty"`    // select 可选值
+	ListColumn bool     `json:"list_column"`          // 是否显示在列表中
+	Filter     string   `json:"filter,omitempty"`     // 列表筛选方式，为空表示不可筛选
+	Sortable   bool     `json:"sortable"`             // 是否允许排序
+}
+
+// Table 动态表定义
+type Table struct {
+	Name   string
+	Fields []Field
+}
+
+// ValidateName 校验动态表或字段标识。
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("标识 %q 不合法，只能包含小写字母、数字和下划线，且以字母开头", name)
+	}
+	return nil
+}
+
+// TableName 返回物理表名。
+func (t Table) TableName() string {
+	return TablePrefix + t.Name
+}
+
+// Validate 校验表结构定义。
+func (t Table) Validate() error {
+	if err := ValidateName(t.Name); err != nil {
+		return err
+	}
+	if len(t.Fields) == 0 {
+		return errors.New("至少需要定义一个字段")
+	}
+
+	seen := make(map[string]struct{}, len(t.Fields))
+	for _, field := range t.Fields {
+		if err := ValidateName(field.Name); err != nil {
+			return err
+		}
+		if _, ok := reservedColumns[field.Name]; ok {
+			return fmt.Errorf("字段 %s 为系统字段，不能重复定义", field.Name)
+		}
+		if _, ok := seen[field.Name]; ok {
+			return fmt.Errorf("字段 %s 重复", field.Name)
+		}
+		seen[field.Name] = struct{}{}
+
+		if strings.TrimSpace(field.Label) == "" {
+			return fmt.Errorf("字段 %s 缺少显示名称", field.Name)
+		}
+		if _, ok := fieldTypes[field.Type]; !ok {
+			return fmt.Errorf("字段 %s 的类型 %q 不支持", field.Name, field.Type)
+		}
+		if _, ok := filterTypes[field.Filter]; !ok {
+			return fmt.Errorf("字段 %s 的筛选方式 %q 不支持", field.Name, field.Filter)
+		}
+		if field.Filter == FilterLike && !isTextType(field.Type) {
+			return fmt.Errorf("字段 %s 不是文本类型，不能使用模糊筛选", field.Name)
+		}
+		if field.Filter == FilterRange && !isRangeType(field.Type) {
+			return fmt.Errorf("字段 %s 不支持区间筛选", field.Name)
+		}
+		if field.Type == FieldSelect && len(field.Options) == 0 {
+			return fmt.Errorf("字段 %s 为下拉选项，必须配置 options", field.Name)
+		}
+		if field.MaxLength < 0 {
+			return fmt.Errorf("字段 %s 的最大长度不能为负数", field.Name)
+		}
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return fmt.Errorf("字段 %s 的正则不合法: %w", field.Name, err)
+			}
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return fmt.Errorf("字段 %s 的最小值不能大于最大值", field.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateChange 校验结构变更，已有字段不允许修改类型。
+//
+// 说明：删除字段只会从定义中隐藏，物理列和数据保留，避免误操作丢数据。
+func (t Table) ValidateChange(previous Table) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	types := make(map[string]string, len(previous.Fields))
+	for _, field := range previous.Fields {
+		types[field.Name] = field.Type
+	}
+	for _, field := range t.Fields {
+		if old, ok := types[field.Name]; ok && columnKind(old) != columnKind(field.Type) {
+			return fmt.Errorf("字段 %s 已存在，不能从 %s 修改为 %s", field.Name, old, field.Type)
+		}
+	}
+	return nil
+}
+
+// Field 按列名查找字段。
+func (t Table) Field(name string) (Field, bool) {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// StructType 根据字段定义构建 GORM 模型类型。
+//
+// 说明：业务字段统一使用指针类型，非必填字段可以保存 NULL；时间字段复用 JSONTime 保持与内置模型一致的输出格式。
+func (t Table) StructType() reflect.Type {
+	fields := []reflect.StructField{
+		{Name: "ID", Type: reflect.TypeOf(uint(0)), Tag: `gorm:"primarykey" json:"id"`},
+		{Name: "CreatedAt", Type: reflect.TypeOf(model.JSONTime{}), Tag: `json:"created_at"`},
+		{Name: "UpdatedAt", Type: reflect.TypeOf(model.JSONTime{}), Tag: `json:"updated_at"`},
+	}
+	for i, field := range t.Fields {
+		gormTag := "column:" + field.Name
+		switch field.Type {
+		case FieldString, FieldSelect:
+			gormTag += fmt.Sprintf(";size:%d", stringSize(field))
+		case FieldText:
+			gormTag += ";type:text"
+		case FieldDate:
+			gormTag += ";size:10"
+		}
+		if field.Filter != "" && field.Type != FieldText {
+			gormTag += ";index"
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: reflect.PointerTo(goType(field.Type)),
+			Tag:  reflect.StructTag(fmt.Sprintf(`gorm:"%s" json:"%s"`, gormTag, field.Name)),
+		})
+	}
+	return reflect.StructOf(fields)
+}
+
+// NewRecord 创建单条记录实例，返回结构体指针。
+func (t Table) NewRecord() interface{} {
+	return reflect.New(t.StructType()).Interface()
+}
+
+// NewRecords 创建记录切片，返回切片指针。
+func (t Table) NewRecords() interface{} {
+	return reflect.New(reflect.SliceOf(t.StructType())).Interface()
+}
+
+// Migrate 创建或更新物理表结构，只新增列不删除列。
+func (t Table) Migrate(db *gorm.DB) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return db.Table(t.TableName()).AutoMigrate(t.NewRecord())
+}
+
+// goType 返回字段类型对应的 Go 类型。
+func goType(fieldType string) reflect.Type {
+	switch fieldType {
+	case FieldInt:
+		return reflect.TypeOf(int64(0))
+	case FieldFloat:
+		return reflect.TypeOf(float64(0))
+	case FieldBool:
+		return reflect.TypeOf(false)
+	case FieldDatetime:
+		return reflect.TypeOf(model.JSONTime{})
+	default:
+		return reflect.TypeOf("")
+	}
+}
+
+// columnKind 返回字段的物理列类别，string 与 select 共用同一种列。
+func columnKind(fieldType string) string {
+	if fieldType == FieldSelect {
+		return FieldString
+	}
+	return fieldType
+}
+
+// stringSize 返回 varchar 长度。
+func stringSize(field Field) int {
+	if field.MaxLength > 0 {
+		return field.MaxLength
+	}
+	return defaultStringSize
+}
+
+func isTextType(fieldType string) bool {
+	return fieldType == FieldString || fieldType == FieldText || fieldType == FieldSelect
+}
+
+func isRangeType(fieldType string) bool {
+	return fieldType == FieldInt || fieldType == FieldFloat || fieldType == FieldDate || fieldType == FieldDatetime
+}
@@ -0,0 +1,207 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestTable 创建覆盖常用字段类型的测试表定义。
+func newTestTable() Table {
+	minScore, maxScore := 0.0, 100.0
+	return Table{
+		Name: "feedback",
+		Fields: []Field{
+			{Name: "title", Label: "标题", Type: FieldString, Required: true, MaxLength: 20, Filter: FilterLike},
+			{Name: "category", Label: "分类", Type: FieldSelect, Options: []string{"建议", "缺陷"}, Filter: FilterEq},
+			{Name: "score", Label: "评分", Type: FieldInt, Min: &minScore, Max: &maxScore, Filter: FilterRange, Sortable: true},
+			{Name: "resolved", Label: "已处理", Type: FieldBool},
+			{Name: "visit_at", Label: "回访时间", Type: FieldDatetime},
+		},
+	}
+}
+
+// newTestDB 创建已迁移测试表的内存数据库。
+func newTestDB(t *testing.T, table Table) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := table.Migrate(db); err != nil {
+		t.Fatalf("迁移动态表失败: %v", err)
+	}
+	return db
+}
+
+// TestValidate 验证表结构定义的校验规则。
+func TestValidate(t *testing.T) {
+	cases := map[string]Table{
+		"表标识不合法":   {Name: "Bad-Name", Fields: []Field{{Name: "a", Label: "A", Type: FieldString}}},
+		"系统字段重复":   {Name: "t", Fields: []Field{{Name: "id", Label: "ID", Type: FieldInt}}},
+		"类型不支持":    {Name: "t", Fields: []Field{{Name: "a", Label: "A", Type: "json"}}},
+		"下拉缺少选项":   {Name: "t", Fields: []Field{{Name: "a", Label: "A", Type: FieldSelect}}},
+		"数值不能模糊筛选": {Name: "t", Fields: []Field{{Name: "a", Label: "A", Type: FieldInt, Filter: FilterLike}}},
+	}
+	for name, table := range cases {
+		if err := table.Validate(); err == nil {
+			t.Fatalf("%s: 期望校验失败", name)
+		}
+	}
+	if err := newTestTable().Validate(); err != nil {
+		t.Fatalf("合法定义校验失败: %v", err)
+	}
+}
+
+// TestValidateChange 验证已有字段不能修改列类型，select 与 string 可互转。
+func TestValidateChange(t *testing.T) {
+	previous := newTestTable()
+
+	next := newTestTable()
+	next.Fields[2].Type = FieldString
+	if err := next.ValidateChange(previous); err == nil {
+		t.Fatalf("期望禁止修改字段类型")
+	}
+
+	next = newTestTable()
+	next.Fields[1].Type = FieldString
+	next.Fields[1].Filter = FilterLike
+	next.Fields = append(next.Fields, Field{Name: "remark", Label: "备注", Type: FieldText})
+	if err := next.ValidateChange(previous); err != nil {
+		t.Fatalf("期望允许新增字段和 select 改为 string: %v", err)
+	}
+}
+
+// TestNormalize 验证请求数据的类型转换与校验。
+func TestNormalize(t *testing.T) {
+	table := newTestTable()
+
+	values, err := table.Normalize(map[string]interface{}{
+		"title":    "页面加载慢",
+		"category": "缺陷",
+		"score":    float64(80),
+		"resolved": "是",
+		"visit_at": "2026-01-02 15:04:05",
+		"id":       float64(99),
+	}, false)
+	if err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	if values["score"] != int64(80) || values["resolved"] != true {
+		t.Fatalf("数值或布尔转换不正确: %#v", values)
+	}
+	if _, ok := values["id"]; ok {
+		t.Fatalf("系统字段不应写入: %#v", values)
+	}
+
+	invalid := []map[string]interface{}{
+		{"category": "缺陷"},
+		{"title": strings.Repeat("长", 21)},
+		{"title": "a", "category": "其他"},
+		{"title": "a", "score": float64(101)},
+		{"title": "a", "score": 1.5},
+		{"title": "a", "visit_at": "2026/01/02"},
+	}
+	for _, input := range invalid {
+		if _, err := table.Normalize(input, false); err == nil {
+			t.Fatalf("期望校验失败: %#v", input)
+		}
+	}
+
+	// 部分更新只校验出现的字段。
+	if _, err := table.Normalize(map[string]interface{}{"score": "60"}, true); err != nil {
+		t.Fatalf("部分更新校验失败: %v", err)
+	}
+}
+
+// TestMigrateAndQuery 验证物理表迁移、写入、筛选、排序和导出行。
+func TestMigrateAndQuery(t *testing.T) {
+	table := newTestTable()
+	db := newTestDB(t, table)
+
+	inputs := []map[string]interface{}{
+		{"title": "页面加载慢", "category": "缺陷", "score": float64(30), "visit_at": "2026-01-02 15:04:05"},
+		{"title": "增加导出", "category": "建议", "score": float64(90), "resolved": true},
+		{"title": "登录慢", "category": "缺陷", "score": float64(60)},
+	}
+	for _, input := range inputs {
+		values, err := table.Normalize(input, false)
+		if err != nil {
+			t.Fatalf("转换失败: %v", err)
+		}
+		record := table.NewRecord()
+		table.Fill(record, values)
+		if err := db.Table(table.TableName()).Create(record).Error; err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+	}
+
+	query, err := table.ApplyFilters(db.Table(table.TableName()), url.Values{
+		"title":       {"慢"},
+		"category":    {"缺陷"},
+		"score_start": {"50"},
+	})
+	if err != nil {
+		t.Fatalf("构建筛选失败: %v", err)
+	}
+	records := table.NewRecords()
+	if err := query.Order(table.Order("score", "ascend")).Find(records).Error; err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	list := reflect.ValueOf(records).Elem()
+	if list.Len() != 1 {
+		t.Fatalf("期望筛选出 1 条记录，实际 %d", list.Len())
+	}
+
+	data, err := json.Marshal(list.Index(0).Interface())
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	var item map[string]interface{}
+	_ = json.Unmarshal(data, &item)
+	if item["title"] != "登录慢" || item["resolved"] != nil || item["created_at"] == nil {
+		t.Fatalf("记录内容不正确: %s", data)
+	}
+
+	all := table.NewRecords()
+	if err := db.Table(table.TableName()).Order(table.Order("created_at", "ascend")).Find(all).Error; err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	row := table.Row(reflect.ValueOf(all).Elem().Index(0))
+	if row[0] != "页面加载慢" || row[2] != int64(30) || row[3] != "" || row[4] != "2026-01-02 15:04:05" {
+		t.Fatalf("导出行不正确: %#v", row)
+	}
+
+	// 未标记可排序的字段回退到创建时间倒序，避免任意列排序。
+	if order := table.Order("title", "ascend"); order.Column.Name != "created_at" || !order.Desc {
+		t.Fatalf("非法排序字段未回退: %#v", order)
+	}
+}
+
+// TestMigrateAddColumn 验证新增字段后迁移只补齐列，原有数据保留。
+func TestMigrateAddColumn(t *testing.T) {
+	table := newTestTable()
+	db := newTestDB(t, table)
+	record := table.NewRecord()
+	table.Fill(record, map[string]interface{}{"title": "旧数据"})
+	if err := db.Table(table.TableName()).Create(record).Error; err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	table.Fields = append(table.Fields, Field{Name: "remark", Label: "备注", Type: FieldText})
+	if err := table.Migrate(db); err != nil {
+		t.Fatalf("新增字段迁移失败: %v", err)
+	}
+	if !db.Migrator().HasColumn(table.TableName(), "remark") {
+		t.Fatalf("期望新增 remark 列")
+	}
+	var count int64
+	if err := db.Table(table.TableName()).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("原有数据丢失: count=%d err=%v", count, err)
+	}
+}
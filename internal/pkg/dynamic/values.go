@@ -0,0 +1,310 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"bico-admin/internal/core/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05"
+
+	// systemFieldCount StructType 中系统字段（id/created_at/updated_at）的数量。
+	systemFieldCount = 3
+)
+
+// Normalize 校验并转换请求数据，返回以列名为 key 的数据。
+//
+// 说明：
+// - 未定义的字段（包括 id、created_at 等系统字段）直接忽略
+// - partial 为 true 时用于更新，只校验请求中出现的字段
+// - 数值、布尔、时间同时接受 JSON 原生类型和字符串，便于复用到 Excel 导入
+func (t Table) Normalize(input map[string]interface{}, partial bool) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(t.Fields))
+	for _, field := range t.Fields {
+		raw, present := input[field.Name]
+		if !present {
+			if !partial && field.Required {
+				return nil, fmt.Errorf("%s不能为空", field.Label)
+			}
+			continue
+		}
+		if isEmpty(raw) {
+			if field.Required {
+				return nil, fmt.Errorf("%s不能为空", field.Label)
+			}
+			values[field.Name] = nil
+			continue
+		}
+
+		value, err := normalizeValue(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+// Fill 将 Normalize 的结果写入记录实例。
+func (t Table) Fill(record interface{}, values map[string]interface{}) {
+	v := reflect.ValueOf(record).Elem()
+	for i, field := range t.Fields {
+		value, ok := values[field.Name]
+		if !ok {
+			continue
+		}
+		target := v.Field(systemFieldCount + i)
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		ptr := reflect.New(target.Type().Elem())
+		if tm, ok := value.(time.Time); ok {
+			ptr.Elem().Set(reflect.ValueOf(model.JSONTime(tm)))
+		} else {
+			ptr.Elem().Set(reflect.ValueOf(value))
+		}
+		target.Set(ptr)
+	}
+}
+
+// Row 按字段顺序读取记录值，用于 Excel 导出。
+func (t Table) Row(record reflect.Value) []interface{} {
+	record = reflect.Indirect(record)
+	row := make([]interface{}, 0, len(t.Fields))
+	for i, field := range t.Fields {
+		value := record.Field(systemFieldCount + i)
+		if value.IsNil() {
+			row = append(row, "")
+			continue
+		}
+		switch typed := value.Elem().Interface().(type) {
+		case model.JSONTime:
+			row = append(row, typed.String())
+		case bool:
+			if typed {
+				row = append(row, "是")
+			} else {
+				row = append(row, "否")
+			}
+		default:
+			if field.Type == FieldInt || field.Type == FieldFloat {
+				row = append(row, typed)
+			} else {
+				row = append(row, fmt.Sprint(typed))
+			}
+		}
+	}
+	return row
+}
+
+// Headers 返回 Excel 表头，即字段显示名称。
+func (t Table) Headers() []string {
+	headers := make([]string, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		headers = append(headers, field.Label)
+	}
+	return headers
+}
+
+// ApplyFilters 根据字段筛选配置追加查询条件，列名通过 clause 引用，避免与数据库关键字冲突。
+func (t Table) ApplyFilters(query *gorm.DB, params url.Values) (*gorm.DB, error) {
+	for _, field := range t.Fields {
+		column := clause.Column{Name: field.Name}
+		switch field.Filter {
+		case FilterEq:
+			raw := params.Get(field.Name)
+			if raw == "" {
+				continue
+			}
+			value, err := normalizeValue(field, raw)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(clause.Eq{Column: column, Value: value})
+		case FilterLike:
+			if raw := params.Get(field.Name); raw != "" {
+				query = query.Where(clause.Like{Column: column, Value: "%" + raw + "%"})
+			}
+		case FilterRange:
+			if raw := params.Get(field.Name + "_start"); raw != "" {
+				value, err := normalizeValue(field, raw)
+				if err != nil {
+					return nil, err
+				}
+				query = query.Where(clause.Gte{Column: column, Value: value})
+			}
+			if raw := params.Get(field.Name + "_end"); raw != "" {
+				value, err := normalizeValue(field, raw)
+				if err != nil {
+					return nil, err
+				}
+				query = query.Where(clause.Lte{Column: column, Value: value})
+			}
+		}
+	}
+	return query, nil
+}
+
+// Order 返回排序字段，只允许系统字段和标记为可排序的字段，其余情况按创建时间倒序。
+func (t Table) Order(sortField string, sortOrder string) clause.OrderByColumn {
+	allowed := sortField == "id" || sortField == "created_at" || sortField == "updated_at"
+	if field, ok := t.Field(sortField); ok && field.Sortable {
+		allowed = true
+	}
+	if !allowed {
+		return clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}
+	}
+	// 前端约定 ascend 表示升序，其余值默认降序。
+	return clause.OrderByColumn{Column: clause.Column{Name: sortField}, Desc: sortOrder != "ascend"}
+}
+
+// normalizeValue 按字段类型转换并校验单个值。
+func normalizeValue(field Field, raw interface{}) (interface{}, error) {
+	switch field.Type {
+	case FieldInt:
+		number, err := toFloat(raw)
+		if err != nil || number != math.Trunc(number) {
+			return nil, fmt.Errorf("%s必须是整数", field.Label)
+		}
+		if err := checkRange(field, number); err != nil {
+			return nil, err
+		}
+		return int64(number), nil
+	case FieldFloat:
+		number, err := toFloat(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s必须是数字", field.Label)
+		}
+		if err := checkRange(field, number); err != nil {
+			return nil, err
+		}
+		return number, nil
+	case FieldBool:
+		value, err := toBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s必须是布尔值", field.Label)
+		}
+		return value, nil
+	case FieldDate:
+		s := strings.TrimSpace(fmt.Sprint(raw))
+		if _, err := time.ParseInLocation(dateLayout, s, time.Local); err != nil {
+			return nil, fmt.Errorf("%s格式应为 %s", field.Label, dateLayout)
+		}
+		return s, nil
+	case FieldDatetime:
+		s := strings.TrimSpace(fmt.Sprint(raw))
+		value, err := time.ParseInLocation(datetimeLayout, s, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%s格式应为 %s", field.Label, datetimeLayout)
+		}
+		return value, nil
+	default:
+		return normalizeText(field, raw)
+	}
+}
+
+// normalizeText 校验文本类字段的长度、正则与可选值。
+func normalizeText(field Field, raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		// 导入或前端传入的数字按文本保存，其他复杂类型直接拒绝。
+		switch raw.(type) {
+		case float64, int, int64, bool, json.Number:
+			s = fmt.Sprint(raw)
+		default:
+			return nil, fmt.Errorf("%s必须是文本", field.Label)
+		}
+	}
+
+	maxLength := field.MaxLength
+	if maxLength == 0 && field.Type != FieldText {
+		maxLength = defaultStringSize
+	}
+	if maxLength > 0 && utf8.RuneCountInString(s) > maxLength {
+		return nil, fmt.Errorf("%s长度不能超过 %d", field.Label, maxLength)
+	}
+	if field.Pattern != "" {
+		// 定义保存时已校验过正则，这里编译失败只会出现在历史脏数据中。
+		pattern, err := regexp.Compile(field.Pattern)
+		if err != nil || !pattern.MatchString(s) {
+			return nil, fmt.Errorf("%s格式不正确", field.Label)
+		}
+	}
+	if field.Type == FieldSelect {
+		for _, option := range field.Options {
+			if option == s {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("%s的值不在可选范围内", field.Label)
+	}
+	return s, nil
+}
+
+// checkRange 校验数值范围。
+func checkRange(field Field, number float64) error {
+	if field.Min != nil && number < *field.Min {
+		return fmt.Errorf("%s不能小于 %v", field.Label, *field.Min)
+	}
+	if field.Max != nil && number > *field.Max {
+		return fmt.Errorf("%s不能大于 %v", field.Label, *field.Max)
+	}
+	return nil
+}
+
+func toFloat(raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	default:
+		return 0, fmt.Errorf("unsupported number %T", raw)
+	}
+}
+
+func toBool(raw interface{}) (bool, error) {
+	switch v := raw.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "1", "是", "yes":
+			return true, nil
+		case "false", "0", "否", "no":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("unsupported bool %v", raw)
+}
+
+func isEmpty(raw interface{}) bool {
+	if raw == nil {
+		return true
+	}
+	s, ok := raw.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
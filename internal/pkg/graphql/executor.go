@@ -0,0 +1,398 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"bico-admin/internal/pkg/crud"
+
+	"github.com/gin-gonic/gin"
+	graphqlgo "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Request GraphQL 请求体
+type Request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// OperationType 返回请求将要执行的操作类型，如 query、mutation。
+//
+// 说明：按 OperationName 选择操作，未指定时仅在文档只有一个操作时返回其类型；
+// 语法错误或无法确定操作时返回空字符串，由 Execute 输出标准错误。
+func (r *Request) OperationType() string {
+	doc, err := parser.Parse(parser.ParseParams{Source: r.Query})
+	if err != nil {
+		return ""
+	}
+	var selected *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if r.OperationName == "" {
+			if selected != nil {
+				return ""
+			}
+			selected = op
+		} else if op.Name != nil && op.Name.Value == r.OperationName {
+			selected = op
+		}
+	}
+	if selected == nil {
+		return ""
+	}
+	return selected.Operation
+}
+
+// IsMutation 判断请求是否执行变更操作。
+func (r *Request) IsMutation() bool {
+	return r.OperationType() == ast.OperationTypeMutation
+}
+
+// Executor 基于 CRUD 模块生成 schema 并执行 GraphQL 请求。
+//
+// 说明：
+// - 查询与变更不单独实现业务逻辑，而是调用模块已注册的 List/Get/Create/Update/Delete 方法
+// - 每个字段执行前复用 PermissionChecker，与 REST 路由使用同一个权限 key
+// - 非标准 CRUD 的扩展路由不进入 schema，仍通过 REST 访问
+type Executor struct {
+	schema graphqlgo.Schema
+	perm   crud.PermissionChecker
+	engine *gin.Engine
+}
+
+// operation 描述一个 GraphQL 字段对应的 CRUD 路由。
+type operation struct {
+	handler reflect.Value
+	route   crud.Route
+}
+
+type ginContextKey struct{}
+
+// NewExecutor 根据模块列表生成 GraphQL schema。
+func NewExecutor(modules []crud.Module, perm crud.PermissionChecker) (*Executor, error) {
+	e := &Executor{
+		perm: perm,
+		// 独立引擎仅用于承载内部调用的 gin.Context，不注册任何路由。
+		engine: gin.New(),
+	}
+
+	builder := newTypeBuilder()
+	queries := graphqlgo.Fields{}
+	mutations := graphqlgo.Fields{}
+	for _, module := range modules {
+		if module == nil {
+			continue
+		}
+		if err := e.addModule(builder, module, queries, mutations); err != nil {
+			return nil, err
+		}
+	}
+	if len(queries) == 0 {
+		return nil, errors.New("没有可用于生成 GraphQL 查询的 CRUD 模块")
+	}
+
+	schemaConfig := graphqlgo.SchemaConfig{
+		Query: graphqlgo.NewObject(graphqlgo.ObjectConfig{Name: "Query", Fields: queries}),
+	}
+	if len(mutations) > 0 {
+		schemaConfig.Mutation = graphqlgo.NewObject(graphqlgo.ObjectConfig{Name: "Mutation", Fields: mutations})
+	}
+	schema, err := graphqlgo.NewSchema(schemaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("生成 GraphQL schema 失败: %w", err)
+	}
+	e.schema = schema
+	return e, nil
+}
+
+// addModule 将单个模块的标准 CRUD 路由转换为查询和变更字段。
+func (e *Executor) addModule(builder *typeBuilder, module crud.Module, queries graphqlgo.Fields, mutations graphqlgo.Fields) error {
+	config := module.ModuleConfig()
+	// 未声明模型的模块无法生成输出类型，保持仅 REST 可用。
+	if config.Swagger.Model == nil || config.Name == "" {
+		return nil
+	}
+
+	typeName := typeNameFromModule(config)
+	fieldName := lowerFirst(typeName)
+	item := builder.objectType(reflect.TypeOf(config.Swagger.Model))
+	handlerVal := reflect.ValueOf(module)
+
+	for _, route := range config.Routes {
+		// 公开路由不进入 GraphQL，避免绕过登录态读取数据。
+		if route.Public {
+			continue
+		}
+		if !handlerVal.MethodByName(route.Handler).IsValid() {
+			return fmt.Errorf("模块 %s 缺少 handler 方法 %s", config.Name, route.Handler)
+		}
+		op := operation{handler: handlerVal, route: route}
+
+		switch route.Handler {
+		case "List":
+			queries[fieldName+"List"] = &graphqlgo.Field{
+				Type:        builder.pageType(item),
+				Description: "获取" + config.Description + "列表",
+				Args:        listArguments(config.Swagger.ListRequest),
+				Resolve:     e.resolveList(op),
+			}
+		case "Get":
+			queries[fieldName] = &graphqlgo.Field{
+				Type:        item,
+				Description: "获取" + config.Description + "详情",
+				Args:        idArguments(),
+				Resolve:     e.resolveGet(op),
+			}
+		case "Create":
+			input := builder.inputType(typeName+"CreateInput", config.Swagger.CreateRequest)
+			if input == nil {
+				continue
+			}
+			mutations["create"+typeName] = &graphqlgo.Field{
+				Type:        item,
+				Description: "创建" + config.Description,
+				Args: graphqlgo.FieldConfigArgument{
+					"input": {Type: graphqlgo.NewNonNull(input)},
+				},
+				Resolve: e.resolveCreate(op),
+			}
+		case "Update":
+			input := builder.inputType(typeName+"UpdateInput", config.Swagger.UpdateRequest)
+			if input == nil {
+				continue
+			}
+			args := idArguments()
+			args["input"] = &graphqlgo.ArgumentConfig{Type: graphqlgo.NewNonNull(input)}
+			mutations["update"+typeName] = &graphqlgo.Field{
+				Type:        item,
+				Description: "更新" + config.Description,
+				Args:        args,
+				Resolve:     e.resolveUpdate(op),
+			}
+		case "Delete":
+			mutations["delete"+typeName] = &graphqlgo.Field{
+				Type:        graphqlgo.Boolean,
+				Description: "删除" + config.Description,
+				Args:        idArguments(),
+				Resolve:     e.resolveDelete(op),
+			}
+		case "DeleteBatch":
+			mutations["delete"+typeName+"Batch"] = &graphqlgo.Field{
+				Type:        graphqlgo.Boolean,
+				Description: "批量删除" + config.Description,
+				Args: graphqlgo.FieldConfigArgument{
+					"ids": {Type: graphqlgo.NewNonNull(graphqlgo.NewList(graphqlgo.NewNonNull(graphqlgo.Int)))},
+				},
+				Resolve: e.resolveDeleteBatch(op),
+			}
+		}
+	}
+	return nil
+}
+
+// Execute 执行 GraphQL 请求。
+//
+// 说明：外层 gin.Context 需已通过 JWT 与用户状态中间件，解析出的 user_id 会传递给每个字段调用。
+func (e *Executor) Execute(c *gin.Context, req *Request) *graphqlgo.Result {
+	ctx := context.WithValue(c.Request.Context(), ginContextKey{}, c)
+	return graphqlgo.Do(graphqlgo.Params{
+		Schema:         e.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+}
+
+// resolveList 将参数转为 query string 后调用模块 List。
+func (e *Executor) resolveList(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		query := url.Values{}
+		for name, value := range p.Args {
+			query.Set(name, formatQueryValue(value))
+		}
+		return e.call(p.Context, op, http.MethodGet, "?"+query.Encode(), nil, nil)
+	}
+}
+
+// resolveGet 调用模块 Get。
+func (e *Executor) resolveGet(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		return e.call(p.Context, op, http.MethodGet, "", idParams(p.Args), nil)
+	}
+}
+
+// resolveCreate 将 input 编码为 JSON 请求体后调用模块 Create。
+func (e *Executor) resolveCreate(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		return e.call(p.Context, op, http.MethodPost, "", nil, p.Args["input"])
+	}
+}
+
+// resolveUpdate 将 input 编码为 JSON 请求体后调用模块 Update。
+func (e *Executor) resolveUpdate(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		return e.call(p.Context, op, http.MethodPut, "", idParams(p.Args), p.Args["input"])
+	}
+}
+
+// resolveDelete 调用模块 Delete，成功时返回 true。
+func (e *Executor) resolveDelete(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		if _, err := e.call(p.Context, op, http.MethodDelete, "", idParams(p.Args), nil); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+// resolveDeleteBatch 调用模块 DeleteBatch，成功时返回 true。
+func (e *Executor) resolveDeleteBatch(op operation) graphqlgo.FieldResolveFn {
+	return func(p graphqlgo.ResolveParams) (interface{}, error) {
+		body := map[string]interface{}{"ids": p.Args["ids"]}
+		if _, err := e.call(p.Context, op, http.MethodDelete, "", nil, body); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+// call 构造内部 gin.Context 并按 REST 路由相同的顺序执行权限检查与 handler。
+func (e *Executor) call(
+	ctx context.Context,
+	op operation,
+	method string,
+	rawQuery string,
+	params gin.Params,
+	body interface{},
+) (interface{}, error) {
+	parent, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		return nil, errors.New("缺少请求上下文")
+	}
+
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, parent.Request.URL.Path+rawQuery, reader)
+	if err != nil {
+		return nil, err
+	}
+	// 保留原始请求头与来源地址，确保 ClientIP/UA 等信息与外层请求一致。
+	req.Header = parent.Request.Header.Clone()
+	req.RemoteAddr = parent.Request.RemoteAddr
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	c := gin.CreateTestContextOnly(w, e.engine)
+	c.Request = req
+	c.Params = params
+	// 透传 JWT 中间件写入的 user_id/username 等上下文，权限中间件依赖这些值。
+	for key, value := range parent.Keys {
+		c.Set(key, value)
+	}
+
+	if op.route.Permission != "" && e.perm != nil {
+		e.perm.RequirePermission(op.route.Permission)(c)
+		if c.IsAborted() {
+			return nil, responseError(w)
+		}
+	}
+
+	op.handler.MethodByName(op.route.Handler).Call([]reflect.Value{reflect.ValueOf(c)})
+	return decodeResponse(w)
+}
+
+// decodeResponse 解析统一响应结构，业务错误转换为 GraphQL 错误。
+func decodeResponse(w *httptest.ResponseRecorder) (interface{}, error) {
+	var resp struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if w.Code != http.StatusOK || resp.Code != 0 {
+		return nil, errors.New(resp.Msg)
+	}
+	if len(resp.Data) == 0 {
+		return nil, nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// responseError 读取中间件中断时写入的错误文案。
+func responseError(w *httptest.ResponseRecorder) error {
+	_, err := decodeResponse(w)
+	if err != nil {
+		return err
+	}
+	return errors.New("无权访问")
+}
+
+// idArguments 返回单条记录操作的 id 参数。
+func idArguments() graphqlgo.FieldConfigArgument {
+	return graphqlgo.FieldConfigArgument{
+		"id": {Type: graphqlgo.NewNonNull(graphqlgo.Int), Description: "记录 ID"},
+	}
+}
+
+// idParams 将 id 参数转换为 gin 路由参数。
+func idParams(args map[string]interface{}) gin.Params {
+	return gin.Params{{Key: "id", Value: formatQueryValue(args["id"])}}
+}
+
+// formatQueryValue 将参数值转换为 query string，切片按逗号拼接以兼容 role_ids 等筛选。
+func formatQueryValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatQueryValue(item))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
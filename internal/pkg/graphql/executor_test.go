@@ -0,0 +1,178 @@
+package graphql
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type testArticle struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testArticleListReq struct {
+	Title string `form:"title"`
+}
+
+type testArticleCreateReq struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type testArticleUpdateReq struct {
+	Title string `json:"title"`
+}
+
+var testArticlePerms = crud.NewCRUDPerms("test", "article", "文章")
+
+// testArticleHandler 覆盖 GraphQL 委托调用的测试模块。
+type testArticleHandler struct {
+	crud.CRUDHandler[testArticle, testArticleListReq, testArticleCreateReq, testArticleUpdateReq]
+}
+
+func (h *testArticleHandler) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:        "article",
+		Group:       "/articles",
+		Description: "文章",
+		Routes:      testArticlePerms.Routes(),
+		Swagger: crud.SwaggerConfig{
+			Model:         testArticle{},
+			ListRequest:   testArticleListReq{},
+			CreateRequest: testArticleCreateReq{},
+			UpdateRequest: testArticleUpdateReq{},
+		},
+	}
+}
+
+// testPermChecker 仅放行预设权限的测试权限检查器。
+type testPermChecker struct {
+	allowed map[string]bool
+}
+
+func (p *testPermChecker) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !p.allowed[permission] {
+			response.ErrorWithCode(c, 403, "无权访问")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// newTestExecutor 创建基于内存数据库的测试执行器。
+func newTestExecutor(t *testing.T, allowed ...string) *Executor {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("创建测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&testArticle{}); err != nil {
+		t.Fatalf("迁移测试表失败: %v", err)
+	}
+	if err := db.Create(&[]testArticle{{Title: "hello"}, {Title: "world"}}).Error; err != nil {
+		t.Fatalf("创建测试数据失败: %v", err)
+	}
+
+	h := &testArticleHandler{}
+	h.DB = db
+	h.BuildListQuery = func(db *gorm.DB, req *testArticleListReq) *gorm.DB {
+		query := db.Model(&testArticle{})
+		if req.Title != "" {
+			query = query.Where("title = ?", req.Title)
+		}
+		return query
+	}
+	h.NewModelFromCreate = func(req *testArticleCreateReq) (*testArticle, error) {
+		return &testArticle{Title: req.Title}, nil
+	}
+
+	perm := &testPermChecker{allowed: map[string]bool{}}
+	for _, key := range allowed {
+		perm.allowed[key] = true
+	}
+	executor, err := NewExecutor([]crud.Module{h}, perm)
+	if err != nil {
+		t.Fatalf("生成 schema 失败: %v", err)
+	}
+	return executor
+}
+
+// execute 在带登录态的 gin.Context 中执行 GraphQL 请求。
+func execute(executor *Executor, query string) map[string]interface{} {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/admin-api/graphql", strings.NewReader(""))
+	c.Set("user_id", uint(1))
+
+	result := executor.Execute(c, &Request{Query: query})
+	out := map[string]interface{}{"data": result.Data}
+	if len(result.Errors) > 0 {
+		out["error"] = result.Errors[0].Message
+	}
+	return out
+}
+
+// TestExecutorListDelegatesToCRUDHandler 验证列表查询复用 CRUDHandler 的查询 hook。
+func TestExecutorListDelegatesToCRUDHandler(t *testing.T) {
+	executor := newTestExecutor(t, testArticlePerms.List)
+
+	out := execute(executor, `{ articleList(title: "world") { total list { id title created_at } } }`)
+	if out["error"] != nil {
+		t.Fatalf("查询失败: %v", out["error"])
+	}
+	page := out["data"].(map[string]interface{})["articleList"].(map[string]interface{})
+	if page["total"] != 1 {
+		t.Fatalf("列表筛选未生效，total: %v", page["total"])
+	}
+	list := page["list"].([]interface{})
+	if list[0].(map[string]interface{})["title"] != "world" {
+		t.Fatalf("列表数据不正确: %v", list)
+	}
+}
+
+// TestExecutorEnforcesRoutePermission 验证字段级复用路由权限 key。
+func TestExecutorEnforcesRoutePermission(t *testing.T) {
+	executor := newTestExecutor(t, testArticlePerms.List)
+
+	out := execute(executor, `mutation { createArticle(input: {title: "new"}) { id } }`)
+	if out["error"] != "无权访问" {
+		t.Fatalf("缺少创建权限时应拒绝，实际: %v", out)
+	}
+
+	out = execute(executor, `{ article(id: 1) { title } }`)
+	if out["error"] != nil {
+		t.Fatalf("具备列表权限时详情查询应成功: %v", out["error"])
+	}
+}
+
+// TestRequestOperationType 验证按 OperationName 识别请求的操作类型。
+func TestRequestOperationType(t *testing.T) {
+	doc := `query list { articleList { total } } mutation create { createArticle(input: {title: "x"}) { id } }`
+	cases := []struct {
+		req  Request
+		want string
+	}{
+		{Request{Query: `{ articleList { total } }`}, "query"},
+		{Request{Query: `mutation { deleteArticle(id: 1) }`}, "mutation"},
+		{Request{Query: doc, OperationName: "list"}, "query"},
+		{Request{Query: doc, OperationName: "create"}, "mutation"},
+		{Request{Query: doc}, ""},
+		{Request{Query: `mutation {`}, ""},
+	}
+	for _, tc := range cases {
+		if got := tc.req.OperationType(); got != tc.want {
+			t.Fatalf("%q (%s) 期望 %q，实际 %q", tc.req.Query, tc.req.OperationName, tc.want, got)
+		}
+	}
+}
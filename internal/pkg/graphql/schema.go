@@ -0,0 +1,324 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"bico-admin/internal/pkg/crud"
+
+	graphqlgo "github.com/graphql-go/graphql"
+)
+
+// typeBuilder 负责把 CRUD 模块声明的 Go 类型转换为 GraphQL 类型。
+//
+// 说明：同一个 Go 类型只生成一次，嵌套关联（例如 AdminUser.Roles）与独立模块共用同一个对象类型。
+type typeBuilder struct {
+	objects map[reflect.Type]*graphqlgo.Object
+	inputs  map[string]*graphqlgo.InputObject
+	names   map[string]reflect.Type
+}
+
+// newTypeBuilder 创建类型构建器。
+func newTypeBuilder() *typeBuilder {
+	return &typeBuilder{
+		objects: make(map[reflect.Type]*graphqlgo.Object),
+		inputs:  make(map[string]*graphqlgo.InputObject),
+		names:   make(map[string]reflect.Type),
+	}
+}
+
+// objectType 将结构体转换为输出对象类型。
+func (b *typeBuilder) objectType(t reflect.Type) *graphqlgo.Object {
+	t = indirectType(t)
+	if obj, ok := b.objects[t]; ok {
+		return obj
+	}
+
+	obj := graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: b.uniqueName(t.Name(), t),
+		// 使用 thunk 延迟生成字段，允许模型之间互相引用。
+		Fields: graphqlgo.FieldsThunk(func() graphqlgo.Fields {
+			fields := graphqlgo.Fields{}
+			b.collectOutputFields(t, fields)
+			return fields
+		}),
+	})
+	b.objects[t] = obj
+	return obj
+}
+
+// collectOutputFields 收集结构体字段，匿名嵌入字段（例如 BaseModel）直接展开。
+func (b *typeBuilder) collectOutputFields(t reflect.Type, fields graphqlgo.Fields) {
+	t = indirectType(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous {
+			b.collectOutputFields(field.Type, fields)
+			continue
+		}
+		name := jsonFieldName(field)
+		if !isValidFieldName(name) {
+			continue
+		}
+		output := b.outputType(field.Type)
+		// 无法映射的字段（例如 interface{}、map）不暴露，避免生成不完整的 schema。
+		if output == nil {
+			continue
+		}
+		fields[name] = &graphqlgo.Field{Type: output, Description: field.Tag.Get("comment")}
+	}
+}
+
+// outputType 将字段类型映射为 GraphQL 输出类型。
+func (b *typeBuilder) outputType(t reflect.Type) graphqlgo.Output {
+	t = indirectType(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		item := b.outputType(t.Elem())
+		if item == nil {
+			return nil
+		}
+		return graphqlgo.NewList(item)
+	}
+	if t.Kind() == reflect.Struct && !isTimeLikeType(t) {
+		return b.objectType(t)
+	}
+	return scalarType(t)
+}
+
+// inputType 将请求结构体转换为输入对象类型。
+func (b *typeBuilder) inputType(name string, sample interface{}) *graphqlgo.InputObject {
+	if sample == nil {
+		return nil
+	}
+	if input, ok := b.inputs[name]; ok {
+		return input
+	}
+
+	t := indirectType(reflect.TypeOf(sample))
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := graphqlgo.InputObjectConfigFieldMap{}
+	b.collectInputFields(t, fields)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	input := graphqlgo.NewInputObject(graphqlgo.InputObjectConfig{Name: name, Fields: fields})
+	b.inputs[name] = input
+	return input
+}
+
+// collectInputFields 收集请求体字段，binding:"required" 的非指针字段映射为非空类型。
+func (b *typeBuilder) collectInputFields(t reflect.Type, fields graphqlgo.InputObjectConfigFieldMap) {
+	t = indirectType(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous {
+			b.collectInputFields(field.Type, fields)
+			continue
+		}
+		name := tagName(field.Tag.Get("json"))
+		if !isValidFieldName(name) {
+			continue
+		}
+		input := inputScalarType(field.Type)
+		if input == nil {
+			continue
+		}
+		if hasRequiredBinding(field) && field.Type.Kind() != reflect.Pointer {
+			input = graphqlgo.NewNonNull(input)
+		}
+		fields[name] = &graphqlgo.InputObjectFieldConfig{Type: input, Description: field.Tag.Get("comment")}
+	}
+}
+
+// listArguments 将列表查询结构体的 form 字段和分页参数转换为查询参数。
+func listArguments(sample interface{}) graphqlgo.FieldConfigArgument {
+	args := graphqlgo.FieldConfigArgument{
+		"page":      {Type: graphqlgo.Int, Description: "页码"},
+		"pageSize":  {Type: graphqlgo.Int, Description: "每页数量"},
+		"sortField": {Type: graphqlgo.String, Description: "排序字段"},
+		"sortOrder": {Type: graphqlgo.String, Description: "排序方向：ascend 为升序，其余为降序"},
+	}
+	if sample == nil {
+		return args
+	}
+	collectListArguments(indirectType(reflect.TypeOf(sample)), args)
+	return args
+}
+
+// collectListArguments 遍历 form tag，保持与 REST 查询参数同名。
+func collectListArguments(t reflect.Type, args graphqlgo.FieldConfigArgument) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			collectListArguments(indirectType(field.Type), args)
+			continue
+		}
+		name := tagName(field.Tag.Get("form"))
+		if !isValidFieldName(name) {
+			continue
+		}
+		input := inputScalarType(field.Type)
+		if input == nil {
+			continue
+		}
+		args[name] = &graphqlgo.ArgumentConfig{Type: input, Description: field.Tag.Get("comment")}
+	}
+}
+
+// pageType 生成列表分页包装类型，与 REST 的 {list,total} 结构保持一致。
+func (b *typeBuilder) pageType(item *graphqlgo.Object) *graphqlgo.Object {
+	return graphqlgo.NewObject(graphqlgo.ObjectConfig{
+		Name: item.Name() + "Page",
+		Fields: graphqlgo.Fields{
+			"list":  &graphqlgo.Field{Type: graphqlgo.NewList(item)},
+			"total": &graphqlgo.Field{Type: graphqlgo.Int},
+		},
+	})
+}
+
+// uniqueName 保证不同包下的同名类型不会在 schema 中冲突。
+func (b *typeBuilder) uniqueName(name string, t reflect.Type) string {
+	candidate := name
+	for i := 2; ; i++ {
+		existing, ok := b.names[candidate]
+		if !ok || existing == t {
+			b.names[candidate] = t
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// scalarType 将基础类型映射为 GraphQL 标量。
+func scalarType(t reflect.Type) graphqlgo.Output {
+	if isTimeLikeType(t) {
+		return graphqlgo.String
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return graphqlgo.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return graphqlgo.Int
+	case reflect.Int64, reflect.Uint64, reflect.Float32, reflect.Float64:
+		// GraphQL Int 仅为 32 位，64 位整数按 Float 输出避免溢出后被置空。
+		return graphqlgo.Float
+	case reflect.String:
+		return graphqlgo.String
+	default:
+		return nil
+	}
+}
+
+// inputScalarType 将请求字段映射为输入类型，仅支持标量和标量切片。
+func inputScalarType(t reflect.Type) graphqlgo.Input {
+	t = indirectType(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		item := inputScalarType(t.Elem())
+		if item == nil {
+			return nil
+		}
+		return graphqlgo.NewList(item)
+	}
+	scalar, ok := scalarType(t).(*graphqlgo.Scalar)
+	if !ok {
+		return nil
+	}
+	return scalar
+}
+
+// typeNameFromModule 将模块名 admin_user 转换为 AdminUser。
+func typeNameFromModule(config crud.ModuleConfig) string {
+	parts := strings.FieldsFunc(config.Name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ':'
+	})
+	var sb strings.Builder
+	for _, part := range parts {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+// lowerFirst 将首字母转为小写，用于生成查询字段名。
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// jsonFieldName 读取 JSON 字段名，未声明 json tag 的字段沿用 Go 字段名。
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := tagName(tag); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// tagName 解析 struct tag 中逗号前的字段名。
+func tagName(tag string) string {
+	if tag == "-" {
+		return ""
+	}
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		tag = tag[:idx]
+	}
+	return tag
+}
+
+// hasRequiredBinding 判断字段是否带 required 约束。
+func hasRequiredBinding(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidFieldName 校验名称是否符合 GraphQL 命名规则。
+func isValidFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		if i == 0 && !isLetter {
+			return false
+		}
+		if !isLetter && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// indirectType 解开指针类型。
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isTimeLikeType 将时间类字段按字符串处理，与 JSON 序列化结果保持一致。
+func isTimeLikeType(t reflect.Type) bool {
+	return (t.PkgPath() == "time" && t.Name() == "Time") || t.Name() == "JSONTime"
+}
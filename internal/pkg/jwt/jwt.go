@@ -10,12 +10,28 @@ var (
 	ErrTokenInvalid = errors.New("token 无效")
 )
 
+// APITokenPrefix 个人访问令牌前缀，认证中间件据此区分访问令牌与 JWT。
+const APITokenPrefix = "bico_pat_"
+
 // Claims JWT 载荷
+//
+// Family 为刷新令牌族 ID，同一次登录轮换出的令牌共享该值，用于整体吊销。
+// Scopes 非空时请求只能使用其中的权限，个人访问令牌以此限定权限范围。
+// Actor 非空表示模拟登录，UserID 为被模拟的用户，Actor 为实际操作者（对应 RFC 8693 的 act 声明）。
 type Claims struct {
+	UserID   uint     `json:"user_id"`
+	Username string   `json:"username"`
+	Version  uint     `json:"version"`
+	Family   string   `json:"fid,omitempty"`
+	Scopes   []string `json:"scp,omitempty"`
+	Actor    *Actor   `json:"act,omitempty"`
+	Exp      int64    `json:"exp"`
+}
+
+// Actor 模拟登录的实际操作者
+type Actor struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
-	Version  uint   `json:"version"`
-	Exp      int64  `json:"exp"`
 }
 
 // GetExpiration 获取过期时间
@@ -25,34 +41,50 @@ func (c *Claims) GetExpiration() int64 {
 
 // JWTManager JWT 管理器
 type JWTManager struct {
-	secret      string
-	expireHours int
+	keyring *Keyring
+	expire  time.Duration
 }
 
-// NewJWTManager 创建 JWT 管理器
-func NewJWTManager(secret string, expireHours int) *JWTManager {
+// NewJWTManager 创建使用 HS256 共享密钥的 JWT 管理器，expire 为访问令牌有效期
+func NewJWTManager(secret string, expire time.Duration) *JWTManager {
+	return NewJWTManagerWithKeyring(NewSecretKeyring(secret), expire)
+}
+
+// NewJWTManagerWithKeyring 创建使用密钥环签名的 JWT 管理器
+func NewJWTManagerWithKeyring(keyring *Keyring, expire time.Duration) *JWTManager {
 	return &JWTManager{
-		secret:      secret,
-		expireHours: expireHours,
+		keyring: keyring,
+		expire:  expire,
 	}
 }
 
+// Expire 返回访问令牌有效期
+func (j *JWTManager) Expire() time.Duration {
+	return j.expire
+}
+
 // GenerateToken 生成 token
-func (j *JWTManager) GenerateToken(userID uint, username string, version uint) (string, error) {
+func (j *JWTManager) GenerateToken(userID uint, username string, version uint, family string) (string, error) {
+	return j.GenerateTokenWithActor(userID, username, version, family, nil)
+}
+
+// GenerateTokenWithActor 生成 token，actor 非空时签发模拟登录令牌
+func (j *JWTManager) GenerateTokenWithActor(userID uint, username string, version uint, family string, actor *Actor) (string, error) {
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		Version:  version,
-		Exp:      time.Now().Add(time.Duration(j.expireHours) * time.Hour).Unix(),
+		Family:   family,
+		Actor:    actor,
+		Exp:      time.Now().Add(j.expire).Unix(),
 	}
 
-	token := createToken(claims, j.secret)
-	return token, nil
+	return createToken(claims, j.keyring.signer())
 }
 
 // ParseToken 解析 token
 func (j *JWTManager) ParseToken(tokenString string) (*Claims, error) {
-	claims, err := parseToken(tokenString, j.secret)
+	claims, err := parseToken(tokenString, j.keyring.verifier)
 	if err != nil {
 		return nil, ErrTokenInvalid
 	}
@@ -78,3 +110,8 @@ func (j *JWTManager) ValidateToken(tokenString string) (map[string]interface{},
 		"exp":      float64(claims.Exp),
 	}, nil
 }
+
+// JWKS 返回用于校验令牌的公钥集合，使用 HS256 共享密钥时为空
+func (j *JWTManager) JWKS() JWKSet {
+	return j.keyring.JWKS()
+}
@@ -0,0 +1,255 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 密钥状态
+const (
+	KeyActive  = "active"
+	KeyRetired = "retired"
+)
+
+// 检查密钥环文件是否变更的最小间隔；遇到未知 kid 时使用更短的间隔，尽快识别其他实例轮换出的新密钥。
+const (
+	keyringReloadInterval     = 30 * time.Second
+	keyringMissReloadInterval = time.Second
+)
+
+// KeyFile 密钥环文件内容，由 `bico-admin jwt rotate` 维护。
+type KeyFile struct {
+	Keys []KeyEntry `json:"keys"`
+}
+
+// ReadKeyFile 读取密钥环文件，文件不存在时返回空密钥环。
+func ReadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &KeyFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file KeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("jwt: 解析密钥环文件失败: %w", err)
+	}
+	return &file, nil
+}
+
+// WriteKeyFile 以 0600 权限原子写入密钥环文件，避免运行中的实例读到半个文件。
+func WriteKeyFile(path string, file *KeyFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".jwt-keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Active 返回当前签名密钥。
+func (f *KeyFile) Active() *KeyEntry {
+	for i := range f.Keys {
+		if f.Keys[i].Status == KeyActive {
+			return &f.Keys[i]
+		}
+	}
+	return nil
+}
+
+// Rotate 生成新的签名密钥，原签名密钥转为退役，只用于校验。
+//
+// 退役超过 retain 的密钥被移除：retain 不小于访问令牌有效期时，被移除密钥签发的令牌都已过期。
+func (f *KeyFile) Rotate(alg string, retain time.Duration) (*KeyEntry, error) {
+	entry, err := GenerateKey(alg)
+	if err != nil {
+		return nil, err
+	}
+	now := entry.CreatedAt
+	keys := make([]KeyEntry, 0, len(f.Keys)+1)
+	for _, key := range f.Keys {
+		if key.Status == KeyActive {
+			key.Status = KeyRetired
+			key.RetiredAt = &now
+		}
+		if key.RetiredAt != nil && now.Sub(*key.RetiredAt) > retain {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	f.Keys = append(keys, *entry)
+	return entry, nil
+}
+
+// Keyring 签名与校验密钥集合。
+//
+// 签名始终使用 active 密钥；校验按令牌头部的 kid 查找，退役密钥仍可校验其签发的未过期令牌。
+// 从文件加载时会定期检查文件变更，其他实例轮换密钥后无需重启即可生效。
+type Keyring struct {
+	mu        sync.RWMutex
+	path      string
+	legacy    *signingKey
+	active    *signingKey
+	keys      map[string]*signingKey
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewSecretKeyring 创建仅包含 HS256 共享密钥的密钥环，令牌不携带 kid。
+func NewSecretKeyring(secret string) *Keyring {
+	key := newSecretKey(secret)
+	return &Keyring{active: key, keys: map[string]*signingKey{"": key}}
+}
+
+// LoadKeyring 从文件加载密钥环。
+//
+// legacySecret 非空时保留为只校验的 HS256 密钥，用于校验切换前签发、未携带 kid 的令牌。
+func LoadKeyring(path, legacySecret string) (*Keyring, error) {
+	keyring := &Keyring{path: path}
+	if legacySecret != "" {
+		keyring.legacy = newSecretKey(legacySecret)
+	}
+	if err := keyring.load(); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// JWKS 返回全部非对称密钥的公钥，包括仍在校验期内的退役密钥。
+func (r *Keyring) JWKS() JWKSet {
+	r.reloadIfChanged(keyringReloadInterval)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	set := JWKSet{Keys: []JSONWebKey{}}
+	ids := make([]string, 0, len(r.keys))
+	for id := range r.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if jwk, ok := r.keys[id].jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// signer 返回当前签名密钥。
+func (r *Keyring) signer() *signingKey {
+	r.reloadIfChanged(keyringReloadInterval)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.active
+}
+
+// verifier 按 kid 查找校验密钥。
+func (r *Keyring) verifier(kid string) (*signingKey, bool) {
+	r.reloadIfChanged(keyringReloadInterval)
+	if key, ok := r.lookup(kid); ok {
+		return key, true
+	}
+	r.reloadIfChanged(keyringMissReloadInterval)
+	return r.lookup(kid)
+}
+
+// lookup 在已加载的密钥中查找 kid。
+func (r *Keyring) lookup(kid string) (*signingKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+// load 读取并解析密钥环文件，文件中必须有且只有一把 active 密钥。
+func (r *Keyring) load() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("jwt: 密钥环文件 %s 不存在，请先执行 bico-admin jwt rotate", r.path)
+		}
+		return err
+	}
+	file, err := ReadKeyFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*signingKey, len(file.Keys)+1)
+	if r.legacy != nil {
+		keys[""] = r.legacy
+	}
+	var active *signingKey
+	for _, entry := range file.Keys {
+		if entry.ID == "" {
+			return fmt.Errorf("jwt: 密钥环文件中存在缺少 kid 的密钥")
+		}
+		key, err := parseKeyEntry(entry)
+		if err != nil {
+			return err
+		}
+		if entry.Status == KeyActive {
+			if active != nil {
+				return fmt.Errorf("jwt: 密钥环文件中存在多把 active 密钥")
+			}
+			active = key
+		}
+		keys[entry.ID] = key
+	}
+	if active == nil {
+		return fmt.Errorf("jwt: 密钥环文件 %s 中没有 active 密钥", r.path)
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.active = active
+	r.modTime = info.ModTime()
+	r.checkedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// reloadIfChanged 文件修改时间变化时重新加载，加载失败时继续使用已加载的密钥。
+func (r *Keyring) reloadIfChanged(interval time.Duration) {
+	if r.path == "" {
+		return
+	}
+	r.mu.Lock()
+	if time.Since(r.checkedAt) < interval {
+		r.mu.Unlock()
+		return
+	}
+	r.checkedAt = time.Now()
+	modTime := r.modTime
+	r.mu.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		return
+	}
+	_ = r.load()
+}
@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestKeyringRotation 验证各算法签发与校验、轮换后退役密钥继续校验，以及切换前的无 kid 令牌兼容。
+func TestKeyringRotation(t *testing.T) {
+	const legacySecret = "0123456789abcdef0123456789abcdef"
+	path := filepath.Join(t.TempDir(), "jwt-keys.json")
+	legacyToken, err := NewJWTManager(legacySecret, time.Hour).GenerateToken(1, "admin", 1, "")
+	if err != nil {
+		t.Fatalf("签发旧令牌失败: %v", err)
+	}
+
+	file := &KeyFile{}
+	if _, err := file.Rotate(AlgHS256, time.Hour); err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	if err := WriteKeyFile(path, file); err != nil {
+		t.Fatalf("写入密钥环失败: %v", err)
+	}
+	keyring, err := LoadKeyring(path, legacySecret)
+	if err != nil {
+		t.Fatalf("加载密钥环失败: %v", err)
+	}
+	manager := NewJWTManagerWithKeyring(keyring, time.Hour)
+
+	if _, err := manager.ParseToken(legacyToken); err != nil {
+		t.Fatalf("配置旧密钥时应能校验无 kid 的令牌: %v", err)
+	}
+	retired, err := LoadKeyring(path, "")
+	if err != nil {
+		t.Fatalf("加载密钥环失败: %v", err)
+	}
+	if _, err := NewJWTManagerWithKeyring(retired, time.Hour).ParseToken(legacyToken); err == nil {
+		t.Fatal("停用旧密钥后无 kid 的令牌应校验失败")
+	}
+
+	var issued []string
+	for _, alg := range []string{AlgRS256, AlgES256, AlgEdDSA} {
+		token, err := manager.GenerateToken(1, "admin", 1, "")
+		if err != nil {
+			t.Fatalf("签发令牌失败: %v", err)
+		}
+		issued = append(issued, token)
+
+		if _, err := file.Rotate(alg, time.Hour); err != nil {
+			t.Fatalf("轮换 %s 密钥失败: %v", alg, err)
+		}
+		if err := WriteKeyFile(path, file); err != nil {
+			t.Fatalf("写入密钥环失败: %v", err)
+		}
+		// 跳过重新加载间隔，模拟间隔后再次检查文件。
+		keyring.checkedAt = time.Time{}
+
+		token, err = manager.GenerateToken(1, "admin", 1, "")
+		if err != nil {
+			t.Fatalf("%s 签发令牌失败: %v", alg, err)
+		}
+		if got := tokenHeader(t, token); got.Alg != alg || got.Kid != file.Active().ID {
+			t.Fatalf("应使用新的 active 密钥签名，实际头部: %+v", got)
+		}
+		if claims, err := manager.ParseToken(token); err != nil || claims.Username != "admin" {
+			t.Fatalf("%s 令牌校验失败: %v", alg, err)
+		}
+	}
+	for _, token := range issued {
+		if _, err := manager.ParseToken(token); err != nil {
+			t.Fatalf("退役密钥签发的未过期令牌应继续有效 (%s): %v", tokenHeader(t, token).Alg, err)
+		}
+	}
+
+	set := manager.JWKS()
+	if len(set.Keys) != 3 {
+		t.Fatalf("JWKS 应只包含三把非对称密钥，实际: %+v", set.Keys)
+	}
+	for _, key := range set.Keys {
+		if key.Alg == AlgHS256 || key.Kid == "" {
+			t.Fatalf("JWKS 不应公开对称密钥: %+v", key)
+		}
+	}
+
+	// 退役超过保留期的密钥被移除后，其签发的令牌不再有效。
+	if _, err := file.Rotate(AlgEdDSA, -time.Second); err != nil {
+		t.Fatalf("轮换密钥失败: %v", err)
+	}
+	if err := WriteKeyFile(path, file); err != nil {
+		t.Fatalf("写入密钥环失败: %v", err)
+	}
+	keyring.checkedAt = time.Time{}
+	if len(file.Keys) != 1 {
+		t.Fatalf("应移除超过保留期的退役密钥，剩余: %d", len(file.Keys))
+	}
+	if _, err := manager.ParseToken(issued[1]); err != ErrTokenInvalid {
+		t.Fatalf("已移除密钥签发的令牌应无效，实际: %v", err)
+	}
+}
+
+// TestParseTokenRejectsAlgorithmMismatch 验证头部算法与密钥算法不一致时拒绝令牌，防止算法混淆。
+func TestParseTokenRejectsAlgorithmMismatch(t *testing.T) {
+	entry, err := GenerateKey(AlgRS256)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	key, err := parseKeyEntry(*entry)
+	if err != nil {
+		t.Fatalf("解析密钥失败: %v", err)
+	}
+	keyring := &Keyring{active: key, keys: map[string]*signingKey{key.id: key}}
+	manager := NewJWTManagerWithKeyring(keyring, time.Hour)
+
+	token, err := manager.GenerateToken(1, "admin", 1, "")
+	if err != nil {
+		t.Fatalf("签发令牌失败: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	forged, _ := json.Marshal(header{Alg: AlgHS256, Typ: "JWT", Kid: key.id})
+	parts[0] = base64.RawURLEncoding.EncodeToString(forged)
+	if _, err := manager.ParseToken(strings.Join(parts, ".")); err != ErrTokenInvalid {
+		t.Fatalf("算法不一致的令牌应无效，实际: %v", err)
+	}
+
+	if _, err := GenerateKey("none"); err != ErrUnsupportedAlgorithm {
+		t.Fatalf("不支持的算法应返回 ErrUnsupportedAlgorithm，实际: %v", err)
+	}
+}
+
+func tokenHeader(t *testing.T, token string) header {
+	t.Helper()
+	raw, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	if err != nil {
+		t.Fatalf("解析令牌头部失败: %v", err)
+	}
+	var h header
+	if err := json.Unmarshal(raw, &h); err != nil {
+		t.Fatalf("解析令牌头部失败: %v", err)
+	}
+	return h
+}
@@ -0,0 +1,242 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("jwt: 不支持的签名算法")
+
+// KeyEntry 密钥环文件中的一把密钥。
+//
+// Key 为 HS256 的 base64url 密钥，或非对称算法的 PKCS#8 PEM 私钥；Status 为 active 或 retired。
+type KeyEntry struct {
+	ID        string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	Status    string     `json:"status"`
+	Key       string     `json:"key"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// JSONWebKey JWKS 中的公钥。
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet 公钥集合，对应 /.well-known/jwks.json 的响应体。
+type JWKSet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// signingKey 解析后的签名密钥。
+type signingKey struct {
+	id      string
+	alg     string
+	secret  []byte
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// GenerateKey 生成指定算法的新密钥，状态为 active。
+func GenerateKey(alg string) (*KeyEntry, error) {
+	var material string
+	switch alg {
+	case AlgHS256:
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		material = base64.RawURLEncoding.EncodeToString(secret)
+	case AlgRS256, AlgES256, AlgEdDSA:
+		var (
+			private crypto.Signer
+			err     error
+		)
+		switch alg {
+		case AlgRS256:
+			private, err = rsa.GenerateKey(rand.Reader, 2048)
+		case AlgES256:
+			private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		default:
+			_, private, err = ed25519.GenerateKey(rand.Reader)
+		}
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return nil, err
+		}
+		material = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &KeyEntry{
+		ID:        hex.EncodeToString(id),
+		Algorithm: alg,
+		Status:    KeyActive,
+		Key:       material,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// parseKeyEntry 解析密钥环文件中的密钥，并校验密钥类型与算法一致。
+func parseKeyEntry(entry KeyEntry) (*signingKey, error) {
+	key := &signingKey{id: entry.ID, alg: entry.Algorithm}
+	if entry.Algorithm == AlgHS256 {
+		secret, err := base64.RawURLEncoding.DecodeString(entry.Key)
+		if err != nil || len(secret) < 32 {
+			return nil, fmt.Errorf("jwt: 密钥 %s 的 HS256 密钥无效", entry.ID)
+		}
+		key.secret = secret
+		return key, nil
+	}
+
+	block, _ := pem.Decode([]byte(entry.Key))
+	if block == nil {
+		return nil, fmt.Errorf("jwt: 密钥 %s 不是 PEM 格式", entry.ID)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: 解析密钥 %s 失败: %w", entry.ID, err)
+	}
+	var ok bool
+	switch entry.Algorithm {
+	case AlgRS256:
+		_, ok = parsed.(*rsa.PrivateKey)
+	case AlgES256:
+		var ec *ecdsa.PrivateKey
+		ec, ok = parsed.(*ecdsa.PrivateKey)
+		ok = ok && ec.Curve == elliptic.P256()
+	case AlgEdDSA:
+		_, ok = parsed.(ed25519.PrivateKey)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if !ok {
+		return nil, fmt.Errorf("jwt: 密钥 %s 与算法 %s 不匹配", entry.ID, entry.Algorithm)
+	}
+	key.private = parsed.(crypto.Signer)
+	key.public = key.private.Public()
+	return key, nil
+}
+
+// newSecretKey 由配置的 jwt.secret 构造 HS256 密钥，kid 为空以兼容未携带 kid 的令牌。
+func newSecretKey(secret string) *signingKey {
+	return &signingKey{alg: AlgHS256, secret: []byte(secret)}
+}
+
+// sign 计算签名，ES256 按 JWS 规范输出定长 R||S。
+func (k *signingKey) sign(message []byte) ([]byte, error) {
+	switch k.alg {
+	case AlgHS256:
+		h := hmac.New(sha256.New, k.secret)
+		h.Write(message)
+		return h.Sum(nil), nil
+	case AlgRS256:
+		digest := sha256.Sum256(message)
+		return rsa.SignPKCS1v15(rand.Reader, k.private.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+	case AlgES256:
+		digest := sha256.Sum256(message)
+		r, s, err := ecdsa.Sign(rand.Reader, k.private.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			return nil, err
+		}
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	case AlgEdDSA:
+		return ed25519.Sign(k.private.(ed25519.PrivateKey), message), nil
+	}
+	return nil, ErrUnsupportedAlgorithm
+}
+
+// verify 校验签名。
+func (k *signingKey) verify(message, signature []byte) bool {
+	switch k.alg {
+	case AlgHS256:
+		expected, _ := k.sign(message)
+		return hmac.Equal(expected, signature)
+	case AlgRS256:
+		digest := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(k.public.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	case AlgES256:
+		if len(signature) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(message)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(k.public.(*ecdsa.PublicKey), digest[:], r, s)
+	case AlgEdDSA:
+		return ed25519.Verify(k.public.(ed25519.PublicKey), message, signature)
+	}
+	return false
+}
+
+// jwk 返回公钥的 JWK 表示，对称密钥不公开。
+func (k *signingKey) jwk() (JSONWebKey, bool) {
+	encode := base64.RawURLEncoding.EncodeToString
+	jwk := JSONWebKey{Kid: k.id, Alg: k.alg, Use: "sig"}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(public.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdh, err := public.ECDH()
+		if err != nil {
+			return jwk, false
+		}
+		// 未压缩点格式为 0x04 || X || Y。
+		point := ecdh.Bytes()
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = encode(point[1:33])
+		jwk.Y = encode(point[33:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(public)
+	default:
+		return jwk, false
+	}
+	return jwk, true
+}
@@ -1,8 +1,6 @@
 package jwt
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
@@ -11,51 +9,67 @@ import (
 type header struct {
 	Alg string `json:"alg"`
 	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
 }
 
-func createToken(claims *Claims, secret string) string {
-	h := header{Alg: "HS256", Typ: "JWT"}
-	
-	headerJSON, _ := json.Marshal(h)
-	claimsJSON, _ := json.Marshal(claims)
-	
+func createToken(claims *Claims, key *signingKey) (string, error) {
+	h := header{Alg: key.alg, Typ: "JWT", Kid: key.id}
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
 	headerB64 := base64.RawURLEncoding.EncodeToString(headerJSON)
 	claimsB64 := base64.RawURLEncoding.EncodeToString(claimsJSON)
-	
+
 	message := headerB64 + "." + claimsB64
-	signature := sign(message, secret)
-	
-	return message + "." + signature
+	signature, err := key.sign([]byte(message))
+	if err != nil {
+		return "", err
+	}
+
+	return message + "." + base64.RawURLEncoding.EncodeToString(signature), nil
 }
 
-func parseToken(tokenString, secret string) (*Claims, error) {
+// parseToken 按头部 kid 查找密钥校验签名；头部算法必须与密钥算法一致，防止算法混淆攻击。
+func parseToken(tokenString string, lookup func(kid string) (*signingKey, bool)) (*Claims, error) {
 	parts := strings.Split(tokenString, ".")
 	if len(parts) != 3 {
 		return nil, ErrTokenInvalid
 	}
-	
-	message := parts[0] + "." + parts[1]
-	signature := parts[2]
-	
-	if sign(message, secret) != signature {
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, ErrTokenInvalid
+	}
+	key, ok := lookup(h.Kid)
+	if !ok || key.alg != h.Alg {
+		return nil, ErrTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
 		return nil, ErrTokenInvalid
 	}
-	
+
 	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
 		return nil, ErrTokenInvalid
 	}
-	
+
 	var claims Claims
 	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
 		return nil, ErrTokenInvalid
 	}
-	
-	return &claims, nil
-}
 
-func sign(message, secret string) string {
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(message))
-	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	return &claims, nil
 }
@@ -0,0 +1,214 @@
+// Package ldapauth 提供基于 LDAP / Active Directory 的账号密码认证：服务账号查找用户，再以用户 DN 绑定校验密码。
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrUserNotFound       = errors.New("ldap: 用户不存在")
+	ErrInvalidCredentials = errors.New("ldap: 用户名或密码错误")
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Config LDAP 连接与查询配置。
+//
+// UserFilter 与 GroupFilter 中的 {username} 会替换为转义后的登录名，GroupFilter 中的 {dn} 替换为用户 DN。
+// 未配置 GroupBaseDN 时只读取用户条目上的 GroupAttr（如 memberOf）。
+type Config struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	UsernameAttr       string
+	NameAttr           string
+	GroupAttr          string
+	GroupBaseDN        string
+	GroupFilter        string
+	Timeout            time.Duration
+}
+
+// Entry 认证通过的目录用户。
+//
+// Groups 同时包含组 DN 与其首个 RDN 的值（如 cn=admins,ou=groups,... 与 admins），便于按任一形式映射角色。
+type Entry struct {
+	DN       string
+	Username string
+	Name     string
+	Groups   []string
+}
+
+// Client LDAP 认证客户端，每次认证使用独立连接。
+type Client struct {
+	cfg Config
+}
+
+// New 创建 LDAP 认证客户端。
+func New(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.UsernameAttr == "" {
+		cfg.UsernameAttr = "uid"
+	}
+	return &Client{cfg: cfg}
+}
+
+// Authenticate 查找用户并以其 DN 绑定校验密码，成功后读取用户组。
+func (c *Client) Authenticate(username, password string) (*Entry, error) {
+	// 空密码在 LDAP 中是匿名绑定，会被服务端视为成功，必须在此拒绝。
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := c.bindService(conn); err != nil {
+		return nil, err
+	}
+	attrs := []string{c.cfg.UsernameAttr}
+	if c.cfg.NameAttr != "" {
+		attrs = append(attrs, c.cfg.NameAttr)
+	}
+	if c.cfg.GroupAttr != "" {
+		attrs = append(attrs, c.cfg.GroupAttr)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(c.cfg.Timeout.Seconds()), false,
+		replaceFilter(c.cfg.UserFilter, username, ""), attrs, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		return nil, fmt.Errorf("ldap: 查找用户失败: %w", err)
+	}
+	if result == nil || len(result.Entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("ldap: 用户过滤条件匹配到多个条目")
+	}
+	found := result.Entries[0]
+
+	if err := conn.Bind(found.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("ldap: 用户绑定失败: %w", err)
+	}
+
+	entry := &Entry{
+		DN:       found.DN,
+		Username: found.GetAttributeValue(c.cfg.UsernameAttr),
+		Name:     found.GetAttributeValue(c.cfg.NameAttr),
+	}
+	if entry.Username == "" {
+		entry.Username = username
+	}
+	var groupDNs []string
+	if c.cfg.GroupAttr != "" {
+		groupDNs = append(groupDNs, found.GetAttributeValues(c.cfg.GroupAttr)...)
+	}
+	if c.cfg.GroupBaseDN != "" && c.cfg.GroupFilter != "" {
+		names, err := c.searchGroups(conn, username, found.DN)
+		if err != nil {
+			return nil, err
+		}
+		groupDNs = append(groupDNs, names...)
+	}
+	entry.Groups = expandGroups(groupDNs)
+	return entry, nil
+}
+
+// dial 建立连接，按配置启用 StartTLS。
+func (c *Client) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.cfg.InsecureSkipVerify}
+	conn, err := ldap.DialURL(c.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: c.cfg.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: 连接失败: %w", err)
+	}
+	conn.SetTimeout(c.cfg.Timeout)
+	if c.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap: StartTLS 失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// bindService 以服务账号绑定，未配置服务账号时使用匿名查询。
+func (c *Client) bindService(conn *ldap.Conn) error {
+	if c.cfg.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+		return fmt.Errorf("ldap: 服务账号绑定失败: %w", err)
+	}
+	return nil
+}
+
+// searchGroups 查询用户所属组，返回组 DN；用户绑定后可能无查询权限，先切回服务账号。
+func (c *Client) searchGroups(conn *ldap.Conn, username, userDN string) ([]string, error) {
+	if err := c.bindService(conn); err != nil {
+		return nil, err
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(c.cfg.Timeout.Seconds()), false,
+		replaceFilter(c.cfg.GroupFilter, username, userDN), []string{"1.1"}, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ldap: 查询用户组失败: %w", err)
+	}
+	groups := make([]string, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		groups = append(groups, e.DN)
+	}
+	return groups, nil
+}
+
+// replaceFilter 将过滤条件中的占位符替换为转义后的值，防止 LDAP 注入。
+func replaceFilter(filter, username, dn string) string {
+	return strings.NewReplacer(
+		"{username}", ldap.EscapeFilter(username),
+		"{dn}", ldap.EscapeFilter(dn),
+	).Replace(filter)
+}
+
+// expandGroups 为每个组 DN 追加其首个 RDN 的值，并去重。
+func expandGroups(dns []string) []string {
+	seen := make(map[string]bool, len(dns)*2)
+	groups := make([]string, 0, len(dns)*2)
+	add := func(value string) {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			groups = append(groups, value)
+		}
+	}
+	for _, dn := range dns {
+		add(dn)
+		if parsed, err := ldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 && len(parsed.RDNs[0].Attributes) > 0 {
+			add(parsed.RDNs[0].Attributes[0].Value)
+		}
+	}
+	return groups
+}
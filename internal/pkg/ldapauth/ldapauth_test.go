@@ -0,0 +1,59 @@
+package ldapauth
+
+import (
+	"errors"
+	"testing"
+
+	"bico-admin/internal/pkg/ldapauth/ldaptest"
+)
+
+// TestAuthenticate 验证服务账号查找、用户绑定与用户组读取。
+func TestAuthenticate(t *testing.T) {
+	directory := ldaptest.Start(t)
+
+	client := New(Config{
+		URL:          ldaptest.URL(directory),
+		BindDN:       ldaptest.BindDN,
+		BindPassword: ldaptest.BindPassword,
+		BaseDN:       ldaptest.BaseDN,
+		UserFilter:   ldaptest.UserFilter,
+		UsernameAttr: "cn",
+		NameAttr:     "displayName",
+		GroupAttr:    "memberOf",
+		GroupBaseDN:  ldaptest.GroupBaseDN,
+		GroupFilter:  "(member={dn})",
+	})
+
+	entry, err := client.Authenticate("alice", "alice-pass")
+	if err != nil {
+		t.Fatalf("认证失败: %v", err)
+	}
+	if entry.Username != "alice" || entry.Name != "Alice Liddell" {
+		t.Fatalf("用户属性不正确: %+v", entry)
+	}
+	for _, group := range []string{"cn=staff,ou=groups,dc=example,dc=org", "staff", "ops"} {
+		if !contains(entry.Groups, group) {
+			t.Fatalf("用户组缺少 %s: %v", group, entry.Groups)
+		}
+	}
+
+	if _, err := client.Authenticate("alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("密码错误应返回 ErrInvalidCredentials，实际: %v", err)
+	}
+	if _, err := client.Authenticate("alice", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("空密码应返回 ErrInvalidCredentials，实际: %v", err)
+	}
+	if _, err := client.Authenticate("nobody", "x"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("用户不存在应返回 ErrUserNotFound，实际: %v", err)
+	}
+}
+
+// contains 判断字符串切片是否包含目标值。
+func contains(list []string, target string) bool {
+	for _, item := range list {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
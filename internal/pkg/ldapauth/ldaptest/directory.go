@@ -0,0 +1,51 @@
+// Package ldaptest 提供进程内的 LDAP 测试目录，用于测试目录认证流程。
+package ldaptest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+)
+
+// 测试目录中的固定条目。
+const (
+	BaseDN       = "ou=people,dc=example,dc=org"
+	GroupBaseDN  = "ou=groups,dc=example,dc=org"
+	BindDN       = "cn=reader,ou=people,dc=example,dc=org"
+	BindPassword = "reader-pass"
+	// UserFilter 测试目录按 DN 匹配过滤条件，用户过滤条件需使用 cn。
+	UserFilter = "(&(objectClass=person)(cn={username}))"
+)
+
+// Start 启动测试目录，测试结束时自动停止。
+//
+// 目录包含服务账号 reader，以及用户 alice（密码 alice-pass，memberOf 为 staff，另属于 ops 组的 member）。
+func Start(t *testing.T) *testdirectory.Directory {
+	t.Helper()
+	users := []*gldap.Entry{
+		gldap.NewEntry(BindDN, map[string][]string{
+			"cn":       {"reader"},
+			"password": {BindPassword},
+		}),
+		gldap.NewEntry("cn=alice,"+BaseDN, map[string][]string{
+			"cn":          {"alice"},
+			"displayName": {"Alice Liddell"},
+			"password":    {"alice-pass"},
+			"memberOf":    {"cn=staff," + GroupBaseDN},
+		}),
+	}
+	groups := []*gldap.Entry{
+		testdirectory.NewGroup(t, "ops", []string{"alice"}),
+	}
+	return testdirectory.Start(t,
+		testdirectory.WithNoTLS(t),
+		testdirectory.WithDefaults(t, &testdirectory.Defaults{Users: users, Groups: groups}),
+	)
+}
+
+// URL 返回测试目录的连接地址。
+func URL(d *testdirectory.Directory) string {
+	return fmt.Sprintf("ldap://%s:%d", d.Host(), d.Port())
+}
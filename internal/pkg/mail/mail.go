@@ -0,0 +1,196 @@
+// Package mail 提供邮件发送接口与 SMTP 实现。
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 连接加密方式
+const (
+	EncryptionNone     = "none"
+	EncryptionStartTLS = "starttls"
+	EncryptionTLS      = "tls"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var ErrInvalidAddress = errors.New("mail: 邮箱地址格式错误")
+
+// Message 纯文本邮件。
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口，便于替换为第三方邮件服务或测试实现。
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPConfig SMTP 连接配置。
+//
+// Encryption 为 none、starttls 或 tls：starttls 要求服务端支持 STARTTLS，tls 为隐式 TLS（通常是 465 端口）。
+// Username 为空时不认证；net/smtp 只允许在 TLS 连接或本机地址上使用明文认证。
+type SMTPConfig struct {
+	Host               string
+	Port               int
+	Username           string
+	Password           string
+	From               string
+	Encryption         string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// SMTPMailer 通过 SMTP 发送邮件，每封邮件使用独立连接。
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer 创建 SMTP 发送器。
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.Encryption == "" {
+		cfg.Encryption = EncryptionStartTLS
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+// Send 发送邮件，ctx 的截止时间与配置的超时取较早者。
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("mail: 发件人地址错误: %w", err)
+	}
+	recipients := make([]string, 0, len(msg.To))
+	for _, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return ErrInvalidAddress
+		}
+		recipients = append(recipients, addr.Address)
+	}
+	if len(recipients) == 0 {
+		return ErrInvalidAddress
+	}
+	data, err := buildMessage(from, recipients, msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range recipients {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 建立连接并完成 TLS 协商与认证，连接的读写截止时间取自 ctx。
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host, InsecureSkipVerify: m.cfg.InsecureSkipVerify}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if m.cfg.Encryption == EncryptionTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.cfg.Encryption == EncryptionStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("mail: 服务器不支持 STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// buildMessage 组装 UTF-8 纯文本邮件，主题按 RFC 2047 编码，正文使用 base64 传输编码。
+func buildMessage(from *mail.Address, to []string, msg *Message) ([]byte, error) {
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, errors.New("mail: 邮件主题不能包含换行")
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bico-admin/internal/pkg/mail"
+	"bico-admin/internal/pkg/mail/mailtest"
+)
+
+// TestSMTPMailerSend 验证经 AUTH PLAIN 认证后投递中文主题与正文，以及认证失败与地址校验。
+func TestSMTPMailerSend(t *testing.T) {
+	server := mailtest.Start(t, "mailer", "secret")
+	cfg := mail.SMTPConfig{
+		Host:       server.Host(),
+		Port:       server.Port(),
+		Username:   "mailer",
+		Password:   "secret",
+		From:       "Bico Admin <noreply@example.com>",
+		Encryption: mail.EncryptionNone,
+	}
+
+	body := strings.Repeat("请点击以下链接重置密码：https://admin.example.com/reset?token=abc\n", 3)
+	err := mail.NewSMTPMailer(cfg).Send(context.Background(), &mail.Message{
+		To:      []string{"Alice <alice@example.com>"},
+		Subject: "重置密码",
+		Body:    body,
+	})
+	if err != nil {
+		t.Fatalf("发送邮件失败: %v", err)
+	}
+	msg, ok := server.Next(time.Second)
+	if !ok {
+		t.Fatal("测试服务器未收到邮件")
+	}
+	if msg.From != "noreply@example.com" || len(msg.To) != 1 || msg.To[0] != "alice@example.com" {
+		t.Fatalf("信封地址错误: %+v", msg)
+	}
+	if msg.Subject != "重置密码" || msg.Body != body {
+		t.Fatalf("主题或正文解码后不一致: %q %q", msg.Subject, msg.Body)
+	}
+
+	cfg.Password = "wrong"
+	if err := mail.NewSMTPMailer(cfg).Send(context.Background(), &mail.Message{To: []string{"alice@example.com"}, Subject: "x"}); err == nil {
+		t.Fatal("认证失败时应返回错误")
+	}
+	if err := mail.NewSMTPMailer(cfg).Send(context.Background(), &mail.Message{To: []string{"not-an-email"}}); !errors.Is(err, mail.ErrInvalidAddress) {
+		t.Fatalf("非法收件人应返回 ErrInvalidAddress，实际: %v", err)
+	}
+	if err := mail.NewSMTPMailer(cfg).Send(context.Background(), &mail.Message{To: []string{"alice@example.com"}, Subject: "a\r\nBcc: x@example.com"}); err == nil {
+		t.Fatal("主题包含换行时应拒绝发送")
+	}
+}
@@ -0,0 +1,166 @@
+// Package mailtest 提供进程内的 SMTP 测试服务器，用于测试邮件发送流程。
+package mailtest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Message 服务器收到的邮件，Subject 与 Body 已解码。
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Server SMTP 测试服务器，不支持 TLS；配置了用户名时要求 AUTH PLAIN 认证。
+type Server struct {
+	listener net.Listener
+	username string
+	password string
+	messages chan Message
+	wg       sync.WaitGroup
+}
+
+// Start 在本机随机端口启动测试服务器，测试结束时自动停止。
+//
+// username 为空时不要求认证。
+func Start(t *testing.T, username, password string) *Server {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("启动 SMTP 测试服务器失败: %v", err)
+	}
+	s := &Server{listener: listener, username: username, password: password, messages: make(chan Message, 16)}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+// Host 返回监听地址。
+func (s *Server) Host() string {
+	return "127.0.0.1"
+}
+
+// Port 返回监听端口。
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Next 等待下一封邮件，超时返回 false。
+func (s *Server) Next(timeout time.Duration) (Message, bool) {
+	select {
+	case msg := <-s.messages:
+		return msg, true
+	case <-time.After(timeout):
+		return Message{}, false
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+// handle 处理一个会话，只实现客户端发送邮件所需的命令。
+func (s *Server) handle(conn *textproto.Conn) {
+	_ = conn.PrintfLine("220 mailtest ready")
+	authed := s.username == ""
+	var msg Message
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250-mailtest\r\n250 AUTH PLAIN")
+		case "AUTH":
+			mechanism, payload, _ := strings.Cut(arg, " ")
+			credentials, _ := base64.StdEncoding.DecodeString(payload)
+			parts := strings.Split(string(credentials), "\x00")
+			if strings.ToUpper(mechanism) == "PLAIN" && len(parts) == 3 && parts[1] == s.username && parts[2] == s.password {
+				authed = true
+				_ = conn.PrintfLine("235 authenticated")
+			} else {
+				_ = conn.PrintfLine("535 authentication failed")
+			}
+		case "MAIL":
+			if !authed {
+				_ = conn.PrintfLine("530 authentication required")
+				continue
+			}
+			msg = Message{From: trimPath(arg)}
+			_ = conn.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, trimPath(arg))
+			_ = conn.PrintfLine("250 ok")
+		case "DATA":
+			_ = conn.PrintfLine("354 end with .")
+			data, err := io.ReadAll(conn.DotReader())
+			if err != nil {
+				return
+			}
+			parseData(&msg, data)
+			s.messages <- msg
+			_ = conn.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			_ = conn.PrintfLine("250 ok")
+		case "QUIT":
+			_ = conn.PrintfLine("221 bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// trimPath 从 "FROM:<a@b>" 形式的参数中取出地址。
+func trimPath(arg string) string {
+	_, path, _ := strings.Cut(arg, ":")
+	return strings.Trim(strings.TrimSpace(path), "<>")
+}
+
+// parseData 解码主题与 base64 正文。
+func parseData(msg *Message, data []byte) {
+	parsed, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	if err != nil {
+		return
+	}
+	msg.Subject, _ = new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	body, _ := io.ReadAll(parsed.Body)
+	if strings.EqualFold(parsed.Header.Get("Content-Transfer-Encoding"), "base64") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\r", "", "\n", "").Replace(string(body)))
+		if err == nil {
+			body = decoded
+		}
+	}
+	msg.Body = string(body)
+}
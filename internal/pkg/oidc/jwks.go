@@ -0,0 +1,301 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// clockSkew 校验有效期时允许的时钟偏差。
+	clockSkew = time.Minute
+	// jwksRefreshInterval 遇到未知 kid 时重新拉取 JWKS 的最小间隔，防止被恶意令牌刷爆请求。
+	jwksRefreshInterval = time.Minute
+)
+
+// ErrUnknownKey ID Token 引用的 kid 不在 JWKS 中。
+var ErrUnknownKey = errors.New("oidc: 未找到 ID Token 签名公钥")
+
+// Claims ID Token 或 userinfo 中的声明。
+type Claims map[string]interface{}
+
+// String 读取字符串声明，不存在或类型不符时返回空串。
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings 读取字符串数组声明，单个字符串按一个元素处理（如 aud、groups）。
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// Time 读取秒级时间戳声明。
+func (c Claims) Time(name string) (time.Time, bool) {
+	switch value := c[name].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case json.Number:
+		n, err := value.Int64()
+		return time.Unix(n, 0), err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// JSONWebKey JWKS 中的单个公钥，仅支持 RSA 与 EC。
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey 将 JWK 转换为公钥。
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: 不支持的椭圆曲线 %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("oidc: 不支持的密钥类型 %s", k.Kty)
+	}
+}
+
+// keySet 按 kid 缓存的 JWKS 公钥集合。
+type keySet struct {
+	uri    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// newKeySet 创建 JWKS 公钥集合，首次校验时拉取。
+func newKeySet(uri string, client *http.Client) *keySet {
+	return &keySet{uri: uri, client: client}
+}
+
+// verify 校验 JWS 签名并返回载荷声明。
+func (s *keySet) verify(ctx context.Context, raw string) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("oidc: ID Token 格式错误")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	hash, ok := signatureHash(header.Alg)
+	if !ok {
+		return nil, fmt.Errorf("oidc: 不支持的签名算法 %s", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("oidc: ID Token 签名格式错误")
+	}
+
+	key, err := s.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(header.Alg, "RS") || rsa.VerifyPKCS1v15(pub, hash, digest, signature) != nil {
+			return nil, errors.New("oidc: ID Token 签名无效")
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(header.Alg, "ES") || len(signature) != 2*size {
+			return nil, errors.New("oidc: ID Token 签名无效")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		sv := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, sv) {
+			return nil, errors.New("oidc: ID Token 签名无效")
+		}
+	default:
+		return nil, ErrUnknownKey
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// key 按 kid 查找公钥，未命中时在刷新间隔外重新拉取一次（身份提供方轮换密钥）。
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < jwksRefreshInterval {
+		return nil, ErrUnknownKey
+	}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookup 在已缓存的公钥中查找；令牌未携带 kid 且仅有一个公钥时直接使用。
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// fetch 拉取 JWKS，忽略无法解析或非签名用途的公钥。
+func (s *keySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: 拉取 JWKS 失败: %s", resp.Status)
+	}
+	var doc struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return fmt.Errorf("oidc: 解析 JWKS 失败: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// signatureHash 返回签名算法对应的摘要算法，不支持 none 与对称算法。
+func signatureHash(alg string) (crypto.Hash, bool) {
+	switch alg {
+	case "RS256", "ES256":
+		return crypto.SHA256, true
+	case "RS384", "ES384":
+		return crypto.SHA384, true
+	case "RS512", "ES512":
+		return crypto.SHA512, true
+	default:
+		return 0, false
+	}
+}
+
+// decodeSegment 解码 JWT 的 base64url 段并解析 JSON。
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("oidc: ID Token 格式错误")
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.New("oidc: ID Token 格式错误")
+	}
+	return nil
+}
+
+// decodeBigInt 解码 base64url 编码的大整数。
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("oidc: JWK 参数格式错误")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,257 @@
+// Package oidc 提供通用 OpenID Connect 授权码登录能力：发现文档、PKCE、ID Token 校验与用户信息读取。
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrIssuerMismatch = errors.New("oidc: 发现文档 issuer 与配置不一致")
+	ErrNonceMismatch  = errors.New("oidc: nonce 不匹配")
+	ErrMissingIDToken = errors.New("oidc: 令牌响应缺少 id_token")
+)
+
+// defaultScopes 未配置 scopes 时请求的范围。
+var defaultScopes = []string{"openid", "profile", "email"}
+
+// Config 单个 OIDC 提供方配置。
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// HTTPClient 为空时使用带超时的默认客户端。
+	HTTPClient *http.Client
+}
+
+// Discovery OIDC 发现文档中用到的字段。
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider OIDC 提供方客户端，发现文档在首次使用时加载并缓存。
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      *keySet
+}
+
+// NewProvider 创建 OIDC 提供方客户端，不会立即请求发现文档，避免身份提供方不可用时阻塞启动。
+func NewProvider(cfg Config) *Provider {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	return &Provider{cfg: cfg, client: client}
+}
+
+// Discover 读取 /.well-known/openid-configuration，成功后缓存结果。
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var doc Discovery
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return nil, err
+	}
+	if strings.TrimRight(doc.Issuer, "/") != p.cfg.Issuer {
+		return nil, ErrIssuerMismatch
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("oidc: 发现文档缺少必要端点")
+	}
+	p.discovery = &doc
+	p.keys = newKeySet(doc.JWKSURI, p.client)
+	return p.discovery, nil
+}
+
+// AuthCodeURL 构建授权地址，challenge 为 PKCE S256 挑战值。
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, challenge string) (string, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return doc.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// tokenResponse 令牌端点响应。
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange 用授权码和 PKCE 校验码换取令牌，校验 ID Token 后返回声明。
+// 身份提供方提供 userinfo 端点时，ID Token 中缺失的声明由 userinfo 补齐。
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("oidc: 解析令牌响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("oidc: 换取令牌失败: %s %s", token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	claims, err := p.VerifyIDToken(ctx, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.String("nonce") != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	if doc.UserinfoEndpoint != "" && token.AccessToken != "" {
+		var info Claims
+		if err := p.getJSON(ctx, doc.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, err
+		}
+		// userinfo 必须属于同一主体，否则可能被替换为其他用户的信息。
+		if info.String("sub") != claims.String("sub") {
+			return nil, errors.New("oidc: userinfo 主体与 ID Token 不一致")
+		}
+		for k, v := range info {
+			if _, ok := claims[k]; !ok {
+				claims[k] = v
+			}
+		}
+	}
+	return claims, nil
+}
+
+// VerifyIDToken 校验 ID Token 签名、签发方、受众与有效期。
+func (p *Provider) VerifyIDToken(ctx context.Context, raw string) (Claims, error) {
+	if _, err := p.Discover(ctx); err != nil {
+		return nil, err
+	}
+	claims, err := p.keys.verify(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimRight(claims.String("iss"), "/") != p.cfg.Issuer {
+		return nil, errors.New("oidc: ID Token 签发方不匹配")
+	}
+	audiences := claims.Strings("aud")
+	if !contains(audiences, p.cfg.ClientID) {
+		return nil, errors.New("oidc: ID Token 受众不匹配")
+	}
+	if len(audiences) > 1 && claims.String("azp") != "" && claims.String("azp") != p.cfg.ClientID {
+		return nil, errors.New("oidc: ID Token azp 不匹配")
+	}
+	now := time.Now()
+	exp, ok := claims.Time("exp")
+	if !ok || now.After(exp.Add(clockSkew)) {
+		return nil, errors.New("oidc: ID Token 已过期")
+	}
+	if iat, ok := claims.Time("iat"); ok && iat.After(now.Add(clockSkew)) {
+		return nil, errors.New("oidc: ID Token 签发时间无效")
+	}
+	if claims.String("sub") == "" {
+		return nil, errors.New("oidc: ID Token 缺少 sub")
+	}
+	return claims, nil
+}
+
+// getJSON 发起 GET 请求并解析 JSON 响应，bearer 不为空时附带访问令牌。
+func (p *Provider) getJSON(ctx context.Context, endpoint, bearer string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: 请求 %s 失败: %s", endpoint, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("oidc: 解析 %s 响应失败: %w", endpoint, err)
+	}
+	return nil
+}
+
+// contains 判断字符串切片是否包含目标值。
+func contains(list []string, target string) bool {
+	for _, item := range list {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
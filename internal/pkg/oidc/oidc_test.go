@@ -0,0 +1,88 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bico-admin/internal/pkg/oidc"
+	"bico-admin/internal/pkg/oidc/oidctest"
+)
+
+// TestAuthorizationCodeFlow 验证发现文档、PKCE 授权码换取与 userinfo 合并。
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server := oidctest.NewServer("bico", "secret")
+	defer server.Close()
+	server.SetClaims(map[string]interface{}{"sub": "u-1", "preferred_username": "alice"})
+	server.SetUserinfo(map[string]interface{}{"groups": []string{"ops"}})
+
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.Issuer() + "/",
+		ClientID:     "bico",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	})
+	ctx := context.Background()
+	verifier, _ := oidc.RandomString()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.S256Challenge(verifier))
+	if err != nil {
+		t.Fatalf("构建授权地址失败: %v", err)
+	}
+	code, state, err := server.Authorize(authURL)
+	if err != nil || state != "state-1" {
+		t.Fatalf("授权失败: state=%s err=%v", state, err)
+	}
+
+	if _, err := provider.Exchange(ctx, code, "wrong-verifier", "nonce-1"); err == nil {
+		t.Fatal("PKCE 校验码错误时不应换取成功")
+	}
+
+	code, _, _ = server.Authorize(authURL)
+	if _, err := provider.Exchange(ctx, code, verifier, "other-nonce"); !errors.Is(err, oidc.ErrNonceMismatch) {
+		t.Fatalf("nonce 不匹配时应返回 ErrNonceMismatch，实际: %v", err)
+	}
+
+	code, _, _ = server.Authorize(authURL)
+	claims, err := provider.Exchange(ctx, code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("换取令牌失败: %v", err)
+	}
+	if claims.String("sub") != "u-1" || claims.String("preferred_username") != "alice" {
+		t.Fatalf("ID Token 声明不正确: %v", claims)
+	}
+	if groups := claims.Strings("groups"); len(groups) != 1 || groups[0] != "ops" {
+		t.Fatalf("userinfo 声明未合并: %v", claims)
+	}
+}
+
+// TestVerifyIDTokenRejectsInvalidTokens 验证受众、有效期与签名校验。
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	server := oidctest.NewServer("bico", "")
+	defer server.Close()
+	provider := oidc.NewProvider(oidc.Config{Issuer: server.Issuer(), ClientID: "bico"})
+	ctx := context.Background()
+	now := time.Now()
+
+	valid := map[string]interface{}{"iss": server.Issuer(), "aud": "bico", "sub": "u-1", "exp": now.Add(time.Minute).Unix()}
+	token, _ := server.SignIDToken(valid)
+	if _, err := provider.VerifyIDToken(ctx, token); err != nil {
+		t.Fatalf("合法 ID Token 校验失败: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, token[:len(token)-4]+"AAAA"); err == nil {
+		t.Fatal("篡改签名的 ID Token 不应通过校验")
+	}
+
+	cases := map[string]map[string]interface{}{
+		"受众不匹配": {"iss": server.Issuer(), "aud": "other", "sub": "u-1", "exp": now.Add(time.Minute).Unix()},
+		"已过期":   {"iss": server.Issuer(), "aud": "bico", "sub": "u-1", "exp": now.Add(-time.Hour).Unix()},
+		"签发方错误": {"iss": "https://evil.example", "aud": "bico", "sub": "u-1", "exp": now.Add(time.Minute).Unix()},
+	}
+	for name, claims := range cases {
+		token, _ := server.SignIDToken(claims)
+		if _, err := provider.VerifyIDToken(ctx, token); err == nil {
+			t.Fatalf("%s的 ID Token 不应通过校验", name)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 哈希算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// Hasher 密码哈希算法，哈希串自身编码算法与参数。
+type Hasher interface {
+	Hash(plain string) (string, error)
+	Verify(hashed, plain string) bool
+	// NeedsRehash 哈希不是本算法生成，或参数与当前配置不同。
+	NeedsRehash(hashed string) bool
+}
+
+// BcryptHasher bcrypt 哈希，Cost 为 0 时使用 bcrypt.DefaultCost。
+// bcrypt 只接受 72 字节以内的密码，超出时 Hash 返回错误。
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) cost() int {
+	if h.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return h.Cost
+}
+
+// Hash 生成 $2a$ 格式的哈希。
+func (h BcryptHasher) Hash(plain string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.cost())
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify 校验 bcrypt 哈希，成本取自哈希本身。
+func (h BcryptHasher) Verify(hashed, plain string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain)) == nil
+}
+
+// NeedsRehash 非 bcrypt 哈希或成本不同时需要重新生成。
+func (h BcryptHasher) NeedsRehash(hashed string) bool {
+	if !isBcrypt(hashed) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost != h.cost()
+}
+
+func isBcrypt(hashed string) bool {
+	return strings.HasPrefix(hashed, "$2a$") || strings.HasPrefix(hashed, "$2b$") || strings.HasPrefix(hashed, "$2y$")
+}
+
+// argon2id 默认参数，取自 RFC 9106 第二推荐配置。
+const (
+	argon2idPrefix         = "$argon2id$"
+	defaultArgon2Memory    = 64 * 1024
+	defaultArgon2Time      = 3
+	defaultArgon2Threads   = 2
+	argon2SaltLength       = 16
+	argon2KeyLength        = 32
+	argon2MaxEncodedMemory = 4 * 1024 * 1024
+)
+
+// Argon2idHasher argon2id 哈希，Memory 单位为 KiB，零值参数使用默认值。
+//
+// 哈希为 PHC 格式：$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>，salt 与 hash 为无填充 base64。
+type Argon2idHasher struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+// argon2Params 从哈希中解析出的参数。
+type argon2Params struct {
+	version int
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func (h Argon2idHasher) withDefaults() Argon2idHasher {
+	if h.Memory == 0 {
+		h.Memory = defaultArgon2Memory
+	}
+	if h.Time == 0 {
+		h.Time = defaultArgon2Time
+	}
+	if h.Threads == 0 {
+		h.Threads = defaultArgon2Threads
+	}
+	return h
+}
+
+// Hash 使用随机 salt 生成 PHC 格式的哈希。
+func (h Argon2idHasher) Hash(plain string) (string, error) {
+	h = h.withDefaults()
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, h.Time, h.Memory, h.Threads, argon2KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 按哈希中记录的参数重新计算并比较，与当前配置无关。
+func (h Argon2idHasher) Verify(hashed, plain string) bool {
+	params, err := parseArgon2id(hashed)
+	if err != nil || params.version != argon2.Version {
+		return false
+	}
+	key := argon2.IDKey([]byte(plain), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+	return subtle.ConstantTimeCompare(key, params.key) == 1
+}
+
+// NeedsRehash 非 argon2id 哈希、版本或参数与当前配置不同时需要重新生成。
+func (h Argon2idHasher) NeedsRehash(hashed string) bool {
+	params, err := parseArgon2id(hashed)
+	if err != nil {
+		return true
+	}
+	h = h.withDefaults()
+	return params.version != argon2.Version || params.memory != h.Memory || params.time != h.Time ||
+		params.threads != h.Threads || len(params.key) != argon2KeyLength
+}
+
+// parseArgon2id 解析 PHC 格式的 argon2id 哈希。
+func parseArgon2id(hashed string) (*argon2Params, error) {
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return nil, fmt.Errorf("不是 argon2id 哈希")
+	}
+	params := &argon2Params{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &params.version); err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, err
+	}
+	// 参数来自数据库，仍限制上限，避免异常数据导致单次校验耗尽内存。
+	if params.memory == 0 || params.memory > argon2MaxEncodedMemory || params.time == 0 || params.threads == 0 {
+		return nil, fmt.Errorf("argon2id 参数无效")
+	}
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, err
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, err
+	}
+	if len(params.key) == 0 {
+		return nil, fmt.Errorf("argon2id 哈希为空")
+	}
+	return params, nil
+}
+
+// NewHasher 按算法名称创建哈希算法，名称为空时使用 bcrypt。
+func NewHasher(algorithm string, bcryptCost int, argon Argon2idHasher) (Hasher, error) {
+	switch algorithm {
+	case "", AlgorithmBcrypt:
+		return BcryptHasher{Cost: bcryptCost}, nil
+	case AlgorithmArgon2id:
+		return argon, nil
+	}
+	return nil, fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashersVerifyAcrossAlgorithms(t *testing.T) {
+	argon := Argon2idHasher{Memory: 1024, Time: 1, Threads: 1}
+	bcryptHasher := BcryptHasher{Cost: 4}
+	long := strings.Repeat("长密码", 30)
+
+	argonHash, err := argon.Hash(long)
+	if err != nil {
+		t.Fatalf("argon2id 哈希失败: %v", err)
+	}
+	if !strings.HasPrefix(argonHash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("argon2id 哈希格式错误: %s", argonHash)
+	}
+	if !Verify(argonHash, long) || Verify(argonHash, long[:len(long)-1]) {
+		t.Fatal("argon2id 应校验完整密码")
+	}
+	if _, err := bcryptHasher.Hash(long); err == nil {
+		t.Fatal("bcrypt 不应接受超过 72 字节的密码")
+	}
+
+	bcryptHash, err := bcryptHasher.Hash("secret-password")
+	if err != nil {
+		t.Fatalf("bcrypt 哈希失败: %v", err)
+	}
+	if !Verify(bcryptHash, "secret-password") || Verify(bcryptHash, "other") {
+		t.Fatal("bcrypt 哈希校验结果错误")
+	}
+	if Verify("plain-text", "plain-text") || Verify("$argon2id$v=19$m=0,t=1,p=1$AAAA$AAAA", "") {
+		t.Fatal("无法识别或参数无效的哈希不应通过")
+	}
+
+	cases := []struct {
+		hasher Hasher
+		hash   string
+		rehash bool
+	}{
+		{argon, argonHash, false},
+		{Argon2idHasher{Memory: 2048, Time: 1, Threads: 1}, argonHash, true},
+		{argon, bcryptHash, true},
+		{bcryptHasher, bcryptHash, false},
+		{BcryptHasher{Cost: 5}, bcryptHash, true},
+		{bcryptHasher, argonHash, true},
+	}
+	for i, tc := range cases {
+		if got := tc.hasher.NeedsRehash(tc.hash); got != tc.rehash {
+			t.Fatalf("用例 %d: NeedsRehash=%v, 期望 %v", i, got, tc.rehash)
+		}
+	}
+
+	if _, err := NewHasher("md5", 0, Argon2idHasher{}); err == nil {
+		t.Fatal("不支持的算法应返回错误")
+	}
+}
@@ -1,20 +1,34 @@
 package password
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
+// defaultHasher 生成新哈希使用的算法，启动时由配置设置。
+var defaultHasher Hasher = BcryptHasher{}
+
+// SetHasher 设置生成新哈希使用的算法，已有哈希仍按其自身编码的算法校验。
+func SetHasher(h Hasher) {
+	defaultHasher = h
+}
+
 // Hash 加密密码
 func Hash(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedBytes), nil
+	return defaultHasher.Hash(password)
 }
 
-// Verify 验证密码
+// Verify 验证密码，按哈希前缀识别算法，因此切换算法后旧哈希仍可校验。
 func Verify(hashedPassword, plainPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
-	return err == nil
+	switch {
+	case strings.HasPrefix(hashedPassword, argon2idPrefix):
+		return Argon2idHasher{}.Verify(hashedPassword, plainPassword)
+	case isBcrypt(hashedPassword):
+		return BcryptHasher{}.Verify(hashedPassword, plainPassword)
+	}
+	return false
+}
+
+// NeedsRehash 判断哈希的算法或参数是否与当前配置不一致，登录成功后应重新生成。
+func NeedsRehash(hashedPassword string) bool {
+	return defaultHasher.NeedsRehash(hashedPassword)
 }
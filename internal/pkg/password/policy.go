@@ -0,0 +1,102 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrTooCommon 密码位于常见密码黑名单中。
+var ErrTooCommon = errors.New("密码过于常见，请更换")
+
+// commonPasswords 内置常见弱密码，比较时忽略大小写。
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "111111", "000000", "888888", "666666",
+	"123123", "654321", "987654321", "11111111", "88888888", "12341234", "1q2w3e4r", "1qaz2wsx",
+	"qwerty", "qwerty123", "qwertyuiop", "asdfghjkl", "zxcvbnm", "abc123", "abcd1234", "a1b2c3d4",
+	"password", "password1", "password123", "passw0rd", "p@ssw0rd", "p@ssword", "iloveyou", "welcome",
+	"welcome1", "letmein", "admin", "admin123", "admin888", "admin@123", "administrator", "root",
+	"root123", "test", "test123", "guest", "changeme", "default", "secret", "woaini1314", "5201314",
+}
+
+// Policy 密码策略，零值表示不做任何限制。
+//
+// History 为禁止复用的最近密码个数，MaxAge 为密码最长使用期限（0 表示永不过期），
+// 二者由调用方结合存储实现，Validate 只检查密码本身。
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	DenyCommon    bool
+	DenyList      []string
+	History       int
+	MaxAge        time.Duration
+}
+
+// Validate 校验密码是否满足策略，username 非空时禁止密码与用户名相同。
+func (p Policy) Validate(plain, username string) error {
+	if p.MinLength > 0 && len([]rune(plain)) < p.MinLength {
+		return fmt.Errorf("密码长度不能少于 %d 位", p.MinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	var missing []string
+	if p.RequireUpper && !hasUpper {
+		missing = append(missing, "大写字母")
+	}
+	if p.RequireLower && !hasLower {
+		missing = append(missing, "小写字母")
+	}
+	if p.RequireDigit && !hasDigit {
+		missing = append(missing, "数字")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("密码必须包含%s", strings.Join(missing, "、"))
+	}
+
+	lower := strings.ToLower(plain)
+	if username != "" && lower == strings.ToLower(username) {
+		return errors.New("密码不能与用户名相同")
+	}
+	if p.DenyCommon && containsFold(commonPasswords, lower) {
+		return ErrTooCommon
+	}
+	if containsFold(p.DenyList, lower) {
+		return ErrTooCommon
+	}
+	return nil
+}
+
+// Expired 判断自 changedAt 起密码是否已超过最长使用期限。
+func (p Policy) Expired(changedAt time.Time, now time.Time) bool {
+	return p.MaxAge > 0 && now.Sub(changedAt) > p.MaxAge
+}
+
+// containsFold 忽略大小写判断 list 中是否包含 lower。
+func containsFold(list []string, lower string) bool {
+	for _, item := range list {
+		if strings.ToLower(strings.TrimSpace(item)) == lower {
+			return true
+		}
+	}
+	return false
+}
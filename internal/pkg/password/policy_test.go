@@ -0,0 +1,46 @@
+package password
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := Policy{MinLength: 8, RequireLower: true, RequireDigit: true, DenyCommon: true, DenyList: []string{"Bico2024"}}
+
+	cases := []struct {
+		plain string
+		ok    bool
+	}{
+		{"abc1", false},
+		{"abcdefgh", false},
+		{"password1", false},
+		{"bico2024", false},
+		{"tester123", false},
+		{"blue-sky-42", true},
+	}
+	for _, tc := range cases {
+		err := policy.Validate(tc.plain, "tester123")
+		if (err == nil) != tc.ok {
+			t.Fatalf("Validate(%q) err=%v, 期望通过=%v", tc.plain, err, tc.ok)
+		}
+	}
+
+	if err := (Policy{}).Validate("1", ""); err != nil {
+		t.Fatalf("零值策略不应限制密码: %v", err)
+	}
+}
+
+func TestPolicyExpired(t *testing.T) {
+	now := time.Now()
+	policy := Policy{MaxAge: 24 * time.Hour}
+	if policy.Expired(now.Add(-time.Hour), now) {
+		t.Fatalf("未超过期限不应过期")
+	}
+	if !policy.Expired(now.Add(-48*time.Hour), now) {
+		t.Fatalf("超过期限应过期")
+	}
+	if (Policy{}).Expired(now.Add(-10000*time.Hour), now) {
+		t.Fatalf("未配置期限时永不过期")
+	}
+}
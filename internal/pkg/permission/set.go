@@ -0,0 +1,101 @@
+// Package permission 编译与判定权限集合。
+//
+// 权限 key 以冒号分段，如 system:admin_user:list。授权项可以是具体 key，也可以是以 :* 结尾的通配符，
+// 如 system:* 匹配 system 下的全部权限，单独的 * 匹配全部权限。拒绝项语法相同，且优先于授权项。
+package permission
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Wildcard 通配符
+const Wildcard = "*"
+
+// Set 编译后的权限集合，判定只做按段前缀的哈希查找，与授权项数量无关。
+// 编译后不再修改，可在多个请求间共享。
+type Set struct {
+	Grants []string `json:"grants"`
+	Denies []string `json:"denies"`
+
+	grants map[string]struct{}
+	denies map[string]struct{}
+}
+
+// Compile 编译授权项与拒绝项，忽略空项并去重。
+func Compile(grants, denies []string) *Set {
+	s := &Set{grants: toSet(grants), denies: toSet(denies)}
+	s.Grants = slices.Sorted(maps.Keys(s.grants))
+	s.Denies = slices.Sorted(maps.Keys(s.denies))
+	return s
+}
+
+// Allows 判断是否拥有权限：未命中任何拒绝项且命中至少一个授权项。
+func (s *Set) Allows(permission string) bool {
+	if s == nil || permission == "" {
+		return false
+	}
+	return !matches(s.denies, permission) && matches(s.grants, permission)
+}
+
+// Denied 判断权限是否命中拒绝项。
+func (s *Set) Denied(permission string) bool {
+	return s != nil && matches(s.denies, permission)
+}
+
+// Expand 从全部权限 key 中筛选出允许的具体权限，保持 all 的顺序。
+func (s *Set) Expand(all []string) []string {
+	result := make([]string, 0, len(all))
+	for _, key := range all {
+		if s.Allows(key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// Match 判断单个授权项或拒绝项是否匹配权限。
+func Match(pattern, permission string) bool {
+	if pattern == Wildcard || pattern == permission {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, ":"+Wildcard)
+	return ok && strings.HasPrefix(permission, prefix+":")
+}
+
+// IsWildcard 判断是否为通配符项。
+func IsWildcard(pattern string) bool {
+	return pattern == Wildcard || strings.HasSuffix(pattern, ":"+Wildcard)
+}
+
+// matches 依次查找权限本身、各级前缀通配符与全局通配符，
+// 如 a:b:c 查找 a:b:c、a:b:*、a:*、*。
+func matches(set map[string]struct{}, permission string) bool {
+	if len(set) == 0 {
+		return false
+	}
+	if _, ok := set[permission]; ok {
+		return true
+	}
+	for i := len(permission) - 1; i > 0; i-- {
+		if permission[i] != ':' {
+			continue
+		}
+		if _, ok := set[permission[:i+1]+Wildcard]; ok {
+			return true
+		}
+	}
+	_, ok := set[Wildcard]
+	return ok
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = struct{}{}
+		}
+	}
+	return set
+}
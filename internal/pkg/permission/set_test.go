@@ -0,0 +1,48 @@
+package permission
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetAllows(t *testing.T) {
+	set := Compile(
+		[]string{"system:*", "demo:item:list", " ", "demo:item:list"},
+		[]string{"system:admin_role:*", "system:admin_user:delete"},
+	)
+	cases := []struct {
+		permission string
+		allowed    bool
+	}{
+		{"system:admin_user:list", true},
+		{"system:manage", true},
+		{"system", false},
+		{"systemx:list", false},
+		{"system:admin_user:delete", false},
+		{"system:admin_role:list", false},
+		{"demo:item:list", true},
+		{"demo:item:create", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := set.Allows(tc.permission); got != tc.allowed {
+			t.Fatalf("Allows(%q)=%v, 期望 %v", tc.permission, got, tc.allowed)
+		}
+	}
+	if !slices.Equal(set.Grants, []string{"demo:item:list", "system:*"}) {
+		t.Fatalf("授权项应去重排序: %v", set.Grants)
+	}
+
+	all := Compile([]string{Wildcard}, []string{"demo:*"})
+	expanded := all.Expand([]string{"system:manage", "demo:item:list", "other:x"})
+	if !slices.Equal(expanded, []string{"system:manage", "other:x"}) {
+		t.Fatalf("展开结果错误: %v", expanded)
+	}
+	var empty *Set
+	if empty.Allows("system:manage") || Compile(nil, nil).Allows("system:manage") {
+		t.Fatal("空集合不应允许任何权限")
+	}
+	if !Match("system:*", "system:a:b") || Match("system:*", "system") || !IsWildcard("a:*") || IsWildcard("a:b") {
+		t.Fatal("通配符匹配错误")
+	}
+}
@@ -52,10 +52,10 @@ func ApplyCRUDModules(doc string, modules []CRUDModule) (string, error) {
 
 // applyCRUDModule 将单个模块的路由和类型定义写入 Swagger spec。
 func applyCRUDModule(paths map[string]interface{}, definitions map[string]interface{}, module CRUDModule) {
-	modelName := schemaName(module.Config.Swagger.Model)
-	listReqName := schemaName(module.Config.Swagger.ListRequest)
-	createReqName := schemaName(module.Config.Swagger.CreateRequest)
-	updateReqName := schemaName(module.Config.Swagger.UpdateRequest)
+	modelName := SchemaName(module.Config.Swagger.Model)
+	listReqName := SchemaName(module.Config.Swagger.ListRequest)
+	createReqName := SchemaName(module.Config.Swagger.CreateRequest)
+	updateReqName := SchemaName(module.Config.Swagger.UpdateRequest)
 
 	addDefinition(definitions, module.Config.Swagger.Model)
 	addDefinition(definitions, module.Config.Swagger.ListRequest)
@@ -235,7 +235,7 @@ func responseSchemaForRoute(route crud.Route, modelName string) map[string]inter
 
 // addDefinition 将 Go 类型转换为 Swagger definition。
 func addDefinition(definitions map[string]interface{}, sample interface{}) {
-	name := schemaName(sample)
+	name := SchemaName(sample)
 	if name == "" {
 		return
 	}
@@ -284,6 +284,9 @@ func addSharedDefinitions(definitions map[string]interface{}) {
 
 // schemaForStruct 将结构体字段转换为 Swagger object schema。
 func schemaForStruct(t reflect.Type) map[string]interface{} {
+	expandingTypes[t] = true
+	defer delete(expandingTypes, t)
+
 	properties := make(map[string]interface{})
 	required := make([]string, 0)
 	collectStructProperties(t, properties, &required)
@@ -320,7 +323,12 @@ func collectStructProperties(t reflect.Type, properties map[string]interface{},
 			continue
 		}
 
-		properties[name] = schemaFromType(field.Type)
+		property := schemaFromType(field.Type)
+		if field.Type.Kind() == reflect.Pointer {
+			// 指针字段区分“未传”和“零值”，标记可空供 OpenAPI 3.1 转换为 null 联合类型。
+			property["x-nullable"] = true
+		}
+		properties[name] = property
 		if hasRequiredBinding(field) {
 			*required = append(*required, name)
 		}
@@ -340,6 +348,13 @@ func schemaFromType(t reflect.Type) map[string]interface{} {
 		if isTimeLikeType(t) {
 			return map[string]interface{}{"type": swaggerTypeString, "format": "date-time"}
 		}
+		if expandingTypes[t] {
+			// 自引用类型（例如树节点的 children）不再展开，引用已注册的 definition。
+			if name := SchemaName(reflect.New(t).Interface()); typeRegistry[name] == t {
+				return refSchema(name)
+			}
+			return map[string]interface{}{"type": swaggerTypeObject}
+		}
 		return schemaForStruct(t)
 	}
 
@@ -388,10 +403,16 @@ func routeSecurity(routes []crud.Route) []map[string][]string {
 	return nil
 }
 
-// swaggerPath 拼接 gin 路由路径并转换 :id 为 Swagger path 参数。
+// swaggerPath 拼接 gin 路由路径并将 :param 转换为 Swagger path 参数。
 func swaggerPath(basePath string, group string, path string) string {
 	full := "/" + strings.Trim(strings.Trim(basePath, "/")+"/"+strings.Trim(group, "/")+"/"+strings.Trim(path, "/"), "/")
-	full = strings.ReplaceAll(full, "/:id", "/{id}")
+	segments := strings.Split(full, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	full = strings.Join(segments, "/")
 	if full == "/" {
 		return full
 	}
@@ -482,8 +503,8 @@ func fieldDescription(field reflect.StructField) string {
 	return field.Name
 }
 
-// schemaName 生成稳定的 definition 名称。
-func schemaName(sample interface{}) string {
+// SchemaName 生成稳定的 definition 名称，格式为 "包名.类型名"。
+func SchemaName(sample interface{}) string {
 	if sample == nil {
 		return ""
 	}
@@ -516,6 +537,9 @@ func isTimeLikeType(t reflect.Type) bool {
 
 var typeRegistry = map[string]reflect.Type{}
 
+// expandingTypes 记录正在展开的结构体，用于识别自引用。
+var expandingTypes = map[reflect.Type]bool{}
+
 // typeBySchemaName 读取已注册 definition 对应的 Go 类型。
 func typeBySchemaName(name string) reflect.Type {
 	return typeRegistry[name]
@@ -0,0 +1,374 @@
+package swagger
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	openAPIVersion      = "3.1.0"
+	errorResponseSchema = "swagger.ErrorResponse"
+	definitionRefPrefix = "#/definitions/"
+	componentRefPrefix  = "#/components/schemas/"
+	defaultMediaType    = "application/json"
+)
+
+// envelopeSchemas 统一响应包装对应的 definition 名称。
+var envelopeSchemas = map[string]struct{}{
+	"handler.adminResponse": {},
+	"swagger.Response":      {},
+	"swagger.PageResponse":  {},
+}
+
+// ConvertToOpenAPI31 将增强后的 Swagger 2.0 JSON 转换为 OpenAPI 3.1 JSON。
+//
+// 说明：
+// - 输入应为 ApplyCRUDModules 处理后的文档，确保 swag 注释和 CRUD 模块路由都已包含
+// - 统一响应包装的 200 响应使用 oneOf 区分成功数据与业务错误（code 非 0）
+// - 带 x-nullable 的字段转换为 3.1 的 null 联合类型
+// - securityDefinitions 转换为 components.securitySchemes
+func ConvertToOpenAPI31(doc string) (string, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return "", err
+	}
+
+	out := map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info":    spec["info"],
+		"paths":   convertPaths(spec),
+	}
+	if basePath, _ := spec["basePath"].(string); basePath != "" {
+		// 使用相对地址，避免把文档生成时的 host 固化进部署环境。
+		out["servers"] = []map[string]interface{}{{"url": basePath}}
+	}
+	if tags, ok := spec["tags"]; ok {
+		out["tags"] = tags
+	}
+
+	schemas := make(map[string]interface{})
+	if definitions, ok := spec["definitions"].(map[string]interface{}); ok {
+		for name, definition := range definitions {
+			schemas[name] = convertSchema(definition)
+		}
+	}
+	schemas[errorResponseSchema] = map[string]interface{}{
+		"type":     swaggerTypeObject,
+		"required": []string{"code", "msg"},
+		"properties": map[string]interface{}{
+			"code": map[string]interface{}{"type": swaggerTypeInteger, "description": "业务错误码，非 0 表示失败"},
+			"msg":  map[string]interface{}{"type": swaggerTypeString, "description": "错误信息"},
+		},
+	}
+	components := map[string]interface{}{"schemas": schemas}
+	if securitySchemes := convertSecurityDefinitions(spec); len(securitySchemes) > 0 {
+		components["securitySchemes"] = securitySchemes
+	}
+	out["components"] = components
+
+	converted, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(converted), nil
+}
+
+// convertPaths 转换全部路径和 operation。
+func convertPaths(spec map[string]interface{}) map[string]interface{} {
+	consumes := stringSlice(spec["consumes"])
+	produces := stringSlice(spec["produces"])
+
+	paths := make(map[string]interface{})
+	source, _ := spec["paths"].(map[string]interface{})
+	for path, rawItem := range source {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		converted := make(map[string]interface{}, len(item))
+		for method, rawOperation := range item {
+			operation, ok := rawOperation.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			converted[method] = convertOperation(operation, consumes, produces)
+		}
+		paths[path] = converted
+	}
+	return paths
+}
+
+// convertOperation 转换单个 operation，body/formData 参数合并为 requestBody。
+func convertOperation(operation map[string]interface{}, defaultConsumes []string, defaultProduces []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, key := range []string{"tags", "summary", "description", "operationId", "security", "deprecated"} {
+		if value, ok := operation[key]; ok {
+			out[key] = value
+		}
+	}
+
+	consumes := stringSlice(operation["consumes"])
+	if len(consumes) == 0 {
+		consumes = defaultConsumes
+	}
+	produces := stringSlice(operation["produces"])
+	if len(produces) == 0 {
+		produces = defaultProduces
+	}
+	if len(produces) == 0 {
+		produces = []string{defaultMediaType}
+	}
+
+	parameters := make([]interface{}, 0)
+	formProperties := make(map[string]interface{})
+	formRequired := make([]string, 0)
+	rawParameters, _ := operation["parameters"].([]interface{})
+	for _, rawParameter := range rawParameters {
+		parameter, ok := rawParameter.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := parameter["name"].(string)
+		required, _ := parameter["required"].(bool)
+		switch parameter["in"] {
+		case "body":
+			mediaTypes := consumes
+			if len(mediaTypes) == 0 {
+				mediaTypes = []string{defaultMediaType}
+			}
+			out["requestBody"] = map[string]interface{}{
+				"description": parameter["description"],
+				"required":    required,
+				"content":     mediaContent(mediaTypes, convertSchema(parameter["schema"])),
+			}
+		case "formData":
+			formProperties[name] = parameterSchema(parameter)
+			if required {
+				formRequired = append(formRequired, name)
+			}
+		default:
+			converted := map[string]interface{}{
+				"name":     name,
+				"in":       parameter["in"],
+				"required": required,
+				"schema":   parameterSchema(parameter),
+			}
+			if description, ok := parameter["description"]; ok {
+				converted["description"] = description
+			}
+			parameters = append(parameters, converted)
+		}
+	}
+	if len(parameters) > 0 {
+		out["parameters"] = parameters
+	}
+	if len(formProperties) > 0 {
+		formSchema := map[string]interface{}{"type": swaggerTypeObject, "properties": formProperties}
+		if len(formRequired) > 0 {
+			sort.Strings(formRequired)
+			formSchema["required"] = formRequired
+		}
+		mediaType := "multipart/form-data"
+		if len(consumes) > 0 {
+			mediaType = consumes[0]
+		}
+		out["requestBody"] = map[string]interface{}{
+			"required": len(formRequired) > 0,
+			"content":  mediaContent([]string{mediaType}, formSchema),
+		}
+	}
+
+	out["responses"] = convertResponses(operation["responses"], produces)
+	return out
+}
+
+// convertResponses 转换响应定义，统一响应包装追加错误分支。
+func convertResponses(raw interface{}, produces []string) map[string]interface{} {
+	responses := make(map[string]interface{})
+	source, _ := raw.(map[string]interface{})
+	for code, rawResponse := range source {
+		response, ok := rawResponse.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		description, _ := response["description"].(string)
+		if description == "" {
+			description = "OK"
+		}
+		converted := map[string]interface{}{"description": description}
+
+		if schema, ok := response["schema"].(map[string]interface{}); ok {
+			if schema["type"] == "file" {
+				// 文件下载响应没有统一包装，直接声明二进制内容。
+				converted["content"] = mediaContent(produces, map[string]interface{}{"type": swaggerTypeString, "format": "binary"})
+			} else {
+				body := convertSchema(schema)
+				if code == "200" && usesEnvelope(schema) {
+					// 业务错误同样返回 HTTP 200，客户端需要通过 code 区分成功与失败。
+					body = map[string]interface{}{
+						"oneOf": []interface{}{body, map[string]interface{}{"$ref": componentRefPrefix + errorResponseSchema}},
+					}
+				}
+				converted["content"] = mediaContent(produces, body)
+			}
+		}
+		responses[code] = converted
+	}
+	return responses
+}
+
+// usesEnvelope 判断响应 schema 是否基于统一响应包装。
+func usesEnvelope(schema map[string]interface{}) bool {
+	if ref, ok := schema["$ref"].(string); ok {
+		_, found := envelopeSchemas[strings.TrimPrefix(ref, definitionRefPrefix)]
+		return found
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, item := range allOf {
+			if sub, ok := item.(map[string]interface{}); ok && usesEnvelope(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertSchema 递归转换 schema：改写 $ref、处理 x-nullable 与 file 类型。
+func convertSchema(raw interface{}) map[string]interface{} {
+	schema, ok := raw.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	out := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "$ref":
+			ref, _ := value.(string)
+			out[key] = componentRefPrefix + strings.TrimPrefix(ref, definitionRefPrefix)
+		case "properties":
+			properties := make(map[string]interface{})
+			if source, ok := value.(map[string]interface{}); ok {
+				for name, property := range source {
+					properties[name] = convertSchema(property)
+				}
+			}
+			out[key] = properties
+		case "items", "additionalProperties":
+			if sub, ok := value.(map[string]interface{}); ok {
+				out[key] = convertSchema(sub)
+			} else {
+				out[key] = value
+			}
+		case "allOf", "anyOf", "oneOf":
+			items := make([]interface{}, 0)
+			if source, ok := value.([]interface{}); ok {
+				for _, item := range source {
+					items = append(items, convertSchema(item))
+				}
+			}
+			out[key] = items
+		case "x-nullable":
+			// 3.1 不再支持 nullable 关键字，下面统一转换为 null 联合类型。
+		default:
+			out[key] = value
+		}
+	}
+
+	if out["type"] == "file" {
+		out["type"] = swaggerTypeString
+		out["format"] = "binary"
+	}
+	if nullable, _ := schema["x-nullable"].(bool); nullable {
+		return nullableSchema(out)
+	}
+	return out
+}
+
+// nullableSchema 将 schema 标记为可空。
+func nullableSchema(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	// $ref 或组合类型无法直接追加 null，使用 oneOf 包装。
+	return map[string]interface{}{
+		"oneOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+	}
+}
+
+// parameterSchema 从 Swagger 2.0 参数中提取 schema 字段。
+func parameterSchema(parameter map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	for _, key := range []string{"type", "format", "items", "enum", "default", "minimum", "maximum", "x-nullable"} {
+		if value, ok := parameter[key]; ok {
+			schema[key] = value
+		}
+	}
+	return convertSchema(schema)
+}
+
+// convertSecurityDefinitions 转换安全定义。
+//
+// 说明：后台 token 通过 Authorization: Bearer {token} 传递，3.1 中声明为 http bearer 便于客户端生成器自动拼接前缀。
+func convertSecurityDefinitions(spec map[string]interface{}) map[string]interface{} {
+	definitions, _ := spec["securityDefinitions"].(map[string]interface{})
+	schemes := make(map[string]interface{}, len(definitions))
+	for name, raw := range definitions {
+		definition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scheme := make(map[string]interface{})
+		if description, ok := definition["description"]; ok {
+			scheme["description"] = description
+		}
+		switch definition["type"] {
+		case "apiKey":
+			if definition["in"] == "header" && strings.EqualFold(stringValue(definition["name"]), "Authorization") {
+				scheme["type"] = "http"
+				scheme["scheme"] = "bearer"
+				scheme["bearerFormat"] = "JWT"
+			} else {
+				scheme["type"] = "apiKey"
+				scheme["in"] = definition["in"]
+				scheme["name"] = definition["name"]
+			}
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		default:
+			scheme["type"] = definition["type"]
+		}
+		schemes[name] = scheme
+	}
+	return schemes
+}
+
+// mediaContent 为每个媒体类型生成相同 schema 的 content。
+func mediaContent(mediaTypes []string, schema map[string]interface{}) map[string]interface{} {
+	content := make(map[string]interface{}, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = map[string]interface{}{"schema": schema}
+	}
+	return content
+}
+
+// stringSlice 读取 JSON 字符串数组。
+func stringSlice(raw interface{}) []string {
+	items, _ := raw.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// stringValue 读取字符串值，类型不符时返回空串。
+func stringValue(raw interface{}) string {
+	value, _ := raw.(string)
+	return value
+}
@@ -0,0 +1,82 @@
+package swagger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bico-admin/internal/pkg/crud"
+)
+
+type testOpenAPIModel struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Enabled *bool  `json:"enabled"`
+}
+
+const testSwaggerDoc = `{
+	"swagger": "2.0",
+	"info": {"title": "test", "version": "1.0"},
+	"basePath": "/admin-api",
+	"securityDefinitions": {"BearerAuth": {"type": "apiKey", "in": "header", "name": "Authorization"}},
+	"paths": {
+		"/upload": {"post": {
+			"consumes": ["multipart/form-data"],
+			"parameters": [{"name": "file", "in": "formData", "type": "file", "required": true}],
+			"responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/swagger.Response"}}}
+		}}
+	},
+	"definitions": {}
+}`
+
+// TestConvertToOpenAPI31 验证 CRUD 文档转换后的关键结构。
+func TestConvertToOpenAPI31(t *testing.T) {
+	perms := crud.NewCRUDPerms("test", "item", "测试")
+	enhanced, err := ApplyCRUDModules(testSwaggerDoc, []CRUDModule{{Config: crud.ModuleConfig{
+		Name:        "item",
+		Group:       "/items",
+		Description: "测试",
+		Routes:      perms.Routes(),
+		Swagger:     crud.SwaggerConfig{Model: testOpenAPIModel{}, UpdateRequest: testOpenAPIModel{}},
+	}}})
+	if err != nil {
+		t.Fatalf("增强 Swagger 文档失败: %v", err)
+	}
+
+	converted, err := ConvertToOpenAPI31(enhanced)
+	if err != nil {
+		t.Fatalf("转换 OpenAPI 3.1 失败: %v", err)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(converted), &spec); err != nil {
+		t.Fatalf("转换结果不是合法 JSON: %v", err)
+	}
+
+	if spec["openapi"] != "3.1.0" {
+		t.Fatalf("版本号错误: %v", spec["openapi"])
+	}
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	enabled := schemas["swagger.testOpenAPIModel"].(map[string]interface{})["properties"].(map[string]interface{})["enabled"].(map[string]interface{})
+	if types, ok := enabled["type"].([]interface{}); !ok || len(types) != 2 || types[1] != "null" {
+		// 指针字段必须转换为 null 联合类型。
+		t.Fatalf("指针字段未标记为可空: %v", enabled)
+	}
+
+	scheme := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})["BearerAuth"].(map[string]interface{})
+	if scheme["type"] != "http" || scheme["scheme"] != "bearer" {
+		t.Fatalf("安全定义转换错误: %v", scheme)
+	}
+
+	get := spec["paths"].(map[string]interface{})["/items/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	schema := get["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if oneOf, ok := schema["oneOf"].([]interface{}); !ok || len(oneOf) != 2 {
+		// 统一响应包装需要同时描述成功与业务错误两种形态。
+		t.Fatalf("统一响应未使用 oneOf: %v", schema)
+	}
+
+	upload := spec["paths"].(map[string]interface{})["/upload"].(map[string]interface{})["post"].(map[string]interface{})
+	body := upload["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["multipart/form-data"].(map[string]interface{})["schema"].(map[string]interface{})
+	file := body["properties"].(map[string]interface{})["file"].(map[string]interface{})
+	if file["format"] != "binary" {
+		t.Fatalf("formData 文件参数未转换为 requestBody: %v", body)
+	}
+}
@@ -0,0 +1,51 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateAndValidate 验证生成的密钥可以校验动态码，并容忍一个窗口的时钟偏差。
+func TestGenerateAndValidate(t *testing.T) {
+	key, err := Generate("Bico Admin", "admin")
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	if !strings.HasPrefix(key.Image, "data:image/png;base64,") {
+		t.Fatalf("二维码格式不正确: %.40s", key.Image)
+	}
+	if !strings.HasPrefix(key.URL, "otpauth://totp/") {
+		t.Fatalf("otpauth 地址不正确: %s", key.URL)
+	}
+
+	now := time.Now()
+	code, err := GenerateCode(key.Secret, now)
+	if err != nil {
+		t.Fatalf("生成动态码失败: %v", err)
+	}
+	if !Validate(code, key.Secret, now.Add(30*time.Second)) {
+		t.Fatalf("相邻窗口的动态码应通过校验")
+	}
+	if Validate(code, key.Secret, now.Add(5*time.Minute)) {
+		t.Fatalf("过期动态码不应通过校验")
+	}
+}
+
+// TestRecoveryCodes 验证恢复码唯一且摘要忽略格式差异。
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := RecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("生成恢复码失败: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 21 || seen[code] {
+			t.Fatalf("恢复码格式不正确或重复: %s", code)
+		}
+		seen[code] = true
+	}
+	if HashRecoveryCode(codes[0]) != HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))) {
+		t.Fatalf("恢复码摘要应忽略大小写与分隔符")
+	}
+}
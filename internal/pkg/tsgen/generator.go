@@ -0,0 +1,489 @@
+package tsgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/swagger"
+)
+
+const fileHeader = "// 此文件由 bico-admin gen ts 生成，请勿手动修改。\n\n"
+
+// 生成的文件名
+const (
+	TypesFile       = "types.ts"
+	APIFile         = "api.ts"
+	PermissionsFile = "permissions.ts"
+)
+
+var (
+	pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+	nonWordPattern   = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	methodOrder      = []string{"get", "post", "put", "patch", "delete"}
+	crudHandlers     = map[string]struct{}{"List": {}, "Get": {}, "Create": {}, "Update": {}, "Delete": {}, "DeleteBatch": {}}
+)
+
+// crudRoute 记录文档路径对应的 CRUD 模块路由。
+type crudRoute struct {
+	config crud.ModuleConfig
+	route  crud.Route
+}
+
+// generator 保存一次生成过程中的文档与命名信息。
+type generator struct {
+	definitions map[string]interface{}
+	paths       map[string]interface{}
+	names       map[string]string
+	inputs      map[string]struct{}
+	routes      map[string]crudRoute
+	used        map[string]struct{}
+}
+
+// Generate 根据增强后的 Swagger 文档与 CRUD 模块生成 TypeScript 客户端。
+//
+// 说明：
+// - doc 应为 ApplyCRUDModules 处理后的文档，swag 注释与声明式 CRUD 路由都会生成接口函数
+// - 标准 CRUD 路由按 getXxxList/getXxx/createXxx/updateXxx/deleteXxx/deleteXxxBatch 命名，其余接口按 方法+路径 命名
+// - basePermissions 为不属于 CRUD 模块的基础权限，与模块权限树一起导出为常量
+//
+// 返回值以文件名为 key，内容为完整的 TypeScript 源码。
+func Generate(doc string, modules []crud.Module, basePermissions []crud.Permission) (map[string]string, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		inputs: make(map[string]struct{}),
+		routes: make(map[string]crudRoute),
+		used:   make(map[string]struct{}),
+	}
+	g.definitions, _ = spec["definitions"].(map[string]interface{})
+	g.paths, _ = spec["paths"].(map[string]interface{})
+	g.names = typeNames(g.definitions)
+
+	configs := make([]crud.ModuleConfig, 0, len(modules))
+	for _, module := range modules {
+		if module == nil {
+			continue
+		}
+		config := module.ModuleConfig()
+		if config.Group == "" {
+			continue
+		}
+		configs = append(configs, config)
+		for _, route := range config.Routes {
+			g.routes[routeKey(route.Method, docPath(config.Group, route.Path))] = crudRoute{config: config, route: route}
+		}
+		for _, sample := range []interface{}{config.Swagger.ListRequest, config.Swagger.CreateRequest, config.Swagger.UpdateRequest} {
+			if name := swagger.SchemaName(sample); name != "" {
+				g.inputs[name] = struct{}{}
+			}
+		}
+	}
+	g.collectBodyInputs()
+
+	permissions := append([]crud.Permission{}, basePermissions...)
+	for _, config := range configs {
+		permissions = append(permissions, config.Permissions...)
+	}
+
+	return map[string]string{
+		TypesFile:       g.typesFile(configs),
+		APIFile:         g.apiFile(),
+		PermissionsFile: permissionsFile(permissions),
+	}, nil
+}
+
+// collectBodyInputs 标记作为 body 参数使用的 definition，生成时按请求参数处理可选字段。
+func (g *generator) collectBodyInputs() {
+	for _, rawItem := range g.paths {
+		item, _ := rawItem.(map[string]interface{})
+		for _, rawOperation := range item {
+			operation, _ := rawOperation.(map[string]interface{})
+			parameters, _ := operation["parameters"].([]interface{})
+			for _, rawParameter := range parameters {
+				parameter, _ := rawParameter.(map[string]interface{})
+				if parameter["in"] != "body" {
+					continue
+				}
+				schema, _ := parameter["schema"].(map[string]interface{})
+				if name := refName(schema); name != "" {
+					g.inputs[name] = struct{}{}
+				}
+			}
+		}
+	}
+}
+
+// typesFile 生成全部 definition 对应的类型声明。
+func (g *generator) typesFile(configs []crud.ModuleConfig) string {
+	var b strings.Builder
+	b.WriteString(fileHeader)
+	b.WriteString("/** 分页参数 */\nexport interface PageParams {\n  page?: number;\n  pageSize?: number;\n  sortField?: string;\n  /** ascend 为升序，其余为降序 */\n  sortOrder?: string;\n}\n\n")
+	b.WriteString("/** 分页结果 */\nexport interface PageResult<T> {\n  list: T[];\n  total: number;\n}\n")
+
+	definitions := make([]string, 0, len(g.names))
+	for name := range g.names {
+		definitions = append(definitions, name)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return g.names[definitions[i]] < g.names[definitions[j]]
+	})
+
+	for _, name := range definitions {
+		schema, _ := g.definitions[name].(map[string]interface{})
+		_, input := g.inputs[name]
+		b.WriteString("\n")
+		if description, _ := schema["description"].(string); description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", description)
+		}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+			fmt.Fprintf(&b, "export interface %s {\n", g.names[name])
+			g.writeProperties(&b, schema, "  ", input)
+			b.WriteString("}\n")
+			continue
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n", g.names[name], g.tsType(schema, "", input))
+	}
+
+	// 列表参数 = 分页参数 + 模块筛选条件，与 CRUDHandler.List 的绑定规则一致。
+	for _, config := range configs {
+		if config.Swagger.Model == nil {
+			continue
+		}
+		listType := "PageParams"
+		if name, ok := g.names[swagger.SchemaName(config.Swagger.ListRequest)]; ok {
+			listType += " & " + name
+		}
+		fmt.Fprintf(&b, "\n/** %s列表参数 */\nexport type %sListParams = %s;\n", moduleLabel(config), pascalCase(config.Name), listType)
+	}
+	return b.String()
+}
+
+// apiFunc 描述一个生成的接口函数。
+type apiFunc struct {
+	name       string
+	summary    string
+	permission string
+	method     string
+	url        string
+	params     []string
+	options    []string
+	result     string
+}
+
+// apiFile 生成接口函数。
+func (g *generator) apiFile() string {
+	g.used = make(map[string]struct{})
+
+	paths := make([]string, 0, len(g.paths))
+	for path := range g.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	funcs := make([]apiFunc, 0)
+	seen := make(map[string]struct{})
+	for _, path := range paths {
+		item, _ := g.paths[path].(map[string]interface{})
+		for _, method := range methodOrder {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fn, ok := g.crudFunc(method, path)
+			if !ok {
+				fn = g.operationFunc(method, path, operation)
+			}
+			if _, exists := seen[fn.name]; exists {
+				// CRUD 命名冲突时退回路径命名，保证函数名唯一。
+				fn.name = pathFuncName(method, path)
+			}
+			seen[fn.name] = struct{}{}
+			if summary, _ := operation["summary"].(string); summary != "" && fn.summary == "" {
+				fn.summary = summary
+			}
+			funcs = append(funcs, fn)
+		}
+	}
+
+	var body strings.Builder
+	for _, fn := range funcs {
+		body.WriteString("\n")
+		writeFunc(&body, fn)
+	}
+
+	var b strings.Builder
+	b.WriteString(fileHeader)
+	b.WriteString("import { request } from '@umijs/max';\nimport { buildApiUrl } from '../config';\n")
+	if len(g.used) > 0 {
+		used := make([]string, 0, len(g.used))
+		for name := range g.used {
+			used = append(used, name)
+		}
+		sort.Strings(used)
+		fmt.Fprintf(&b, "import type {\n  %s,\n} from './types';\n", strings.Join(used, ",\n  "))
+	}
+	b.WriteString(body.String())
+	return b.String()
+}
+
+// crudFunc 为标准 CRUD 路由生成函数，类型直接取自模块的 SwaggerConfig。
+func (g *generator) crudFunc(method string, path string) (apiFunc, bool) {
+	matched, ok := g.routes[routeKey(method, path)]
+	if !ok {
+		return apiFunc{}, false
+	}
+	config, route := matched.config, matched.route
+	if _, ok := crudHandlers[route.Handler]; !ok || config.Swagger.Model == nil {
+		// 扩展路由和未声明模型的通用模块都有手写 swag 注释，按通用接口生成。
+		return apiFunc{}, false
+	}
+	module := pascalCase(config.Name)
+	model := g.namedType(swagger.SchemaName(config.Swagger.Model), "any")
+	fn := apiFunc{
+		permission: route.Permission,
+		method:     strings.ToUpper(method),
+		url:        urlExpr(path),
+		result:     "API.Response<" + model + ">",
+	}
+
+	switch route.Handler {
+	case "List":
+		fn.name = "get" + module + "List"
+		fn.summary = "获取" + moduleLabel(config) + "列表"
+		fn.params = []string{"params?: " + module + "ListParams"}
+		fn.options = []string{"params"}
+		fn.result = "API.Response<PageResult<" + model + ">>"
+		g.used["PageResult"] = struct{}{}
+		g.used[module+"ListParams"] = struct{}{}
+	case "Get":
+		fn.name = "get" + module
+		fn.summary = "获取" + moduleLabel(config) + "详情"
+		fn.params = []string{"id: number"}
+	case "Create":
+		fn.name = "create" + module
+		fn.summary = "创建" + moduleLabel(config)
+		fn.params = []string{"data: " + g.namedType(swagger.SchemaName(config.Swagger.CreateRequest), "Partial<"+model+">")}
+		fn.options = []string{"data"}
+	case "Update":
+		fn.name = "update" + module
+		fn.summary = "更新" + moduleLabel(config)
+		fn.params = []string{"id: number", "data: " + g.namedType(swagger.SchemaName(config.Swagger.UpdateRequest), "Partial<"+model+">")}
+		fn.options = []string{"data"}
+	case "Delete":
+		fn.name = "delete" + module
+		fn.summary = "删除" + moduleLabel(config)
+		fn.params = []string{"id: number"}
+		fn.result = "API.Response<null>"
+	case "DeleteBatch":
+		fn.name = "delete" + module + "Batch"
+		fn.summary = "批量删除" + moduleLabel(config)
+		fn.params = []string{"ids: number[]"}
+		fn.options = []string{"data: { ids }"}
+		fn.result = "API.Response<null>"
+	}
+	return fn, true
+}
+
+// operationFunc 根据 swag 注释生成的 operation 生成函数。
+func (g *generator) operationFunc(method string, path string, operation map[string]interface{}) apiFunc {
+	fn := apiFunc{
+		name:   pathFuncName(method, path),
+		method: strings.ToUpper(method),
+		url:    urlExpr(path),
+	}
+
+	query := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	queryRequired := make([]interface{}, 0)
+	hasForm := false
+	parameters, _ := operation["parameters"].([]interface{})
+	for _, rawParameter := range parameters {
+		parameter, _ := rawParameter.(map[string]interface{})
+		name, _ := parameter["name"].(string)
+		required, _ := parameter["required"].(bool)
+		switch parameter["in"] {
+		case "path":
+			fn.params = append(fn.params, camelCase(name)+": "+g.tsType(parameter, "", true))
+		case "query":
+			query["properties"].(map[string]interface{})[name] = parameter
+			if required {
+				queryRequired = append(queryRequired, name)
+			}
+		case "body":
+			fn.params = append(fn.params, "data: "+g.tsType(parameter["schema"], "", true))
+			fn.options = append(fn.options, "data")
+		case "formData":
+			hasForm = true
+		}
+	}
+	if len(query["properties"].(map[string]interface{})) > 0 {
+		query["required"] = queryRequired
+		prefix := "params?: "
+		if len(queryRequired) > 0 {
+			prefix = "params: "
+		}
+		fn.params = append(fn.params, prefix+g.tsType(query, "", true))
+		fn.options = append(fn.options, "params")
+	}
+	if hasForm {
+		// 上传接口由调用方自行组装 FormData，文件字段名以 swag 注释为准。
+		fn.params = append(fn.params, "data: FormData")
+		fn.options = append(fn.options, "data")
+	}
+
+	fn.result = "any"
+	responses, _ := operation["responses"].(map[string]interface{})
+	response, _ := responses["200"].(map[string]interface{})
+	if schema, ok := response["schema"].(map[string]interface{}); ok {
+		if schema["type"] == "file" {
+			fn.result = "Blob"
+			fn.options = append(fn.options, "responseType: 'blob'")
+		} else {
+			fn.result = g.responseType(schema)
+		}
+	}
+	return fn
+}
+
+// responseType 解析统一响应包装中的 data 类型。
+func (g *generator) responseType(schema map[string]interface{}) string {
+	if name := refName(schema); isEnvelope(name) {
+		return "API.Response<null>"
+	}
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok || len(allOf) == 0 {
+		return g.tsType(schema, "", false)
+	}
+	first, _ := allOf[0].(map[string]interface{})
+	if !isEnvelope(refName(first)) {
+		return g.tsType(schema, "", false)
+	}
+	data := interface{}(nil)
+	for _, item := range allOf[1:] {
+		sub, _ := item.(map[string]interface{})
+		properties, _ := sub["properties"].(map[string]interface{})
+		if value, ok := properties["data"]; ok {
+			data = value
+		}
+	}
+	if data == nil {
+		return "API.Response<null>"
+	}
+	return "API.Response<" + g.tsType(data, "", false) + ">"
+}
+
+// namedType 返回 definition 对应的类型名，不存在时使用 fallback。
+func (g *generator) namedType(definition string, fallback string) string {
+	name, ok := g.names[definition]
+	if !ok {
+		return fallback
+	}
+	g.used[name] = struct{}{}
+	return name
+}
+
+// writeFunc 输出单个接口函数。
+func writeFunc(b *strings.Builder, fn apiFunc) {
+	if fn.permission != "" {
+		fmt.Fprintf(b, "/**\n * %s\n * 权限：%s\n */\n", fn.summary, fn.permission)
+	} else {
+		fmt.Fprintf(b, "/** %s */\n", fn.summary)
+	}
+	fmt.Fprintf(b, "export async function %s(%s) {\n", fn.name, strings.Join(fn.params, ", "))
+	fmt.Fprintf(b, "  return request<%s>(buildApiUrl(%s), {\n", fn.result, fn.url)
+	fmt.Fprintf(b, "    method: '%s',\n", fn.method)
+	for _, option := range fn.options {
+		fmt.Fprintf(b, "    %s,\n", option)
+	}
+	b.WriteString("  });\n}\n")
+}
+
+// permissionsFile 将权限树导出为常量，前端 access 与按钮权限统一引用。
+func permissionsFile(permissions []crud.Permission) string {
+	var b strings.Builder
+	b.WriteString(fileHeader)
+	b.WriteString("export const PERMISSIONS = {\n")
+
+	seen := make(map[string]struct{})
+	var walk func(items []crud.Permission)
+	walk = func(items []crud.Permission) {
+		for _, item := range items {
+			if _, exists := seen[item.Key]; !exists && item.Key != "" {
+				seen[item.Key] = struct{}{}
+				if item.Label != "" {
+					fmt.Fprintf(&b, "  /** %s */\n", item.Label)
+				}
+				fmt.Fprintf(&b, "  %s: %s,\n", permissionConstName(item.Key), literal(item.Key))
+			}
+			walk(item.Children)
+		}
+	}
+	walk(permissions)
+
+	b.WriteString("} as const;\n\n")
+	b.WriteString("export type PermissionKey = (typeof PERMISSIONS)[keyof typeof PERMISSIONS];\n")
+	return b.String()
+}
+
+// permissionConstName 将 system:admin_user:list 转换为 SYSTEM_ADMIN_USER_LIST。
+func permissionConstName(key string) string {
+	return strings.ToUpper(strings.Trim(nonWordPattern.ReplaceAllString(key, "_"), "_"))
+}
+
+// pathFuncName 按 方法+路径 生成函数名，如 PUT /admin-roles/{id}/permissions -> putAdminRolesByIdPermissions。
+func pathFuncName(method string, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if match := pathParamPattern.FindStringSubmatch(segment); match != nil {
+			b.WriteString("By" + pascalCase(match[1]))
+			continue
+		}
+		b.WriteString(pascalCase(segment))
+	}
+	return b.String()
+}
+
+// urlExpr 生成 buildApiUrl 的参数表达式，路径参数使用模板字符串。
+func urlExpr(path string) string {
+	if !pathParamPattern.MatchString(path) {
+		return "'" + path + "'"
+	}
+	return "`" + pathParamPattern.ReplaceAllStringFunc(path, func(segment string) string {
+		return "${" + camelCase(strings.Trim(segment, "{}")) + "}"
+	}) + "`"
+}
+
+// docPath 拼接模块路由并转换为文档路径格式。
+func docPath(group string, path string) string {
+	full := "/" + strings.Trim(strings.Trim(group, "/")+"/"+strings.Trim(path, "/"), "/")
+	segments := strings.Split(full, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// routeKey 生成路由索引 key。
+func routeKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// moduleLabel 返回模块显示名称。
+func moduleLabel(config crud.ModuleConfig) string {
+	if config.Description != "" {
+		return config.Description
+	}
+	return config.Name
+}
@@ -0,0 +1,136 @@
+package tsgen
+
+import (
+	"strings"
+	"testing"
+
+	"bico-admin/internal/core/model"
+	"bico-admin/internal/pkg/crud"
+	"bico-admin/internal/pkg/swagger"
+)
+
+type testArticle struct {
+	ID        uint           `json:"id"`
+	Title     string         `json:"title"`
+	CreatedAt model.JSONTime `json:"created_at"`
+}
+
+type testArticleListReq struct {
+	Title string `form:"title"`
+}
+
+type testArticleUpdateReq struct {
+	Title   string `json:"title" binding:"required"`
+	Enabled *bool  `json:"enabled"`
+}
+
+var testArticlePerms = crud.NewCRUDPerms("test", "article", "文章")
+
+type testArticleModule struct{}
+
+func (testArticleModule) ModuleConfig() crud.ModuleConfig {
+	return crud.ModuleConfig{
+		Name:        "article",
+		Group:       "/articles",
+		Description: "文章",
+		Permissions: testArticlePerms.Tree,
+		Routes:      testArticlePerms.Routes(),
+		Swagger: crud.SwaggerConfig{
+			Model:         testArticle{},
+			ListRequest:   testArticleListReq{},
+			CreateRequest: testArticleUpdateReq{},
+			UpdateRequest: testArticleUpdateReq{},
+		},
+	}
+}
+
+const testSwaggerDoc = `{
+	"swagger": "2.0",
+	"paths": {
+		"/auth/login": {"post": {
+			"summary": "登录",
+			"parameters": [{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/handler.loginRequest"}}],
+			"responses": {"200": {"description": "OK", "schema": {"allOf": [
+				{"$ref": "#/definitions/handler.adminResponse"},
+				{"type": "object", "properties": {"data": {"$ref": "#/definitions/handler.loginResponse"}}}
+			]}}}
+		}}
+	},
+	"definitions": {
+		"handler.adminResponse": {"type": "object"},
+		"handler.loginRequest": {"type": "object", "required": ["username"], "properties": {"username": {"type": "string"}, "remember": {"type": "boolean"}}},
+		"handler.loginResponse": {"type": "object", "properties": {"token": {"type": "string"}}}
+	}
+}`
+
+// generateTestFiles 基于测试模块生成 TypeScript 文件。
+func generateTestFiles(t *testing.T) map[string]string {
+	t.Helper()
+	modules := []crud.Module{testArticleModule{}}
+	doc, err := swagger.ApplyCRUDModules(testSwaggerDoc, []swagger.CRUDModule{{Config: modules[0].ModuleConfig()}})
+	if err != nil {
+		t.Fatalf("增强 Swagger 文档失败: %v", err)
+	}
+	files, err := Generate(doc, modules, []crud.Permission{{Key: "dashboard:menu", Label: "工作台"}})
+	if err != nil {
+		t.Fatalf("生成 TypeScript 失败: %v", err)
+	}
+	return files
+}
+
+// assertContains 断言生成内容包含指定片段。
+func assertContains(t *testing.T, content string, parts ...string) {
+	t.Helper()
+	for _, part := range parts {
+		if !strings.Contains(content, part) {
+			t.Fatalf("生成结果缺少 %q:\n%s", part, content)
+		}
+	}
+}
+
+// TestGenerateTypes 验证字段类型与可选规则。
+func TestGenerateTypes(t *testing.T) {
+	types := generateTestFiles(t)[TypesFile]
+
+	assertContains(t, types,
+		// JSONTime 按字符串输出。
+		"  created_at: string;",
+		// 指针字段生成为可选，required 字段保持必填。
+		"  enabled?: boolean;",
+		"  title: string;",
+		// swag 注释的请求参数中未声明 required 的字段为可选。
+		"  remember?: boolean;",
+		"  username: string;",
+		"export type ArticleListParams = PageParams & TestArticleListReq;",
+	)
+	if strings.Contains(types, "AdminResponse") {
+		t.Fatalf("统一响应包装不应生成独立类型:\n%s", types)
+	}
+}
+
+// TestGenerateAPI 验证 CRUD 路由与 swag 注释接口的函数签名。
+func TestGenerateAPI(t *testing.T) {
+	api := generateTestFiles(t)[APIFile]
+
+	assertContains(t, api,
+		"export async function getArticleList(params?: ArticleListParams) {",
+		"request<API.Response<PageResult<TestArticle>>>(buildApiUrl('/articles')",
+		"export async function updateArticle(id: number, data: TestArticleUpdateReq) {",
+		"buildApiUrl(`/articles/${id}`)",
+		"export async function deleteArticleBatch(ids: number[]) {",
+		" * 权限：test:article:edit",
+		"export async function postAuthLogin(data: LoginRequest) {",
+		"request<API.Response<LoginResponse>>(buildApiUrl('/auth/login')",
+	)
+}
+
+// TestGeneratePermissions 验证权限常量覆盖基础权限与模块权限树。
+func TestGeneratePermissions(t *testing.T) {
+	permissions := generateTestFiles(t)[PermissionsFile]
+
+	assertContains(t, permissions,
+		"  DASHBOARD_MENU: 'dashboard:menu',",
+		"  TEST_ARTICLE_LIST: 'test:article:list',",
+		"export type PermissionKey = (typeof PERMISSIONS)[keyof typeof PERMISSIONS];",
+	)
+}
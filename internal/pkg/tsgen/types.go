@@ -0,0 +1,209 @@
+package tsgen
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const definitionRefPrefix = "#/definitions/"
+
+// envelopeSchemas 统一响应包装，生成时替换为 API.Response<T>。
+var envelopeSchemas = map[string]struct{}{
+	"handler.adminResponse": {},
+	"swagger.Response":      {},
+	"swagger.PageResponse":  {},
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeNames 为 definition 分配 TypeScript 类型名。
+//
+// 说明：优先去掉包名前缀（model.AdminUser -> AdminUser），仅在不同包同名时保留包名避免冲突。
+func typeNames(definitions map[string]interface{}) map[string]string {
+	counts := make(map[string]int)
+	for name := range definitions {
+		if isEnvelope(name) {
+			continue
+		}
+		counts[shortTypeName(name)]++
+	}
+
+	names := make(map[string]string, len(definitions))
+	for name := range definitions {
+		if isEnvelope(name) {
+			continue
+		}
+		short := shortTypeName(name)
+		if counts[short] > 1 {
+			short = pascalCase(strings.ReplaceAll(name, ".", "_"))
+		}
+		names[name] = short
+	}
+	return names
+}
+
+// shortTypeName 去掉包名前缀并转换为 PascalCase。
+func shortTypeName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return pascalCase(name)
+}
+
+// isEnvelope 判断 definition 是否为统一响应包装。
+func isEnvelope(name string) bool {
+	_, ok := envelopeSchemas[name]
+	return ok
+}
+
+// refName 读取 $ref 指向的 definition 名称。
+func refName(schema map[string]interface{}) string {
+	ref, _ := schema["$ref"].(string)
+	return strings.TrimPrefix(ref, definitionRefPrefix)
+}
+
+// tsType 将 Swagger schema 转换为 TypeScript 类型表达式。
+//
+// input 为 true 时表示请求参数：未声明 required 的字段生成为可选。
+func (g *generator) tsType(raw interface{}, indent string, input bool) string {
+	schema, ok := raw.(map[string]interface{})
+	if !ok || len(schema) == 0 {
+		return "any"
+	}
+
+	if name := refName(schema); name != "" {
+		if isEnvelope(name) {
+			return "API.Response<any>"
+		}
+		tsName, ok := g.names[name]
+		if !ok {
+			return "any"
+		}
+		g.used[tsName] = struct{}{}
+		return tsName
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		parts := make([]string, 0, len(allOf))
+		for _, item := range allOf {
+			parts = append(parts, g.tsType(item, indent, input))
+		}
+		return strings.Join(parts, " & ")
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, value := range enum {
+			values = append(values, literal(value))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch schema["type"] {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "string":
+		if schema["format"] == "binary" {
+			return "Blob"
+		}
+		// JSONTime、time.Time 在文档中均为 date-time 字符串，前端按字符串处理。
+		return "string"
+	case "file":
+		return "Blob"
+	case "array":
+		item := g.tsType(schema["items"], indent, input)
+		if strings.ContainsAny(item, "|&") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if properties, ok := schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+			return g.tsObject(schema, indent, input)
+		}
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			return "Record<string, " + g.tsType(additional, indent, input) + ">"
+		}
+		return "Record<string, any>"
+	}
+	return "any"
+}
+
+// tsObject 将带 properties 的 schema 转换为内联对象类型。
+func (g *generator) tsObject(schema map[string]interface{}, indent string, input bool) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	g.writeProperties(&b, schema, indent+"  ", input)
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// writeProperties 按字段名排序输出属性，保证多次生成结果稳定。
+//
+// 可选规则：指针字段（x-nullable）始终可选；请求参数中未标记 required 的字段可选。
+func (g *generator) writeProperties(b *strings.Builder, schema map[string]interface{}, indent string, input bool) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := make(map[string]struct{})
+	if items, ok := schema["required"].([]interface{}); ok {
+		for _, item := range items {
+			if name, ok := item.(string); ok {
+				required[name] = struct{}{}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		_, isRequired := required[name]
+		nullable, _ := property["x-nullable"].(bool)
+		optional := nullable || (input && !isRequired)
+
+		if description, _ := property["description"].(string); description != "" {
+			fmt.Fprintf(b, "%s/** %s */\n", indent, description)
+		}
+		key := name
+		if !identifierPattern.MatchString(key) {
+			key = literal(key)
+		}
+		if optional {
+			key += "?"
+		}
+		fmt.Fprintf(b, "%s%s: %s;\n", indent, key, g.tsType(property, indent, input))
+	}
+}
+
+// literal 生成 TypeScript 字面量。
+func literal(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+	}
+	return fmt.Sprint(value)
+}
+
+// pascalCase 将 admin_user、admin-users、loginRequest 转换为 PascalCase。
+func pascalCase(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == '/' || r == ':' || r == ' '
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// camelCase 将字符串转换为 camelCase。
+func camelCase(s string) string {
+	p := pascalCase(s)
+	if p == "" {
+		return p
+	}
+	return strings.ToLower(p[:1]) + p[1:]
+}
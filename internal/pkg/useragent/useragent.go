@@ -0,0 +1,56 @@
+// Package useragent 从 User-Agent 中提取便于展示的设备描述。
+package useragent
+
+import "strings"
+
+// rule 按顺序匹配的关键字与展示名称。
+type rule struct {
+	token string
+	name  string
+}
+
+// 浏览器规则需要按特异性排序：Edge、Opera 的 UA 同时包含 Chrome，Chrome 的 UA 同时包含 Safari。
+var browsers = []rule{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+}
+
+// iOS 与 Android 设备的 UA 同时包含 Mac OS X、Linux，需要先匹配。
+var systems = []rule{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// Parse 返回形如 "Chrome / macOS" 的设备描述，无法识别的部分省略。
+func Parse(ua string) string {
+	parts := make([]string, 0, 2)
+	if name := match(ua, browsers); name != "" {
+		parts = append(parts, name)
+	}
+	if name := match(ua, systems); name != "" {
+		parts = append(parts, name)
+	}
+	if len(parts) == 0 {
+		return "未知设备"
+	}
+	return strings.Join(parts, " / ")
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(ua, r.token) {
+			return r.name
+		}
+	}
+	return ""
+}
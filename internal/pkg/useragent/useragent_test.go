@@ -0,0 +1,20 @@
+package useragent
+
+import "testing"
+
+// TestParse 验证常见浏览器与系统的识别顺序。
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36":                   "Chrome / macOS",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":           "Edge / Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1": "Safari / iOS",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0":                                                                  "Firefox / Linux",
+		"curl/8.4.0": "curl",
+		"":           "未知设备",
+	}
+	for ua, expected := range cases {
+		if got := Parse(ua); got != expected {
+			t.Errorf("Parse(%q) = %q，期望 %q", ua, got, expected)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package webauthntest 提供软件实现的 WebAuthn 认证器，用于在测试中完成注册与登录仪式。
+//
+// 认证器使用 ES256 密钥、none 证明格式，每次签名计数加一，并始终上报用户在场与用户验证。
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+// 认证器数据标志位
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+var ErrNoCredential = errors.New("webauthntest: 没有可用的凭据")
+
+// Authenticator 软件认证器，可保存多个凭据。
+type Authenticator struct {
+	origin      string
+	credentials []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	counter    uint32
+}
+
+// New 创建认证器，origin 为模拟的浏览器页面来源。
+func New(origin string) *Authenticator {
+	return &Authenticator{origin: origin}
+}
+
+// Clone 复制认证器及其凭据与计数，用于模拟被复制的密钥。
+func (a *Authenticator) Clone() *Authenticator {
+	clone := &Authenticator{origin: a.origin}
+	for _, c := range a.credentials {
+		copied := *c
+		clone.credentials = append(clone.credentials, &copied)
+	}
+	return clone
+}
+
+// Create 按 navigator.credentials.create 的参数生成新凭据，返回浏览器提交给服务端的凭据 JSON。
+func (a *Authenticator) Create(options []byte) ([]byte, error) {
+	var opts struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			RP        struct {
+				ID string `json:"id"`
+			} `json:"rp"`
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, err
+	}
+	userHandle, err := decode(opts.PublicKey.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	cred := &credential{id: id, rpID: opts.PublicKey.RP.ID, userHandle: userHandle, key: key}
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	authData := cred.authData(flagUserPresent | flagUserVerified | flagAttestedData)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(id)))
+	authData = append(authData, id...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	clientData, err := a.clientData("webauthn.create", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+	return json.Marshal(map[string]any{
+		"id":    encode(id),
+		"rawId": encode(id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"attestationObject": encode(attestation),
+			"transports":        []string{"internal"},
+		},
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]any{},
+	})
+}
+
+// Get 按 navigator.credentials.get 的参数签名，返回浏览器提交给服务端的凭据 JSON。
+// 参数中指定了允许的凭据时只使用这些凭据，否则使用该站点的第一个凭据。
+func (a *Authenticator) Get(options []byte) ([]byte, error) {
+	var opts struct {
+		PublicKey struct {
+			Challenge        string `json:"challenge"`
+			RPID             string `json:"rpId"`
+			AllowCredentials []struct {
+				ID string `json:"id"`
+			} `json:"allowCredentials"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]bool, len(opts.PublicKey.AllowCredentials))
+	for _, c := range opts.PublicKey.AllowCredentials {
+		allowed[c.ID] = true
+	}
+	var cred *credential
+	for _, c := range a.credentials {
+		if c.rpID == opts.PublicKey.RPID && (len(allowed) == 0 || allowed[encode(c.id)]) {
+			cred = c
+			break
+		}
+	}
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	cred.counter++
+	authData := cred.authData(flagUserPresent | flagUserVerified)
+	clientData, err := a.clientData("webauthn.get", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    encode(cred.id),
+		"rawId": encode(cred.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(cred.userHandle),
+		},
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]any{},
+	})
+}
+
+// authData 生成认证器数据头部：站点 ID 摘要、标志位与签名计数。
+func (c *credential) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, c.counter)
+}
+
+func (a *Authenticator) clientData(kind, challenge string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        kind,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decode 兼容带填充与不带填充的 base64url。
+func decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimPadding(value))
+}
+
+func trimPadding(value string) string {
+	for len(value) > 0 && value[len(value)-1] == '=' {
+		value = value[:len(value)-1]
+	}
+	return value
+}
@@ -52,6 +52,36 @@ export default [
                 component: "./system/admin-roles",
                 access: "system:admin_role:menu",
             },
+            {
+                path: "/system/departments",
+                name: "departments",
+                component: "./system/departments",
+                access: "system:department:menu",
+            },
+            {
+                path: "/system/login-logs",
+                name: "login-logs",
+                component: "./system/login-logs",
+                access: "system:login_log:menu",
+            },
+            {
+                path: "/system/ip-rules",
+                name: "ip-rules",
+                component: "./system/ip-rules",
+                access: "system:ip_rule:menu",
+            },
+            {
+                path: "/system/login-locks",
+                name: "login-locks",
+                component: "./system/login-locks",
+                access: "system:login_lock:menu",
+            },
+            {
+                path: "/system/permission-explain",
+                name: "permission-explain",
+                component: "./system/permission-explain",
+                access: "system:permission_explain:menu",
+            },
         ],
     },
     {
@@ -6,10 +6,11 @@ import { SettingDrawer } from "@ant-design/pro-components";
 import type { RequestConfig, RunTimeLayoutConfig } from "@umijs/max";
 import { history } from "@umijs/max";
 import React from "react";
-import { AvatarDropdown, AvatarName } from "@/components";
+import { AvatarDropdown, AvatarName, ImpersonationBanner } from "@/components";
 import { getCurrentUser as fetchCurrentUser } from "@/services/auth";
 import { getAppConfig } from "@/services/common";
 import { buildLoginUrl, getCurrentPathWithSearch, LOGIN_PATH } from "@/utils/redirect";
+import { clearTokens } from "@/utils/token";
 import defaultSettings from "../config/defaultSettings";
 import { errorConfig } from "./requestErrorConfig";
 const loginPath = LOGIN_PATH;
@@ -102,7 +103,7 @@ export async function getInitialState(): Promise<{
         } catch (e) {
             console.error("获取用户信息失败:", e);
             // 清除无效的 token
-            localStorage.removeItem("token");
+            clearTokens();
             localStorage.removeItem("currentUser");
             return undefined;
         }
@@ -246,6 +247,7 @@ export const layout: RunTimeLayoutConfig = ({
             return (
                 <>
                     <MobileDrawerScrollLocker />
+                    <ImpersonationBanner />
                     {children}
                     {initialState?.appConfig?.debug && (
                         <SettingDrawer
@@ -0,0 +1,120 @@
+import { ReloadOutlined } from '@ant-design/icons';
+import { Input, Slider } from 'antd';
+import React, { useEffect, useRef } from 'react';
+import type { CaptchaResult } from '@/services/auth/types';
+
+interface CaptchaProps {
+  captcha: CaptchaResult;
+  /** 图片验证码为输入的字符，滑块验证码为拼图块横坐标与拖动轨迹的 JSON */
+  value?: string;
+  onChange?: (value: string) => void;
+  onRefresh: () => void;
+  onPressEnter?: () => void;
+}
+
+const imageStyle: React.CSSProperties = {
+  height: 40,
+  cursor: 'pointer',
+  borderRadius: 8,
+  border: '1px solid #d9d9d9',
+  backgroundColor: '#fff',
+};
+
+/**
+ * 验证码输入，按验证码类型展示图片输入框或滑块拼图，可作为表单控件使用。
+ */
+const Captcha: React.FC<CaptchaProps> = ({ captcha, value, onChange, onRefresh, onPressEnter }) => {
+  // 拖动轨迹，每个点为 [拼图块横坐标, 指针纵向偏移, 距首次按下的毫秒数]，服务端据此识别脚本提交；
+  // 同一验证码多次拖动时轨迹连续记录
+  const trace = useRef<number[][]>([]);
+  const start = useRef(0);
+  const pointer = useRef<{ y: number } | null>(null);
+  const offsetRef = useRef(0);
+
+  useEffect(() => {
+    trace.current = [];
+    pointer.current = null;
+  }, [captcha.id]);
+
+  if (captcha.type === 'slider' && captcha.piece) {
+    let offset = 0;
+    try {
+      offset = value ? Number(JSON.parse(value).x) || 0 : 0;
+    } catch {
+      offset = 0;
+    }
+    offsetRef.current = offset;
+    const pieceWidth = 51;
+    const record = (e: React.PointerEvent) => {
+      if (!pointer.current) return;
+      const point = [offsetRef.current, e.clientY - pointer.current.y, Math.round(e.timeStamp - start.current)];
+      const last = trace.current[trace.current.length - 1];
+      // 位置未变化的点不记录，控制轨迹长度
+      if (last && last[0] === point[0] && last[1] === point[1]) return;
+      trace.current.push(point);
+    };
+    return (
+      <div style={{ width: '100%', maxWidth: captcha.width }}>
+        <div style={{ position: 'relative', width: captcha.width, height: captcha.height, borderRadius: 8, overflow: 'hidden' }}>
+          <img src={captcha.image} alt="验证码" draggable={false} style={{ display: 'block' }} />
+          <img
+            src={captcha.piece}
+            alt=""
+            draggable={false}
+            style={{ position: 'absolute', left: offset, top: captcha.pieceY, filter: 'drop-shadow(0 0 3px rgba(0,0,0,.6))' }}
+          />
+          <ReloadOutlined
+            onClick={onRefresh}
+            title="换一张"
+            style={{ position: 'absolute', right: 8, top: 8, color: '#fff', fontSize: 16, cursor: 'pointer' }}
+          />
+        </div>
+        <div
+          onPointerDown={(e) => {
+            // 指针移出滑条后仍记录轨迹并收到抬起事件
+            e.currentTarget.setPointerCapture(e.pointerId);
+            pointer.current = { y: e.clientY };
+            if (trace.current.length === 0) {
+              start.current = e.timeStamp;
+              trace.current = [[offsetRef.current, 0, 0]];
+            }
+          }}
+          onPointerMove={record}
+          onPointerUp={(e) => {
+            record(e);
+            pointer.current = null;
+            onChange?.(JSON.stringify({ x: offsetRef.current, trace: trace.current }));
+          }}
+        >
+          <Slider
+            min={0}
+            max={captcha.width - pieceWidth}
+            value={offset}
+            tooltip={{ open: false }}
+            onChange={(v) => {
+              offsetRef.current = v;
+              onChange?.(JSON.stringify({ x: v, trace: trace.current }));
+            }}
+          />
+        </div>
+      </div>
+    );
+  }
+
+  return (
+    <div style={{ display: 'flex', gap: 8 }}>
+      <Input
+        size="large"
+        value={value}
+        maxLength={8}
+        onChange={(e) => onChange?.(e.target.value)}
+        onPressEnter={onPressEnter}
+        placeholder={captcha.type === 'math' ? '请输入计算结果' : '请输入验证码'}
+        style={{ flex: 1 }}
+      />
+      <img src={captcha.image} alt="验证码" onClick={onRefresh} style={imageStyle} title="点击刷新验证码" />
+    </div>
+  );
+};
+
+export default Captcha;
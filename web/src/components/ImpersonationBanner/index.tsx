@@ -0,0 +1,52 @@
+import { useModel } from '@umijs/max';
+import { Alert, Button, message } from 'antd';
+import React, { useState } from 'react';
+import { stopImpersonation } from '@/services/auth';
+import { saveTokens } from '@/utils/token';
+
+/**
+ * 模拟登录提示条，显示被模拟用户与实际操作者，并提供退出模拟入口。
+ */
+const ImpersonationBanner: React.FC = () => {
+  const { initialState } = useModel('@@initialState');
+  const [loading, setLoading] = useState(false);
+  const currentUser = initialState?.currentUser;
+
+  if (!currentUser?.impersonated) return null;
+
+  const handleStop = async () => {
+    setLoading(true);
+    try {
+      const res = await stopImpersonation();
+      if (res.code !== 0 || !res.data?.token) {
+        message.error(res.msg || '退出模拟失败');
+        return;
+      }
+      saveTokens(res.data);
+      localStorage.removeItem('currentUser');
+      // 身份切换后权限与菜单全部变化，整页重新加载初始化状态。
+      window.location.href = '/system/admin-users';
+    } catch (e: any) {
+      message.error(e?.message || '退出模拟失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <Alert
+      type="warning"
+      showIcon
+      banner
+      style={{ marginBottom: 16 }}
+      title={`正在以 ${currentUser.name || currentUser.username} 的身份浏览（操作者：${currentUser.impersonator?.username}），期间不能修改密码等安全设置。`}
+      action={
+        <Button size="small" type="primary" loading={loading} onClick={handleStop}>
+          退出模拟
+        </Button>
+      }
+    />
+  );
+};
+
+export default ImpersonationBanner;
@@ -1,12 +1,21 @@
-import { LockOutlined, UserOutlined } from '@ant-design/icons';
+import { DeleteOutlined, KeyOutlined, LockOutlined, PlusOutlined, UserOutlined } from '@ant-design/icons';
 import { ModalForm, ProForm, ProFormText } from '@ant-design/pro-components';
 import { history, useModel } from '@umijs/max';
-import { App } from 'antd';
-import React, { useCallback } from 'react';
+import { App, Button, Empty, Input, List, Modal, Popconfirm, Space, Typography } from 'antd';
+import React, { useCallback, useEffect, useState } from 'react';
 import AvatarUpload from '@/components/AvatarUpload';
+import {
+  beginPasskeyRegistration,
+  deletePasskey,
+  finishPasskeyRegistration,
+  getPasskeys,
+  renamePasskey,
+} from '@/services/auth/passkey';
 import { changePassword, updateProfile, uploadAvatar } from '@/services/auth/profile';
-import type { ChangePasswordParams, UpdateProfileParams } from '@/services/auth/types';
+import type { ChangePasswordParams, Passkey, UpdateProfileParams } from '@/services/auth/types';
+import { clearTokens } from '@/utils/token';
 import { MIN_PASSWORD_LENGTH } from '@/utils/validation';
+import { createPasskey, isPasskeySupported } from '@/utils/webauthn';
 
 interface AccountModalProps {
   open: boolean;
@@ -142,7 +151,7 @@ export const PasswordModal: React.FC<AccountModalProps> = ({ open, onOpenChange
       form.resetFields();
       // 短暂保留成功反馈后清理凭证，避免旧会话继续使用。
       window.setTimeout(() => {
-        localStorage.removeItem('token');
+        clearTokens();
         localStorage.removeItem('currentUser');
         history.replace('/auth/login');
       }, 1500);
@@ -206,3 +215,143 @@ export const PasswordModal: React.FC<AccountModalProps> = ({ open, onOpenChange
     </ModalForm>
   );
 };
+
+const formatTime = (value: string) => new Date(value).toLocaleString();
+
+// PasskeyModal 管理当前用户的通行密钥：添加、重命名与删除。
+export const PasskeyModal: React.FC<AccountModalProps> = ({ open, onOpenChange }) => {
+  const { message } = App.useApp();
+  const [passkeys, setPasskeys] = useState<Passkey[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [adding, setAdding] = useState(false);
+  const [name, setName] = useState('');
+
+  const loadPasskeys = useCallback(async () => {
+    setLoading(true);
+    try {
+      const response = await getPasskeys();
+      if (response.code === 0) {
+        setPasskeys(response.data || []);
+      }
+    } finally {
+      setLoading(false);
+    }
+  }, []);
+
+  useEffect(() => {
+    if (open) {
+      loadPasskeys();
+    }
+  }, [open, loadPasskeys]);
+
+  // handleAdd 依次获取注册参数、调用浏览器创建凭据并提交服务端。
+  const handleAdd = useCallback(async () => {
+    setAdding(true);
+    try {
+      const begin = await beginPasskeyRegistration();
+      if (begin.code !== 0 || !begin.data?.token) {
+        throw new Error(begin.msg || '获取注册参数失败');
+      }
+      const credential = await createPasskey(begin.data.options);
+      const response = await finishPasskeyRegistration({
+        token: begin.data.token,
+        name: name.trim() || '通行密钥',
+        credential,
+      });
+      if (response.code !== 0) {
+        throw new Error(response.msg || '添加通行密钥失败');
+      }
+      message.success('通行密钥已添加');
+      setName('');
+      loadPasskeys();
+    } catch (error) {
+      // 用户在浏览器弹窗中取消时同样走到这里，只提示不重试。
+      message.error(getErrorMessage(error, '添加通行密钥失败'));
+    } finally {
+      setAdding(false);
+    }
+  }, [loadPasskeys, message, name]);
+
+  const handleRename = useCallback(async (passkey: Passkey, value: string) => {
+    const next = value.trim();
+    if (!next || next === passkey.name) return;
+    const response = await renamePasskey(passkey.id, next);
+    if (response.code === 0) {
+      loadPasskeys();
+    } else {
+      message.error(response.msg || '重命名失败');
+    }
+  }, [loadPasskeys, message]);
+
+  const handleDelete = useCallback(async (passkey: Passkey) => {
+    const response = await deletePasskey(passkey.id);
+    if (response.code === 0) {
+      message.success('通行密钥已删除');
+      loadPasskeys();
+    } else {
+      message.error(response.msg || '删除失败');
+    }
+  }, [loadPasskeys, message]);
+
+  return (
+    <Modal
+      title="通行密钥"
+      width={560}
+      open={open}
+      onCancel={() => onOpenChange(false)}
+      footer={null}
+      destroyOnHidden
+    >
+      <Typography.Paragraph type="secondary">
+        通行密钥可用于免密码登录；绑定后使用账号密码登录时，也可用通行密钥完成两步验证。
+      </Typography.Paragraph>
+      <Space.Compact style={{ width: '100%', marginBottom: 16 }}>
+        <Input
+          placeholder="名称，如：工作电脑"
+          maxLength={64}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+        <Button
+          type="primary"
+          icon={<PlusOutlined />}
+          loading={adding}
+          disabled={!isPasskeySupported()}
+          onClick={handleAdd}
+        >
+          添加通行密钥
+        </Button>
+      </Space.Compact>
+      <List
+        loading={loading}
+        dataSource={passkeys}
+        locale={{ emptyText: <Empty description="尚未添加通行密钥" /> }}
+        renderItem={(passkey) => (
+          <List.Item
+            actions={[
+              <Popconfirm
+                key="delete"
+                title="删除后该通行密钥不能再用于登录，确定删除？"
+                onConfirm={() => handleDelete(passkey)}
+              >
+                <Button type="link" danger icon={<DeleteOutlined />} />
+              </Popconfirm>,
+            ]}
+          >
+            <List.Item.Meta
+              avatar={<KeyOutlined style={{ fontSize: 20 }} />}
+              title={
+                <Typography.Text editable={{ onChange: (value) => handleRename(passkey, value), maxLength: 64 }}>
+                  {passkey.name}
+                </Typography.Text>
+              }
+              description={`添加于 ${formatTime(passkey.created_at)}，${
+                passkey.last_used_at ? `最近使用 ${formatTime(passkey.last_used_at)}` : '尚未使用'
+              }`}
+            />
+          </List.Item>
+        )}
+      />
+    </Modal>
+  );
+};
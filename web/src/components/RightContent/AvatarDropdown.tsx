@@ -1,4 +1,5 @@
 import {
+  KeyOutlined,
   LockOutlined,
   LogoutOutlined,
   UserOutlined,
@@ -11,7 +12,8 @@ import React, { useState } from 'react';
 import { flushSync } from 'react-dom';
 import { logout } from '@/services/auth';
 import { buildLoginUrl, getCurrentPathWithSearch, LOGIN_PATH } from '@/utils/redirect';
-import { PasswordModal, ProfileModal } from './AccountSettingsModals';
+import { clearTokens } from '@/utils/token';
+import { PasskeyModal, PasswordModal, ProfileModal } from './AccountSettingsModals';
 import HeaderDropdown from '../HeaderDropdown';
 
 export type GlobalHeaderRightProps = {
@@ -47,7 +49,7 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
   menu,
   children,
 }) => {
-  const [activeModal, setActiveModal] = useState<'profile' | 'password' | null>(null);
+  const [activeModal, setActiveModal] = useState<'profile' | 'password' | 'passkeys' | null>(null);
 
   /**
    * 退出登录，并且将当前的 url 保存
@@ -60,7 +62,7 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
       console.error('退出登录失败:', error);
     } finally {
       // 清除本地存储
-      localStorage.removeItem('token');
+      clearTokens();
       localStorage.removeItem('currentUser');
       
       // 跳转到登录页
@@ -84,7 +86,7 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
       loginOut();
       return;
     }
-    if (key === 'profile' || key === 'password') {
+    if (key === 'profile' || key === 'password' || key === 'passkeys') {
       // 账户设置使用弹窗承载，不再改变当前页面路由。
       setActiveModal(key);
     }
@@ -121,7 +123,8 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
   }
 
   const menuItems = [
-    ...(menu
+    // 模拟登录期间不能修改被模拟用户的资料与密码。
+    ...(menu && !currentUser.impersonated
       ? [
           {
             key: 'profile',
@@ -133,6 +136,16 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
             icon: <LockOutlined />,
             label: '修改密码',
           },
+          // 未启用通行密钥时不展示管理入口。
+          ...(initialState.appConfig?.passkey
+            ? [
+                {
+                  key: 'passkeys',
+                  icon: <KeyOutlined />,
+                  label: '通行密钥',
+                },
+              ]
+            : []),
           {
             type: 'divider' as const,
           },
@@ -164,6 +177,10 @@ export const AvatarDropdown: React.FC<GlobalHeaderRightProps> = ({
         open={activeModal === 'password'}
         onOpenChange={handleModalOpenChange}
       />
+      <PasskeyModal
+        open={activeModal === 'passkeys'}
+        onOpenChange={handleModalOpenChange}
+      />
     </>
   );
 };
@@ -12,10 +12,12 @@ import { AvatarDropdown, AvatarName } from './RightContent/AvatarDropdown';
  */
 export { default as ErrorBoundary } from './ErrorBoundary';
 export { default as OfflineBanner } from './OfflineBanner';
+export { default as ImpersonationBanner } from './ImpersonationBanner';
 export { default as CrudModal } from './CrudModal';
 export { default as CrudTable } from './CrudTable';
 export { default as ExcelImportExportActions } from './ExcelImportExportActions';
 export { default as PageContainer } from './PageContainer';
 export { default as AvatarUpload } from './AvatarUpload';
+export { default as Captcha } from './Captcha';
 
 export { AvatarDropdown, AvatarName };
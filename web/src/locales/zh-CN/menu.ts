@@ -10,6 +10,11 @@ export default {
   'menu.system': '系统管理',
   'menu.system.admin-users': '用户管理',
   'menu.system.admin-roles': '角色管理',
+  'menu.system.departments': '部门管理',
+  'menu.system.login-logs': '登录日志',
+  'menu.system.ip-rules': 'IP 访问控制',
+  'menu.system.login-locks': '登录锁定',
+  'menu.system.permission-explain': '权限排查',
   'menu.developer': '开发者工具',
   'menu.developer.api-docs': '接口文档',
   'menu.developer.demo': '示例',
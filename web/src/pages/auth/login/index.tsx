@@ -1,4 +1,4 @@
-import { LockOutlined, UserOutlined } from "@ant-design/icons";
+import { KeyOutlined, LockOutlined, UserOutlined } from "@ant-design/icons";
 import {
     LoginForm,
     ProFormCheckbox,
@@ -13,13 +13,20 @@ import {
     useIntl,
     useModel,
 } from "@umijs/max";
-import { Alert, App } from "antd";
+import { Alert, App, Button, Divider, Input, Modal, Space } from "antd";
 import { createStyles } from "antd-style";
 import React, { useState } from "react";
 import { flushSync } from "react-dom";
-import { login, getCaptcha } from '@/services/auth';
+import { login, getCaptcha, verifyTwoFactor, setupPendingTwoFactor, changeExpiredPassword, ssoAuthorize, ssoCallback, forgotPassword, resetPassword, beginPasskeyLogin, finishPasskeyLogin, beginPasskeyTwoFactor, verifyTwoFactorPasskey } from '@/services/auth';
+import type { CaptchaResult, LoginResult } from '@/services/auth/types';
+import Captcha from '@/components/Captcha';
 import { saveCredentials, getCredentials, clearCredentials } from '@/utils/crypto';
 import { getSafeRedirectFromSearch } from '@/utils/redirect';
+import { saveTokens } from '@/utils/token';
+import { getPasskey, isPasskeySupported } from '@/utils/webauthn';
+
+// 跳转身份提供方前暂存登录后的目标页，回调时地址栏中的 redirect 已丢失。
+const SSO_REDIRECT_KEY = 'sso_redirect';
 
 const useStyles = createStyles(({ token }) => {
     return {
@@ -73,17 +80,33 @@ const LoginMessage: React.FC<{
 
 const Login: React.FC = () => {
     const [userLoginState, setUserLoginState] = useState<{status?: string; message?: string}>({});
-    const [captchaData, setCaptchaData] = useState<{id: string; image: string}>({id: '', image: 'data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=='});
+    const [captchaData, setCaptchaData] = useState<CaptchaResult>({id: '', type: 'digit', image: 'data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==', width: 240, height: 80});
+    // 按需验证码模式下，登录返回 428 后才展示验证码。
+    const [captchaNeeded, setCaptchaNeeded] = useState(false);
+    // 两步验证待验证状态：setup 为 true 时需先扫码绑定，methods 为可用的验证方式。
+    const [twoFactor, setTwoFactor] = useState<{token: string; setup: boolean; methods: string[]; image?: string; secret?: string} | null>(null);
+    const [twoFactorCode, setTwoFactorCode] = useState('');
+    // 密码过期或管理员要求修改时，需设置新密码后才能进入系统。
+    const [passwordChange, setPasswordChange] = useState<{token: string; expired: boolean} | null>(null);
+    const [newPassword, setNewPassword] = useState({ password: '', confirm: '' });
+    // 找回密码：forgot 为申请重置邮件，reset 为打开邮件链接后设置新密码。
+    const [forgot, setForgot] = useState<{account: string; captchaCode: string} | null>(null);
+    const [reset, setReset] = useState<{token: string; password: string; confirm: string; captchaCode: string} | null>(null);
     const [form] = ProForm.useForm();
     const { initialState, setInitialState } = useModel("@@initialState");
     const { styles } = useStyles();
-    const { message } = App.useApp();
+    const { message, modal } = App.useApp();
     const intl = useIntl();
     
     const appName = initialState?.appConfig?.name || 'Bico Admin';
     const appLogo = initialState?.appConfig?.logo || 'logo.png';
+    const ssoProviders = initialState?.appConfig?.ssoProviders || [];
+    const passwordResetEnabled = !!initialState?.appConfig?.passwordReset;
+    const showLoginCaptcha = !initialState?.appConfig?.captchaAdaptive || captchaNeeded;
+    const passkeyEnabled = !!initialState?.appConfig?.passkey && isPasskeySupported();
+    const ssoRedirect = React.useRef<string | null>(null);
 
-    const getRedirectPath = () => getSafeRedirectFromSearch(history.location.search);
+    const getRedirectPath = () => ssoRedirect.current || getSafeRedirectFromSearch(history.location.search);
     
     // 检查是否已登录，已登录则自动跳转
     React.useEffect(() => {
@@ -100,10 +123,9 @@ const Login: React.FC = () => {
         try {
             const response = await getCaptcha();
             if (response.data) {
-                setCaptchaData({
-                    id: response.data.id,
-                    image: response.data.image,
-                });
+                setCaptchaData(response.data);
+                // 换新验证码后旧的滑块位置或输入不再有效。
+                form.setFieldValue('captchaCode', '');
             }
         } catch (error) {
             console.error('获取验证码失败', error);
@@ -135,42 +157,273 @@ const Login: React.FC = () => {
         }
     };
 
+    const finishLogin = async (tokens: LoginResult) => {
+        // 保存访问令牌与刷新令牌
+        saveTokens(tokens);
+
+        // 更新全局状态（获取用户信息）
+        await fetchUserInfo();
+
+        const defaultLoginSuccessMessage = intl.formatMessage({
+            id: "pages.login.success",
+            defaultMessage: "登录成功！",
+        });
+        message.success(defaultLoginSuccessMessage);
+
+        // 使用 Umi history.replace 进行软路由跳转，避免整页重载导致空白和二次加载静态资源
+        history.replace(getRedirectPath());
+    };
+
+    // 身份校验通过后的最后一步：需要修改密码时先弹出修改密码框。
+    const proceedLogin = async (result: LoginResult) => {
+        if (result.passwordChangeToken) {
+            setNewPassword({ password: '', confirm: '' });
+            setPasswordChange({ token: result.passwordChangeToken, expired: !!result.passwordExpired });
+            return;
+        }
+        if (!result.token) {
+            throw new Error('登录响应数据为空');
+        }
+        await finishLogin(result);
+    };
+
+    const handlePasswordChangeSubmit = async () => {
+        if (!passwordChange) {
+            return;
+        }
+        if (newPassword.password !== newPassword.confirm) {
+            message.error('两次输入的密码不一致');
+            return;
+        }
+        try {
+            const response = await changeExpiredPassword({
+                passwordChangeToken: passwordChange.token,
+                newPassword: newPassword.password,
+            });
+            if (!response.data?.token) {
+                throw new Error('登录响应数据为空');
+            }
+            setPasswordChange(null);
+            await finishLogin(response.data);
+        } catch (error: any) {
+            message.error(error.message || '修改密码失败，请重试！');
+        }
+    };
+
+    const handleTwoFactorSubmit = async () => {
+        if (!twoFactor) {
+            return;
+        }
+        try {
+            const response = await verifyTwoFactor({
+                twoFactorToken: twoFactor.token,
+                code: twoFactorCode.trim(),
+            });
+            if (!response.data) {
+                throw new Error('登录响应数据为空');
+            }
+            const tokens = response.data;
+            const recoveryCodes = tokens.recoveryCodes || [];
+            setTwoFactor(null);
+            if (recoveryCodes.length === 0) {
+                await proceedLogin(tokens);
+                return;
+            }
+            // 首次绑定返回的恢复码只展示一次，确认保存后再进入系统。
+            modal.info({
+                title: '请妥善保存恢复码',
+                content: (
+                    <div>
+                        <p>认证设备丢失时可使用恢复码登录，每个恢复码只能使用一次。</p>
+                        <pre>{recoveryCodes.join('\n')}</pre>
+                    </div>
+                ),
+                okText: '已保存',
+                onOk: () => proceedLogin(tokens),
+            });
+        } catch (error: any) {
+            message.error(error.message || '验证失败，请重试！');
+        }
+    };
+
+    // 使用已绑定的通行密钥完成两步验证。
+    const handleTwoFactorPasskey = async () => {
+        if (!twoFactor) {
+            return;
+        }
+        try {
+            const begin = await beginPasskeyTwoFactor(twoFactor.token);
+            if (!begin.data) {
+                throw new Error('获取通行密钥参数失败');
+            }
+            const credential = await getPasskey(begin.data.options);
+            const response = await verifyTwoFactorPasskey(twoFactor.token, credential);
+            if (!response.data) {
+                throw new Error('登录响应数据为空');
+            }
+            setTwoFactor(null);
+            await proceedLogin(response.data);
+        } catch (error: any) {
+            message.error(error.message || '验证失败，请重试！');
+        }
+    };
+
+    // 免密码登录：由浏览器选择本站点的通行密钥，通过后直接进入系统。
+    const handlePasskeyLogin = async () => {
+        try {
+            const begin = await beginPasskeyLogin();
+            if (!begin.data?.token) {
+                throw new Error('获取通行密钥参数失败');
+            }
+            const credential = await getPasskey(begin.data.options);
+            const response = await finishPasskeyLogin(begin.data.token, credential);
+            await handleLoginResult(response.data);
+        } catch (error: any) {
+            setUserLoginState({ status: 'error', message: error.message || '通行密钥登录失败，请重试！' });
+        }
+    };
+
+    // 密码登录与单点登录共用：需要两步验证时先弹出动态码输入框。
+    const handleLoginResult = async (result?: LoginResult) => {
+        if (result?.twoFactorToken) {
+            const pending: {token: string; setup: boolean; methods: string[]; image?: string; secret?: string} = {
+                token: result.twoFactorToken,
+                setup: !!result.twoFactorSetup,
+                methods: result.twoFactorMethods || ['totp'],
+            };
+            if (pending.setup) {
+                const keyResponse = await setupPendingTwoFactor(pending.token);
+                pending.image = keyResponse.data?.image;
+                pending.secret = keyResponse.data?.secret;
+            }
+            setTwoFactorCode('');
+            setTwoFactor(pending);
+            return;
+        }
+
+        // 登录成功（响应拦截器已处理错误情况，这里只会收到成功的响应）
+        if (!result) {
+            throw new Error('登录响应数据为空');
+        }
+        await proceedLogin(result);
+    };
+
+    // 单点登录回调：身份提供方重定向回后台入口时，code 与 state 位于 hash 之前的查询串中。
+    React.useEffect(() => {
+        const params = new URLSearchParams(window.location.search);
+        const code = params.get('code');
+        const state = params.get('state');
+        const error = params.get('error');
+        if (!state || (!code && !error)) {
+            return;
+        }
+        // 回调参数只能使用一次，处理前先从地址栏移除。
+        window.history.replaceState(null, '', window.location.pathname + window.location.hash);
+        ssoRedirect.current = sessionStorage.getItem(SSO_REDIRECT_KEY);
+        sessionStorage.removeItem(SSO_REDIRECT_KEY);
+        if (!code) {
+            setUserLoginState({ status: 'error', message: params.get('error_description') || '单点登录已取消' });
+            return;
+        }
+        ssoCallback({ state, code })
+            .then((response) => handleLoginResult(response.data))
+            .catch((e: any) => {
+                setUserLoginState({ status: 'error', message: e.message || '单点登录失败，请重试！' });
+            });
+    }, []);
+
+    // 找回密码邮件中的链接形如 #/auth/login?reset_token=...，令牌只能使用一次，读取后从地址栏移除。
+    React.useEffect(() => {
+        const params = new URLSearchParams(history.location.search);
+        const token = params.get('reset_token');
+        if (!token) {
+            return;
+        }
+        history.replace(history.location.pathname);
+        setReset({ token, password: '', confirm: '', captchaCode: '' });
+    }, []);
+
+    const handleForgotSubmit = async () => {
+        if (!forgot) {
+            return;
+        }
+        try {
+            const response = await forgotPassword({
+                account: forgot.account.trim(),
+                captchaId: captchaData.id,
+                captchaCode: forgot.captchaCode,
+            });
+            setForgot(null);
+            message.success(response.msg || '重置密码邮件已发送');
+        } catch (error: any) {
+            message.error(error.message || '发送失败，请重试！');
+        }
+        fetchCaptcha();
+    };
+
+    const handleResetSubmit = async () => {
+        if (!reset) {
+            return;
+        }
+        if (reset.password !== reset.confirm) {
+            message.error('两次输入的密码不一致');
+            return;
+        }
+        try {
+            const response = await resetPassword({
+                token: reset.token,
+                newPassword: reset.password,
+                captchaId: captchaData.id,
+                captchaCode: reset.captchaCode,
+            });
+            setReset(null);
+            form.setFieldsValue({ password: '' });
+            message.success(response.msg || '密码已重置，请使用新密码登录');
+        } catch (error: any) {
+            message.error(error.message || '重置密码失败，请重试！');
+        }
+        fetchCaptcha();
+    };
+
+    // 弹窗中的验证码与登录表单共用同一张验证码图片。
+    const renderCaptchaInput = (value: string, onChange: (value: string) => void, onPressEnter: () => void) => (
+        <Captcha captcha={captchaData} value={value} onChange={onChange} onRefresh={fetchCaptcha} onPressEnter={onPressEnter} />
+    );
+
+    const handleSSOLogin = async (provider: string) => {
+        try {
+            const response = await ssoAuthorize(provider);
+            if (!response.data?.url) {
+                throw new Error('获取登录地址失败');
+            }
+            sessionStorage.setItem(SSO_REDIRECT_KEY, getRedirectPath());
+            window.location.href = response.data.url;
+        } catch (error: any) {
+            setUserLoginState({ status: 'error', message: error.message || '单点登录失败，请重试！' });
+        }
+    };
+
     const handleSubmit = async (values: API.LoginParams & { rememberPassword?: boolean; captchaCode?: string }) => {
         try {
             // 调用后端登录接口
             const response = await login({
                 ...values,
-                captchaId: captchaData.id,
-                captchaCode: values.captchaCode || '',
+                captchaId: showLoginCaptcha ? captchaData.id : '',
+                captchaCode: showLoginCaptcha ? values.captchaCode || '' : '',
             });
             
-            // 登录成功（响应拦截器已处理错误情况，这里只会收到成功的响应）
-            if (!response.data?.token) {
-                throw new Error('登录响应数据为空');
-            }
-            
-            // 保存 token
-            localStorage.setItem('token', response.data.token);
-            
             // 处理记住密码
             if (values.rememberPassword) {
                 saveCredentials(values.username || '', values.password || '');
             } else {
                 clearCredentials();
             }
-            
-            // 更新全局状态（获取用户信息）
-            await fetchUserInfo();
-            
-            const defaultLoginSuccessMessage = intl.formatMessage({
-                id: "pages.login.success",
-                defaultMessage: "登录成功！",
-            });
-            message.success(defaultLoginSuccessMessage);
-            
-            // 使用 Umi history.replace 进行软路由跳转，避免整页重载导致空白和二次加载静态资源
-            history.replace(getRedirectPath());
+
+            await handleLoginResult(response.data);
         } catch (error: any) {
+            if (error?.data?.code === 428) {
+                setCaptchaNeeded(true);
+            }
             // 响应拦截器已将后端错误信息放到 error.message 中
             setUserLoginState({ 
                 status: 'error',
@@ -213,6 +466,23 @@ const Login: React.FC = () => {
                     initialValues={{
                         rememberPassword: false,
                     }}
+                    actions={(ssoProviders.length > 0 || passkeyEnabled) && (
+                        <>
+                            <Divider plain>其他登录方式</Divider>
+                            <Space wrap style={{ width: '100%', justifyContent: 'center' }}>
+                                {passkeyEnabled && (
+                                    <Button icon={<KeyOutlined />} onClick={handlePasskeyLogin}>
+                                        使用通行密钥登录
+                                    </Button>
+                                )}
+                                {ssoProviders.map((provider) => (
+                                    <Button key={provider.name} onClick={() => handleSSOLogin(provider.name)}>
+                                        {provider.label}
+                                    </Button>
+                                ))}
+                            </Space>
+                        </>
+                    )}
                     onFinish={async (values) => {
                         await handleSubmit(values as API.LoginParams);
                     }}
@@ -266,37 +536,19 @@ const Login: React.FC = () => {
                             },
                         ]}
                     />
-                    <div style={{ display: 'flex', gap: 8 }}>
-                        <ProFormText
+                    {showLoginCaptcha && (
+                        <ProForm.Item
                             name="captchaCode"
-                            fieldProps={{
-                                size: "large",
-                                autoFocus: false,
-                                maxLength: 4,
-                            }}
-                            placeholder="请输入验证码"
                             rules={[
                                 {
                                     required: true,
-                                    message: "请输入验证码！",
+                                    message: captchaData.type === 'slider' ? "请拖动滑块完成拼图！" : "请输入验证码！",
                                 },
                             ]}
-                            style={{ flex: 1 }}
-                        />
-                        <img
-                            src={captchaData.image}
-                            alt="验证码"
-                            onClick={fetchCaptcha}
-                            style={{
-                                height: 40,
-                                cursor: 'pointer',
-                                borderRadius: 8,
-                                border: '1px solid #d9d9d9',
-                                backgroundColor: '#fff',
-                            }}
-                            title="点击刷新验证码"
-                        />
-                    </div>
+                        >
+                            <Captcha captcha={captchaData} onRefresh={fetchCaptcha} />
+                        </ProForm.Item>
+                    )}
                     <div
                         style={{
                             marginBottom: 24,
@@ -308,8 +560,138 @@ const Login: React.FC = () => {
                                 defaultMessage="记住密码"
                             />
                         </ProFormCheckbox>
+                        {passwordResetEnabled && (
+                            <a
+                                style={{ float: 'right' }}
+                                onClick={() => {
+                                    setForgot({ account: form.getFieldValue('username') || '', captchaCode: '' });
+                                    fetchCaptcha();
+                                }}
+                            >
+                                忘记密码
+                            </a>
+                        )}
                     </div>
                 </LoginForm>
+                <Modal
+                    open={!!twoFactor}
+                    title={twoFactor?.setup ? '绑定两步验证' : '两步验证'}
+                    okText="验证"
+                    okButtonProps={{ disabled: !twoFactorCode.trim() }}
+                    // 仅绑定了通行密钥时没有动态码可提交，不展示确认按钮。
+                    footer={twoFactor?.methods.includes('totp') ? undefined : null}
+                    onOk={handleTwoFactorSubmit}
+                    onCancel={() => {
+                        setTwoFactor(null);
+                        fetchCaptcha();
+                    }}
+                    destroyOnHidden
+                >
+                    {twoFactor?.setup && (
+                        <div style={{ textAlign: 'center', marginBottom: 16 }}>
+                            <p>所属角色要求开启两步验证，请使用认证器 App 扫描二维码：</p>
+                            {twoFactor.image && <img src={twoFactor.image} alt="二维码" width={200} height={200} />}
+                            <p style={{ wordBreak: 'break-all' }}>密钥：{twoFactor.secret}</p>
+                        </div>
+                    )}
+                    {twoFactor?.methods.includes('passkey') && (
+                        <Button block size="large" icon={<KeyOutlined />} onClick={handleTwoFactorPasskey} style={{ marginBottom: 12 }}>
+                            使用通行密钥验证
+                        </Button>
+                    )}
+                    {twoFactor?.methods.includes('totp') && (
+                        <Input
+                            size="large"
+                            value={twoFactorCode}
+                            onChange={(e) => setTwoFactorCode(e.target.value)}
+                            onPressEnter={handleTwoFactorSubmit}
+                            placeholder={twoFactor?.setup ? '请输入认证器中的 6 位动态码' : '请输入 6 位动态码或恢复码'}
+                            autoFocus
+                        />
+                    )}
+                </Modal>
+                <Modal
+                    open={!!passwordChange}
+                    title="修改密码"
+                    okText="确认修改"
+                    okButtonProps={{ disabled: !newPassword.password || !newPassword.confirm }}
+                    onOk={handlePasswordChangeSubmit}
+                    onCancel={() => {
+                        setPasswordChange(null);
+                        fetchCaptcha();
+                    }}
+                    destroyOnHidden
+                >
+                    <p>{passwordChange?.expired ? '密码已过期，请设置新密码后继续登录。' : '管理员要求修改密码，请设置新密码后继续登录。'}</p>
+                    <Input.Password
+                        size="large"
+                        value={newPassword.password}
+                        onChange={(e) => setNewPassword((s) => ({ ...s, password: e.target.value }))}
+                        placeholder="请输入新密码"
+                        style={{ marginBottom: 12 }}
+                        autoFocus
+                    />
+                    <Input.Password
+                        size="large"
+                        value={newPassword.confirm}
+                        onChange={(e) => setNewPassword((s) => ({ ...s, confirm: e.target.value }))}
+                        onPressEnter={handlePasswordChangeSubmit}
+                        placeholder="请再次输入新密码"
+                    />
+                </Modal>
+                <Modal
+                    open={!!forgot}
+                    title="找回密码"
+                    okText="发送重置邮件"
+                    okButtonProps={{ disabled: !forgot?.account.trim() || !forgot?.captchaCode }}
+                    onOk={handleForgotSubmit}
+                    onCancel={() => {
+                        setForgot(null);
+                        fetchCaptcha();
+                    }}
+                    destroyOnHidden
+                >
+                    <p>请输入用户名或绑定的邮箱，我们会向账号绑定的邮箱发送重置链接。</p>
+                    <Input
+                        size="large"
+                        value={forgot?.account}
+                        onChange={(e) => setForgot((s) => s && { ...s, account: e.target.value })}
+                        placeholder="用户名或邮箱"
+                        style={{ marginBottom: 12 }}
+                        autoFocus
+                    />
+                    {renderCaptchaInput(forgot?.captchaCode || '', (v) => setForgot((s) => s && { ...s, captchaCode: v }), handleForgotSubmit)}
+                </Modal>
+                <Modal
+                    open={!!reset}
+                    title="重置密码"
+                    okText="确认重置"
+                    okButtonProps={{ disabled: !reset?.password || !reset?.confirm || !reset?.captchaCode }}
+                    onOk={handleResetSubmit}
+                    onCancel={() => {
+                        setReset(null);
+                        fetchCaptcha();
+                    }}
+                    destroyOnHidden
+                >
+                    <p>请设置新密码，重置后已登录的设备需要重新登录。</p>
+                    <Input.Password
+                        size="large"
+                        value={reset?.password}
+                        onChange={(e) => setReset((s) => s && { ...s, password: e.target.value })}
+                        placeholder="请输入新密码"
+                        style={{ marginBottom: 12 }}
+                        autoFocus
+                    />
+                    <Input.Password
+                        size="large"
+                        value={reset?.confirm}
+                        onChange={(e) => setReset((s) => s && { ...s, confirm: e.target.value })}
+                        placeholder="请再次输入新密码"
+                        style={{ marginBottom: 12 }}
+                    />
+                    {renderCaptchaInput(reset?.captchaCode || '', (v) => setReset((s) => s && { ...s, captchaCode: v }), handleResetSubmit)}
+                </Modal>
             </div>
         </div>
     );
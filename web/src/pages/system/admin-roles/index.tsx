@@ -2,15 +2,15 @@
  * 角色管理 - 使用 CrudTable 重构
  */
 import type { ProColumns } from '@ant-design/pro-components';
-import { ProFormText, ProFormTextArea, ProFormSwitch } from '@ant-design/pro-components';
-import { Tag, Space, Drawer, Tree, Button, message, Tooltip } from 'antd';
+import { ProFormText, ProFormTextArea, ProFormSwitch, ProFormSelect } from '@ant-design/pro-components';
+import { Tag, Space, Drawer, Tree, Button, message, Tooltip, Tabs, Select, Typography } from 'antd';
 import React, { useState, useEffect, useCallback, useRef } from 'react';
 import type { ActionType } from '@ant-design/pro-components';
 import { useAccess } from '@umijs/max';
 import CrudTable from '@/components/CrudTable';
 import { createCrudService } from '@/services/crud';
-import { getAllPermissions, updateRolePermissions } from '@/services/system/admin-role';
-import type { Permission } from '@/services/system/admin-role/types';
+import { getAllAdminRoles, getAllPermissions, updateRoleDenies, updateRoleParents, updateRolePermissions } from '@/services/system/admin-role';
+import type { AdminRole as RoleOption, Permission } from '@/services/system/admin-role/types';
 
 // 类型定义
 interface AdminRole {
@@ -19,7 +19,10 @@ interface AdminRole {
   system: boolean;
   description: string;
   enabled: boolean;
+  allowed_ips?: string[] | null;
   permissions?: string[];
+  denies?: string[] | null;
+  parent_ids?: number[] | null;
   created_at: string;
 }
 
@@ -57,6 +60,14 @@ const FormContent: React.FC = () => (
   <>
     <ProFormText name="name" label="角色名称" placeholder="请输入角色名称" rules={[{ required: true }]} />
     <ProFormTextArea name="description" label="描述" placeholder="请输入描述" />
+    <ProFormSelect
+      name="allowed_ips"
+      label="允许登录的 IP"
+      mode="tags"
+      placeholder="输入 IP 或 CIDR 后回车，留空不限制"
+      tooltip="拥有该角色的用户只能从这些地址登录与访问后台"
+      fieldProps={{ tokenSeparators: [',', ' '], open: false }}
+    />
     <ProFormSwitch name="enabled" label="状态" initialValue={true} />
   </>
 );
@@ -89,6 +100,8 @@ const filterRedundant = (perms: string[], tree: Permission[]): string[] => {
   return Array.from(filtered);
 };
 
+const isWildcard = (p: string) => p.endsWith(':*');
+
 const expandPerms = (perms: string[], tree: Permission[]): string[] => {
   const expanded = new Set<string>();
   perms.forEach((p) => {
@@ -105,32 +118,64 @@ export default function AdminRoleList() {
   const [currentRole, setCurrentRole] = useState<AdminRole>();
   const [allPermissions, setAllPermissions] = useState<Permission[]>([]);
   const [selectedKeys, setSelectedKeys] = useState<string[]>([]);
+  const [wildcards, setWildcards] = useState<string[]>([]);
+  const [denyKeys, setDenyKeys] = useState<string[]>([]);
+  const [denyWildcards, setDenyWildcards] = useState<string[]>([]);
+  const [parentIds, setParentIds] = useState<number[]>([]);
+  const [roleOptions, setRoleOptions] = useState<RoleOption[]>([]);
 
   useEffect(() => {
     getAllPermissions().then((res) => res.code === 0 && setAllPermissions(res.data || []));
   }, []);
 
   const handleOpenDrawer = useCallback((record: AdminRole) => {
+    const perms = record.permissions || [];
+    const denies = record.denies || [];
     setCurrentRole(record);
-    setSelectedKeys(filterRedundant(record.permissions || [], allPermissions));
+    setSelectedKeys(filterRedundant(perms.filter((p) => !isWildcard(p)), allPermissions));
+    setWildcards(perms.filter(isWildcard));
+    setDenyKeys(denies.filter((p) => !isWildcard(p)));
+    setDenyWildcards(denies.filter(isWildcard));
+    setParentIds(record.parent_ids || []);
     setDrawerOpen(true);
+    getAllAdminRoles().then((res) => res.code === 0 && setRoleOptions(res.data || []));
   }, [allPermissions]);
 
   const handleSavePermissions = useCallback(async () => {
     if (!currentRole) return;
     try {
-      const res = await updateRolePermissions(currentRole.id, { permissions: expandPerms(selectedKeys, allPermissions) });
-      if (res.code === 0) {
-        message.success('权限配置成功');
-        setDrawerOpen(false);
-        actionRef.current?.reload();
-      } else {
-        message.error(res.msg || '配置失败');
+      // 依次保存授权、拒绝项与继承角色，任一步失败即停止并提示。
+      const steps = [
+        () => updateRolePermissions(currentRole.id, { permissions: [...expandPerms(selectedKeys, allPermissions), ...wildcards] }),
+        () => updateRoleDenies(currentRole.id, { permissions: [...denyKeys, ...denyWildcards] }),
+        () => updateRoleParents(currentRole.id, { parent_ids: parentIds }),
+      ];
+      for (const save of steps) {
+        const res = await save();
+        if (res.code !== 0) {
+          message.error(res.msg || '配置失败');
+          return;
+        }
       }
+      message.success('权限配置成功');
+      setDrawerOpen(false);
+      actionRef.current?.reload();
     } catch (e: any) {
       message.error(e.message || '配置失败');
     }
-  }, [currentRole, selectedKeys, allPermissions]);
+  }, [currentRole, selectedKeys, wildcards, denyKeys, denyWildcards, parentIds, allPermissions]);
+
+  const wildcardSelect = (value: string[], onChange: (v: string[]) => void) => (
+    <Select
+      mode="tags"
+      style={{ width: '100%', marginBottom: 12 }}
+      placeholder="通配符，如 system:* 或 system:admin_user:*，回车添加"
+      value={value}
+      onChange={onChange}
+      open={false}
+      tokenSeparators={[',', ' ']}
+    />
+  );
 
   return (
     <>
@@ -140,7 +185,7 @@ export default function AdminRoleList() {
         service={roleService}
         columns={columns}
         formContent={<FormContent />}
-        recordToValues={(r) => ({ name: r.name, description: r.description, enabled: r.enabled })}
+        recordToValues={(r) => ({ name: r.name, description: r.description, allowed_ips: r.allowed_ips || [], enabled: r.enabled })}
         transformParams={(params) => ({
           ...params,
           enabled: params.enabled === 'true' ? true : params.enabled === 'false' ? false : undefined,
@@ -178,7 +223,7 @@ export default function AdminRoleList() {
 
       <Drawer
         title="配置权限"
-        width={500}
+        width={560}
         open={drawerOpen}
         onClose={() => setDrawerOpen(false)}
         extra={
@@ -188,12 +233,67 @@ export default function AdminRoleList() {
           </Space>
         }
       >
-        <Tree
-          checkable
-          defaultExpandAll
-          checkedKeys={selectedKeys}
-          onCheck={(keys) => setSelectedKeys(Array.isArray(keys) ? (keys as string[]) : (keys.checked as string[]))}
-          treeData={convertToTreeData(allPermissions)}
+        <Tabs
+          items={[
+            {
+              key: 'grants',
+              label: '授权',
+              children: (
+                <>
+                  {wildcardSelect(wildcards, setWildcards)}
+                  <Tree
+                    checkable
+                    defaultExpandAll
+                    checkedKeys={selectedKeys}
+                    onCheck={(keys) => setSelectedKeys(Array.isArray(keys) ? (keys as string[]) : (keys.checked as string[]))}
+                    treeData={convertToTreeData(allPermissions)}
+                  />
+                </>
+              ),
+            },
+            {
+              key: 'denies',
+              label: '拒绝',
+              children: (
+                <>
+                  <Typography.Paragraph type="secondary">
+                    拒绝项优先于任何角色（含继承）授予的权限，拥有该角色的用户都无法使用。
+                  </Typography.Paragraph>
+                  {wildcardSelect(denyWildcards, setDenyWildcards)}
+                  <Tree
+                    checkable
+                    checkStrictly
+                    defaultExpandAll
+                    checkedKeys={denyKeys}
+                    onCheck={(keys) => setDenyKeys(Array.isArray(keys) ? (keys as string[]) : (keys.checked as string[]))}
+                    treeData={convertToTreeData(allPermissions)}
+                  />
+                </>
+              ),
+            },
+            {
+              key: 'parents',
+              label: '继承角色',
+              children: (
+                <>
+                  <Typography.Paragraph type="secondary">
+                    继承父角色及其祖先的全部授权与拒绝项，父角色停用后不再继承。
+                  </Typography.Paragraph>
+                  <Select
+                    mode="multiple"
+                    style={{ width: '100%' }}
+                    placeholder="选择要继承的角色"
+                    value={parentIds}
+                    onChange={setParentIds}
+                    optionFilterProp="label"
+                    options={roleOptions
+                      .filter((r) => !r.system && r.id !== currentRole?.id)
+                      .map((r) => ({ label: r.name, value: r.id }))}
+                  />
+                </>
+              ),
+            },
+          ]}
         />
       </Drawer>
     </>
@@ -1,33 +1,95 @@
 /**
  * 用户管理 - 使用 CrudTable 重构
  */
-import type { ProColumns } from '@ant-design/pro-components';
-import { ProFormText, ProFormSwitch, ProFormSelect } from '@ant-design/pro-components';
-import { Avatar, Tag, Space, Form } from 'antd';
-import React, { useCallback, useState, useEffect } from 'react';
+import { ClockCircleOutlined } from '@ant-design/icons';
+import type { ActionType, ProColumns } from '@ant-design/pro-components';
+import {
+  ProForm,
+  ProFormDateTimeRangePicker,
+  ProFormText,
+  ProFormSwitch,
+  ProFormSelect,
+  ProFormTreeSelect,
+  ProFormDependency,
+} from '@ant-design/pro-components';
+import { Avatar, Button, Modal, Select, Table, Tag, Tooltip, Space, Form, Popconfirm, message } from 'antd';
+import dayjs from 'dayjs';
+import React, { useCallback, useState, useEffect, useMemo, useRef } from 'react';
+import { useAccess, useModel } from '@umijs/max';
 import AvatarUpload from '@/components/AvatarUpload';
 import CrudTable from '@/components/CrudTable';
 import { createCrudService } from '@/services/crud';
 import { getAllAdminRoles } from '@/services/system/admin-role';
+import {
+  getExpiringRoleGrants,
+  getUserRoleGrants,
+  impersonateUser,
+  revokeUserRoleGrant,
+  saveUserRoleGrant,
+  type RoleGrant,
+} from '@/services/system/admin-user';
+import { getDepartmentTree, toTreeSelectData, type Department } from '@/services/system/department';
 import { uploadAvatar } from '@/services/auth/profile';
 import { MIN_PASSWORD_LENGTH } from '@/utils/validation';
+import { saveTokens } from '@/utils/token';
 
 // 类型定义
 interface AdminUser {
   id: number;
   username: string;
   name: string;
+  email: string;
   avatar: string;
   enabled: boolean;
+  must_change_password: boolean;
+  allowed_ips?: string[] | null;
   roles?: { id: number; name: string }[];
+  role_grants?: { role_id: number; valid_from?: string | null; valid_until?: string | null }[];
+  department_ids?: number[];
+  primary_department_id?: number;
   created_at: string;
 }
 
 // CRUD 服务
 const userService = createCrudService<AdminUser>('/admin-users');
 
+// 部门树选择数据
+const departmentTreeRequest = async () => {
+  const res = await getDepartmentTree();
+  return toTreeSelectData(res.data || []);
+};
+
+// 展开部门树为 id → 名称
+const flattenDepartments = (items: Department[], names: Record<number, string> = {}) => {
+  items.forEach((item) => {
+    names[item.id] = item.name;
+    flattenDepartments(item.children || [], names);
+  });
+  return names;
+};
+
+// 授权有效期描述
+const formatGrantPeriod = (grant: { valid_from?: string | null; valid_until?: string | null }) => {
+  const format = (value?: string | null) => (value ? dayjs(value).format('YYYY-MM-DD HH:mm') : '');
+  if (!grant.valid_from && !grant.valid_until) {
+    return '永久';
+  }
+  if (!grant.valid_until) {
+    return `${format(grant.valid_from)} 起`;
+  }
+  return `${grant.valid_from ? format(grant.valid_from) : '即日'} 至 ${format(grant.valid_until)}`;
+};
+
+// 授权状态标签
+const renderGrantStatus = (grant: RoleGrant) => {
+  if (grant.active) {
+    return <Tag color="green">生效中</Tag>;
+  }
+  return <Tag>未生效</Tag>;
+};
+
 // 列配置
-const columns: ProColumns<AdminUser>[] = [
+const buildColumns = (departmentNames: Record<number, string>): ProColumns<AdminUser>[] => [
   { title: 'ID', dataIndex: 'id', width: 80, search: false },
   { title: '用户名', dataIndex: 'username', width: 150 },
   {
@@ -38,6 +100,7 @@ const columns: ProColumns<AdminUser>[] = [
     render: (_, r) => <Avatar src={r.avatar} alt={`${r.name || r.username}的头像`} size={40} />,
   },
   { title: '姓名', dataIndex: 'name', width: 150 },
+  { title: '邮箱', dataIndex: 'email', width: 200, search: false },
   {
     title: '角色',
     dataIndex: 'role_ids',
@@ -48,7 +111,43 @@ const columns: ProColumns<AdminUser>[] = [
       return (res.data || []).map((r: any) => ({ label: r.name, value: r.id }));
     },
     width: 200,
-    render: (_, r) => <Space size={4}>{r.roles?.map((role) => <Tag key={role.id} color="blue">{role.name}</Tag>)}</Space>,
+    render: (_, r) => (
+      <Space size={4} wrap>
+        {r.roles?.map((role) => {
+          const grant = r.role_grants?.find((g) => g.role_id === role.id);
+          if (!grant) {
+            return <Tag key={role.id} color="blue">{role.name}</Tag>;
+          }
+          // 限时授权：未生效显示为灰色，生效中显示到期时间
+          const pending = !!grant.valid_from && dayjs(grant.valid_from).isAfter(dayjs());
+          return (
+            <Tooltip key={role.id} title={formatGrantPeriod(grant)}>
+              <Tag color={pending ? 'default' : 'orange'} icon={<ClockCircleOutlined />}>
+                {role.name}
+              </Tag>
+            </Tooltip>
+          );
+        })}
+      </Space>
+    ),
+  },
+  {
+    title: '部门',
+    dataIndex: 'department_id',
+    valueType: 'treeSelect',
+    tooltip: '筛选时包含下级部门成员',
+    request: departmentTreeRequest,
+    fieldProps: { treeDefaultExpandAll: true },
+    width: 200,
+    render: (_, r) => (
+      <Space size={4} wrap>
+        {r.department_ids?.map((id) => (
+          <Tag key={id} color={id === r.primary_department_id ? 'gold' : undefined}>
+            {departmentNames[id] || id}
+          </Tag>
+        ))}
+      </Space>
+    ),
   },
   {
     title: '状态',
@@ -113,6 +212,15 @@ const FormContent: React.FC<{ record?: AdminUser }> = ({ record }) => {
         />
       )}
       <ProFormText name="name" label="姓名" placeholder="请输入姓名" />
+      <ProFormText
+        name="email"
+        label="邮箱"
+        placeholder="用于接收找回密码邮件"
+        rules={[
+          { type: 'email', message: '邮箱格式错误' },
+          { max: 128, message: '邮箱不能超过128位' },
+        ]}
+      />
       <ProFormText name="avatar" hidden />
       <div style={{ marginBottom: 24 }}>
         <AvatarUpload
@@ -131,6 +239,41 @@ const FormContent: React.FC<{ record?: AdminUser }> = ({ record }) => {
           return (res.data || []).map((r: any) => ({ label: r.name, value: r.id }));
         }}
       />
+      <ProFormTreeSelect
+        name="department_ids"
+        label="所属部门"
+        request={departmentTreeRequest}
+        fieldProps={{ multiple: true, treeDefaultExpandAll: true }}
+        transform={(value) => ({ department_ids: value || [] })}
+      />
+      <ProFormDependency name={['department_ids']}>
+        {({ department_ids }) => (
+          <ProFormSelect
+            name="primary_department_id"
+            label="主部门"
+            tooltip="未选择时以第一个部门为主部门"
+            disabled={!department_ids?.length}
+            params={{ department_ids }}
+            request={async () => {
+              const res = await getDepartmentTree();
+              const names = flattenDepartments(res.data || []);
+              return (department_ids || []).map((id: number) => ({ label: names[id] || String(id), value: id }));
+            }}
+            // 取消所属后主部门随之失效，交由后端取第一个部门
+            transform={(value, _, all) => ({
+              primary_department_id: all.department_ids?.includes(value) ? value : 0,
+            })}
+          />
+        )}
+      </ProFormDependency>
+      <ProFormSelect
+        name="allowed_ips"
+        label="允许登录的 IP"
+        mode="tags"
+        placeholder="输入 IP 或 CIDR 后回车，留空不限制"
+        tooltip="同时需满足所属角色配置的来源限制"
+        fieldProps={{ tokenSeparators: [',', ' '], open: false }}
+      />
       <ProFormSwitch name="enabled" label="状态" initialValue={true} />
       {isEdit && (
         <ProFormText.Password
@@ -140,29 +283,270 @@ const FormContent: React.FC<{ record?: AdminUser }> = ({ record }) => {
           rules={[{ min: MIN_PASSWORD_LENGTH, message: `密码长度至少${MIN_PASSWORD_LENGTH}位` }]}
         />
       )}
+      <ProFormSwitch
+        name="must_change_password"
+        label="下次登录修改密码"
+        initialValue={!isEdit}
+        tooltip="开启后用户下次登录必须先设置新密码"
+      />
     </>
   );
 };
 
+// 用户角色授权弹窗：查看全部授权，授予限时角色或撤销授权
+const RoleGrantsModal: React.FC<{ user?: AdminUser; editable: boolean; onClose: () => void }> = ({
+  user,
+  editable,
+  onClose,
+}) => {
+  const [grants, setGrants] = useState<RoleGrant[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [form] = Form.useForm();
+
+  const load = useCallback(async () => {
+    if (!user) return;
+    setLoading(true);
+    try {
+      const res = await getUserRoleGrants(user.id);
+      setGrants(res.data || []);
+    } finally {
+      setLoading(false);
+    }
+  }, [user]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  const handleRevoke = async (grant: RoleGrant) => {
+    const res = await revokeUserRoleGrant(grant.user_id, grant.id);
+    if (res.code !== 0) {
+      message.error(res.msg || '撤销失败');
+      return;
+    }
+    message.success('角色授权已撤销');
+    load();
+  };
+
+  return (
+    <Modal
+      title={`角色授权 - ${user?.name || user?.username || ''}`}
+      open={!!user}
+      width={800}
+      footer={null}
+      destroyOnHidden
+      onCancel={onClose}
+    >
+      {editable && (
+        <ProForm
+          form={form}
+          layout="inline"
+          style={{ marginBottom: 16 }}
+          submitter={{ searchConfig: { submitText: '授予' }, resetButtonProps: false }}
+          onFinish={async (values) => {
+            const res = await saveUserRoleGrant(user!.id, values);
+            if (res.code !== 0) {
+              message.error(res.msg || '授权失败');
+              return false;
+            }
+            message.success('角色授权已保存');
+            form.resetFields();
+            load();
+            return true;
+          }}
+        >
+          <ProFormSelect
+            name="role_id"
+            placeholder="选择角色"
+            rules={[{ required: true, message: '请选择角色' }]}
+            width="sm"
+            request={async () => {
+              const res = await getAllAdminRoles();
+              return (res.data || []).map((r: any) => ({ label: r.name, value: r.id }));
+            }}
+          />
+          <ProFormDateTimeRangePicker
+            name="period"
+            tooltip="留空表示不限，已有该角色时覆盖原有效期"
+            fieldProps={{ allowEmpty: [true, true], placeholder: ['立即生效', '永久有效'] }}
+            transform={(value) => {
+              const [from, until] = value || [];
+              return {
+                valid_from: from ? dayjs(from).toISOString() : undefined,
+                valid_until: until ? dayjs(until).toISOString() : undefined,
+              };
+            }}
+          />
+          <ProFormText name="reason" placeholder="授权原因" width="sm" rules={[{ max: 255 }]} />
+        </ProForm>
+      )}
+      <Table<RoleGrant>
+        rowKey="id"
+        size="small"
+        loading={loading}
+        dataSource={grants}
+        pagination={false}
+        columns={[
+          { title: '角色', dataIndex: 'role_name', width: 140 },
+          { title: '有效期', key: 'period', render: (_, g) => formatGrantPeriod(g) },
+          { title: '状态', dataIndex: 'active', width: 90, render: (_, g) => renderGrantStatus(g) },
+          { title: '原因', dataIndex: 'reason', ellipsis: true },
+          ...(editable
+            ? [
+                {
+                  title: '操作',
+                  key: 'action',
+                  width: 80,
+                  render: (_: unknown, g: RoleGrant) => (
+                    <Popconfirm title={`确定撤销角色 ${g.role_name} 吗？`} onConfirm={() => handleRevoke(g)}>
+                      <a style={{ color: '#ff4d4f' }}>撤销</a>
+                    </Popconfirm>
+                  ),
+                },
+              ]
+            : []),
+        ]}
+      />
+    </Modal>
+  );
+};
+
+// 即将到期的角色授权
+const ExpiringGrantsModal: React.FC<{ open: boolean; onClose: () => void }> = ({ open, onClose }) => {
+  const [days, setDays] = useState(7);
+  const [grants, setGrants] = useState<RoleGrant[]>([]);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    if (!open) return;
+    setLoading(true);
+    getExpiringRoleGrants(days)
+      .then((res) => setGrants(res.data || []))
+      .finally(() => setLoading(false));
+  }, [open, days]);
+
+  return (
+    <Modal title="即将到期的角色授权" open={open} width={800} footer={null} destroyOnHidden onCancel={onClose}>
+      <Space style={{ marginBottom: 16 }}>
+        到期时间在
+        <Select
+          value={days}
+          onChange={setDays}
+          style={{ width: 100 }}
+          options={[1, 7, 30, 90].map((d) => ({ label: `${d} 天`, value: d }))}
+        />
+        以内
+      </Space>
+      <Table<RoleGrant>
+        rowKey="id"
+        size="small"
+        loading={loading}
+        dataSource={grants}
+        pagination={false}
+        columns={[
+          { title: '用户', key: 'user', render: (_, g) => (g.name ? `${g.name}（${g.username}）` : g.username) },
+          { title: '角色', dataIndex: 'role_name', width: 140 },
+          {
+            title: '到期时间',
+            dataIndex: 'valid_until',
+            width: 160,
+            render: (_, g) => dayjs(g.valid_until).format('YYYY-MM-DD HH:mm'),
+          },
+          { title: '状态', dataIndex: 'active', width: 90, render: (_, g) => renderGrantStatus(g) },
+          { title: '原因', dataIndex: 'reason', ellipsis: true },
+        ]}
+      />
+    </Modal>
+  );
+};
+
+// 以目标用户身份登录，成功后整页重新加载以切换权限与菜单。
+const handleImpersonate = async (record: AdminUser) => {
+  try {
+    const res = await impersonateUser(record.id);
+    if (res.code !== 0 || !res.data?.token) {
+      message.error(res.msg || '模拟登录失败');
+      return;
+    }
+    saveTokens(res.data);
+    localStorage.removeItem('currentUser');
+    window.location.href = '/';
+  } catch (e: any) {
+    message.error(e?.message || '模拟登录失败');
+  }
+};
+
 export default function AdminUserList() {
+  const { initialState } = useModel('@@initialState');
+  const currentUser = initialState?.currentUser;
+  const canImpersonate = !!currentUser?.super_admin && !currentUser?.impersonated;
+  const access = useAccess() as Record<string, boolean>;
+  const actionRef = useRef<ActionType>(null);
+  const [departments, setDepartments] = useState<Department[]>([]);
+  const [grantUser, setGrantUser] = useState<AdminUser>();
+  const [expiringOpen, setExpiringOpen] = useState(false);
+
+  useEffect(() => {
+    getDepartmentTree().then((res) => setDepartments(res.data || []));
+  }, []);
+  const columns = useMemo(() => buildColumns(flattenDepartments(departments)), [departments]);
+
   return (
-    <CrudTable<AdminUser>
-      title="用户"
-      permissionPrefix="system:admin_user"
-      service={userService}
-      columns={columns}
-      formContent={<FormContent />}
-      recordToValues={(r) => ({
-        username: r.username,
-        name: r.name,
-        enabled: r.enabled,
-        role_ids: r.roles?.map((role) => role.id),
-      })}
-      transformParams={(params) => ({
-        ...params,
-        role_ids: Array.isArray(params.role_ids) ? params.role_ids.join(',') : params.role_ids,
-        enabled: params.enabled === 'true' ? true : params.enabled === 'false' ? false : undefined,
-      })}
-    />
+    <>
+      <CrudTable<AdminUser>
+        title="用户"
+        actionRef={actionRef}
+        permissionPrefix="system:admin_user"
+        service={userService}
+        columns={columns}
+        formContent={<FormContent />}
+        recordToValues={(r) => ({
+          username: r.username,
+          name: r.name,
+          email: r.email,
+          enabled: r.enabled,
+          must_change_password: r.must_change_password,
+          allowed_ips: r.allowed_ips || [],
+          role_ids: r.roles?.map((role) => role.id),
+          department_ids: r.department_ids || [],
+          primary_department_id: r.primary_department_id || undefined,
+        })}
+        actionColumnWidth={canImpersonate ? 240 : 200}
+        toolBarExtra={[
+          <Button key="expiring" icon={<ClockCircleOutlined />} onClick={() => setExpiringOpen(true)}>
+            即将到期授权
+          </Button>,
+        ]}
+        renderActions={(record, defaultActions) => (
+          <Space>
+            <a onClick={() => setGrantUser(record)}>授权</a>
+            {canImpersonate && record.id !== currentUser?.id && record.enabled && (
+              <Popconfirm
+                title={`以 ${record.name || record.username} 的身份登录？`}
+                description="最长 1 小时，可随时在页面顶部退出模拟"
+                onConfirm={() => handleImpersonate(record)}
+              >
+                <a>登录为</a>
+              </Popconfirm>
+            )}
+            {defaultActions}
+          </Space>
+        )}
+        transformParams={(params) => ({
+          ...params,
+          role_ids: Array.isArray(params.role_ids) ? params.role_ids.join(',') : params.role_ids,
+          enabled: params.enabled === 'true' ? true : params.enabled === 'false' ? false : undefined,
+        })}
+      />
+      <RoleGrantsModal
+        user={grantUser}
+        editable={!!access['system:admin_user:edit']}
+        onClose={() => {
+          setGrantUser(undefined);
+          actionRef.current?.reload();
+        }}
+      />
+      <ExpiringGrantsModal open={expiringOpen} onClose={() => setExpiringOpen(false)} />
+    </>
   );
 }
@@ -0,0 +1,214 @@
+/**
+ * 部门管理 - 部门树、负责人与移动
+ */
+import { PlusOutlined } from '@ant-design/icons';
+import type { ActionType, ProColumns } from '@ant-design/pro-components';
+import {
+  ModalForm,
+  ProFormDigit,
+  ProFormSelect,
+  ProFormSwitch,
+  ProFormText,
+  ProFormTreeSelect,
+  ProTable,
+} from '@ant-design/pro-components';
+import { Button, Popconfirm, Space, Tag, message } from 'antd';
+import React, { useRef, useState } from 'react';
+import { useAccess } from '@umijs/max';
+import CrudModal from '@/components/CrudModal';
+import PageContainer from '@/components/PageContainer';
+import { createCrudService } from '@/services/crud';
+import { getDepartmentTree, moveDepartment, toTreeSelectData, type Department } from '@/services/system/department';
+
+// CRUD 服务
+const departmentService = createCrudService<Department>('/departments');
+const userService = createCrudService<{ id: number; username: string; name: string }>('/admin-users');
+
+// 负责人下拉按用户名或姓名搜索
+const searchLeaders = async ({ keyWords }: { keyWords?: string }) => {
+  const res = await userService.list({ page: 1, pageSize: 20, name: keyWords || undefined });
+  return (res.data || []).map((u) => ({ label: u.name ? `${u.name}（${u.username}）` : u.username, value: u.id }));
+};
+
+// 上级部门下拉，exclude 为当前部门，其自身及下级不可选
+const parentTreeRequest = (exclude?: number) => async () => {
+  const res = await getDepartmentTree();
+  return [{ title: '顶级部门', value: 0, children: toTreeSelectData(res.data || [], exclude) }];
+};
+
+// 表单内容组件
+const FormContent: React.FC<{ record?: Department; parentId?: number }> = ({ record, parentId = 0 }) => (
+  <>
+    {!record && (
+      <ProFormTreeSelect
+        name="parent_id"
+        label="上级部门"
+        initialValue={parentId}
+        request={parentTreeRequest()}
+        fieldProps={{ treeDefaultExpandAll: true }}
+      />
+    )}
+    <ProFormText name="name" label="部门名称" placeholder="请输入部门名称" rules={[{ required: true }, { max: 64 }]} />
+    <ProFormSelect
+      name="leader_id"
+      label="负责人"
+      showSearch
+      allowClear
+      debounceTime={300}
+      request={searchLeaders}
+      fieldProps={{
+        // 编辑时先展示已有负责人，搜索结果中可能不包含该用户
+        options: record?.leader_id ? [{ label: record.leader_name, value: record.leader_id }] : undefined,
+        filterOption: false,
+      }}
+      transform={(value) => ({ leader_id: value ?? 0 })}
+    />
+    <ProFormDigit name="sort" label="排序" initialValue={0} tooltip="同级部门按排序值升序" fieldProps={{ precision: 0 }} />
+    <ProFormSwitch name="enabled" label="状态" initialValue={true} />
+  </>
+);
+
+export default function DepartmentList() {
+  const access = useAccess() as Record<string, boolean>;
+  const actionRef = useRef<ActionType>(null);
+  const [modalOpen, setModalOpen] = useState(false);
+  const [currentRow, setCurrentRow] = useState<Department>();
+  const [parentId, setParentId] = useState(0);
+  const [moving, setMoving] = useState<Department>();
+
+  const reload = () => actionRef.current?.reload();
+
+  const openCreate = (parent = 0) => {
+    setCurrentRow(undefined);
+    setParentId(parent);
+    setModalOpen(true);
+  };
+
+  const handleDelete = async (record: Department) => {
+    const res = await departmentService.delete!(record.id);
+    if (res.code === 0) {
+      message.success('删除成功');
+      reload();
+    } else {
+      message.error(res.msg || '删除失败');
+    }
+  };
+
+  const columns: ProColumns<Department>[] = [
+    { title: '部门名称', dataIndex: 'name', width: 240 },
+    { title: '负责人', dataIndex: 'leader_name', width: 160, search: false },
+    { title: '排序', dataIndex: 'sort', width: 80, search: false },
+    {
+      title: '状态',
+      dataIndex: 'enabled',
+      width: 100,
+      valueType: 'select',
+      valueEnum: { true: { text: '启用', status: 'Success' }, false: { text: '禁用', status: 'Default' } },
+      render: (_, r) => <Tag color={r.enabled ? 'green' : 'red'}>{r.enabled ? '启用' : '禁用'}</Tag>,
+    },
+    { title: '创建时间', dataIndex: 'created_at', valueType: 'dateTime', width: 180, search: false },
+    {
+      title: '操作',
+      valueType: 'option',
+      width: 240,
+      render: (_, record) => (
+        <Space>
+          {access['system:department:create'] && <a onClick={() => openCreate(record.id)}>添加下级</a>}
+          {access['system:department:edit'] && (
+            <a
+              onClick={() => {
+                setCurrentRow(record);
+                setModalOpen(true);
+              }}
+            >
+              编辑
+            </a>
+          )}
+          {access['system:department:edit'] && <a onClick={() => setMoving(record)}>移动</a>}
+          {access['system:department:delete'] && (
+            <Popconfirm
+              title={`确定删除部门 ${record.name} 吗？`}
+              description="部门下仍有成员或子部门时不能删除"
+              onConfirm={() => handleDelete(record)}
+            >
+              <a style={{ color: '#ff4d4f' }}>删除</a>
+            </Popconfirm>
+          )}
+        </Space>
+      ),
+    },
+  ];
+
+  return (
+    <PageContainer>
+      <ProTable<Department>
+        rowKey="id"
+        actionRef={actionRef}
+        columns={columns}
+        search={{ labelWidth: 120 }}
+        pagination={false}
+        expandable={{ defaultExpandAllRows: true }}
+        request={async (params) => {
+          const res = await getDepartmentTree({
+            name: params.name || undefined,
+            enabled: params.enabled === 'true' ? true : params.enabled === 'false' ? false : undefined,
+          });
+          return { data: res.data || [], success: res.code === 0 };
+        }}
+        toolBarRender={() =>
+          access['system:department:create']
+            ? [
+                <Button key="create" type="primary" icon={<PlusOutlined />} onClick={() => openCreate()}>
+                  新建
+                </Button>,
+              ]
+            : []
+        }
+      />
+
+      <CrudModal<Department>
+        title="部门"
+        open={modalOpen}
+        onOpenChange={setModalOpen}
+        record={currentRow}
+        onCreate={departmentService.create}
+        onUpdate={departmentService.update}
+        onSuccess={() => {
+          setModalOpen(false);
+          reload();
+        }}
+        recordToValues={(r) => ({ name: r.name, leader_id: r.leader_id ?? undefined, sort: r.sort, enabled: r.enabled })}
+      >
+        <FormContent parentId={parentId} />
+      </CrudModal>
+
+      <ModalForm<{ parent_id: number; sort?: number }>
+        title={`移动部门 ${moving?.name || ''}`}
+        open={!!moving}
+        width={480}
+        modalProps={{ destroyOnHidden: true, onCancel: () => setMoving(undefined) }}
+        initialValues={{ parent_id: moving?.parent_id, sort: moving?.sort }}
+        onFinish={async (values) => {
+          const res = await moveDepartment(moving!.id, values);
+          if (res.code !== 0) {
+            message.error(res.msg || '移动失败');
+            return false;
+          }
+          message.success('部门已移动');
+          setMoving(undefined);
+          reload();
+          return true;
+        }}
+      >
+        <ProFormTreeSelect
+          name="parent_id"
+          label="上级部门"
+          rules={[{ required: true }]}
+          request={parentTreeRequest(moving?.id)}
+          fieldProps={{ treeDefaultExpandAll: true }}
+        />
+        <ProFormDigit name="sort" label="排序" fieldProps={{ precision: 0 }} />
+      </ModalForm>
+    </PageContainer>
+  );
+}
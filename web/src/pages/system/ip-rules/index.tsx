@@ -0,0 +1,98 @@
+/**
+ * IP 访问控制 - 全局允许/拒绝规则
+ */
+import type { ProColumns } from '@ant-design/pro-components';
+import { ProFormText, ProFormTextArea, ProFormSwitch, ProFormRadio } from '@ant-design/pro-components';
+import { Alert, Tag } from 'antd';
+import React from 'react';
+import CrudTable from '@/components/CrudTable';
+import { createCrudService } from '@/services/crud';
+
+// 类型定义
+interface IPRule {
+  id: number;
+  cidr: string;
+  action: 'allow' | 'deny';
+  remark: string;
+  enabled: boolean;
+  created_at: string;
+}
+
+// CRUD 服务
+const ipRuleService = createCrudService<IPRule>('/ip-rules');
+
+const actionEnum = {
+  allow: { text: '允许', status: 'Success' },
+  deny: { text: '拒绝', status: 'Error' },
+};
+
+// 列配置
+const columns: ProColumns<IPRule>[] = [
+  { title: 'ID', dataIndex: 'id', width: 80, search: false },
+  { title: 'IP / 网段', dataIndex: 'cidr', width: 200 },
+  {
+    title: '动作',
+    dataIndex: 'action',
+    width: 100,
+    valueType: 'select',
+    valueEnum: actionEnum,
+    render: (_, r) => <Tag color={r.action === 'allow' ? 'green' : 'red'}>{r.action === 'allow' ? '允许' : '拒绝'}</Tag>,
+  },
+  { title: '备注', dataIndex: 'remark', search: false, width: 220, ellipsis: true },
+  {
+    title: '状态',
+    dataIndex: 'enabled',
+    width: 100,
+    valueType: 'select',
+    valueEnum: { true: { text: '启用', status: 'Success' }, false: { text: '禁用', status: 'Default' } },
+    render: (_, r) => <Tag color={r.enabled ? 'green' : 'red'}>{r.enabled ? '启用' : '禁用'}</Tag>,
+  },
+  { title: '创建时间', dataIndex: 'created_at', valueType: 'dateTime', width: 180, search: false, sorter: true },
+];
+
+// 表单内容组件
+const FormContent: React.FC = () => (
+  <>
+    <Alert
+      type="info"
+      showIcon
+      style={{ marginBottom: 16 }}
+      message="拒绝规则优先；存在允许规则时，只有命中允许规则的地址可以访问后台。保存后会拒绝当前 IP 的变更将被阻止。"
+    />
+    <ProFormText
+      name="cidr"
+      label="IP / 网段"
+      placeholder="如 203.0.113.7 或 10.0.0.0/8"
+      rules={[{ required: true }, { max: 64 }]}
+    />
+    <ProFormRadio.Group
+      name="action"
+      label="动作"
+      initialValue="allow"
+      options={[
+        { label: '允许', value: 'allow' },
+        { label: '拒绝', value: 'deny' },
+      ]}
+      rules={[{ required: true }]}
+    />
+    <ProFormTextArea name="remark" label="备注" placeholder="请输入备注" fieldProps={{ maxLength: 255 }} />
+    <ProFormSwitch name="enabled" label="状态" initialValue={true} />
+  </>
+);
+
+export default function IPRuleList() {
+  return (
+    <CrudTable<IPRule>
+      title="规则"
+      permissionPrefix="system:ip_rule"
+      service={ipRuleService}
+      columns={columns}
+      formContent={<FormContent />}
+      recordToValues={(r) => ({ cidr: r.cidr, action: r.action, remark: r.remark, enabled: r.enabled })}
+      transformParams={(params) => ({
+        ...params,
+        enabled: params.enabled === 'true' ? true : params.enabled === 'false' ? false : undefined,
+      })}
+    />
+  );
+}
@@ -0,0 +1,75 @@
+/**
+ * 登录锁定 - 查看连续登录失败被锁定的账号与 IP，支持提前解锁
+ */
+import type { ActionType, ProColumns } from '@ant-design/pro-components';
+import { ProTable } from '@ant-design/pro-components';
+import { Popconfirm, Tag, message } from 'antd';
+import React, { useRef } from 'react';
+import { useAccess } from '@umijs/max';
+import PageContainer from '@/components/PageContainer';
+import { getLoginLocks, unlockLoginLock, type LoginLock } from '@/services/system/login-lock';
+import { transformTableParams, transformTableResponse } from '@/utils/table';
+
+const kindEnum = {
+  username: { text: '账号' },
+  ip: { text: 'IP' },
+};
+
+export default function LoginLockList() {
+  const access = useAccess();
+  const actionRef = useRef<ActionType>(null);
+
+  const handleUnlock = async (record: LoginLock) => {
+    try {
+      await unlockLoginLock(record.kind, record.subject);
+      message.success('已解除锁定');
+    } catch (e: any) {
+      message.error(e?.message || e?.data?.msg || '解锁失败');
+    }
+    actionRef.current?.reload();
+  };
+
+  const columns: ProColumns<LoginLock>[] = [
+    {
+      title: '类型',
+      dataIndex: 'kind',
+      width: 90,
+      valueType: 'select',
+      valueEnum: kindEnum,
+      render: (_, r) => <Tag color={r.kind === 'ip' ? 'orange' : 'blue'}>{r.kind === 'ip' ? 'IP' : '账号'}</Tag>,
+    },
+    { title: '锁定对象', dataIndex: 'subject', width: 180 },
+    { title: '失败次数', dataIndex: 'failures', width: 100, search: false },
+    { title: '最后失败 IP', dataIndex: 'ip', width: 140, search: false },
+    { title: '锁定时间', dataIndex: 'created_at', valueType: 'dateTime', width: 180, search: false },
+    { title: '解锁时间', dataIndex: 'locked_until', valueType: 'dateTime', width: 180, search: false },
+    {
+      title: '操作',
+      valueType: 'option',
+      width: 100,
+      render: (_, record) =>
+        access['system:login_lock:unlock']
+          ? [
+              <Popconfirm key="unlock" title={`确定解除 ${record.subject} 的锁定吗？`} onConfirm={() => handleUnlock(record)}>
+                <a>解锁</a>
+              </Popconfirm>,
+            ]
+          : [],
+    },
+  ];
+
+  return (
+    <PageContainer>
+      <ProTable<LoginLock>
+        rowKey="id"
+        actionRef={actionRef}
+        columns={columns}
+        search={{ labelWidth: 120 }}
+        pagination={{ showSizeChanger: true, defaultPageSize: 20 }}
+        request={async (params, sort) =>
+          transformTableResponse<LoginLock>(await getLoginLocks(transformTableParams(params, sort)))
+        }
+      />
+    </PageContainer>
+  );
+}
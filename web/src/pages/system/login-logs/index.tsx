@@ -0,0 +1,103 @@
+/**
+ * 登录日志 - 只读列表，支持筛选与导出
+ */
+import type { ProColumns } from '@ant-design/pro-components';
+import { ProTable } from '@ant-design/pro-components';
+import { Button, Tag, Tooltip, message } from 'antd';
+import { DownloadOutlined } from '@ant-design/icons';
+import React, { useRef, useState } from 'react';
+import { useAccess } from '@umijs/max';
+import PageContainer from '@/components/PageContainer';
+import { exportLoginLogs, getLoginLogs, type LoginLog } from '@/services/system/login-log';
+import { downloadBlob, getFilenameFromContentDisposition } from '@/utils/download';
+import { transformTableParams, transformTableResponse } from '@/utils/table';
+
+const reasonEnum = {
+  bad_password: { text: '密码错误' },
+  user_not_found: { text: '用户不存在' },
+  locked: { text: '账号锁定' },
+  disabled: { text: '账号禁用' },
+  bad_captcha: { text: '验证码错误' },
+  bad_two_factor: { text: '动态码错误' },
+  bad_passkey: { text: '通行密钥验证失败' },
+  sso_failed: { text: '单点登录失败' },
+  sso_unlinked: { text: '未绑定本地账号' },
+  provider_error: { text: '认证服务异常' },
+  ip_denied: { text: '来源 IP 受限' },
+};
+
+const columns: ProColumns<LoginLog>[] = [
+  { title: 'ID', dataIndex: 'id', width: 80, search: false },
+  { title: '用户名', dataIndex: 'username', width: 140 },
+  {
+    title: '结果',
+    dataIndex: 'result',
+    width: 90,
+    valueType: 'select',
+    valueEnum: { success: { text: '成功', status: 'Success' }, failure: { text: '失败', status: 'Error' } },
+    render: (_, r) => <Tag color={r.result === 'success' ? 'green' : 'red'}>{r.result === 'success' ? '成功' : '失败'}</Tag>,
+  },
+  { title: '失败原因', dataIndex: 'reason', width: 120, valueType: 'select', valueEnum: reasonEnum },
+  { title: 'IP', dataIndex: 'ip', width: 140 },
+  {
+    title: '设备',
+    dataIndex: 'device',
+    width: 160,
+    search: false,
+    render: (_, r) => <Tooltip title={r.user_agent}>{r.device}</Tooltip>,
+  },
+  {
+    title: '登录时间',
+    dataIndex: 'created_at',
+    valueType: 'dateTimeRange',
+    width: 180,
+    sorter: true,
+    render: (_, r) => r.created_at,
+    search: {
+      transform: (value: [string, string]) => ({ start_time: value[0], end_time: value[1] }),
+    },
+  },
+];
+
+export default function LoginLogList() {
+  const access = useAccess();
+  const filtersRef = useRef<Record<string, any>>({});
+  const [exporting, setExporting] = useState(false);
+
+  const handleExport = async () => {
+    setExporting(true);
+    try {
+      const { page, pageSize, ...filters } = filtersRef.current;
+      const resp: any = await exportLoginLogs(filters);
+      const cd = resp?.response?.headers?.get?.('content-disposition');
+      downloadBlob(resp?.data as Blob, getFilenameFromContentDisposition(cd) || '导出_登录日志.xlsx');
+    } catch (e: any) {
+      message.error(e?.message || e?.data?.msg || '导出失败');
+    } finally {
+      setExporting(false);
+    }
+  };
+
+  return (
+    <PageContainer>
+      <ProTable<LoginLog>
+        rowKey="id"
+        columns={columns}
+        search={{ labelWidth: 120 }}
+        pagination={{ showSizeChanger: true, defaultPageSize: 20 }}
+        toolBarRender={() => [
+          access['system:login_log:export'] && (
+            <Button key="export" icon={<DownloadOutlined />} loading={exporting} onClick={handleExport}>
+              导出
+            </Button>
+          ),
+        ].filter(Boolean)}
+        request={async (params, sort) => {
+          const apiParams = transformTableParams(params, sort);
+          filtersRef.current = apiParams;
+          return transformTableResponse<LoginLog>(await getLoginLogs(apiParams));
+        }}
+      />
+    </PageContainer>
+  );
+}
@@ -0,0 +1,216 @@
+/**
+ * 权限排查 - 按权限 key 或接口路径说明用户能否访问及原因
+ */
+import {
+  ProCard,
+  ProForm,
+  ProFormDependency,
+  ProFormRadio,
+  ProFormSelect,
+  ProFormText,
+  ProFormTreeSelect,
+} from '@ant-design/pro-components';
+import { Alert, Descriptions, Space, Table, Tag, message } from 'antd';
+import dayjs from 'dayjs';
+import React, { useState } from 'react';
+import PageContainer from '@/components/PageContainer';
+import { createCrudService } from '@/services/crud';
+import { getAllPermissions } from '@/services/system/admin-role';
+import type { Permission } from '@/services/system/admin-role/types';
+import {
+  explainPermission,
+  explainRoute,
+  type PermissionExplanation,
+  type RoleExplanation,
+} from '@/services/system/permission-explain';
+
+const userService = createCrudService<{ id: number; username: string; name: string }>('/admin-users');
+
+// 用户下拉按用户名或姓名搜索
+const searchUsers = async ({ keyWords }: { keyWords?: string }) => {
+  const res = await userService.list({ page: 1, pageSize: 20, name: keyWords || undefined });
+  return (res.data || []).map((u) => ({ label: u.name ? `${u.name}（${u.username}）` : u.username, value: u.id }));
+};
+
+// 权限树转换为 TreeSelect 数据，节点同时展示名称与 key
+const toTreeData = (items: Permission[]): any[] =>
+  items.map((item) => ({
+    title: `${item.label}（${item.key}）`,
+    value: item.key,
+    children: toTreeData(item.children || []),
+  }));
+
+const formatTime = (value?: string) => (value ? dayjs(value).format('YYYY-MM-DD HH:mm') : '');
+
+// 角色在判定中的状态
+const renderRoleStatus = (role: RoleExplanation) => {
+  if (!role.enabled) return <Tag color="red">已禁用</Tag>;
+  if (!role.grant_active) return <Tag>授权未生效</Tag>;
+  if (!role.effective) return <Tag color="orange">继承链被截断</Tag>;
+  return <Tag color="green">参与计算</Tag>;
+};
+
+const roleColumns = [
+  {
+    title: '角色',
+    key: 'name',
+    render: (_: unknown, r: RoleExplanation) => (
+      <Space size={4}>
+        {r.name}
+        {r.inherited && <Tag>继承</Tag>}
+      </Space>
+    ),
+  },
+  { title: '编码', dataIndex: 'code', width: 140 },
+  { title: '状态', key: 'status', width: 130, render: (_: unknown, r: RoleExplanation) => renderRoleStatus(r) },
+  {
+    title: '有效期',
+    key: 'period',
+    width: 260,
+    render: (_: unknown, r: RoleExplanation) =>
+      r.valid_from || r.valid_until ? `${formatTime(r.valid_from) || '即日'} 至 ${formatTime(r.valid_until) || '永久'}` : '-',
+  },
+  {
+    title: '匹配的授权项',
+    key: 'grants',
+    render: (_: unknown, r: RoleExplanation) =>
+      r.matched_grants?.map((p) => (
+        <Tag key={p} color="green">
+          {p}
+        </Tag>
+      )),
+  },
+  {
+    title: '匹配的拒绝项',
+    key: 'denies',
+    render: (_: unknown, r: RoleExplanation) =>
+      r.matched_denies?.map((p) => (
+        <Tag key={p} color="red">
+          {p}
+        </Tag>
+      )),
+  },
+];
+
+type QueryValues = { user_id: number; mode: 'permission' | 'route'; permission?: string; method?: string; path?: string };
+
+export default function PermissionExplain() {
+  const [result, setResult] = useState<PermissionExplanation>();
+
+  const handleFinish = async (values: QueryValues) => {
+    const res =
+      values.mode === 'route'
+        ? await explainRoute({ user_id: values.user_id, method: values.method, path: values.path! })
+        : await explainPermission({ user_id: values.user_id, permission: values.permission! });
+    if (res.code !== 0) {
+      message.error(res.msg || '排查失败');
+      setResult(undefined);
+      return;
+    }
+    setResult(res.data);
+  };
+
+  return (
+    <PageContainer>
+      <ProCard>
+        <ProForm<QueryValues>
+          layout="inline"
+          initialValues={{ mode: 'permission', method: 'GET' }}
+          submitter={{ searchConfig: { submitText: '排查' }, resetButtonProps: false }}
+          onFinish={handleFinish}
+        >
+          <ProFormSelect
+            name="user_id"
+            label="用户"
+            showSearch
+            debounceTime={300}
+            request={searchUsers}
+            fieldProps={{ filterOption: false, style: { width: 220 } }}
+            rules={[{ required: true, message: '请选择用户' }]}
+          />
+          <ProFormRadio.Group
+            name="mode"
+            radioType="button"
+            options={[
+              { label: '按权限', value: 'permission' },
+              { label: '按接口', value: 'route' },
+            ]}
+          />
+          <ProFormDependency name={['mode']}>
+            {({ mode }) =>
+              mode === 'route' ? (
+                <>
+                  <ProFormSelect
+                    name="method"
+                    options={['GET', 'POST', 'PUT', 'DELETE', 'PATCH']}
+                    fieldProps={{ style: { width: 110 } }}
+                  />
+                  <ProFormText
+                    name="path"
+                    placeholder="如 /admin-api/admin-users/1"
+                    fieldProps={{ style: { width: 320 } }}
+                    rules={[{ required: true, message: '请输入接口路径' }]}
+                  />
+                </>
+              ) : (
+                <ProFormTreeSelect
+                  name="permission"
+                  placeholder="选择或搜索权限"
+                  request={async () => {
+                    const res = await getAllPermissions();
+                    return toTreeData(res.data || []);
+                  }}
+                  fieldProps={{ showSearch: true, treeNodeFilterProp: 'title', style: { width: 360 } }}
+                  rules={[{ required: true, message: '请选择权限' }]}
+                />
+              )
+            }
+          </ProFormDependency>
+        </ProForm>
+      </ProCard>
+
+      {result && (
+        <ProCard style={{ marginTop: 16 }}>
+          <Alert
+            showIcon
+            type={result.allowed ? 'success' : 'error'}
+            message={result.allowed ? '允许访问' : '无权访问'}
+            description={result.reason}
+          />
+          <Descriptions column={2} bordered size="small" style={{ marginTop: 16 }}>
+            <Descriptions.Item label="用户">{result.username}</Descriptions.Item>
+            <Descriptions.Item label="用户状态">
+              <Tag color={result.user_enabled ? 'green' : 'red'}>{result.user_enabled ? '启用' : '禁用'}</Tag>
+            </Descriptions.Item>
+            <Descriptions.Item label="权限">
+              {result.permission || '无需权限'}
+              {result.permission && !result.registered && <Tag color="orange" style={{ marginLeft: 8 }}>不在权限树中</Tag>}
+            </Descriptions.Item>
+            <Descriptions.Item label="超级管理员">{result.super_admin ? '是' : '否'}</Descriptions.Item>
+            {result.route && (
+              <Descriptions.Item label="匹配路由" span={2}>
+                {result.route.method} {result.route.path}（模块 {result.route.module}
+                {result.route.public ? '，公开' : ''}）
+              </Descriptions.Item>
+            )}
+            <Descriptions.Item label="缓存" span={2}>
+              <Space>
+                <Tag>{result.cache.status_cached ? '用户状态已缓存' : '用户状态未缓存'}</Tag>
+                <Tag>{result.cache.permission_cached ? '权限已缓存' : '权限未缓存'}</Tag>
+                {result.cache.stale && <Tag color="orange">缓存与最新数据不一致</Tag>}
+              </Space>
+            </Descriptions.Item>
+          </Descriptions>
+          <Table<RoleExplanation>
+            rowKey={(r) => `${r.inherited ? 'i' : 'd'}-${r.id}`}
+            size="small"
+            style={{ marginTop: 16 }}
+            columns={roleColumns}
+            dataSource={result.roles}
+            pagination={false}
+          />
+        </ProCard>
+      )}
+    </PageContainer>
+  );
+}
@@ -2,6 +2,7 @@ import type { RequestOptions } from '@@/plugin-request/request';
 import { history, type RequestConfig } from '@umijs/max';
 import { message } from 'antd';
 import { buildLoginUrl, getCurrentPathWithSearch, LOGIN_PATH } from '@/utils/redirect';
+import { clearTokens, getFreshToken } from '@/utils/token';
 
 // 错误处理方案： 错误类型
 enum ErrorShowType {
@@ -68,9 +69,9 @@ export const errorConfig: RequestConfig = {
 
   // 请求拦截器
   requestInterceptors: [
-    (config: RequestOptions) => {
-      // 添加 token 到请求头
-      const token = localStorage.getItem('token');
+    async (config: RequestOptions) => {
+      // 添加 token 到请求头，临近过期时先刷新
+      const token = await getFreshToken();
       if (token) {
         config.headers = {
           ...config.headers,
@@ -96,7 +97,7 @@ export const errorConfig: RequestConfig = {
           // 401 未授权（包括 token 失效、账户被禁用等），清除 token 并跳转登录
           if (apiResponse.code === 401 && history.location.pathname !== LOGIN_PATH) {
             message.error(apiResponse.msg || '未授权，请重新登录');
-            localStorage.removeItem('token');
+            clearTokens();
             localStorage.removeItem('currentUser');
             setTimeout(() => {
               history.replace(buildLoginUrl(getCurrentPathWithSearch()));
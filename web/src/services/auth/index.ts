@@ -5,6 +5,14 @@ import type {
   LoginResult,
   CurrentUser,
   CaptchaResult,
+  TwoFactorVerifyParams,
+  TwoFactorKey,
+  PasskeyCeremony,
+  ExpiredPasswordParams,
+  ForgotPasswordParams,
+  ResetPasswordParams,
+  SSOCallbackParams,
+  SSOAuthorizeResult,
 } from './types';
 
 /**
@@ -17,6 +25,114 @@ export async function login(data: LoginParams) {
   });
 }
 
+/**
+ * 登录两步验证
+ */
+export async function verifyTwoFactor(data: TwoFactorVerifyParams) {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/2fa/verify'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 登录中获取两步验证绑定二维码
+ */
+export async function setupPendingTwoFactor(twoFactorToken: string) {
+  return request<API.Response<TwoFactorKey>>(buildApiUrl('/auth/2fa/pending/setup'), {
+    method: 'POST',
+    data: { twoFactorToken },
+  });
+}
+
+/**
+ * 登录中获取通行密钥两步验证参数
+ */
+export async function beginPasskeyTwoFactor(twoFactorToken: string) {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/2fa/passkey/begin'), {
+    method: 'POST',
+    data: { twoFactorToken },
+  });
+}
+
+/**
+ * 使用通行密钥完成两步验证
+ */
+export async function verifyTwoFactorPasskey(twoFactorToken: string, credential: Record<string, any>) {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/2fa/passkey/verify'), {
+    method: 'POST',
+    data: { twoFactorToken, credential },
+  });
+}
+
+/**
+ * 获取通行密钥免密码登录参数
+ */
+export async function beginPasskeyLogin() {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/passkey/login/begin'), {
+    method: 'POST',
+  });
+}
+
+/**
+ * 使用通行密钥免密码登录
+ */
+export async function finishPasskeyLogin(token: string, credential: Record<string, any>) {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/passkey/login/finish'), {
+    method: 'POST',
+    data: { token, credential },
+  });
+}
+
+/**
+ * 登录时修改密码（密码过期或管理员要求修改）
+ */
+export async function changeExpiredPassword(data: ExpiredPasswordParams) {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/password/expired'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 找回密码，向账号绑定的邮箱发送重置链接
+ */
+export async function forgotPassword(data: ForgotPasswordParams) {
+  return request<API.Response<any>>(buildApiUrl('/auth/password/forgot'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 凭邮件中的重置令牌设置新密码
+ */
+export async function resetPassword(data: ResetPasswordParams) {
+  return request<API.Response<any>>(buildApiUrl('/auth/password/reset'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 发起单点登录，返回身份提供方授权地址
+ */
+export async function ssoAuthorize(provider: string) {
+  return request<API.Response<SSOAuthorizeResult>>(buildApiUrl(`/auth/sso/${encodeURIComponent(provider)}/authorize`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 提交身份提供方回调参数完成单点登录
+ */
+export async function ssoCallback(data: SSOCallbackParams) {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/sso/callback'), {
+    method: 'POST',
+    data,
+  });
+}
+
 /**
  * 退出登录
  */
@@ -26,6 +142,15 @@ export async function logout() {
   });
 }
 
+/**
+ * 退出模拟登录，返回操作者自己的令牌
+ */
+export async function stopImpersonation() {
+  return request<API.Response<LoginResult>>(buildApiUrl('/auth/impersonate/stop'), {
+    method: 'POST',
+  });
+}
+
 /**
  * 获取当前用户信息
  */
@@ -0,0 +1,50 @@
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../config';
+import type { Passkey, PasskeyCeremony } from './types';
+
+/**
+ * 获取当前用户的通行密钥
+ */
+export async function getPasskeys() {
+  return request<API.Response<Passkey[]>>(buildApiUrl('/auth/passkeys'), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 获取通行密钥注册参数
+ */
+export async function beginPasskeyRegistration() {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/passkeys/register/begin'), {
+    method: 'POST',
+  });
+}
+
+/**
+ * 提交浏览器创建的凭据完成注册
+ */
+export async function finishPasskeyRegistration(data: { token: string; name: string; credential: Record<string, any> }) {
+  return request<API.Response<Passkey>>(buildApiUrl('/auth/passkeys/register/finish'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 重命名通行密钥
+ */
+export async function renamePasskey(id: number, name: string) {
+  return request<API.Response<any>>(buildApiUrl(`/auth/passkeys/${id}`), {
+    method: 'PUT',
+    data: { name },
+  });
+}
+
+/**
+ * 删除通行密钥
+ */
+export async function deletePasskey(id: number) {
+  return request<API.Response<any>>(buildApiUrl(`/auth/passkeys/${id}`), {
+    method: 'DELETE',
+  });
+}
@@ -12,16 +12,96 @@ export interface LoginParams {
 
 export interface LoginResult {
   token: string;
+  /** 刷新令牌，每次刷新后轮换 */
+  refreshToken?: string;
+  /** 访问令牌有效秒数 */
+  expiresIn?: number;
+  /** 需要两步验证时返回，凭此令牌调用 /auth/2fa/verify */
+  twoFactorToken?: string;
+  /** 角色强制要求但尚未绑定两步验证 */
+  twoFactorSetup?: boolean;
+  /** 可用的两步验证方式：totp / passkey */
+  twoFactorMethods?: string[];
+  /** 强制绑定完成时返回的恢复码，仅展示一次 */
+  recoveryCodes?: string[];
+  /** 需要修改密码时返回，凭此令牌调用 /auth/password/expired */
+  passwordChangeToken?: string;
+  /** 密码已过期（否则为管理员要求修改） */
+  passwordExpired?: boolean;
+}
+
+export interface TwoFactorVerifyParams {
+  twoFactorToken: string;
+  code: string;
+}
+
+export interface ExpiredPasswordParams {
+  passwordChangeToken: string;
+  newPassword: string;
+}
+
+export interface ForgotPasswordParams {
+  /** 用户名或邮箱 */
+  account: string;
+  captchaId: string;
+  captchaCode: string;
+}
+
+export interface ResetPasswordParams {
+  /** 找回密码邮件中的重置令牌 */
+  token: string;
+  newPassword: string;
+  captchaId: string;
+  captchaCode: string;
+}
+
+export interface SSOCallbackParams {
+  state: string;
+  code: string;
+}
+
+export interface SSOAuthorizeResult {
+  /** 身份提供方授权地址 */
+  url: string;
+}
+
+export interface PasskeyCeremony {
+  /** 注册与免密码登录时返回，提交凭据时原样带回 */
+  token?: string;
+  /** 传给 navigator.credentials.create / get 的参数 */
+  options: any;
+}
+
+export interface Passkey {
+  id: number;
+  name: string;
+  transports?: string[];
+  sign_count: number;
+  last_used_at?: string;
+  last_used_ip?: string;
+  created_at: string;
+}
+
+export interface TwoFactorKey {
+  secret: string;
+  url: string;
+  image: string;
 }
 
 export interface CurrentUser {
   id: number;
   username: string;
   name: string;
+  email?: string;
   avatar: string;
   enabled?: boolean;
   roles?: string[];
   permissions?: string[];
+  /** 拥有超级管理员角色 */
+  super_admin?: boolean;
+  /** 当前为模拟登录，impersonator 为实际操作者 */
+  impersonated?: boolean;
+  impersonator?: { id: number; username: string };
 }
 
 export interface UpdateProfileParams {
@@ -37,5 +117,14 @@ export interface ChangePasswordParams {
 
 export interface CaptchaResult {
   id: string;
+  /** digit / string / math / slider */
+  type: string;
+  /** 验证码图片；滑块验证码为带缺口的背景图 */
   image: string;
+  /** 滑块拼图块图片 */
+  piece?: string;
+  /** 滑块拼图块顶部位置 */
+  pieceY?: number;
+  width: number;
+  height: number;
 }
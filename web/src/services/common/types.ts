@@ -6,4 +6,17 @@ export interface AppConfig {
   name: string;
   logo: string;
   debug: boolean;
+  /** 登录页展示的单点登录方式 */
+  ssoProviders?: SSOProvider[];
+  /** 登录页是否展示找回密码入口 */
+  passwordReset?: boolean;
+  /** 登录是否按需展示验证码，为 true 时登录返回 428 后再展示 */
+  captchaAdaptive?: boolean;
+  /** 是否启用通行密钥登录 */
+  passkey?: boolean;
+}
+
+export interface SSOProvider {
+  name: string;
+  label: string;
 }
@@ -0,0 +1,1137 @@
+// 此文件由 bico-admin gen ts 生成，请勿手动修改。
+
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../config';
+import type {
+  AdminDepartment,
+  AdminIPRule,
+  AdminLoginLock,
+  AdminRole,
+  AdminRoleDocItem,
+  AdminRoleListParams,
+  AdminUser,
+  AdminUserListParams,
+  ApiTokenCreatedDoc,
+  ApiTokenDocItem,
+  AppConfigDocResponse,
+  CaptchaResponse,
+  ChangePasswordRequest,
+  CreateAPITokenRequest,
+  CreateDepartmentReq,
+  CreateDynamicTableReq,
+  CreateIPRuleReq,
+  CreateListViewReq,
+  CreateRoleReq,
+  CreateUserReq,
+  CurrentUserDocResponse,
+  DashboardOverview,
+  DeleteBatchDocRequest,
+  DemoExcelImportResponse,
+  DepartmentListParams,
+  DepartmentTreeDocItem,
+  DynamicImportResponse,
+  DynamicMenuItem,
+  DynamicPageDoc,
+  DynamicTable,
+  DynamicTableListParams,
+  ExpiredPasswordRequest,
+  ForgotPasswordRequest,
+  GraphqlRequest,
+  GraphqlResponse,
+  IpRuleListParams,
+  ListViewDocItem,
+  LoginDocResponse,
+  LoginLockListParams,
+  LoginLog,
+  LoginLogListParams,
+  LoginRequest,
+  MoveDepartmentReq,
+  PageResult,
+  PasskeyCeremony,
+  PasskeyDocItem,
+  PasskeyLoginRequest,
+  PasskeyRegisterRequest,
+  PasskeyTwoFactorRequest,
+  PermissionDocItem,
+  PermissionExplanationDocItem,
+  RecoveryCodesResponse,
+  RefreshRequest,
+  RenamePasskeyRequest,
+  ResetPasswordRequest,
+  RoleGrantItem,
+  RoleGrantRequest,
+  RoleParentsResponse,
+  RolePermissionsResponse,
+  SSOAuthorizeResponse,
+  SSOCallbackRequest,
+  SessionDocItem,
+  TwoFactorCodeRequest,
+  TwoFactorDisableRequest,
+  TwoFactorKeyResponse,
+  TwoFactorPendingRequest,
+  TwoFactorStatus,
+  TwoFactorVerifyRequest,
+  UpdateDepartmentReq,
+  UpdateDynamicTableReq,
+  UpdateIPRuleReq,
+  UpdateListViewReq,
+  UpdateProfileRequest,
+  UpdateRoleParentsReq,
+  UpdateRolePermReq,
+  UpdateRoleReq,
+  UpdateUserReq,
+  UploadResponse,
+} from './types';
+
+/**
+ * 获取角色管理列表
+ * 权限：system:admin_role:list
+ */
+export async function getAdminRoleList(params?: AdminRoleListParams) {
+  return request<API.Response<PageResult<AdminRole>>>(buildApiUrl('/admin-roles'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 创建角色管理
+ * 权限：system:admin_role:create
+ */
+export async function createAdminRole(data: CreateRoleReq) {
+  return request<API.Response<AdminRole>>(buildApiUrl('/admin-roles'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取全部启用角色 */
+export async function getAdminRolesAll() {
+  return request<API.Response<AdminRoleDocItem[]>>(buildApiUrl('/admin-roles/all'), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 批量删除角色管理
+ * 权限：system:admin_role:delete
+ */
+export async function deleteAdminRoleBatch(ids: number[]) {
+  return request<API.Response<null>>(buildApiUrl('/admin-roles/batch'), {
+    method: 'DELETE',
+    data: { ids },
+  });
+}
+
+/** 获取完整权限树 */
+export async function getAdminRolesPermissions() {
+  return request<API.Response<PermissionDocItem[]>>(buildApiUrl('/admin-roles/permissions'), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 获取角色管理详情
+ * 权限：system:admin_role:list
+ */
+export async function getAdminRole(id: number) {
+  return request<API.Response<AdminRole>>(buildApiUrl(`/admin-roles/${id}`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 更新角色管理
+ * 权限：system:admin_role:edit
+ */
+export async function updateAdminRole(id: number, data: UpdateRoleReq) {
+  return request<API.Response<AdminRole>>(buildApiUrl(`/admin-roles/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 删除角色管理
+ * 权限：system:admin_role:delete
+ */
+export async function deleteAdminRole(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 获取角色拒绝权限 */
+export async function getAdminRolesByIdDenies(id: number) {
+  return request<API.Response<RolePermissionsResponse>>(buildApiUrl(`/admin-roles/${id}/denies`), {
+    method: 'GET',
+  });
+}
+
+/** 更新角色拒绝权限 */
+export async function putAdminRolesByIdDenies(id: number, data: UpdateRolePermReq) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}/denies`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 获取继承角色 */
+export async function getAdminRolesByIdParents(id: number) {
+  return request<API.Response<RoleParentsResponse>>(buildApiUrl(`/admin-roles/${id}/parents`), {
+    method: 'GET',
+  });
+}
+
+/** 更新继承角色 */
+export async function putAdminRolesByIdParents(id: number, data: UpdateRoleParentsReq) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}/parents`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 获取角色权限 */
+export async function getAdminRolesByIdPermissions(id: number) {
+  return request<API.Response<RolePermissionsResponse>>(buildApiUrl(`/admin-roles/${id}/permissions`), {
+    method: 'GET',
+  });
+}
+
+/** 更新角色权限 */
+export async function putAdminRolesByIdPermissions(id: number, data: UpdateRolePermReq) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}/permissions`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 获取用户管理列表
+ * 权限：system:admin_user:list
+ */
+export async function getAdminUserList(params?: AdminUserListParams) {
+  return request<API.Response<PageResult<AdminUser>>>(buildApiUrl('/admin-users'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 创建用户管理
+ * 权限：system:admin_user:create
+ */
+export async function createAdminUser(data: CreateUserReq) {
+  return request<API.Response<AdminUser>>(buildApiUrl('/admin-users'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 批量删除用户管理
+ * 权限：system:admin_user:delete
+ */
+export async function deleteAdminUserBatch(ids: number[]) {
+  return request<API.Response<null>>(buildApiUrl('/admin-users/batch'), {
+    method: 'DELETE',
+    data: { ids },
+  });
+}
+
+/** 获取即将到期的角色授权 */
+export async function getAdminUsersRoleGrantsExpiring(params?: {
+  /** 天数，默认 7，最大 90 */
+  days?: number;
+}) {
+  return request<API.Response<RoleGrantItem[]>>(buildApiUrl('/admin-users/role-grants/expiring'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 获取用户管理详情
+ * 权限：system:admin_user:list
+ */
+export async function getAdminUser(id: number) {
+  return request<API.Response<AdminUser>>(buildApiUrl(`/admin-users/${id}`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 更新用户管理
+ * 权限：system:admin_user:edit
+ */
+export async function updateAdminUser(id: number, data: UpdateUserReq) {
+  return request<API.Response<AdminUser>>(buildApiUrl(`/admin-users/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 删除用户管理
+ * 权限：system:admin_user:delete
+ */
+export async function deleteAdminUser(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 重置两步验证 */
+export async function putAdminUsersById2faReset(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/2fa/reset`), {
+    method: 'PUT',
+  });
+}
+
+/** 获取用户访问令牌 */
+export async function getAdminUsersByIdApiTokens(id: number) {
+  return request<API.Response<ApiTokenDocItem[]>>(buildApiUrl(`/admin-users/${id}/api-tokens`), {
+    method: 'GET',
+  });
+}
+
+/** 为用户创建访问令牌 */
+export async function postAdminUsersByIdApiTokens(id: number, data: CreateAPITokenRequest) {
+  return request<API.Response<ApiTokenCreatedDoc>>(buildApiUrl(`/admin-users/${id}/api-tokens`), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 吊销用户访问令牌 */
+export async function deleteAdminUsersByIdApiTokensByTid(id: number, tid: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/api-tokens/${tid}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 模拟用户登录 */
+export async function postAdminUsersByIdImpersonate(id: number) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl(`/admin-users/${id}/impersonate`), {
+    method: 'POST',
+  });
+}
+
+/** 获取用户角色授权 */
+export async function getAdminUsersByIdRoleGrants(id: number) {
+  return request<API.Response<RoleGrantItem[]>>(buildApiUrl(`/admin-users/${id}/role-grants`), {
+    method: 'GET',
+  });
+}
+
+/** 授予用户角色 */
+export async function postAdminUsersByIdRoleGrants(id: number, data: RoleGrantRequest) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/role-grants`), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 撤销用户角色授权 */
+export async function deleteAdminUsersByIdRoleGrantsByGid(id: number, gid: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/role-grants/${gid}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 获取用户登录会话 */
+export async function getAdminUsersByIdSessions(id: number) {
+  return request<API.Response<SessionDocItem[]>>(buildApiUrl(`/admin-users/${id}/sessions`), {
+    method: 'GET',
+  });
+}
+
+/** 下线用户全部会话 */
+export async function deleteAdminUsersByIdSessions(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/sessions`), {
+    method: 'DELETE',
+  });
+}
+
+/** 下线用户会话 */
+export async function deleteAdminUsersByIdSessionsBySid(id: number, sid: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/sessions/${sid}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 获取应用配置 */
+export async function getAppConfig() {
+  return request<API.Response<AppConfigDocResponse>>(buildApiUrl('/app-config'), {
+    method: 'GET',
+  });
+}
+
+/** 获取两步验证状态 */
+export async function getAuth2fa() {
+  return request<API.Response<TwoFactorStatus>>(buildApiUrl('/auth/2fa'), {
+    method: 'GET',
+  });
+}
+
+/** 关闭两步验证 */
+export async function postAuth2faDisable(data: TwoFactorDisableRequest) {
+  return request<API.Response<null>>(buildApiUrl('/auth/2fa/disable'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 启用两步验证 */
+export async function postAuth2faEnable(data: TwoFactorCodeRequest) {
+  return request<API.Response<RecoveryCodesResponse>>(buildApiUrl('/auth/2fa/enable'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 通行密钥两步验证参数 */
+export async function postAuth2faPasskeyBegin(data: TwoFactorPendingRequest) {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/2fa/passkey/begin'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 通行密钥两步验证 */
+export async function postAuth2faPasskeyVerify(data: PasskeyTwoFactorRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/2fa/passkey/verify'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 登录中绑定两步验证 */
+export async function postAuth2faPendingSetup(data: TwoFactorPendingRequest) {
+  return request<API.Response<TwoFactorKeyResponse>>(buildApiUrl('/auth/2fa/pending/setup'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 重新生成恢复码 */
+export async function postAuth2faRecoveryCodes(data: TwoFactorCodeRequest) {
+  return request<API.Response<RecoveryCodesResponse>>(buildApiUrl('/auth/2fa/recovery-codes'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取绑定二维码 */
+export async function postAuth2faSetup() {
+  return request<API.Response<TwoFactorKeyResponse>>(buildApiUrl('/auth/2fa/setup'), {
+    method: 'POST',
+  });
+}
+
+/** 登录两步验证 */
+export async function postAuth2faVerify(data: TwoFactorVerifyRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/2fa/verify'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取访问令牌 */
+export async function getAuthApiTokens() {
+  return request<API.Response<ApiTokenDocItem[]>>(buildApiUrl('/auth/api-tokens'), {
+    method: 'GET',
+  });
+}
+
+/** 创建访问令牌 */
+export async function postAuthApiTokens(data: CreateAPITokenRequest) {
+  return request<API.Response<ApiTokenCreatedDoc>>(buildApiUrl('/auth/api-tokens'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 吊销访问令牌 */
+export async function deleteAuthApiTokensById(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/auth/api-tokens/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 上传头像 */
+export async function postAuthAvatar(data: FormData) {
+  return request<API.Response<UploadResponse>>(buildApiUrl('/auth/avatar'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取当前用户 */
+export async function getAuthCurrentUser() {
+  return request<API.Response<CurrentUserDocResponse>>(buildApiUrl('/auth/current-user'), {
+    method: 'GET',
+  });
+}
+
+/** 退出模拟登录 */
+export async function postAuthImpersonateStop() {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/impersonate/stop'), {
+    method: 'POST',
+  });
+}
+
+/** 登录 */
+export async function postAuthLogin(data: LoginRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/login'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 退出登录 */
+export async function postAuthLogout() {
+  return request<API.Response<null>>(buildApiUrl('/auth/logout'), {
+    method: 'POST',
+  });
+}
+
+/** 通行密钥登录参数 */
+export async function postAuthPasskeyLoginBegin() {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/passkey/login/begin'), {
+    method: 'POST',
+  });
+}
+
+/** 通行密钥登录 */
+export async function postAuthPasskeyLoginFinish(data: PasskeyLoginRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/passkey/login/finish'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取通行密钥 */
+export async function getAuthPasskeys() {
+  return request<API.Response<PasskeyDocItem[]>>(buildApiUrl('/auth/passkeys'), {
+    method: 'GET',
+  });
+}
+
+/** 通行密钥注册参数 */
+export async function postAuthPasskeysRegisterBegin() {
+  return request<API.Response<PasskeyCeremony>>(buildApiUrl('/auth/passkeys/register/begin'), {
+    method: 'POST',
+  });
+}
+
+/** 注册通行密钥 */
+export async function postAuthPasskeysRegisterFinish(data: PasskeyRegisterRequest) {
+  return request<API.Response<PasskeyDocItem>>(buildApiUrl('/auth/passkeys/register/finish'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 重命名通行密钥 */
+export async function putAuthPasskeysById(id: number, data: RenamePasskeyRequest) {
+  return request<API.Response<null>>(buildApiUrl(`/auth/passkeys/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 删除通行密钥 */
+export async function deleteAuthPasskeysById(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/auth/passkeys/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 修改密码 */
+export async function putAuthPassword(data: ChangePasswordRequest) {
+  return request<API.Response<null>>(buildApiUrl('/auth/password'), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 登录时修改密码 */
+export async function postAuthPasswordExpired(data: ExpiredPasswordRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/password/expired'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 找回密码 */
+export async function postAuthPasswordForgot(data: ForgotPasswordRequest) {
+  return request<API.Response<null>>(buildApiUrl('/auth/password/forgot'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 重置密码 */
+export async function postAuthPasswordReset(data: ResetPasswordRequest) {
+  return request<API.Response<null>>(buildApiUrl('/auth/password/reset'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 更新个人资料 */
+export async function putAuthProfile(data: UpdateProfileRequest) {
+  return request<API.Response<CurrentUserDocResponse>>(buildApiUrl('/auth/profile'), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 刷新令牌 */
+export async function postAuthRefresh(data: RefreshRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/refresh'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取登录会话 */
+export async function getAuthSessions() {
+  return request<API.Response<SessionDocItem[]>>(buildApiUrl('/auth/sessions'), {
+    method: 'GET',
+  });
+}
+
+/** 下线会话 */
+export async function deleteAuthSessionsById(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/auth/sessions/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 完成单点登录 */
+export async function postAuthSsoCallback(data: SSOCallbackRequest) {
+  return request<API.Response<LoginDocResponse>>(buildApiUrl('/auth/sso/callback'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 发起单点登录 */
+export async function getAuthSsoByProviderAuthorize(provider: string) {
+  return request<API.Response<SSOAuthorizeResponse>>(buildApiUrl(`/auth/sso/${provider}/authorize`), {
+    method: 'GET',
+  });
+}
+
+/** 获取验证码 */
+export async function getCaptcha() {
+  return request<API.Response<CaptchaResponse>>(buildApiUrl('/captcha'), {
+    method: 'GET',
+  });
+}
+
+/** 获取工作台概览 */
+export async function getDashboardOverview() {
+  return request<API.Response<DashboardOverview>>(buildApiUrl('/dashboard/overview'), {
+    method: 'GET',
+  });
+}
+
+/** 导出 Excel */
+export async function getDemoExcelExport() {
+  return request<Blob>(buildApiUrl('/demo/excel/export'), {
+    method: 'GET',
+    responseType: 'blob',
+  });
+}
+
+/** 导入 Excel */
+export async function postDemoExcelImport(data: FormData) {
+  return request<API.Response<DemoExcelImportResponse>>(buildApiUrl('/demo/excel/import'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 下载 Excel 导入模板 */
+export async function getDemoExcelTemplate() {
+  return request<Blob>(buildApiUrl('/demo/excel/template'), {
+    method: 'GET',
+    responseType: 'blob',
+  });
+}
+
+/**
+ * 获取部门管理列表
+ * 权限：system:department:list
+ */
+export async function getDepartmentList(params?: DepartmentListParams) {
+  return request<API.Response<PageResult<AdminDepartment>>>(buildApiUrl('/departments'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 创建部门管理
+ * 权限：system:department:create
+ */
+export async function createDepartment(data: CreateDepartmentReq) {
+  return request<API.Response<AdminDepartment>>(buildApiUrl('/departments'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 批量删除部门管理
+ * 权限：system:department:delete
+ */
+export async function deleteDepartmentBatch(ids: number[]) {
+  return request<API.Response<null>>(buildApiUrl('/departments/batch'), {
+    method: 'DELETE',
+    data: { ids },
+  });
+}
+
+/** 获取部门树 */
+export async function getDepartmentsTree(params?: {
+  /** 是否启用 */
+  enabled?: boolean;
+  /** 部门名称 */
+  name?: string;
+}) {
+  return request<API.Response<DepartmentTreeDocItem[]>>(buildApiUrl('/departments/tree'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 获取部门管理详情
+ * 权限：system:department:list
+ */
+export async function getDepartment(id: number) {
+  return request<API.Response<AdminDepartment>>(buildApiUrl(`/departments/${id}`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 更新部门管理
+ * 权限：system:department:edit
+ */
+export async function updateDepartment(id: number, data: UpdateDepartmentReq) {
+  return request<API.Response<AdminDepartment>>(buildApiUrl(`/departments/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 删除部门管理
+ * 权限：system:department:delete
+ */
+export async function deleteDepartment(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/departments/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 移动部门 */
+export async function putDepartmentsByIdMove(id: number, data: MoveDepartmentReq) {
+  return request<API.Response<null>>(buildApiUrl(`/departments/${id}/move`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 获取动态表数据列表 */
+export async function getDynamicDataByTable(table: string, params?: {
+  /** 页码 */
+  page?: number;
+  /** 每页数量 */
+  pageSize?: number;
+  /** 排序字段，仅支持可排序字段和系统字段 */
+  sortField?: string;
+  /** 排序方式：ascend/descend */
+  sortOrder?: string;
+}) {
+  return request<API.Response<DynamicPageDoc>>(buildApiUrl(`/dynamic-data/${table}`), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 创建动态表数据 */
+export async function postDynamicDataByTable(table: string, data: Record<string, any>) {
+  return request<API.Response<Record<string, any>>>(buildApiUrl(`/dynamic-data/${table}`), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 批量删除动态表数据 */
+export async function deleteDynamicDataByTableBatch(table: string, data: DeleteBatchDocRequest) {
+  return request<API.Response<null>>(buildApiUrl(`/dynamic-data/${table}/batch`), {
+    method: 'DELETE',
+    data,
+  });
+}
+
+/** 导出动态表数据 */
+export async function getDynamicDataByTableExport(table: string, params?: {
+  /** 勾选记录 ID，逗号分隔 */
+  ids?: string;
+}) {
+  return request<Blob>(buildApiUrl(`/dynamic-data/${table}/export`), {
+    method: 'GET',
+    params,
+    responseType: 'blob',
+  });
+}
+
+/** 导入动态表数据 */
+export async function postDynamicDataByTableImport(table: string, data: FormData) {
+  return request<API.Response<DynamicImportResponse>>(buildApiUrl(`/dynamic-data/${table}/import`), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取动态表定义 */
+export async function getDynamicDataByTableSchema(table: string) {
+  return request<API.Response<Record<string, any>>>(buildApiUrl(`/dynamic-data/${table}/schema`), {
+    method: 'GET',
+  });
+}
+
+/** 下载动态表导入模板 */
+export async function getDynamicDataByTableTemplate(table: string) {
+  return request<Blob>(buildApiUrl(`/dynamic-data/${table}/template`), {
+    method: 'GET',
+    responseType: 'blob',
+  });
+}
+
+/** 获取动态表数据详情 */
+export async function getDynamicDataByTableById(table: string, id: number) {
+  return request<API.Response<Record<string, any>>>(buildApiUrl(`/dynamic-data/${table}/${id}`), {
+    method: 'GET',
+  });
+}
+
+/** 更新动态表数据 */
+export async function putDynamicDataByTableById(table: string, id: number, data: Record<string, any>) {
+  return request<API.Response<Record<string, any>>>(buildApiUrl(`/dynamic-data/${table}/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 删除动态表数据 */
+export async function deleteDynamicDataByTableById(table: string, id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/dynamic-data/${table}/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/**
+ * 获取动态表列表
+ * 权限：system:dynamic_table:list
+ */
+export async function getDynamicTableList(params?: DynamicTableListParams) {
+  return request<API.Response<PageResult<DynamicTable>>>(buildApiUrl('/dynamic-tables'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 创建动态表
+ * 权限：system:dynamic_table:create
+ */
+export async function createDynamicTable(data: CreateDynamicTableReq) {
+  return request<API.Response<DynamicTable>>(buildApiUrl('/dynamic-tables'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 批量删除动态表
+ * 权限：system:dynamic_table:delete
+ */
+export async function deleteDynamicTableBatch(ids: number[]) {
+  return request<API.Response<null>>(buildApiUrl('/dynamic-tables/batch'), {
+    method: 'DELETE',
+    data: { ids },
+  });
+}
+
+/** 获取动态表菜单 */
+export async function getDynamicTablesMenus() {
+  return request<API.Response<DynamicMenuItem[]>>(buildApiUrl('/dynamic-tables/menus'), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 获取动态表详情
+ * 权限：system:dynamic_table:list
+ */
+export async function getDynamicTable(id: number) {
+  return request<API.Response<DynamicTable>>(buildApiUrl(`/dynamic-tables/${id}`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 更新动态表
+ * 权限：system:dynamic_table:edit
+ */
+export async function updateDynamicTable(id: number, data: UpdateDynamicTableReq) {
+  return request<API.Response<DynamicTable>>(buildApiUrl(`/dynamic-tables/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 删除动态表
+ * 权限：system:dynamic_table:delete
+ */
+export async function deleteDynamicTable(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/dynamic-tables/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/** GraphQL 查询 */
+export async function postGraphql(data: GraphqlRequest) {
+  return request<GraphqlResponse>(buildApiUrl('/graphql'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 获取IP 访问控制列表
+ * 权限：system:ip_rule:list
+ */
+export async function getIpRuleList(params?: IpRuleListParams) {
+  return request<API.Response<PageResult<AdminIPRule>>>(buildApiUrl('/ip-rules'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 创建IP 访问控制
+ * 权限：system:ip_rule:create
+ */
+export async function createIpRule(data: CreateIPRuleReq) {
+  return request<API.Response<AdminIPRule>>(buildApiUrl('/ip-rules'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/**
+ * 批量删除IP 访问控制
+ * 权限：system:ip_rule:delete
+ */
+export async function deleteIpRuleBatch(ids: number[]) {
+  return request<API.Response<null>>(buildApiUrl('/ip-rules/batch'), {
+    method: 'DELETE',
+    data: { ids },
+  });
+}
+
+/**
+ * 获取IP 访问控制详情
+ * 权限：system:ip_rule:list
+ */
+export async function getIpRule(id: number) {
+  return request<API.Response<AdminIPRule>>(buildApiUrl(`/ip-rules/${id}`), {
+    method: 'GET',
+  });
+}
+
+/**
+ * 更新IP 访问控制
+ * 权限：system:ip_rule:edit
+ */
+export async function updateIpRule(id: number, data: UpdateIPRuleReq) {
+  return request<API.Response<AdminIPRule>>(buildApiUrl(`/ip-rules/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/**
+ * 删除IP 访问控制
+ * 权限：system:ip_rule:delete
+ */
+export async function deleteIpRule(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/ip-rules/${id}`), {
+    method: 'DELETE',
+  });
+}
+
+/**
+ * 获取登录日志列表
+ * 权限：system:login_log:list
+ */
+export async function getLoginLogList(params?: LoginLogListParams) {
+  return request<API.Response<PageResult<LoginLog>>>(buildApiUrl('/login-logs'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 导出登录日志 */
+export async function getLoginLogsExport(params?: {
+  /** 结束时间（2006-01-02 15:04:05） */
+  end_time?: string;
+  /** 勾选记录 ID，逗号分隔 */
+  ids?: string;
+  /** IP 前缀 */
+  ip?: string;
+  /** 失败原因 */
+  reason?: string;
+  /** 结果：success / failure */
+  result?: string;
+  /** 开始时间（2006-01-02 15:04:05） */
+  start_time?: string;
+  /** 用户名 */
+  username?: string;
+}) {
+  return request<Blob>(buildApiUrl('/login-logs/export'), {
+    method: 'GET',
+    params,
+    responseType: 'blob',
+  });
+}
+
+/**
+ * 获取登录日志详情
+ * 权限：system:login_log:list
+ */
+export async function getLoginLog(id: number) {
+  return request<API.Response<LoginLog>>(buildApiUrl(`/login-logs/${id}`), {
+    method: 'GET',
+  });
+}
+
+/** 权限排查 */
+export async function getSecurityExplain(params: {
+  /** 权限 key，如 system:admin_user:list */
+  permission: string;
+  /** 用户 ID */
+  user_id: number;
+}) {
+  return request<API.Response<PermissionExplanationDocItem>>(buildApiUrl('/security/explain'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 按接口路径排查权限 */
+export async function getSecurityExplainRoute(params: {
+  /** 请求方法，默认 GET */
+  method?: string;
+  /** 请求路径，如 /admin-api/admin-users/1，可省略 /admin-api 前缀 */
+  path: string;
+  /** 用户 ID */
+  user_id: number;
+}) {
+  return request<API.Response<PermissionExplanationDocItem>>(buildApiUrl('/security/explain/route'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/**
+ * 获取登录锁定列表
+ * 权限：system:login_lock:list
+ */
+export async function getLoginLockList(params?: LoginLockListParams) {
+  return request<API.Response<PageResult<AdminLoginLock>>>(buildApiUrl('/security/lockouts'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 解除登录锁定 */
+export async function deleteSecurityLockoutsByUsername(username: string, params?: {
+  /** 锁定类型：username（默认）/ ip */
+  kind?: string;
+}) {
+  return request<API.Response<null>>(buildApiUrl(`/security/lockouts/${username}`), {
+    method: 'DELETE',
+    params,
+  });
+}
+
+/** 上传通用文件 */
+export async function postUpload(data: FormData) {
+  return request<API.Response<UploadResponse>>(buildApiUrl('/upload'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取列表视图 */
+export async function getViews(params: {
+  /** 模块名，即 crud.ModuleConfig.Name */
+  module: string;
+}) {
+  return request<API.Response<ListViewDocItem[]>>(buildApiUrl('/views'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 创建列表视图 */
+export async function postViews(data: CreateListViewReq) {
+  return request<API.Response<ListViewDocItem>>(buildApiUrl('/views'), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 获取默认列表视图 */
+export async function getViewsDefault(params: {
+  /** 模块名，即 crud.ModuleConfig.Name */
+  module: string;
+}) {
+  return request<API.Response<ListViewDocItem>>(buildApiUrl('/views/default'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 获取列表视图详情 */
+export async function getViewsById(id: number) {
+  return request<API.Response<ListViewDocItem>>(buildApiUrl(`/views/${id}`), {
+    method: 'GET',
+  });
+}
+
+/** 更新列表视图 */
+export async function putViewsById(id: number, data: UpdateListViewReq) {
+  return request<API.Response<ListViewDocItem>>(buildApiUrl(`/views/${id}`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 删除列表视图 */
+export async function deleteViewsById(id: number) {
+  return request<API.Response<null>>(buildApiUrl(`/views/${id}`), {
+    method: 'DELETE',
+  });
+}
@@ -0,0 +1,86 @@
+// 此文件由 bico-admin gen ts 生成，请勿手动修改。
+
+export const PERMISSIONS = {
+  /** 工作台 */
+  DASHBOARD_MENU: 'dashboard:menu',
+  /** 系统管理 */
+  SYSTEM_MANAGE: 'system:manage',
+  /** 动态数据 */
+  DYNAMIC_DATA: 'dynamic:data',
+  /** 用户管理 */
+  SYSTEM_ADMIN_USER_MENU: 'system:admin_user:menu',
+  /** 查看列表 */
+  SYSTEM_ADMIN_USER_LIST: 'system:admin_user:list',
+  /** 创建 */
+  SYSTEM_ADMIN_USER_CREATE: 'system:admin_user:create',
+  /** 编辑 */
+  SYSTEM_ADMIN_USER_EDIT: 'system:admin_user:edit',
+  /** 删除 */
+  SYSTEM_ADMIN_USER_DELETE: 'system:admin_user:delete',
+  /** 重置两步验证 */
+  SYSTEM_ADMIN_USER_RESET_2FA: 'system:admin_user:reset_2fa',
+  /** 会话管理 */
+  SYSTEM_ADMIN_USER_SESSION: 'system:admin_user:session',
+  /** 访问令牌管理 */
+  SYSTEM_ADMIN_USER_API_TOKEN: 'system:admin_user:api_token',
+  /** 角色管理 */
+  SYSTEM_ADMIN_ROLE_MENU: 'system:admin_role:menu',
+  /** 查看列表 */
+  SYSTEM_ADMIN_ROLE_LIST: 'system:admin_role:list',
+  /** 创建 */
+  SYSTEM_ADMIN_ROLE_CREATE: 'system:admin_role:create',
+  /** 编辑 */
+  SYSTEM_ADMIN_ROLE_EDIT: 'system:admin_role:edit',
+  /** 删除 */
+  SYSTEM_ADMIN_ROLE_DELETE: 'system:admin_role:delete',
+  /** 配置权限 */
+  SYSTEM_ADMIN_ROLE_PERMISSION: 'system:admin_role:permission',
+  /** 部门管理 */
+  SYSTEM_DEPARTMENT_MENU: 'system:department:menu',
+  /** 查看列表 */
+  SYSTEM_DEPARTMENT_LIST: 'system:department:list',
+  /** 创建 */
+  SYSTEM_DEPARTMENT_CREATE: 'system:department:create',
+  /** 编辑 */
+  SYSTEM_DEPARTMENT_EDIT: 'system:department:edit',
+  /** 删除 */
+  SYSTEM_DEPARTMENT_DELETE: 'system:department:delete',
+  /** 动态表 */
+  SYSTEM_DYNAMIC_TABLE_MENU: 'system:dynamic_table:menu',
+  /** 查看列表 */
+  SYSTEM_DYNAMIC_TABLE_LIST: 'system:dynamic_table:list',
+  /** 创建 */
+  SYSTEM_DYNAMIC_TABLE_CREATE: 'system:dynamic_table:create',
+  /** 编辑 */
+  SYSTEM_DYNAMIC_TABLE_EDIT: 'system:dynamic_table:edit',
+  /** 删除 */
+  SYSTEM_DYNAMIC_TABLE_DELETE: 'system:dynamic_table:delete',
+  /** 登录日志 */
+  SYSTEM_LOGIN_LOG_MENU: 'system:login_log:menu',
+  /** 查看列表 */
+  SYSTEM_LOGIN_LOG_LIST: 'system:login_log:list',
+  /** 导出 */
+  SYSTEM_LOGIN_LOG_EXPORT: 'system:login_log:export',
+  /** IP 访问控制 */
+  SYSTEM_IP_RULE_MENU: 'system:ip_rule:menu',
+  /** 查看列表 */
+  SYSTEM_IP_RULE_LIST: 'system:ip_rule:list',
+  /** 创建 */
+  SYSTEM_IP_RULE_CREATE: 'system:ip_rule:create',
+  /** 编辑 */
+  SYSTEM_IP_RULE_EDIT: 'system:ip_rule:edit',
+  /** 删除 */
+  SYSTEM_IP_RULE_DELETE: 'system:ip_rule:delete',
+  /** 登录锁定 */
+  SYSTEM_LOGIN_LOCK_MENU: 'system:login_lock:menu',
+  /** 查看列表 */
+  SYSTEM_LOGIN_LOCK_LIST: 'system:login_lock:list',
+  /** 解锁 */
+  SYSTEM_LOGIN_LOCK_UNLOCK: 'system:login_lock:unlock',
+  /** 权限排查 */
+  SYSTEM_PERMISSION_EXPLAIN_MENU: 'system:permission_explain:menu',
+  /** 排查 */
+  SYSTEM_PERMISSION_EXPLAIN_QUERY: 'system:permission_explain:query',
+} as const;
+
+export type PermissionKey = (typeof PERMISSIONS)[keyof typeof PERMISSIONS];
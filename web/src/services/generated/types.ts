@@ -0,0 +1,796 @@
+// 此文件由 bico-admin gen ts 生成，请勿手动修改。
+
+/** 分页参数 */
+export interface PageParams {
+  page?: number;
+  pageSize?: number;
+  sortField?: string;
+  /** ascend 为升序，其余为降序 */
+  sortOrder?: string;
+}
+
+/** 分页结果 */
+export interface PageResult<T> {
+  list: T[];
+  total: number;
+}
+
+export interface AdminDepartment {
+  children: AdminDepartment[];
+  created_at: string;
+  enabled: boolean;
+  id: number;
+  leader_id?: number;
+  leader_name: string;
+  name: string;
+  parent_id: number;
+  sort: number;
+  updated_at: string;
+}
+
+export interface AdminIPRule {
+  action: string;
+  cidr: string;
+  created_at: string;
+  enabled: boolean;
+  id: number;
+  remark: string;
+  updated_at: string;
+}
+
+export interface AdminLoginLock {
+  created_at: string;
+  failures: number;
+  id: number;
+  ip: string;
+  kind: string;
+  locked_until: string;
+  subject: string;
+}
+
+export interface AdminRole {
+  allowed_ips: string[];
+  created_at: string;
+  denies: string[];
+  description: string;
+  enabled: boolean;
+  id: number;
+  name: string;
+  parent_ids: number[];
+  permissions: string[];
+  system: boolean;
+  two_factor_required: boolean;
+  updated_at: string;
+}
+
+export interface AdminRoleDocItem {
+  description: string;
+  enabled: boolean;
+  id: number;
+  name: string;
+  permissions: string[];
+  system: boolean;
+}
+
+export interface AdminUser {
+  allowed_ips: string[];
+  avatar: string;
+  created_at: string;
+  department_ids: number[];
+  email: string;
+  enabled: boolean;
+  id: number;
+  must_change_password: boolean;
+  name: string;
+  password_changed_at?: string;
+  primary_department_id: number;
+  role_grants: {
+    granted_by: number;
+    id: number;
+    reason: string;
+    role_id: number;
+    user_id: number;
+    valid_from?: string;
+    valid_until?: string;
+  }[];
+  roles: {
+    allowed_ips: string[];
+    created_at: string;
+    denies: string[];
+    description: string;
+    enabled: boolean;
+    id: number;
+    name: string;
+    parent_ids: number[];
+    permissions: string[];
+    system: boolean;
+    two_factor_required: boolean;
+    updated_at: string;
+  }[];
+  two_factor_enabled: boolean;
+  updated_at: string;
+  username: string;
+}
+
+export interface ApiTokenCreatedDoc {
+  allowed_ips: string[];
+  created_at: string;
+  expires_at: string;
+  id: number;
+  last_used_at: string;
+  last_used_ip: string;
+  name: string;
+  permissions: string[];
+  prefix: string;
+  token: string;
+  user_id: number;
+}
+
+export interface ApiTokenDocItem {
+  allowed_ips: string[];
+  created_at: string;
+  expires_at: string;
+  id: number;
+  last_used_at: string;
+  last_used_ip: string;
+  name: string;
+  permissions: string[];
+  prefix: string;
+  user_id: number;
+}
+
+export interface AppConfigDocResponse {
+  captchaAdaptive: boolean;
+  debug: boolean;
+  logo: string;
+  name: string;
+  passkey: boolean;
+  passwordReset: boolean;
+  ssoProviders: SSOProviderInfo[];
+}
+
+export interface CaptchaResponse {
+  height: number;
+  id: string;
+  image: string;
+  piece: string;
+  pieceY: number;
+  type: string;
+  width: number;
+}
+
+export interface ChangePasswordRequest {
+  newPassword: string;
+  oldPassword: string;
+}
+
+export interface CreateAPITokenRequest {
+  allowed_ips?: string[];
+  expires_at?: string;
+  name: string;
+  permissions?: string[];
+}
+
+export interface CreateDepartmentReq {
+  enabled?: boolean;
+  leader_id?: number;
+  name: string;
+  parent_id?: number;
+  sort?: number;
+}
+
+export interface CreateDynamicTableReq {
+  description?: string;
+  enabled?: boolean;
+  fields: {
+    filter?: string;
+    label?: string;
+    list_column?: boolean;
+    max?: number;
+    max_length?: number;
+    min?: number;
+    name?: string;
+    options?: string[];
+    pattern?: string;
+    required?: boolean;
+    sortable?: boolean;
+    type?: string;
+  }[];
+  label: string;
+  name: string;
+}
+
+export interface CreateIPRuleReq {
+  action: string;
+  cidr: string;
+  enabled?: boolean;
+  remark?: string;
+}
+
+export interface CreateListViewReq {
+  columns?: string[];
+  filters?: Record<string, string>;
+  is_default?: boolean;
+  module: string;
+  name: string;
+  page_size?: number;
+  role_ids?: number[];
+  sort_field?: string;
+  sort_order?: string;
+}
+
+export interface CreateRoleReq {
+  allowed_ips?: string[];
+  description?: string;
+  enabled?: boolean;
+  name: string;
+  permissions?: string[];
+  two_factor_required?: boolean;
+}
+
+export interface CreateUserReq {
+  allowed_ips?: string[];
+  avatar?: string;
+  department_ids?: number[];
+  email?: string;
+  enabled?: boolean;
+  must_change_password?: boolean;
+  name?: string;
+  password: string;
+  primary_department_id?: number;
+  role_ids?: number[];
+  username: string;
+}
+
+export interface CurrentUserDocResponse {
+  avatar: string;
+  email: string;
+  enabled: boolean;
+  id: number;
+  /** 模拟登录时由处理器填充，Impersonator 为实际操作者。 */
+  impersonated: boolean;
+  impersonator: ImpersonatorInfo;
+  name: string;
+  permissions: string[];
+  super_admin: boolean;
+  username: string;
+}
+
+export interface DashboardDatabaseInfo {
+  driver: string;
+  idle: number;
+  inUse: number;
+  maxIdleConnections: number;
+  maxOpenConnections: number;
+  openConnections: number;
+  waitCount: number;
+  waitDurationSeconds: number;
+}
+
+export interface DashboardMonitorInfo {
+  collectedAt: string;
+  metrics: DashboardMonitorMetric[];
+}
+
+export interface DashboardMonitorMetric {
+  key: string;
+  label: string;
+  status: string;
+  unit: string;
+  value: number;
+}
+
+export interface DashboardOverview {
+  database: DashboardDatabaseInfo;
+  monitor: DashboardMonitorInfo;
+  runtime: DashboardRuntimeInfo;
+  server: DashboardServerInfo;
+}
+
+export interface DashboardRuntimeInfo {
+  allocMb: number;
+  cpuCores: number;
+  gcCycles: number;
+  goMaxProcs: number;
+  goroutines: number;
+  heapInuseMb: number;
+  nextGcMb: number;
+  sysMb: number;
+}
+
+export interface DashboardServerInfo {
+  arch: string;
+  goVersion: string;
+  hostname: string;
+  mode: string;
+  os: string;
+  port: number;
+  startedAt: string;
+  uptimeSeconds: number;
+}
+
+export interface DeleteBatchDocRequest {
+  ids: number[];
+}
+
+export interface DemoExcelImportResponse {
+  preview: string[][];
+  total: number;
+}
+
+export interface DepartmentListReq {
+  enabled?: boolean;
+  name?: string;
+}
+
+export interface DepartmentTreeDocItem {
+  children: DepartmentTreeDocItem[];
+  enabled: boolean;
+  id: number;
+  leader_id: number;
+  leader_name: string;
+  name: string;
+  parent_id: number;
+  sort: number;
+}
+
+export interface DynamicImportResponse {
+  total: number;
+}
+
+export interface DynamicMenuItem {
+  access: string;
+  id: number;
+  label: string;
+  name: string;
+  path: string;
+}
+
+export interface DynamicPageDoc {
+  list: Record<string, any>[];
+  total: number;
+}
+
+export interface DynamicTable {
+  created_at: string;
+  description: string;
+  enabled: boolean;
+  fields: {
+    filter: string;
+    label: string;
+    list_column: boolean;
+    max?: number;
+    max_length: number;
+    min?: number;
+    name: string;
+    options: string[];
+    pattern: string;
+    required: boolean;
+    sortable: boolean;
+    type: string;
+  }[];
+  id: number;
+  label: string;
+  menu_id: number;
+  name: string;
+  updated_at: string;
+}
+
+export interface DynamicTableListReq {
+  enabled?: boolean;
+  label?: string;
+  name?: string;
+}
+
+export interface ExpiredPasswordRequest {
+  newPassword: string;
+  passwordChangeToken: string;
+}
+
+export interface ForgotPasswordRequest {
+  account: string;
+  captchaCode: string;
+  captchaId: string;
+}
+
+export interface GraphqlRequest {
+  operationName?: string;
+  query: string;
+  variables?: Record<string, any>;
+}
+
+export interface GraphqlResponse {
+  data: any;
+  errors: any[];
+}
+
+export interface ImpersonatorInfo {
+  id: number;
+  username: string;
+}
+
+export interface IpRuleListReq {
+  action?: string;
+  cidr?: string;
+  enabled?: boolean;
+}
+
+export interface ListViewDocItem {
+  columns: string[];
+  created_at: string;
+  filters: Record<string, string>;
+  id: number;
+  is_default: boolean;
+  module: string;
+  name: string;
+  owned: boolean;
+  page_size: number;
+  role_ids: number[];
+  sort_field: string;
+  sort_order: string;
+  updated_at: string;
+  user_id: number;
+}
+
+export interface LoginDocResponse {
+  expiresIn: number;
+  passwordChangeToken: string;
+  passwordExpired: boolean;
+  recoveryCodes: string[];
+  refreshToken: string;
+  token: string;
+  twoFactorMethods: string[];
+  twoFactorSetup: boolean;
+  twoFactorToken: string;
+}
+
+export interface LoginLockListReq {
+  kind?: string;
+  subject?: string;
+}
+
+export interface LoginLog {
+  created_at: string;
+  device: string;
+  id: number;
+  ip: string;
+  reason: string;
+  result: string;
+  user_agent: string;
+  user_id: number;
+  username: string;
+}
+
+export interface LoginLogListReq {
+  end_time?: string;
+  ip?: string;
+  reason?: string;
+  result?: string;
+  start_time?: string;
+  user_id?: number;
+  username?: string;
+}
+
+export interface LoginRequest {
+  captchaCode?: string;
+  captchaId?: string;
+  password: string;
+  username: string;
+}
+
+export interface MoveDepartmentReq {
+  parent_id: number;
+  sort?: number;
+}
+
+export interface PasskeyCeremony {
+  options: Record<string, any>;
+  token: string;
+}
+
+export interface PasskeyDocItem {
+  created_at: string;
+  id: number;
+  last_used_at: string;
+  last_used_ip: string;
+  name: string;
+  sign_count: number;
+  transports: string[];
+  updated_at: string;
+  user_id: number;
+}
+
+export interface PasskeyLoginRequest {
+  /** Credential 为 navigator.credentials.get 返回的凭据 JSON。 */
+  credential: Record<string, any>;
+  token: string;
+}
+
+export interface PasskeyRegisterRequest {
+  /** Credential 为 navigator.credentials.create 返回的凭据 JSON。 */
+  credential: Record<string, any>;
+  name: string;
+  token: string;
+}
+
+export interface PasskeyTwoFactorRequest {
+  credential: Record<string, any>;
+  twoFactorToken: string;
+}
+
+export interface PermissionCacheState {
+  permission_cached: boolean;
+  stale: boolean;
+  status_cached: boolean;
+}
+
+export interface PermissionDocItem {
+  children: PermissionDocItem[];
+  key: string;
+  label: string;
+}
+
+export interface PermissionExplanationDocItem {
+  allowed: boolean;
+  cache: PermissionCacheState;
+  permission: string;
+  reason: string;
+  registered: boolean;
+  roles: RoleExplanation[];
+  route: RouteDocItem;
+  super_admin: boolean;
+  user_enabled: boolean;
+  user_id: number;
+  username: string;
+}
+
+export interface RecoveryCodesResponse {
+  recoveryCodes: string[];
+}
+
+export interface RefreshRequest {
+  refreshToken: string;
+}
+
+export interface RenamePasskeyRequest {
+  name: string;
+}
+
+export interface ResetPasswordRequest {
+  captchaCode: string;
+  captchaId: string;
+  newPassword: string;
+  token: string;
+}
+
+export interface RoleExplanation {
+  code: string;
+  /** 是否参与权限计算 */
+  effective: boolean;
+  enabled: boolean;
+  grant_active: boolean;
+  grant_id: number;
+  id: number;
+  inherited: boolean;
+  matched_denies: string[];
+  matched_grants: string[];
+  name: string;
+  valid_from: string;
+  valid_until: string;
+}
+
+export interface RoleGrantItem {
+  active: boolean;
+  granted_by: number;
+  id: number;
+  name: string;
+  reason: string;
+  role_id: number;
+  role_name: string;
+  user_id: number;
+  username: string;
+  valid_from: string;
+  valid_until: string;
+}
+
+export interface RoleGrantRequest {
+  reason?: string;
+  role_id: number;
+  valid_from?: string;
+  valid_until?: string;
+}
+
+export interface RoleListReq {
+  description?: string;
+  enabled?: boolean;
+  name?: string;
+}
+
+export interface RoleParentsResponse {
+  parent_ids: number[];
+}
+
+export interface RolePermissionsResponse {
+  permissions: string[];
+}
+
+export interface RouteDocItem {
+  method: string;
+  module: string;
+  path: string;
+  permission: string;
+  public: boolean;
+}
+
+export interface SSOAuthorizeResponse {
+  url: string;
+}
+
+export interface SSOCallbackRequest {
+  code: string;
+  state: string;
+}
+
+export interface SSOProviderInfo {
+  label: string;
+  name: string;
+}
+
+export interface SessionDocItem {
+  created_at: string;
+  current: boolean;
+  device: string;
+  expires_at: string;
+  id: number;
+  ip: string;
+  last_seen_at: string;
+  user_agent: string;
+  user_id: number;
+}
+
+export interface TwoFactorCodeRequest {
+  code: string;
+}
+
+export interface TwoFactorDisableRequest {
+  code: string;
+  password: string;
+}
+
+export interface TwoFactorKeyResponse {
+  image: string;
+  secret: string;
+  url: string;
+}
+
+export interface TwoFactorPendingRequest {
+  twoFactorToken: string;
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean;
+  recoveryCodesRemaining: number;
+  /** Required 表示所属角色强制要求两步验证。 */
+  required: boolean;
+}
+
+export interface TwoFactorVerifyRequest {
+  /** Code 为 6 位动态码或恢复码。 */
+  code: string;
+  twoFactorToken: string;
+}
+
+export interface UpdateDepartmentReq {
+  enabled?: boolean;
+  leader_id?: number;
+  name?: string;
+  sort?: number;
+}
+
+export interface UpdateDynamicTableReq {
+  description?: string;
+  enabled?: boolean;
+  fields?: {
+    filter?: string;
+    label?: string;
+    list_column?: boolean;
+    max?: number;
+    max_length?: number;
+    min?: number;
+    name?: string;
+    options?: string[];
+    pattern?: string;
+    required?: boolean;
+    sortable?: boolean;
+    type?: string;
+  }[];
+  label?: string;
+}
+
+export interface UpdateIPRuleReq {
+  action?: string;
+  cidr?: string;
+  enabled?: boolean;
+  remark?: string;
+}
+
+export interface UpdateListViewReq {
+  columns?: string[];
+  filters?: Record<string, string>;
+  is_default?: boolean;
+  name?: string;
+  page_size?: number;
+  role_ids?: number[];
+  sort_field?: string;
+  sort_order?: string;
+}
+
+export interface UpdateProfileRequest {
+  avatar?: string;
+  name?: string;
+  username?: string;
+}
+
+export interface UpdateRoleParentsReq {
+  parent_ids?: number[];
+}
+
+export interface UpdateRolePermReq {
+  permissions?: string[];
+}
+
+export interface UpdateRoleReq {
+  allowed_ips?: string[];
+  description?: string;
+  enabled?: boolean;
+  name?: string;
+  two_factor_required?: boolean;
+}
+
+export interface UpdateUserReq {
+  allowed_ips?: string[];
+  avatar?: string;
+  department_ids?: number[];
+  email?: string;
+  enabled?: boolean;
+  must_change_password?: boolean;
+  name?: string;
+  password?: string;
+  primary_department_id?: number;
+  role_ids?: number[];
+  username?: string;
+}
+
+export interface UploadResponse {
+  url: string;
+}
+
+export interface UserListReq {
+  department_id?: number;
+  enabled?: boolean;
+  include_children?: boolean;
+  name?: string;
+  role_ids?: string;
+  username?: string;
+}
+
+/** 用户管理列表参数 */
+export type AdminUserListParams = PageParams & UserListReq;
+
+/** 角色管理列表参数 */
+export type AdminRoleListParams = PageParams & RoleListReq;
+
+/** 部门管理列表参数 */
+export type DepartmentListParams = PageParams & DepartmentListReq;
+
+/** 动态表列表参数 */
+export type DynamicTableListParams = PageParams & DynamicTableListReq;
+
+/** 登录日志列表参数 */
+export type LoginLogListParams = PageParams & LoginLogListReq;
+
+/** IP 访问控制列表参数 */
+export type IpRuleListParams = PageParams & IpRuleListReq;
+
+/** 登录锁定列表参数 */
+export type LoginLockListParams = PageParams & LoginLockListReq;
@@ -4,7 +4,7 @@
  */
 import { request } from '@umijs/max';
 import { buildApiUrl } from '../../config';
-import type { AdminRole, Permission, UpdatePermissionsParams } from './types';
+import type { AdminRole, Permission, UpdateParentsParams, UpdatePermissionsParams } from './types';
 
 /** 获取所有角色（不分页，用于下拉选择） */
 export async function getAllAdminRoles() {
@@ -27,3 +27,19 @@ export async function updateRolePermissions(id: number, data: UpdatePermissionsP
     data,
   });
 }
+
+/** 更新角色拒绝权限 */
+export async function updateRoleDenies(id: number, data: UpdatePermissionsParams) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}/denies`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 更新角色继承的父角色 */
+export async function updateRoleParents(id: number, data: UpdateParentsParams) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-roles/${id}/parents`), {
+    method: 'PUT',
+    data,
+  });
+}
@@ -16,7 +16,12 @@ export interface AdminRole {
   system: boolean;
 }
 
-/** 更新权限参数 */
+/** 更新权限参数，支持以 :* 结尾的通配符 */
 export interface UpdatePermissionsParams {
   permissions: string[];
 }
+
+/** 更新继承角色参数 */
+export interface UpdateParentsParams {
+  parent_ids: number[];
+}
@@ -0,0 +1,65 @@
+/**
+ * 用户管理 - 特殊接口
+ * 标准 CRUD 使用 createCrudService('/admin-users')
+ */
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../../config';
+import type { LoginResult } from '../../auth/types';
+
+/** 模拟用户登录（仅超级管理员），返回以该用户身份签发的令牌 */
+export async function impersonateUser(id: number) {
+  return request<API.Response<LoginResult>>(buildApiUrl(`/admin-users/${id}/impersonate`), {
+    method: 'POST',
+  });
+}
+
+export interface RoleGrant {
+  id: number;
+  user_id: number;
+  username: string;
+  name: string;
+  role_id: number;
+  role_name: string;
+  valid_from?: string | null;
+  valid_until?: string | null;
+  reason: string;
+  granted_by: number;
+  active: boolean;
+}
+
+export interface RoleGrantParams {
+  role_id: number;
+  valid_from?: string;
+  valid_until?: string;
+  reason?: string;
+}
+
+/** 获取用户全部角色授权，含尚未生效的授权 */
+export async function getUserRoleGrants(id: number) {
+  return request<API.Response<RoleGrant[]>>(buildApiUrl(`/admin-users/${id}/role-grants`), {
+    method: 'GET',
+  });
+}
+
+/** 授予用户角色，已有该角色时覆盖有效期与原因 */
+export async function saveUserRoleGrant(id: number, data: RoleGrantParams) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/role-grants`), {
+    method: 'POST',
+    data,
+  });
+}
+
+/** 撤销用户角色授权 */
+export async function revokeUserRoleGrant(id: number, grantId: number) {
+  return request<API.Response<null>>(buildApiUrl(`/admin-users/${id}/role-grants/${grantId}`), {
+    method: 'DELETE',
+  });
+}
+
+/** 获取 days 天内将要到期的角色授权 */
+export async function getExpiringRoleGrants(days?: number) {
+  return request<API.Response<RoleGrant[]>>(buildApiUrl('/admin-users/role-grants/expiring'), {
+    method: 'GET',
+    params: { days },
+  });
+}
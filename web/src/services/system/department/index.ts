@@ -0,0 +1,44 @@
+/**
+ * 部门管理 - 特殊接口
+ * 标准 CRUD 使用 createCrudService('/departments')
+ */
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../../config';
+
+export interface Department {
+  id: number;
+  parent_id: number;
+  name: string;
+  sort: number;
+  leader_id?: number | null;
+  leader_name?: string;
+  enabled: boolean;
+  children?: Department[];
+  created_at: string;
+}
+
+/** 获取部门树，筛选时上级未命中的部门作为根节点返回 */
+export async function getDepartmentTree(params?: { name?: string; enabled?: boolean }) {
+  return request<API.Response<Department[]>>(buildApiUrl('/departments/tree'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 移动部门到新的上级部门下，parent_id 为 0 表示顶级 */
+export async function moveDepartment(id: number, data: { parent_id: number; sort?: number }) {
+  return request<API.Response<null>>(buildApiUrl(`/departments/${id}/move`), {
+    method: 'PUT',
+    data,
+  });
+}
+
+/** 转换为 TreeSelect 数据，exclude 及其下级不可选 */
+export function toTreeSelectData(items: Department[], exclude?: number): any[] {
+  return items.map((item) => ({
+    title: item.name,
+    value: item.id,
+    disabled: item.id === exclude,
+    children: item.id === exclude ? [] : toTreeSelectData(item.children || [], exclude),
+  }));
+}
@@ -0,0 +1,34 @@
+/**
+ * 登录锁定 - 查看与解锁
+ */
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../../config';
+
+export interface LoginLock {
+  id: number;
+  /** username / ip */
+  kind: string;
+  /** 被锁定的用户名或 IP */
+  subject: string;
+  failures: number;
+  /** 最后一次失败的来源 IP */
+  ip: string;
+  locked_until: string;
+  created_at: string;
+}
+
+/** 分页查询锁定期内的记录 */
+export async function getLoginLocks(params?: Record<string, any>) {
+  return request<API.Response<LoginLock[]> & { total?: number }>(buildApiUrl('/security/lockouts'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 解除锁定 */
+export async function unlockLoginLock(kind: string, subject: string) {
+  return request<API.Response<null>>(buildApiUrl(`/security/lockouts/${encodeURIComponent(subject)}`), {
+    method: 'DELETE',
+    params: { kind },
+  });
+}
@@ -0,0 +1,37 @@
+/**
+ * 登录日志 - 只读接口
+ */
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../../config';
+
+export interface LoginLog {
+  id: number;
+  username: string;
+  user_id: number;
+  /** success / failure */
+  result: string;
+  /** 失败原因：bad_password、user_not_found、locked、disabled、bad_captcha、bad_two_factor、bad_passkey、sso_failed、sso_unlinked、provider_error */
+  reason: string;
+  device: string;
+  ip: string;
+  user_agent: string;
+  created_at: string;
+}
+
+/** 分页查询登录日志 */
+export async function getLoginLogs(params?: Record<string, any>) {
+  return request<API.Response<LoginLog[]> & { total?: number }>(buildApiUrl('/login-logs'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 按筛选条件导出登录日志 */
+export async function exportLoginLogs(params?: Record<string, any>) {
+  return request(buildApiUrl('/login-logs/export'), {
+    method: 'GET',
+    params,
+    responseType: 'blob',
+    getResponse: true,
+  });
+}
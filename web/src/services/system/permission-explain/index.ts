@@ -0,0 +1,50 @@
+/**
+ * 权限排查 - 说明用户为什么能或不能访问
+ */
+import { request } from '@umijs/max';
+import { buildApiUrl } from '../../config';
+
+export interface RoleExplanation {
+  id: number;
+  name: string;
+  code: string;
+  enabled: boolean;
+  inherited: boolean;
+  grant_id?: number;
+  valid_from?: string;
+  valid_until?: string;
+  grant_active: boolean;
+  effective: boolean;
+  matched_grants?: string[];
+  matched_denies?: string[];
+}
+
+export interface PermissionExplanation {
+  user_id: number;
+  username: string;
+  permission: string;
+  allowed: boolean;
+  reason: string;
+  registered: boolean;
+  user_enabled: boolean;
+  super_admin: boolean;
+  roles: RoleExplanation[];
+  cache: { status_cached: boolean; permission_cached: boolean; stale: boolean };
+  route?: { module: string; method: string; path: string; permission: string; public: boolean };
+}
+
+/** 按权限 key 排查 */
+export async function explainPermission(params: { user_id: number; permission: string }) {
+  return request<API.Response<PermissionExplanation>>(buildApiUrl('/security/explain'), {
+    method: 'GET',
+    params,
+  });
+}
+
+/** 按接口路径排查，path 可省略 /admin-api 前缀 */
+export async function explainRoute(params: { user_id: number; method?: string; path: string }) {
+  return request<API.Response<PermissionExplanation>>(buildApiUrl('/security/explain/route'), {
+    method: 'GET',
+    params,
+  });
+}
@@ -11,3 +11,4 @@ export * from './download';
 export * from './redirect';
 export * from './table';
 export * from './format';
+export * from './token';
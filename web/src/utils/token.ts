@@ -0,0 +1,81 @@
+/**
+ * 登录令牌工具
+ * 访问令牌有效期较短，临近过期时使用刷新令牌换取新令牌
+ */
+
+import { buildApiUrl } from '@/services/config';
+
+const TOKEN_KEY = 'token';
+const REFRESH_TOKEN_KEY = 'refreshToken';
+const EXPIRES_AT_KEY = 'tokenExpiresAt';
+
+// 距离过期不足该时间（毫秒）时提前刷新
+const REFRESH_AHEAD = 60 * 1000;
+
+export interface TokenPair {
+  token: string;
+  refreshToken?: string;
+  expiresIn?: number;
+}
+
+/**
+ * 保存登录令牌
+ */
+export function saveTokens(pair: TokenPair) {
+  localStorage.setItem(TOKEN_KEY, pair.token);
+  if (pair.refreshToken) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, pair.refreshToken);
+  }
+  if (pair.expiresIn) {
+    localStorage.setItem(EXPIRES_AT_KEY, String(Date.now() + pair.expiresIn * 1000));
+  }
+}
+
+/**
+ * 清除登录令牌
+ */
+export function clearTokens() {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+  localStorage.removeItem(EXPIRES_AT_KEY);
+}
+
+// 同一时刻只发起一次刷新，避免并发请求重复使用同一个刷新令牌导致整次登录被吊销
+let refreshing: Promise<string | null> | null = null;
+
+async function refresh(refreshToken: string): Promise<string | null> {
+  // 直接使用 fetch，绕过请求拦截器，避免递归刷新
+  const response = await fetch(buildApiUrl('/auth/refresh'), {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken }),
+  });
+  const result = await response.json();
+  if (result?.code !== 0 || !result.data?.token) {
+    return null;
+  }
+  saveTokens(result.data);
+  return result.data.token;
+}
+
+/**
+ * 获取可用的访问令牌，临近过期时自动刷新
+ */
+export async function getFreshToken(): Promise<string | null> {
+  const token = localStorage.getItem(TOKEN_KEY);
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  const expiresAt = Number(localStorage.getItem(EXPIRES_AT_KEY) || 0);
+  if (!token || !refreshToken || !expiresAt || expiresAt - Date.now() > REFRESH_AHEAD) {
+    return token;
+  }
+
+  if (!refreshing) {
+    refreshing = refresh(refreshToken)
+      .catch(() => null)
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  // 刷新失败时沿用旧令牌，由后端返回 401 统一跳转登录
+  return (await refreshing) || token;
+}
@@ -0,0 +1,95 @@
+/**
+ * WebAuthn 通行密钥工具
+ * 服务端下发的参数与提交的凭据均为 JSON，二进制字段使用 base64url 编码，
+ * 这里负责与浏览器 API 使用的 ArrayBuffer 互相转换。
+ */
+
+function toBuffer(value: string): ArrayBuffer {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/');
+  const padded = base64 + '='.repeat((4 - (base64.length % 4)) % 4);
+  const binary = atob(padded);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return bytes.buffer;
+}
+
+function toBase64URL(buffer: ArrayBuffer | null | undefined): string | undefined {
+  if (!buffer) return undefined;
+  const bytes = new Uint8Array(buffer);
+  let binary = '';
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i]);
+  }
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+/**
+ * 当前浏览器是否支持通行密钥
+ */
+export function isPasskeySupported(): boolean {
+  return typeof window !== 'undefined' && !!window.PublicKeyCredential && !!navigator.credentials;
+}
+
+/**
+ * 按服务端注册参数创建通行密钥，返回提交给服务端的凭据 JSON
+ */
+export async function createPasskey(options: any): Promise<Record<string, any>> {
+  const publicKey = options.publicKey;
+  const credential = (await navigator.credentials.create({
+    publicKey: {
+      ...publicKey,
+      challenge: toBuffer(publicKey.challenge),
+      user: { ...publicKey.user, id: toBuffer(publicKey.user.id) },
+      excludeCredentials: (publicKey.excludeCredentials || []).map((item: any) => ({
+        ...item,
+        id: toBuffer(item.id),
+      })),
+    },
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAttestationResponse;
+  return {
+    id: credential.id,
+    rawId: toBase64URL(credential.rawId),
+    type: credential.type,
+    authenticatorAttachment: credential.authenticatorAttachment,
+    clientExtensionResults: credential.getClientExtensionResults(),
+    response: {
+      clientDataJSON: toBase64URL(response.clientDataJSON),
+      attestationObject: toBase64URL(response.attestationObject),
+      transports: response.getTransports?.() || [],
+    },
+  };
+}
+
+/**
+ * 按服务端登录参数使用通行密钥签名，返回提交给服务端的凭据 JSON
+ */
+export async function getPasskey(options: any): Promise<Record<string, any>> {
+  const publicKey = options.publicKey;
+  const credential = (await navigator.credentials.get({
+    publicKey: {
+      ...publicKey,
+      challenge: toBuffer(publicKey.challenge),
+      allowCredentials: (publicKey.allowCredentials || []).map((item: any) => ({
+        ...item,
+        id: toBuffer(item.id),
+      })),
+    },
+  })) as PublicKeyCredential;
+  const response = credential.response as AuthenticatorAssertionResponse;
+  return {
+    id: credential.id,
+    rawId: toBase64URL(credential.rawId),
+    type: credential.type,
+    authenticatorAttachment: credential.authenticatorAttachment,
+    clientExtensionResults: credential.getClientExtensionResults(),
+    response: {
+      clientDataJSON: toBase64URL(response.clientDataJSON),
+      authenticatorData: toBase64URL(response.authenticatorData),
+      signature: toBase64URL(response.signature),
+      userHandle: toBase64URL(response.userHandle),
+    },
+  };
+}
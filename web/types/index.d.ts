@@ -156,6 +156,10 @@ declare namespace API {
     name: string;
     logo: string;
     debug: boolean;
+    ssoProviders?: { name: string; label: string }[];
+    passwordReset?: boolean;
+    captchaAdaptive?: boolean;
+    passkey?: boolean;
   };
 
   /** 更新个人信息参数 */